@@ -0,0 +1,211 @@
+// Package aovivo transmite aos balcões de circulação os empréstimos,
+// devoluções e reservas à medida que acontecem, por Server-Sent Events ou
+// WebSocket. Os eventos são os do outbox (pacote servico): cada conexão
+// guarda o ID do último evento enviado e, a cada aviso do banco (LISTEN/NOTIFY
+// no PostgreSQL, change stream no MongoDB), lê os seguintes. Quem reconecta
+// informa o último ID recebido e recebe os eventos que perdeu.
+package aovivo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/outbox"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Caminho sob o qual o acompanhamento é atendido
+const Caminho = "/aovivo"
+
+// Tipos são os eventos transmitidos
+var Tipos = []string{
+	model.TipoEmprestimoCriado, model.TipoEmprestimoDevolvido, model.TipoEmprestimoRenovado,
+	model.TipoReservaCriada, model.TipoReservaAtendida, model.TipoReservaCancelada,
+}
+
+const (
+	// com avisos do banco, a consulta periódica só cobre avisos perdidos
+	varredura          = 5 * time.Second
+	varreduraSemAvisos = time.Second
+	// pulsacao mantém abertas as conexões sem eventos, que proxies fechariam
+	pulsacao = 15 * time.Second
+	// reconexao é a espera sugerida aos clientes e a espera antes de voltar
+	// a escutar os avisos do banco depois de uma falha
+	reconexao   = 3 * time.Second
+	tamanhoLote = 100
+	// janela é por quanto tempo os eventos enviados são lembrados. Os IDs
+	// seguem o momento do evento, e não o da confirmação: um evento de uma
+	// transação mais longa pode aparecer depois de outros com IDs maiores, e
+	// a janela é relida para não perdê-lo
+	janela = 30 * time.Second
+)
+
+// idEvento é o formato dos IDs gerados pelo pacote servico
+var idEvento = regexp.MustCompile(`^[0-9]{19}-[0-9a-f]{8}$`)
+
+type Feed struct {
+	biblioteca *servico.Biblioteca
+	avisos     repository.AvisosOutbox
+	mux        *http.ServeMux
+
+	mu        sync.Mutex
+	inscritos map[chan struct{}]struct{}
+	encerrado chan struct{} // fechado quando Executar termina, para encerrar as conexões
+}
+
+// New cria o acompanhamento; sem avisos do banco (nil), o outbox é consultado
+// a cada segundo
+func New(b *servico.Biblioteca, avisos repository.AvisosOutbox) *Feed {
+	f := &Feed{biblioteca: b, avisos: avisos, mux: http.NewServeMux(),
+		inscritos: map[chan struct{}]struct{}{}, encerrado: make(chan struct{})}
+	f.mux.HandleFunc("GET "+Caminho+"/{$}", f.pagina)
+	f.mux.HandleFunc("GET "+Caminho+"/eventos", f.sse)
+	f.mux.Handle("GET "+Caminho+"/ws", f.websocket())
+	return f
+}
+
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.ServeHTTP(w, r)
+}
+
+// Executar repassa os avisos do banco às conexões até o contexto ser
+// cancelado; ao terminar, encerra as conexões abertas
+func (f *Feed) Executar(ctx context.Context) {
+	defer close(f.encerrado)
+	intervalo := varreduraSemAvisos
+	if f.avisos != nil {
+		intervalo = varredura
+		go f.escutar(ctx)
+	}
+	t := time.NewTicker(intervalo)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			f.acordar()
+		}
+	}
+}
+
+func (f *Feed) escutar(ctx context.Context) {
+	for {
+		err := f.avisos.Escutar(ctx, f.acordar)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("AVISO: ao vivo: avisos do banco interrompidos (%v); nova tentativa em %s.\n", err, reconexao)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconexao):
+		}
+	}
+}
+
+// acordar avisa todas as conexões; as que ainda não atenderam o aviso
+// anterior não acumulam outro
+func (f *Feed) acordar() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for c := range f.inscritos {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (f *Feed) inscrever() chan struct{} {
+	c := make(chan struct{}, 1)
+	f.mu.Lock()
+	f.inscritos[c] = struct{}{}
+	f.mu.Unlock()
+	return c
+}
+
+func (f *Feed) cancelar(c chan struct{}) {
+	f.mu.Lock()
+	delete(f.inscritos, c)
+	f.mu.Unlock()
+}
+
+// transmitir envia os eventos posteriores a desde e, depois, os novos, até o
+// cliente sair ou o servidor encerrar. Sem desde, só os eventos novos são
+// enviados. pulsar é chamada nos intervalos sem eventos
+func (f *Feed) transmitir(ctx context.Context, desde string, enviar func(outbox.Mensagem) error, pulsar func() error) error {
+	// a inscrição vem antes da primeira leitura para não perder avisos
+	aviso := f.inscrever()
+	defer f.cancelar(aviso)
+	inicio := desde
+	if inicio == "" {
+		var err error
+		if inicio, err = f.biblioteca.UltimoEvento(ctx); err != nil {
+			return err
+		}
+	}
+	cursor := inicio
+	enviados := map[string]bool{}
+	pulso := time.NewTicker(pulsacao)
+	defer pulso.Stop()
+	for {
+		// relê a janela recente, pulando o que já foi enviado
+		piso := fmt.Sprintf("%019d", time.Now().Add(-janela).UnixNano())
+		for id := range enviados {
+			if id < piso {
+				delete(enviados, id)
+			}
+		}
+		limite := max(inicio, min(cursor, piso))
+		for {
+			eventos, err := f.biblioteca.EventosDepois(ctx, limite, Tipos, tamanhoLote)
+			if err != nil {
+				return err
+			}
+			for _, e := range eventos {
+				limite = e.ID
+				if enviados[e.ID] {
+					continue
+				}
+				if err := enviar(outbox.NovaMensagem(e)); err != nil {
+					return err
+				}
+				enviados[e.ID], cursor = true, max(cursor, e.ID)
+			}
+			if len(eventos) < tamanhoLote {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-f.encerrado:
+			return nil
+		case <-aviso:
+		case <-pulso.C:
+			if err := pulsar(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ultimoID lê o ID do último evento recebido pelo cliente
+func ultimoID(r *http.Request) (string, error) {
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.URL.Query().Get("desde")
+	}
+	if id != "" && !idEvento.MatchString(id) {
+		return "", fmt.Errorf("ID de evento inválido: '%s'", id)
+	}
+	return id, nil
+}
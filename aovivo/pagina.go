@@ -0,0 +1,61 @@
+package aovivo
+
+import "net/http"
+
+// GET /aovivo/: painel para os balcões, que mostra os eventos recebidos pelo
+// EventSource, dos mais recentes; o navegador reconecta sozinho
+func (f *Feed) pagina(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(paginaHTML))
+}
+
+const paginaHTML = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Circulação ao vivo</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .3em .6em; border-bottom: 1px solid #ddd; }
+#situacao { color: #666; }
+.EmprestimoCriado { color: #05619b; }
+.EmprestimoDevolvido { color: #2e7d32; }
+.ReservaCancelada { color: #8a6d00; }
+</style>
+</head>
+<body>
+<h1>Circulação ao vivo</h1>
+<p id="situacao">Conectando...</p>
+<table>
+<thead><tr><th>Horário</th><th>Evento</th><th>Registro</th><th>Usuário</th></tr></thead>
+<tbody id="eventos"></tbody>
+</table>
+<script>
+const rotulos = {
+  EmprestimoCriado: "Empréstimo", EmprestimoDevolvido: "Devolução", EmprestimoRenovado: "Renovação",
+  ReservaCriada: "Reserva", ReservaAtendida: "Reserva atendida", ReservaCancelada: "Reserva cancelada",
+};
+const situacao = document.getElementById("situacao");
+const corpo = document.getElementById("eventos");
+const fonte = new EventSource("eventos");
+fonte.onopen = () => { situacao.textContent = "Conectado."; };
+fonte.onerror = () => { situacao.textContent = "Conexão perdida; reconectando..."; };
+for (const tipo of Object.keys(rotulos)) {
+  fonte.addEventListener(tipo, (e) => {
+    const m = JSON.parse(e.data);
+    const linha = document.createElement("tr");
+    linha.className = m.tipo;
+    const cpf = m.dados.cliente_usuario_cpf || m.dados.usuario_cpf || "";
+    for (const texto of [new Date(m.ocorrido_em).toLocaleTimeString(), rotulos[m.tipo], m.agregado + " " + m.chave, cpf]) {
+      const celula = document.createElement("td");
+      celula.textContent = texto;
+      linha.appendChild(celula);
+    }
+    corpo.prepend(linha);
+  });
+}
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,95 @@
+package aovivo
+
+import (
+	"context"
+	"crud-biblioteca/outbox"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// GET /aovivo/eventos: Server-Sent Events, um por evento, com o ID do evento
+// no campo id para que o EventSource o devolva em Last-Event-ID ao reconectar
+func (f *Feed) sse(w http.ResponseWriter, r *http.Request) {
+	desde, err := ultimoID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(w, "retry: %d\n\n", reconexao.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		return
+	}
+	err = f.transmitir(r.Context(), desde, func(m outbox.Mensagem) error {
+		dados, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", m.ID, m.Tipo, dados)
+		return rc.Flush()
+	}, func() error {
+		fmt.Fprint(w, ": pulso\n\n")
+		return rc.Flush()
+	})
+	if err != nil && r.Context().Err() == nil {
+		log.Printf("ERRO: ao vivo: %v\n", err)
+	}
+}
+
+// GET /aovivo/ws?desde=: WebSocket com uma mensagem JSON por evento. O
+// cliente só recebe; o que ele enviar é descartado
+func (f *Feed) websocket() http.Handler {
+	return websocket.Server{Handshake: mesmaOrigem, Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		r := ws.Request()
+		desde, err := ultimoID(r)
+		if err != nil {
+			websocket.JSON.Send(ws, map[string]string{"erro": err.Error()})
+			return
+		}
+		// a leitura só serve para perceber que o cliente fechou a conexão
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			var descartada []byte
+			for websocket.Message.Receive(ws, &descartada) == nil {
+			}
+		}()
+		err = f.transmitir(ctx, desde, func(m outbox.Mensagem) error {
+			return websocket.JSON.Send(ws, m)
+		}, func() error {
+			ws.PayloadType = websocket.PingFrame
+			defer func() { ws.PayloadType = websocket.TextFrame }()
+			_, err := ws.Write(nil)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Printf("ERRO: ao vivo: %v\n", err)
+		}
+	}}
+}
+
+// mesmaOrigem recusa conexões abertas por páginas de outros sites, que o
+// navegador não impede no WebSocket como faz com o EventSource. Clientes
+// fora do navegador não enviam Origin e são aceitos
+func mesmaOrigem(config *websocket.Config, r *http.Request) error {
+	origem := r.Header.Get("Origin")
+	if origem == "" {
+		return nil
+	}
+	u, err := url.Parse(origem)
+	if err != nil || u.Host != r.Host {
+		return fmt.Errorf("origem não permitida: '%s'", origem)
+	}
+	config.Origin = u
+	return nil
+}
@@ -0,0 +1,159 @@
+// Package api expõe as operações da biblioteca como uma API REST/JSON.
+// Os recursos ficam sob o prefixo /api e as regras de negócio são as do
+// pacote servico, as mesmas aplicadas pelo menu interativo.
+package api
+
+import (
+	"crud-biblioteca/resposta"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prefixo das rotas da API
+const Prefixo = "/api"
+
+// limiteCorpo é o tamanho máximo aceito para o corpo das requisições
+const limiteCorpo = 1 << 20
+
+type Servidor struct {
+	biblioteca  *servico.Biblioteca
+	mux         *http.ServeMux
+	operacoes   []operacao  // rotas registradas, na ordem, para o documento OpenAPI
+	componentes componentes // esquemas gerados a partir dos tipos do pacote model
+	documento   *Documento
+}
+
+func New(b *servico.Biblioteca) *Servidor {
+	s := &Servidor{biblioteca: b, mux: http.NewServeMux(), componentes: componentes{}}
+	s.rotasUsuarios()
+	s.rotasLivros()
+	s.rotasAutores()
+	s.rotasEmprestimos()
+	s.rotasWebhooks()
+
+	// a documentação descreve as rotas acima e não faz parte dela
+	s.documento = s.gerarDocumento()
+	s.mux.HandleFunc("GET "+Prefixo+"/openapi.json", s.servirDocumento)
+	s.mux.HandleFunc("GET "+Prefixo+"/docs", s.servirDocs)
+	s.mux.Handle("GET "+Prefixo+"/{$}", http.RedirectHandler(Prefixo+"/docs", http.StatusFound))
+	return s
+}
+
+// ServeHTTP atende a requisição e registra no log o método, o caminho, o
+// status e a duração
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	inicio := time.Now()
+	rw := resposta.Registrar(w)
+	s.mux.ServeHTTP(rw, r)
+	log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), rw.Status, time.Since(inicio).Round(time.Millisecond))
+}
+
+// rota registra o handler no padrão "MÉTODO /caminho", relativo ao Prefixo.
+// A operação entra no documento OpenAPI e, se tiver corpo, ele é validado
+// contra o esquema antes de chegar ao handler
+func (s *Servidor) rota(padrao string, op operacao, h http.HandlerFunc) {
+	op.metodo, op.caminho, _ = strings.Cut(padrao, " ")
+	s.operacoes = append(s.operacoes, op)
+	if op.Corpo != nil {
+		h = s.validarCorpo(op, h)
+	}
+	s.mux.HandleFunc(op.metodo+" "+Prefixo+op.caminho, h)
+}
+
+// erroRequisicao indica uma requisição malformada (JSON inválido, parâmetro
+// que não é número...), respondida com 400
+type erroRequisicao struct {
+	motivo string
+}
+
+func (e erroRequisicao) Error() string { return e.motivo }
+
+type respostaErro struct {
+	Erro   string          `json:"erro"`
+	Campos validacao.Erros `json:"campos,omitempty"`
+}
+
+// statusDoErro escolhe o status HTTP a partir da classe do erro; as
+// requisições malformadas são as únicas próprias da API
+func statusDoErro(err error) int {
+	var req erroRequisicao
+	var esq erroEsquema
+	if errors.As(err, &req) || errors.As(err, &esq) {
+		return http.StatusBadRequest
+	}
+	return resposta.Status(err)
+}
+
+func escreverErro(w http.ResponseWriter, err error) {
+	status := statusDoErro(err)
+	resposta := respostaErro{Erro: err.Error()}
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		resposta.Campos = campos
+		if status != http.StatusBadRequest {
+			resposta.Erro = "dados inválidos"
+		}
+	}
+	if status == http.StatusInternalServerError {
+		// o detalhe do erro interno fica apenas no log do servidor
+		log.Printf("ERRO: %v\n", err)
+		resposta.Erro = "erro interno do servidor"
+	}
+	escreverJSON(w, status, resposta)
+}
+
+func escreverJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("ERRO: falha ao escrever a resposta: %v\n", err)
+	}
+}
+
+// criado responde 201 com o endereço do novo recurso
+func criado(w http.ResponseWriter, local string, v any) {
+	w.Header().Set("Location", Prefixo+local)
+	escreverJSON(w, http.StatusCreated, v)
+}
+
+// lerJSON decodifica o corpo da requisição em v. Campos desconhecidos são
+// rejeitados; em um PATCH, v já traz o registro atual e só os campos
+// presentes no corpo são substituídos
+func lerJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limiteCorpo))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return erroRequisicao{fmt.Sprintf("JSON inválido: %v", err)}
+	}
+	if dec.More() {
+		return erroRequisicao{"JSON inválido: conteúdo após o objeto"}
+	}
+	return nil
+}
+
+// inteiro lê um parâmetro numérico do caminho ou da query; ausente vale 0
+func inteiro(nome, valor string) (int, error) {
+	if valor == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(valor)
+	if err != nil {
+		return 0, erroRequisicao{fmt.Sprintf("parâmetro '%s' deve ser um número inteiro: '%s'", nome, valor)}
+	}
+	return n, nil
+}
+
+// lista garante que listagens vazias sejam serializadas como [] e não null
+func lista[T any](v []T) []T {
+	if v == nil {
+		return []T{}
+	}
+	return v
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"net/http"
+	"strconv"
+)
+
+func (s *Servidor) rotasAutores() {
+	s.rota("GET /autores", operacao{Resumo: "Lista os autores", Resposta: []model.Autor{}, Query: []parametro{
+		{"nome", "string", "parte do primeiro nome ou do sobrenome"},
+	}}, s.listarAutores)
+	s.rota("POST /autores", operacao{Resumo: "Cadastra um autor", Corpo: model.Autor{}, Resposta: model.Autor{}}, s.criarAutor)
+	s.rota("GET /autores/{id}", operacao{Resumo: "Busca um autor pelo ID", Resposta: model.Autor{}}, s.obterAutor)
+	s.rota("PUT /autores/{id}", operacao{Resumo: "Substitui os dados do autor", Corpo: model.Autor{}, Resposta: model.Autor{}}, s.atualizarAutor)
+	s.rota("PATCH /autores/{id}", operacao{Resumo: "Altera os campos enviados do autor", Corpo: model.Autor{}, Resposta: model.Autor{}}, s.atualizarAutor)
+	s.rota("DELETE /autores/{id}", operacao{Resumo: "Remove o autor"}, s.deletarAutor)
+}
+
+// GET /autores?nome=
+func (s *Servidor) listarAutores(w http.ResponseWriter, r *http.Request) {
+	autores, err := s.biblioteca.ListarAutores(r.Context(), r.URL.Query().Get("nome"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(autores))
+}
+
+func (s *Servidor) criarAutor(w http.ResponseWriter, r *http.Request) {
+	var a model.Autor
+	if err := lerJSON(w, r, &a); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	novo, err := s.biblioteca.CriarAutor(r.Context(), a)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/autores/"+strconv.Itoa(novo.ID), novo)
+}
+
+func (s *Servidor) obterAutor(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	a, err := s.biblioteca.ObterAutor(r.Context(), id)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, a)
+}
+
+// atualizarAutor atende PUT e PATCH; o ID é sempre o do caminho
+func (s *Servidor) atualizarAutor(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	var a model.Autor
+	if r.Method == http.MethodPatch {
+		atual, err := s.biblioteca.ObterAutor(r.Context(), id)
+		if err != nil {
+			escreverErro(w, err)
+			return
+		}
+		a = *atual
+	}
+	if err := lerJSON(w, r, &a); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	a.ID = id
+	atualizado, err := s.biblioteca.AtualizarAutor(r.Context(), a)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, atualizado)
+}
+
+func (s *Servidor) deletarAutor(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	if err := s.biblioteca.DeletarAutor(r.Context(), id); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// servirDocumento responde o documento OpenAPI, usado para gerar SDKs
+func (s *Servidor) servirDocumento(w http.ResponseWriter, r *http.Request) {
+	escreverJSON(w, http.StatusOK, s.documento)
+}
+
+// servirDocs apresenta o documento OpenAPI como uma página HTML navegável,
+// sem depender de arquivos ou scripts externos
+func (s *Servidor) servirDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := paginaDocs.Execute(w, s.visaoDocs()); err != nil {
+		log.Printf("ERRO: falha ao gerar a documentação: %v\n", err)
+	}
+}
+
+type docsGrupo struct {
+	Tag       string
+	Operacoes []docsOperacao
+}
+
+type docsOperacao struct {
+	Metodo, Classe, Caminho, Resumo, ID string
+	Parametros                          []Parametro
+	Corpo                               string
+	Respostas                           []docsResposta
+}
+
+type docsResposta struct {
+	Status, Descricao, Tipo string
+}
+
+type docsEsquema struct {
+	Nome   string
+	Campos []docsCampo
+}
+
+type docsCampo struct {
+	Nome, Tipo  string
+	Obrigatorio bool
+}
+
+type docsVisao struct {
+	Titulo, Descricao, Versao, Prefixo string
+	Grupos                             []docsGrupo
+	Esquemas                           []docsEsquema
+}
+
+// ordemMetodos define a ordem de apresentação das operações de um caminho
+var ordemMetodos = []string{"get", "post", "put", "patch", "delete"}
+
+func (s *Servidor) visaoDocs() docsVisao {
+	doc := s.documento
+	v := docsVisao{Titulo: doc.Info["title"], Descricao: doc.Info["description"], Versao: doc.Info["version"], Prefixo: Prefixo}
+
+	caminhos := make([]string, 0, len(doc.Paths))
+	for c := range doc.Paths {
+		caminhos = append(caminhos, c)
+	}
+	sort.Strings(caminhos)
+	for _, tag := range doc.Tags {
+		g := docsGrupo{Tag: tag["name"]}
+		for _, c := range caminhos {
+			for _, m := range ordemMetodos {
+				o, ok := doc.Paths[c][m]
+				if !ok || o.Tags[0] != g.Tag {
+					continue
+				}
+				op := docsOperacao{Metodo: strings.ToUpper(m), Classe: m, Caminho: c, Resumo: o.Summary, ID: o.OperationID, Parametros: o.Parameters}
+				if o.RequestBody != nil {
+					op.Corpo = tipoDocs(o.RequestBody.Content["application/json"].Schema)
+				}
+				status := make([]string, 0, len(o.Responses))
+				for st := range o.Responses {
+					status = append(status, st)
+				}
+				sort.Strings(status)
+				for _, st := range status {
+					resp := docsResposta{Status: st, Descricao: o.Responses[st].Description}
+					if conteudo, ok := o.Responses[st].Content["application/json"]; ok {
+						resp.Tipo = tipoDocs(conteudo.Schema)
+					}
+					op.Respostas = append(op.Respostas, resp)
+				}
+				g.Operacoes = append(g.Operacoes, op)
+			}
+		}
+		v.Grupos = append(v.Grupos, g)
+	}
+
+	nomes := make([]string, 0, len(s.componentes))
+	for n := range s.componentes {
+		nomes = append(nomes, n)
+	}
+	sort.Strings(nomes)
+	for _, n := range nomes {
+		e := s.componentes[n]
+		de := docsEsquema{Nome: n}
+		campos := make([]string, 0, len(e.Properties))
+		for c := range e.Properties {
+			campos = append(campos, c)
+		}
+		sort.Strings(campos)
+		for _, c := range campos {
+			de.Campos = append(de.Campos, docsCampo{Nome: c, Tipo: tipoDocs(e.Properties[c]), Obrigatorio: slices.Contains(e.Required, c)})
+		}
+		v.Esquemas = append(v.Esquemas, de)
+	}
+	return v
+}
+
+// tipoDocs resume o esquema em texto, ex.: "lista de Autor", "string (date-time)"
+func tipoDocs(e *Esquema) string {
+	if e == nil {
+		return ""
+	}
+	var t string
+	switch {
+	case e.Ref != "":
+		t = strings.TrimPrefix(e.Ref, "#/components/schemas/")
+	case e.Type == "array":
+		t = "lista de " + tipoDocs(e.Items)
+	case e.Format != "":
+		t = e.Type + " (" + e.Format + ")"
+	default:
+		t = e.Type
+	}
+	if len(e.Enum) > 0 {
+		t += ": " + strings.Join(e.Enum, ", ")
+	}
+	if e.Nullable {
+		t += ", opcional (null)"
+	}
+	return t
+}
+
+var paginaDocs = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>{{.Titulo}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #222; }
+h2 { border-bottom: 1px solid #ccc; text-transform: capitalize; }
+details { border: 1px solid #ddd; border-radius: 4px; margin: .5em 0; padding: .4em .8em; }
+summary { cursor: pointer; }
+code { background: #f4f4f4; padding: 0 .2em; }
+.metodo { display: inline-block; width: 4.5em; font-weight: bold; }
+.get { color: #1565c0; } .post { color: #2e7d32; } .put, .patch { color: #ef6c00; } .delete { color: #c62828; }
+table { border-collapse: collapse; margin: .5em 0; }
+td, th { border: 1px solid #ddd; padding: .2em .6em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Titulo}} <small>v{{.Versao}}</small></h1>
+<p>{{.Descricao}}</p>
+<p>Rotas relativas a <code>{{.Prefixo}}</code>. Documento OpenAPI 3: <a href="{{.Prefixo}}/openapi.json"><code>{{.Prefixo}}/openapi.json</code></a>.</p>
+{{range .Grupos}}
+<h2>{{.Tag}}</h2>
+{{range .Operacoes}}
+<details id="{{.ID}}">
+<summary><span class="metodo {{.Classe}}">{{.Metodo}}</span> <code>{{.Caminho}}</code> — {{.Resumo}}</summary>
+{{if .Parametros}}<table><tr><th>Parâmetro</th><th>Em</th><th>Tipo</th><th>Descrição</th></tr>
+{{range .Parametros}}<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{.Schema.Type}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Corpo}}<p>Corpo: <a href="#esquema-{{.Corpo}}">{{.Corpo}}</a></p>{{end}}
+<table><tr><th>Status</th><th>Descrição</th><th>Resposta</th></tr>
+{{range .Respostas}}<tr><td>{{.Status}}</td><td>{{.Descricao}}</td><td>{{.Tipo}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{end}}
+<h2>Esquemas</h2>
+{{range .Esquemas}}
+<h3 id="esquema-{{.Nome}}">{{.Nome}}</h3>
+<table><tr><th>Campo</th><th>Tipo</th><th>Obrigatório</th></tr>
+{{range .Campos}}<tr><td><code>{{.Nome}}</code></td><td>{{.Tipo}}</td><td>{{if .Obrigatorio}}sim{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
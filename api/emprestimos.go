@@ -0,0 +1,107 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"net/http"
+	"strconv"
+)
+
+func (s *Servidor) rotasEmprestimos() {
+	s.rota("GET /emprestimos", operacao{Resumo: "Lista os empréstimos, os mais recentes primeiro", Resposta: []model.Emprestimo{}, Query: []parametro{
+		{"cpf", "string", "CPF do usuário"},
+		{"status", "string", "A (ativo), D (devolvido) ou C (cancelado)"},
+	}}, s.listarEmprestimos)
+	s.rota("POST /emprestimos", operacao{Resumo: "Registra um empréstimo", Corpo: model.Emprestimo{}, Resposta: model.Emprestimo{}}, s.criarEmprestimo)
+	s.rota("GET /emprestimos/{id}", operacao{Resumo: "Busca um empréstimo pelo ID", Resposta: model.Emprestimo{}}, s.obterEmprestimo)
+	s.rota("PUT /emprestimos/{id}", operacao{Resumo: "Substitui os dados do empréstimo", Corpo: model.Emprestimo{}, Resposta: model.Emprestimo{}}, s.atualizarEmprestimo)
+	s.rota("PATCH /emprestimos/{id}", operacao{Resumo: "Altera os campos enviados do empréstimo (devolução: status D)", Corpo: model.Emprestimo{}, Resposta: model.Emprestimo{}}, s.atualizarEmprestimo)
+	s.rota("DELETE /emprestimos/{id}", operacao{Resumo: "Remove o empréstimo"}, s.deletarEmprestimo)
+}
+
+// GET /emprestimos?cpf=&status=
+func (s *Servidor) listarEmprestimos(w http.ResponseWriter, r *http.Request) {
+	filtro := repository.FiltroEmprestimo{
+		CPF:    r.URL.Query().Get("cpf"),
+		Status: r.URL.Query().Get("status"),
+	}
+	emprestimos, err := s.biblioteca.ListarEmprestimos(r.Context(), filtro)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(emprestimos))
+}
+
+// criarEmprestimo registra o empréstimo; sem data_emprestimo vale o momento
+// atual e sem status o empréstimo é criado como ativo ("A")
+func (s *Servidor) criarEmprestimo(w http.ResponseWriter, r *http.Request) {
+	var e model.Emprestimo
+	if err := lerJSON(w, r, &e); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	novo, err := s.biblioteca.CriarEmprestimo(r.Context(), e)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/emprestimos/"+strconv.Itoa(novo.ID), novo)
+}
+
+func (s *Servidor) obterEmprestimo(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	e, err := s.biblioteca.ObterEmprestimo(r.Context(), id)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, e)
+}
+
+// atualizarEmprestimo atende PUT e PATCH; o ID é sempre o do caminho.
+// A devolução é um PATCH com {"status": "D"}
+func (s *Servidor) atualizarEmprestimo(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	var e model.Emprestimo
+	if r.Method == http.MethodPatch {
+		atual, err := s.biblioteca.ObterEmprestimo(r.Context(), id)
+		if err != nil {
+			escreverErro(w, err)
+			return
+		}
+		e = *atual
+	}
+	if err := lerJSON(w, r, &e); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	e.ID = id
+	atualizado, err := s.biblioteca.AtualizarEmprestimo(r.Context(), e)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, atualizado)
+}
+
+func (s *Servidor) deletarEmprestimo(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	if err := s.biblioteca.DeletarEmprestimo(r.Context(), id); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
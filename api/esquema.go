@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"crud-biblioteca/validacao"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// erroEsquema indica um corpo que não confere com o esquema OpenAPI da rota
+// (tipo errado, campo desconhecido ou obrigatório ausente), respondido com 400
+type erroEsquema struct {
+	campos validacao.Erros
+}
+
+func (e erroEsquema) Error() string {
+	return "o corpo da requisição não confere com o esquema da API"
+}
+
+func (e erroEsquema) Unwrap() error { return e.campos }
+
+// validarCorpo confere o corpo da requisição com o esquema da operação antes
+// de repassá-lo ao handler
+func (s *Servidor) validarCorpo(op operacao, h http.HandlerFunc) http.HandlerFunc {
+	esquema := s.componentes.esquemaCorpo(op)
+	return func(w http.ResponseWriter, r *http.Request) {
+		corpo, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limiteCorpo))
+		if err != nil {
+			escreverErro(w, erroRequisicao{fmt.Sprintf("não foi possível ler o corpo: %v", err)})
+			return
+		}
+		dec := json.NewDecoder(bytes.NewReader(corpo))
+		dec.UseNumber()
+		var valor any
+		if err := dec.Decode(&valor); err != nil {
+			escreverErro(w, erroRequisicao{fmt.Sprintf("JSON inválido: %v", err)})
+			return
+		}
+
+		var erros validacao.Erros
+		s.componentes.validarValor(esquema, valor, "", &erros)
+		if len(erros) > 0 {
+			escreverErro(w, erroEsquema{erros})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(corpo))
+		h(w, r)
+	}
+}
+
+// validarValor confere um valor decodificado com UseNumber contra o esquema,
+// acumulando os problemas com o caminho do campo (ex.: autores[0].id)
+func (c componentes) validarValor(e *Esquema, v any, campo string, erros *validacao.Erros) {
+	e = c.resolver(e)
+	if e == nil {
+		return
+	}
+	invalido := func(msg string) {
+		nome := campo
+		if nome == "" {
+			nome = "corpo"
+		}
+		*erros = append(*erros, validacao.ErroCampo{Campo: nome, Codigo: validacao.CodigoInvalido, Mensagem: msg})
+	}
+
+	if v == nil {
+		if !e.Nullable {
+			invalido(fmt.Sprintf("deve ser %s, não null", descricaoTipo(e)))
+		}
+		return
+	}
+
+	switch e.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			invalido("deve ser um objeto JSON")
+			return
+		}
+		for _, obrig := range e.Required {
+			if _, ok := obj[obrig]; !ok {
+				*erros = append(*erros, validacao.ErroCampo{Campo: prefixar(campo, obrig), Codigo: validacao.CodigoObrigatorio, Mensagem: "campo obrigatório"})
+			}
+		}
+		chaves := make([]string, 0, len(obj))
+		for k := range obj {
+			chaves = append(chaves, k)
+		}
+		sort.Strings(chaves)
+		for _, k := range chaves {
+			p, ok := e.Properties[k]
+			if !ok {
+				if e.AdditionalProperties != nil && !*e.AdditionalProperties {
+					*erros = append(*erros, validacao.ErroCampo{Campo: prefixar(campo, k), Codigo: validacao.CodigoInvalido, Mensagem: "campo desconhecido"})
+				}
+				continue
+			}
+			c.validarValor(p, obj[k], prefixar(campo, k), erros)
+		}
+	case "array":
+		itens, ok := v.([]any)
+		if !ok {
+			invalido("deve ser uma lista")
+			return
+		}
+		for i, item := range itens {
+			c.validarValor(e.Items, item, fmt.Sprintf("%s[%d]", campo, i), erros)
+		}
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			invalido("deve ser um texto")
+			return
+		}
+		if e.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				invalido("deve ser uma data no formato RFC 3339 (ex.: 2024-03-01T00:00:00Z)")
+			}
+		}
+		if len(e.Enum) > 0 && !slices.Contains(e.Enum, s) {
+			invalido(fmt.Sprintf("valor '%s' não permitido (use %s)", s, strings.Join(e.Enum, ", ")))
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			invalido("deve ser um número inteiro")
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			invalido("deve ser um número")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			invalido("deve ser true ou false")
+		}
+	}
+}
+
+func prefixar(campo, nome string) string {
+	if campo == "" {
+		return nome
+	}
+	return campo + "." + nome
+}
+
+// descricaoTipo descreve o tipo do esquema nas mensagens e na documentação
+func descricaoTipo(e *Esquema) string {
+	switch e.Type {
+	case "object":
+		return "um objeto"
+	case "array":
+		return "uma lista"
+	case "integer":
+		return "um número inteiro"
+	case "number":
+		return "um número"
+	case "boolean":
+		return "true ou false"
+	}
+	return "um texto"
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"net/http"
+	"strconv"
+)
+
+func (s *Servidor) rotasLivros() {
+	s.rota("GET /livros", operacao{Resumo: "Lista os livros", Resposta: []model.Livro{}, Query: []parametro{
+		{"titulo", "string", "parte do título"},
+		{"categoria", "integer", "ID da categoria de assunto, incluindo as subcategorias"},
+		{"obra", "integer", "ID da obra (edições e traduções)"},
+	}}, s.listarLivros)
+	s.rota("POST /livros", operacao{Resumo: "Cadastra um livro e vincula os autores enviados", Corpo: model.Livro{}, Resposta: model.Livro{}}, s.criarLivro)
+	s.rota("GET /livros/{isbn}", operacao{Resumo: "Busca um livro pelo ISBN-10 ou ISBN-13", Resposta: model.Livro{}}, s.obterLivro)
+	s.rota("PUT /livros/{isbn}", operacao{Resumo: "Substitui os dados do livro (exceto autores e categorias)", Corpo: model.Livro{}, Resposta: model.Livro{}}, s.atualizarLivro)
+	s.rota("PATCH /livros/{isbn}", operacao{Resumo: "Altera os campos enviados do livro (exceto autores e categorias)", Corpo: model.Livro{}, Resposta: model.Livro{}}, s.atualizarLivro)
+	s.rota("DELETE /livros/{isbn}", operacao{Resumo: "Remove o livro"}, s.deletarLivro)
+
+	// relacionamento livro-autor (tabela Escreve / autores embutidos)
+	s.rota("GET /livros/{isbn}/autores", operacao{Resumo: "Lista os autores do livro", Resposta: []model.Autor{}}, s.listarAutoresDoLivro)
+	s.rota("POST /livros/{isbn}/autores", operacao{Resumo: "Vincula um autor ao livro, cadastrando-o se necessário", Corpo: model.Autor{}, Resposta: model.Autor{}}, s.vincularAutor)
+	s.rota("DELETE /livros/{isbn}/autores/{id}", operacao{Resumo: "Desvincula o autor do livro"}, s.desvincularAutor)
+}
+
+// respostaLivro evita null nas listas de autores e categorias
+func respostaLivro(l *model.Livro) *model.Livro {
+	l.Autores = lista(l.Autores)
+	l.Categorias = lista(l.Categorias)
+	return l
+}
+
+// GET /livros?titulo=&categoria=&obra=
+func (s *Servidor) listarLivros(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filtro := servico.FiltroLivro{Titulo: q.Get("titulo")}
+	var err error
+	if filtro.CategoriaID, err = inteiro("categoria", q.Get("categoria")); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	if filtro.ObraID, err = inteiro("obra", q.Get("obra")); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	livros, err := s.biblioteca.ListarLivros(r.Context(), filtro)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	for i := range livros {
+		respostaLivro(&livros[i])
+	}
+	escreverJSON(w, http.StatusOK, lista(livros))
+}
+
+// criarLivro cadastra o livro; os autores enviados no corpo são vinculados
+func (s *Servidor) criarLivro(w http.ResponseWriter, r *http.Request) {
+	var l model.Livro
+	if err := lerJSON(w, r, &l); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	novo, err := s.biblioteca.CriarLivro(r.Context(), l)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/livros/"+novo.ISBN, respostaLivro(novo))
+}
+
+// obterLivro aceita o ISBN-10 ou ISBN-13, com ou sem hífens
+func (s *Servidor) obterLivro(w http.ResponseWriter, r *http.Request) {
+	l, err := s.biblioteca.ObterLivro(r.Context(), r.PathValue("isbn"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, respostaLivro(l))
+}
+
+// atualizarLivro atende PUT e PATCH. O ISBN é sempre o do caminho; autores e
+// categorias não são alterados por aqui
+func (s *Servidor) atualizarLivro(w http.ResponseWriter, r *http.Request) {
+	var l model.Livro
+	if r.Method == http.MethodPatch {
+		atual, err := s.biblioteca.ObterLivro(r.Context(), r.PathValue("isbn"))
+		if err != nil {
+			escreverErro(w, err)
+			return
+		}
+		l = *atual
+	}
+	if err := lerJSON(w, r, &l); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	l.ISBN = r.PathValue("isbn")
+	atualizado, err := s.biblioteca.AtualizarLivro(r.Context(), l)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, respostaLivro(atualizado))
+}
+
+func (s *Servidor) deletarLivro(w http.ResponseWriter, r *http.Request) {
+	if err := s.biblioteca.DeletarLivro(r.Context(), r.PathValue("isbn")); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Servidor) listarAutoresDoLivro(w http.ResponseWriter, r *http.Request) {
+	l, err := s.biblioteca.ObterLivro(r.Context(), r.PathValue("isbn"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(l.Autores))
+}
+
+// vincularAutor relaciona o autor do corpo ao livro; se o ID ainda não
+// estiver cadastrado, o autor é criado com os nomes enviados
+func (s *Servidor) vincularAutor(w http.ResponseWriter, r *http.Request) {
+	var a model.Autor
+	if err := lerJSON(w, r, &a); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	autor, err := s.biblioteca.VincularAutor(r.Context(), r.PathValue("isbn"), a)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/livros/"+r.PathValue("isbn")+"/autores/"+strconv.Itoa(autor.ID), autor)
+}
+
+func (s *Servidor) desvincularAutor(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	if err := s.biblioteca.DesvincularAutor(r.Context(), r.PathValue("isbn"), id); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
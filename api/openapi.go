@@ -0,0 +1,320 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Esquema é um Schema Object do OpenAPI 3.0, no subconjunto usado pela API
+type Esquema struct {
+	Ref                  string              `json:"$ref,omitempty"`
+	Type                 string              `json:"type,omitempty"`
+	Format               string              `json:"format,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Nullable             bool                `json:"nullable,omitempty"`
+	Enum                 []string            `json:"enum,omitempty"`
+	Items                *Esquema            `json:"items,omitempty"`
+	Properties           map[string]*Esquema `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	AdditionalProperties *bool               `json:"additionalProperties,omitempty"`
+}
+
+// operacao descreve uma rota para o documento OpenAPI e para a validação
+// do corpo da requisição
+type operacao struct {
+	Resumo   string
+	Corpo    any // valor do tipo esperado no corpo; nil se a rota não tem corpo
+	Resposta any // valor do tipo retornado; nil responde 204 sem conteúdo
+	Query    []parametro
+
+	metodo  string
+	caminho string
+}
+
+type parametro struct {
+	Nome      string
+	Tipo      string // "string" ou "integer"
+	Descricao string
+}
+
+// obrigatorios lista, por tipo, os campos exigidos no corpo de um POST;
+// em PUT e PATCH a chave vem do caminho e nenhum campo é exigido pelo esquema
+var obrigatorios = map[reflect.Type][]string{
+	reflect.TypeOf(model.Usuario{}):    {"cpf"},
+	reflect.TypeOf(model.Livro{}):      {"isbn"},
+	reflect.TypeOf(model.Autor{}):      {"id"},
+	reflect.TypeOf(model.Emprestimo{}): {"id", "cliente_usuario_cpf"},
+}
+
+// enumeracoes restringe os valores de campos de texto, por tipo e campo JSON
+var enumeracoes = map[string][]string{
+	"Emprestimo.status": {"A", "D", "C"},
+}
+
+func init() {
+	var categorias []string
+	for _, c := range model.CategoriasUsuario {
+		categorias = append(categorias, string(c))
+	}
+	enumeracoes["Usuario.categoria"] = categorias
+}
+
+// componentes gera os esquemas a partir dos tipos Go (campos e tags JSON dos
+// structs do pacote model) e guarda os structs nomeados para referência
+type componentes map[string]*Esquema
+
+// sufixoAlteracao identifica a variante sem campos obrigatórios usada em PUT e PATCH
+const sufixoAlteracao = "Alteracao"
+
+func (c componentes) esquema(t reflect.Type, alteracao bool) *Esquema {
+	if t.Kind() == reflect.Pointer {
+		e := *c.esquema(t.Elem(), alteracao)
+		if e.Ref != "" {
+			return &Esquema{Ref: e.Ref}
+		}
+		e.Nullable = true
+		return &e
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Esquema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Esquema{Type: "string"}
+	case reflect.Bool:
+		return &Esquema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Esquema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Esquema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Esquema{Type: "array", Items: c.esquema(t.Elem(), alteracao)}
+	case reflect.Struct:
+		return c.objeto(t, alteracao)
+	}
+	return &Esquema{}
+}
+
+// objeto registra o struct em components/schemas e retorna a referência.
+// A variante de alteração só difere quando o tipo tem campos obrigatórios
+func (c componentes) objeto(t reflect.Type, alteracao bool) *Esquema {
+	nome := nomeEsquema(t)
+	obrig := obrigatorios[t]
+	if alteracao && len(obrig) > 0 {
+		nome += sufixoAlteracao
+		obrig = nil
+	}
+	ref := &Esquema{Ref: "#/components/schemas/" + nome}
+	if _, ok := c[nome]; ok {
+		return ref
+	}
+
+	fechado := false
+	e := &Esquema{Type: "object", Properties: map[string]*Esquema{}, Required: obrig, AdditionalProperties: &fechado}
+	c[nome] = e // registrado antes dos campos para suportar tipos recursivos
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		campo := nomeJSON(f)
+		if campo == "" {
+			continue
+		}
+		p := c.esquema(f.Type, alteracao)
+		if valores, ok := enumeracoes[t.Name()+"."+campo]; ok {
+			p.Enum = valores
+		}
+		e.Properties[campo] = p
+	}
+	return ref
+}
+
+// nomeJSON retorna o nome do campo no JSON, ou "" se ele não é serializado
+func nomeJSON(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return tag
+}
+
+// nomeEsquema usa o nome do tipo com a inicial maiúscula (respostaErro -> RespostaErro)
+func nomeEsquema(t reflect.Type) string {
+	return strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+}
+
+// resolver segue a referência $ref, se houver
+func (c componentes) resolver(e *Esquema) *Esquema {
+	for e != nil && e.Ref != "" {
+		e = c[strings.TrimPrefix(e.Ref, "#/components/schemas/")]
+	}
+	return e
+}
+
+// esquemaCorpo é o esquema do corpo da operação: a variante de alteração
+// em PUT e PATCH
+func (c componentes) esquemaCorpo(op operacao) *Esquema {
+	return c.esquema(reflect.TypeOf(op.Corpo), op.metodo != http.MethodPost)
+}
+
+// Documento OpenAPI 3.0 da API
+type Documento struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       map[string]string               `json:"info"`
+	Servers    []map[string]string             `json:"servers"`
+	Tags       []map[string]string             `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Operacao `json:"paths"`
+	Components map[string]componentes          `json:"components"`
+}
+
+// Operacao é um Operation Object do OpenAPI
+type Operacao struct {
+	Tags        []string             `json:"tags"`
+	Summary     string               `json:"summary"`
+	OperationID string               `json:"operationId"`
+	Parameters  []Parametro          `json:"parameters,omitempty"`
+	RequestBody *Corpo               `json:"requestBody,omitempty"`
+	Responses   map[string]*Resposta `json:"responses"`
+}
+
+type Parametro struct {
+	Name        string   `json:"name"`
+	In          string   `json:"in"`
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Schema      *Esquema `json:"schema"`
+}
+
+type Corpo struct {
+	Required bool                 `json:"required"`
+	Content  map[string]*Conteudo `json:"content"`
+}
+
+type Resposta struct {
+	Description string               `json:"description"`
+	Content     map[string]*Conteudo `json:"content,omitempty"`
+}
+
+type Conteudo struct {
+	Schema *Esquema `json:"schema"`
+}
+
+func jsonDe(e *Esquema) map[string]*Conteudo {
+	return map[string]*Conteudo{"application/json": {Schema: e}}
+}
+
+// gerarDocumento monta o documento OpenAPI a partir das rotas registradas
+func (s *Servidor) gerarDocumento() *Documento {
+	erro := s.componentes.esquema(reflect.TypeOf(respostaErro{}), false)
+	doc := &Documento{
+		OpenAPI: "3.0.3",
+		Info: map[string]string{
+			"title":       "API da Biblioteca",
+			"version":     "1.0.0",
+			"description": "Usuários, livros, autores e empréstimos da biblioteca, com as mesmas regras do menu interativo.",
+		},
+		Servers:    []map[string]string{{"url": Prefixo}},
+		Paths:      map[string]map[string]*Operacao{},
+		Components: map[string]componentes{"schemas": s.componentes},
+	}
+
+	tags := map[string]bool{}
+	for _, op := range s.operacoes {
+		tag := strings.Split(strings.TrimPrefix(op.caminho, "/"), "/")[0]
+		tags[tag] = true
+		o := &Operacao{
+			Tags:        []string{tag},
+			Summary:     op.Resumo,
+			OperationID: idOperacao(op),
+			Responses:   map[string]*Resposta{},
+		}
+
+		for _, nome := range parametrosCaminho(op.caminho) {
+			o.Parameters = append(o.Parameters, Parametro{Name: nome, In: "path", Required: true, Schema: &Esquema{Type: tipoParametro(nome)}})
+		}
+		for _, q := range op.Query {
+			o.Parameters = append(o.Parameters, Parametro{Name: q.Nome, In: "query", Description: q.Descricao, Schema: &Esquema{Type: q.Tipo}})
+		}
+		for _, p := range o.Parameters {
+			if p.Schema.Type == "integer" {
+				o.Responses["400"] = &Resposta{Description: "parâmetro numérico inválido", Content: jsonDe(erro)}
+			}
+		}
+
+		if op.Corpo != nil {
+			o.RequestBody = &Corpo{Required: true, Content: jsonDe(s.componentes.esquemaCorpo(op))}
+			o.Responses["400"] = &Resposta{Description: "JSON malformado, fora do esquema ou parâmetro inválido", Content: jsonDe(erro)}
+			o.Responses["422"] = &Resposta{Description: "dados inválidos ou operação recusada pelas regras da biblioteca", Content: jsonDe(erro)}
+		}
+		switch {
+		case op.Resposta == nil:
+			o.Responses["204"] = &Resposta{Description: "removido"}
+		case op.metodo == http.MethodPost:
+			o.Responses["201"] = &Resposta{Description: "criado", Content: jsonDe(s.componentes.esquema(reflect.TypeOf(op.Resposta), false))}
+		default:
+			o.Responses["200"] = &Resposta{Description: "sucesso", Content: jsonDe(s.componentes.esquema(reflect.TypeOf(op.Resposta), false))}
+		}
+		if len(parametrosCaminho(op.caminho)) > 0 {
+			o.Responses["404"] = &Resposta{Description: "registro não encontrado", Content: jsonDe(erro)}
+		}
+		if op.metodo != http.MethodGet {
+			o.Responses["409"] = &Resposta{Description: "registro duplicado ou referência inválida", Content: jsonDe(erro)}
+		}
+
+		if doc.Paths[op.caminho] == nil {
+			doc.Paths[op.caminho] = map[string]*Operacao{}
+		}
+		doc.Paths[op.caminho][strings.ToLower(op.metodo)] = o
+	}
+
+	nomes := make([]string, 0, len(tags))
+	for t := range tags {
+		nomes = append(nomes, t)
+	}
+	sort.Strings(nomes)
+	for _, t := range nomes {
+		doc.Tags = append(doc.Tags, map[string]string{"name": t})
+	}
+	return doc
+}
+
+// parametrosCaminho extrai os nomes dos curingas do padrão ("/livros/{isbn}" -> isbn)
+func parametrosCaminho(caminho string) []string {
+	var nomes []string
+	for _, seg := range strings.Split(caminho, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			nomes = append(nomes, seg[1:len(seg)-1])
+		}
+	}
+	return nomes
+}
+
+// tipoParametro: os IDs são numéricos; CPF e ISBN são texto
+func tipoParametro(nome string) string {
+	if nome == "id" {
+		return "integer"
+	}
+	return "string"
+}
+
+// idOperacao gera um operationId estável a partir do método e do caminho,
+// ex.: GET /livros/{isbn}/autores -> getLivrosIsbnAutores
+func idOperacao(op operacao) string {
+	id := strings.ToLower(op.metodo)
+	for _, seg := range strings.Split(op.caminho, "/") {
+		seg = strings.Trim(seg, "{}")
+		if seg != "" {
+			id += strings.ToUpper(seg[:1]) + seg[1:]
+		}
+	}
+	return id
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"net/http"
+)
+
+func (s *Servidor) rotasUsuarios() {
+	s.rota("GET /usuarios", operacao{Resumo: "Lista os usuários", Resposta: []model.Usuario{}, Query: []parametro{
+		{"nome", "string", "parte do primeiro nome ou do sobrenome"},
+		{"categoria", "string", "categoria do usuário (graduacao, pos, docente, tecnico, externo)"},
+	}}, s.listarUsuarios)
+	s.rota("POST /usuarios", operacao{Resumo: "Cadastra um usuário", Corpo: model.Usuario{}, Resposta: model.Usuario{}}, s.criarUsuario)
+	s.rota("GET /usuarios/{cpf}", operacao{Resumo: "Busca um usuário pelo CPF", Resposta: model.Usuario{}}, s.obterUsuario)
+	s.rota("PUT /usuarios/{cpf}", operacao{Resumo: "Substitui os dados do usuário", Corpo: model.Usuario{}, Resposta: model.Usuario{}}, s.atualizarUsuario)
+	s.rota("PATCH /usuarios/{cpf}", operacao{Resumo: "Altera os campos enviados do usuário", Corpo: model.Usuario{}, Resposta: model.Usuario{}}, s.atualizarUsuario)
+	s.rota("DELETE /usuarios/{cpf}", operacao{Resumo: "Remove o usuário (responsáveis por menores não podem ser removidos)"}, s.deletarUsuario)
+	s.rota("GET /usuarios/{cpf}/dependentes", operacao{Resumo: "Lista os menores de idade pelos quais o usuário responde", Resposta: []model.Usuario{}}, s.listarDependentes)
+}
+
+// GET /usuarios?nome=&categoria=
+func (s *Servidor) listarUsuarios(w http.ResponseWriter, r *http.Request) {
+	filtro := repository.FiltroUsuario{
+		Nome:      r.URL.Query().Get("nome"),
+		Categoria: model.CategoriaUsuario(r.URL.Query().Get("categoria")),
+	}
+	usuarios, err := s.biblioteca.ListarUsuarios(r.Context(), filtro)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(usuarios))
+}
+
+func (s *Servidor) criarUsuario(w http.ResponseWriter, r *http.Request) {
+	var u model.Usuario
+	if err := lerJSON(w, r, &u); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	novo, err := s.biblioteca.CriarUsuario(r.Context(), u)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/usuarios/"+novo.CPF, novo)
+}
+
+func (s *Servidor) obterUsuario(w http.ResponseWriter, r *http.Request) {
+	u, err := s.biblioteca.ObterUsuario(r.Context(), r.PathValue("cpf"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, u)
+}
+
+// atualizarUsuario atende PUT (substitui o registro) e PATCH (altera só os
+// campos enviados). O CPF é sempre o do caminho
+func (s *Servidor) atualizarUsuario(w http.ResponseWriter, r *http.Request) {
+	var u model.Usuario
+	if r.Method == http.MethodPatch {
+		atual, err := s.biblioteca.ObterUsuario(r.Context(), r.PathValue("cpf"))
+		if err != nil {
+			escreverErro(w, err)
+			return
+		}
+		u = *atual
+	}
+	if err := lerJSON(w, r, &u); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	u.CPF = r.PathValue("cpf")
+	atualizado, err := s.biblioteca.AtualizarUsuario(r.Context(), u)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, atualizado)
+}
+
+func (s *Servidor) deletarUsuario(w http.ResponseWriter, r *http.Request) {
+	if err := s.biblioteca.DeletarUsuario(r.Context(), r.PathValue("cpf")); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Servidor) listarDependentes(w http.ResponseWriter, r *http.Request) {
+	dependentes, err := s.biblioteca.Dependentes(r.Context(), r.PathValue("cpf"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(dependentes))
+}
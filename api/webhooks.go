@@ -0,0 +1,136 @@
+package api
+
+import (
+	"crud-biblioteca/model"
+	"net/http"
+	"strconv"
+)
+
+func (s *Servidor) rotasWebhooks() {
+	s.rota("GET /webhooks", operacao{Resumo: "Lista as assinaturas de webhook, sem os segredos", Resposta: []model.Webhook{}}, s.listarWebhooks)
+	s.rota("POST /webhooks", operacao{Resumo: "Cadastra uma assinatura; sem segredo, um é gerado e mostrado só nesta resposta", Corpo: model.Webhook{}, Resposta: model.Webhook{}}, s.criarWebhook)
+	s.rota("GET /webhooks/{id}", operacao{Resumo: "Busca uma assinatura pelo ID", Resposta: model.Webhook{}}, s.obterWebhook)
+	s.rota("PUT /webhooks/{id}", operacao{Resumo: "Substitui a assinatura; sem segredo, o atual é mantido", Corpo: model.Webhook{}, Resposta: model.Webhook{}}, s.atualizarWebhook)
+	s.rota("PATCH /webhooks/{id}", operacao{Resumo: "Altera os campos enviados da assinatura (desativar: ativo false)", Corpo: model.Webhook{}, Resposta: model.Webhook{}}, s.atualizarWebhook)
+	s.rota("DELETE /webhooks/{id}", operacao{Resumo: "Remove a assinatura e o seu registro de entregas"}, s.deletarWebhook)
+	s.rota("GET /webhooks/{id}/entregas", operacao{Resumo: "Registro de entregas da assinatura, das mais recentes", Resposta: []model.EntregaWebhook{}, Query: []parametro{
+		{"limite", "integer", "quantidade máxima de entregas (padrão 50)"},
+	}}, s.listarEntregas)
+	s.rota("POST /webhooks/{id}/teste", operacao{Resumo: "Enfileira um evento de teste para a assinatura", Resposta: model.EntregaWebhook{}}, s.testarWebhook)
+}
+
+func (s *Servidor) listarWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := s.biblioteca.ListarWebhooks(r.Context())
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(webhooks))
+}
+
+// criarWebhook cadastra a assinatura; sem o campo ativo ela nasce ativa
+func (s *Servidor) criarWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook := model.Webhook{Ativo: true}
+	if err := lerJSON(w, r, &webhook); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	novo, err := s.biblioteca.CriarWebhook(r.Context(), webhook)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	criado(w, "/webhooks/"+strconv.Itoa(novo.ID), novo)
+}
+
+func (s *Servidor) obterWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	webhook, err := s.biblioteca.ObterWebhook(r.Context(), id)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, webhook)
+}
+
+// atualizarWebhook atende PUT e PATCH; o ID é sempre o do caminho
+func (s *Servidor) atualizarWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	var webhook model.Webhook
+	if r.Method == http.MethodPatch {
+		atual, err := s.biblioteca.ObterWebhook(r.Context(), id)
+		if err != nil {
+			escreverErro(w, err)
+			return
+		}
+		webhook = *atual
+	}
+	if err := lerJSON(w, r, &webhook); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	webhook.ID = id
+	atualizado, err := s.biblioteca.AtualizarWebhook(r.Context(), webhook)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, atualizado)
+}
+
+func (s *Servidor) deletarWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	if err := s.biblioteca.DeletarWebhook(r.Context(), id); err != nil {
+		escreverErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /webhooks/{id}/entregas?limite=
+func (s *Servidor) listarEntregas(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	limite, err := inteiro("limite", r.URL.Query().Get("limite"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	entregas, err := s.biblioteca.ListarEntregas(r.Context(), id, limite)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusOK, lista(entregas))
+}
+
+// testarWebhook enfileira o evento de teste; a entrega é feita pelo servidor
+// logo em seguida e o resultado aparece em /webhooks/{id}/entregas
+func (s *Servidor) testarWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := inteiro("id", r.PathValue("id"))
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	entrega, err := s.biblioteca.TestarWebhook(r.Context(), id)
+	if err != nil {
+		escreverErro(w, err)
+		return
+	}
+	escreverJSON(w, http.StatusAccepted, entrega)
+}
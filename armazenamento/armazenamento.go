@@ -0,0 +1,174 @@
+package armazenamento
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tamanho máximo padrão de um arquivo quando RECURSOS_TAMANHO_MAX não é definido (50 MiB)
+const TamanhoMaximoPadrao int64 = 50 << 20
+
+// tipos de arquivo aceitos como recurso digital
+var TiposPermitidos = map[string]bool{
+	"application/pdf":      true,
+	"application/epub+zip": true,
+	"application/zip":      true,
+	"text/plain":           true,
+	"image/png":            true,
+	"image/jpeg":           true,
+}
+
+var ErrTamanhoExcedido = errors.New("arquivo excede o tamanho máximo permitido")
+
+// ArquivoSalvo descreve um arquivo gravado no diretório de armazenamento
+type ArquivoSalvo struct {
+	Caminho string // relativo ao diretório raiz do armazenamento
+	MIME    string
+	Tamanho int64
+	SHA256  string
+}
+
+// Local guarda os arquivos dos recursos digitais em um diretório do sistema de arquivos
+type Local struct {
+	Dir           string
+	TamanhoMaximo int64
+}
+
+func NewLocal(dir string, tamanhoMaximo int64) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("não foi possível criar o diretório de armazenamento: %w", err)
+	}
+	return &Local{Dir: dir, TamanhoMaximo: tamanhoMaximo}, nil
+}
+
+// NewLocalFromEnv configura o armazenamento a partir das variáveis
+// RECURSOS_DIR (padrão "recursos") e RECURSOS_TAMANHO_MAX (em bytes)
+func NewLocalFromEnv() (*Local, error) {
+	dir := os.Getenv("RECURSOS_DIR")
+	if dir == "" {
+		dir = "recursos"
+	}
+	tamanhoMaximo := TamanhoMaximoPadrao
+	if v := os.Getenv("RECURSOS_TAMANHO_MAX"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("valor inválido para RECURSOS_TAMANHO_MAX: %q", v)
+		}
+		tamanhoMaximo = n
+	}
+	return NewLocal(dir, tamanhoMaximo)
+}
+
+// Salvar grava o conteúdo em <Dir>/<prefixo>/<sha256><extensão>, calculando o
+// checksum e detectando o tipo MIME durante a cópia. Arquivos maiores que
+// TamanhoMaximo ou de tipos não permitidos são rejeitados.
+func (a *Local) Salvar(conteudo io.Reader, nomeOriginal, prefixo string) (*ArquivoSalvo, error) {
+	prefixo = filepath.Base(filepath.Clean("/" + prefixo))
+	destinoDir := filepath.Join(a.Dir, prefixo)
+	if err := os.MkdirAll(destinoDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(destinoDir, ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name()) // no sucesso o arquivo já foi renomeado
+
+	// os primeiros 512 bytes bastam para http.DetectContentType
+	cabecalho := make([]byte, 512)
+	n, err := io.ReadFull(conteudo, cabecalho)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		tmp.Close()
+		return nil, err
+	}
+	cabecalho = cabecalho[:n]
+	tipo := detectarMIME(cabecalho, nomeOriginal)
+	if !TiposPermitidos[tipo] {
+		tmp.Close()
+		return nil, fmt.Errorf("tipo de arquivo não permitido: %s", tipo)
+	}
+
+	hash := sha256.New()
+	origem := io.MultiReader(bytes.NewReader(cabecalho), conteudo)
+	// lê um byte além do limite para detectar arquivos grandes demais
+	tamanho, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(origem, a.TamanhoMaximo+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tamanho > a.TamanhoMaximo {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrTamanhoExcedido, a.TamanhoMaximo)
+	}
+
+	soma := hex.EncodeToString(hash.Sum(nil))
+	relativo := filepath.Join(prefixo, soma+strings.ToLower(filepath.Ext(nomeOriginal)))
+	if err := os.Rename(tmp.Name(), filepath.Join(a.Dir, relativo)); err != nil {
+		return nil, err
+	}
+	return &ArquivoSalvo{Caminho: relativo, MIME: tipo, Tamanho: tamanho, SHA256: soma}, nil
+}
+
+// Abrir retorna o arquivo armazenado em caminho (relativo a Dir)
+func (a *Local) Abrir(caminho string) (*os.File, error) {
+	return os.Open(a.caminhoAbsoluto(caminho))
+}
+
+// Copiar escreve o arquivo armazenado em destino e confere o checksum esperado
+func (a *Local) Copiar(caminho, sha256Esperado string, destino io.Writer) error {
+	f, err := a.Abrir(caminho)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(destino, hash), f); err != nil {
+		return err
+	}
+	if soma := hex.EncodeToString(hash.Sum(nil)); soma != sha256Esperado {
+		return fmt.Errorf("checksum divergente: esperado %s, obtido %s", sha256Esperado, soma)
+	}
+	return nil
+}
+
+func (a *Local) Remover(caminho string) error {
+	err := os.Remove(a.caminhoAbsoluto(caminho))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// caminhoAbsoluto impede que caminhos com ".." saiam do diretório de armazenamento
+func (a *Local) caminhoAbsoluto(caminho string) string {
+	return filepath.Join(a.Dir, filepath.Clean("/"+caminho))
+}
+
+// detectarMIME usa o conteúdo do arquivo e recorre à extensão quando a
+// detecção por conteúdo é genérica (ex.: EPUB é identificado como ZIP)
+func detectarMIME(cabecalho []byte, nome string) string {
+	tipo, _, _ := mime.ParseMediaType(http.DetectContentType(cabecalho))
+	ext := strings.ToLower(filepath.Ext(nome))
+	if ext == ".epub" && (tipo == "application/zip" || tipo == "application/octet-stream") {
+		return "application/epub+zip"
+	}
+	if tipo == "application/octet-stream" && ext != "" {
+		if porExtensao, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+			return porExtensao
+		}
+	}
+	return tipo
+}
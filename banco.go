@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/database"
+	"crud-biblioteca/repository"
+	mongoRepo "crud-biblioteca/repository/mongo"
+	postgresRepo "crud-biblioteca/repository/postgres"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// bancos aceitos pela flag -banco
+const (
+	bancoPostgres = "postgres"
+	bancoMongo    = "mongo"
+)
+
+// conectar abre o banco escolhido e monta os repositórios correspondentes;
+// a função retornada encerra a conexão
+func conectar(ctx context.Context, banco string) (repository.Repositorios, func(), error) {
+	switch banco {
+	case bancoPostgres:
+		log.Println("Conectando ao PostgreSQL...")
+		pool, err := database.ConnectPostgres()
+		if err != nil {
+			return repository.Repositorios{}, nil, err
+		}
+		repos := repositoriosPostgres(pool)
+		repos.Avisos = postgresRepo.NewAvisos(pool)
+		repos.Transacao = func(ctx context.Context, f func(ctx context.Context, repos repository.Repositorios) error) error {
+			return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+				return f(ctx, repositoriosPostgres(tx))
+			})
+		}
+		return repos, pool.Close, nil
+	case bancoMongo:
+		log.Println("Conectando ao MongoDB...")
+		mongoClient, err := database.ConnectMongoDB()
+		if err != nil {
+			return repository.Repositorios{}, nil, err
+		}
+		db := mongoClient.Database("bibliotecaDB")
+		repos := repositoriosMongo(db)
+		// o MongoDB só oferece transações em replica sets; em um servidor
+		// isolado as gravações e os seus eventos são feitos sem transação
+		if !replicaSet(ctx, mongoClient) {
+			log.Println("AVISO: o MongoDB não é um replica set; as alterações e os eventos do outbox serão gravados sem transação.")
+			return repos, func() { mongoClient.Disconnect(ctx) }, nil
+		}
+		repos.Avisos = mongoRepo.NewAvisos(db)
+		// as operações feitas com o contexto da sessão entram na transação
+		repos.Transacao = func(ctx context.Context, f func(ctx context.Context, repos repository.Repositorios) error) error {
+			sessao, err := mongoClient.StartSession()
+			if err != nil {
+				return err
+			}
+			defer sessao.EndSession(ctx)
+			_, err = sessao.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+				return nil, f(sc, repositoriosMongo(db))
+			})
+			return err
+		}
+		return repos, func() { mongoClient.Disconnect(ctx) }, nil
+	}
+	return repository.Repositorios{}, nil, fmt.Errorf("banco de dados desconhecido: '%s' (use %s ou %s)", banco, bancoPostgres, bancoMongo)
+}
+
+// replicaSet informa se o servidor aceita transações: membros de replica set
+// e roteadores de cluster (mongos)
+func replicaSet(ctx context.Context, client *mongo.Client) bool {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	return err == nil && (hello.SetName != "" || hello.Msg == "isdbgrid")
+}
+
+// repositoriosPostgres monta os repositórios sobre o pool ou sobre uma transação
+func repositoriosPostgres(db postgresRepo.DBTX) repository.Repositorios {
+	return repository.Repositorios{
+		Usuarios:         postgresRepo.NewUsuarioRepository(db),
+		Livros:           postgresRepo.NewLivroRepository(db),
+		Autores:          postgresRepo.NewAutorRepository(db),
+		Emprestimos:      postgresRepo.NewEmprestimoRepository(db),
+		Categorias:       postgresRepo.NewCategoriaRepository(db),
+		RecursosDigitais: postgresRepo.NewRecursoDigitalRepository(db),
+		Obras:            postgresRepo.NewObraRepository(db),
+		Reservas:         postgresRepo.NewReservaRepository(db),
+		Periodicos:       postgresRepo.NewPeriodicoRepository(db),
+		Fasciculos:       postgresRepo.NewFasciculoRepository(db),
+		Editoras:         postgresRepo.NewEditoraRepository(db),
+		Webhooks:         postgresRepo.NewWebhookRepository(db),
+		Outbox:           postgresRepo.NewOutboxRepository(db),
+	}
+}
+
+func repositoriosMongo(db *mongo.Database) repository.Repositorios {
+	return repository.Repositorios{
+		Usuarios:         mongoRepo.NewUsuarioRepository(db),
+		Livros:           mongoRepo.NewLivroRepository(db),
+		Autores:          mongoRepo.NewAutorRepository(db),
+		Emprestimos:      mongoRepo.NewEmprestimoRepository(db),
+		Categorias:       mongoRepo.NewCategoriaRepository(db),
+		RecursosDigitais: mongoRepo.NewRecursoDigitalRepository(db),
+		Obras:            mongoRepo.NewObraRepository(db),
+		Reservas:         mongoRepo.NewReservaRepository(db),
+		Periodicos:       mongoRepo.NewPeriodicoRepository(db),
+		Fasciculos:       mongoRepo.NewFasciculoRepository(db),
+		Editoras:         mongoRepo.NewEditoraRepository(db),
+		Webhooks:         mongoRepo.NewWebhookRepository(db),
+		Outbox:           mongoRepo.NewOutboxRepository(db),
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// códigos de saída dos subcomandos, para uso em scripts
+const (
+	saidaOK            = 0
+	saidaErro          = 1 // erro inesperado ou falha de conexão
+	saidaUso           = 2 // comando, flag ou argumento inválido
+	saidaNaoEncontrado = 3
+	saidaInvalido      = 4 // dados recusados pela validação
+	saidaConflito      = 5 // registro duplicado ou referência a registro inexistente
+	saidaRegra         = 6 // operação recusada pelas regras da biblioteca
+)
+
+// formatos aceitos por --output
+const (
+	formatoTabela = "table"
+	formatoJSON   = "json"
+)
+
+// executor realiza o comando já com o banco conectado e retorna o que deve ser impresso
+type executor func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error)
+
+// comando é uma ação de linha de comando, como "usuario get <cpf>". preparar
+// registra as flags próprias do comando e devolve o executor que as lê
+type comando struct {
+	entidade string
+	acao     string
+	args     []string // nomes dos argumentos posicionais, para a ajuda
+	resumo   string
+	preparar func(fs *flag.FlagSet) executor
+}
+
+func todosComandos() []comando {
+	return slices.Concat(comandosUsuario(), comandosLivro(), comandosAutor(), comandosEmprestimo(), comandosWebhook(), comandosEvento(), comandosLote())
+}
+
+// erroUso indica uma linha de comando malformada
+type erroUso struct {
+	motivo string
+}
+
+func (e erroUso) Error() string { return e.motivo }
+
+func usoInvalido(format string, args ...any) error {
+	return erroUso{fmt.Sprintf(format, args...)}
+}
+
+// executarComando roda um subcomando sem o menu interativo e retorna o código
+// de saída do processo. banco é o valor da flag global -banco, que pode ser
+// trocado por --backend no próprio subcomando
+func executarComando(ctx context.Context, banco string, argv []string) int {
+	comandos := todosComandos()
+	if argv[0] == "ajuda" || argv[0] == "help" {
+		imprimirAjuda(os.Stdout, comandos)
+		return saidaOK
+	}
+	c, resto, ok := encontrarComando(comandos, argv)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ERRO: comando desconhecido: %s\n\n", strings.Join(argv[:min(len(argv), 2)], " "))
+		imprimirAjuda(os.Stderr, comandos)
+		return saidaUso
+	}
+
+	fs := flag.NewFlagSet(c.nome(), flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	backend := fs.String("backend", banco, "banco de dados: postgres ou mongo")
+	formato := fs.String("output", formatoTabela, "formato da saída: table ou json")
+	exec := c.preparar(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Uso: biblioteca %s\n%s.\n\nFlags:\n", c.uso(), c.resumo)
+		fs.PrintDefaults()
+	}
+
+	args, err := analisarFlags(fs, resto)
+	if errors.Is(err, flag.ErrHelp) {
+		return saidaOK
+	}
+	if err != nil {
+		// o pacote flag já explicou o problema
+		return saidaUso
+	}
+	if len(args) != len(c.args) {
+		fmt.Fprintf(os.Stderr, "ERRO: uso: biblioteca %s\n", c.uso())
+		return saidaUso
+	}
+	if *formato != formatoTabela && *formato != formatoJSON {
+		fmt.Fprintf(os.Stderr, "ERRO: formato de saída desconhecido: '%s' (use %s ou %s)\n", *formato, formatoTabela, formatoJSON)
+		return saidaUso
+	}
+	if *backend != bancoPostgres && *backend != bancoMongo {
+		fmt.Fprintf(os.Stderr, "ERRO: informe o banco de dados com --backend %s ou --backend %s\n", bancoPostgres, bancoMongo)
+		return saidaUso
+	}
+
+	// as mensagens de conexão iriam para a saída de erro; scripts só querem o resultado
+	log.SetOutput(io.Discard)
+	repos, fechar, err := conectar(ctx, *backend)
+	if err != nil {
+		return escreverErroComando(*formato, err)
+	}
+	resultado, err := exec(ctx, servico.New(repos), args)
+	fechar()
+	if err != nil {
+		return escreverErroComando(*formato, err)
+	}
+	if err := imprimirResultado(os.Stdout, *formato, resultado); err != nil {
+		fmt.Fprintf(os.Stderr, "ERRO: falha ao escrever o resultado: %v\n", err)
+		return saidaErro
+	}
+	// resultados parciais, como o relatório de um lote, definem o próprio código
+	if r, ok := resultado.(interface{ codigoSaida() int }); ok {
+		return r.codigoSaida()
+	}
+	return saidaOK
+}
+
+// encontrarComando identifica o comando pela entidade e pela ação e retorna
+// os argumentos restantes. Comandos sem ação são chamados só pela entidade
+func encontrarComando(comandos []comando, argv []string) (comando, []string, bool) {
+	for _, c := range comandos {
+		if c.entidade != argv[0] {
+			continue
+		}
+		if c.acao == "" {
+			return c, argv[1:], true
+		}
+		if len(argv) > 1 && c.acao == argv[1] {
+			return c, argv[2:], true
+		}
+	}
+	return comando{}, nil, false
+}
+
+func (c comando) nome() string {
+	if c.acao == "" {
+		return c.entidade
+	}
+	return c.entidade + " " + c.acao
+}
+
+func (c comando) uso() string {
+	uso := c.nome()
+	for _, a := range c.args {
+		uso += " <" + a + ">"
+	}
+	return uso + " [flags]"
+}
+
+func imprimirAjuda(w io.Writer, comandos []comando) {
+	fmt.Fprintln(w, "Uso: biblioteca [-banco postgres|mongo] <entidade> <ação> [argumentos] [flags]")
+	fmt.Fprintln(w, "Sem entidade, abre o menu interativo. Flags comuns: --backend, --output table|json.")
+	fmt.Fprintln(w, "\nComandos:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range comandos {
+		fmt.Fprintf(tw, "  %s\t%s\n", strings.TrimSuffix(c.uso(), " [flags]"), c.resumo)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nUse 'biblioteca <entidade> <ação> -h' para ver as flags de cada comando.")
+}
+
+// analisarFlags aceita flags antes, entre e depois dos argumentos posicionais
+// (o pacote flag para no primeiro argumento que não é flag)
+func analisarFlags(fs *flag.FlagSet, argv []string) ([]string, error) {
+	var args []string
+	for {
+		if err := fs.Parse(argv); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return args, nil
+		}
+		args = append(args, fs.Arg(0))
+		argv = fs.Args()[1:]
+	}
+}
+
+// codigoSaida escolhe o código de saída a partir da classe do erro, na mesma
+// linha dos status HTTP da API
+func codigoSaida(err error) int {
+	var uso erroUso
+	if errors.As(err, &uso) {
+		return saidaUso
+	}
+	if _, ok := validacao.ErrosDeCampo(err); ok {
+		return saidaInvalido
+	}
+	switch {
+	case errors.Is(err, repository.ErrNaoEncontrado):
+		return saidaNaoEncontrado
+	case errors.Is(err, repository.ErrDuplicado), errors.Is(err, repository.ErrReferencia):
+		return saidaConflito
+	case errors.Is(err, repository.ErrInvalido):
+		return saidaInvalido
+	case errors.Is(err, servico.ErrRegra):
+		return saidaRegra
+	}
+	return saidaErro
+}
+
+// escreverErroComando descreve o erro na saída de erro, em JSON quando for esse
+// o formato pedido, e retorna o código de saída correspondente
+func escreverErroComando(formato string, err error) int {
+	campos, temCampos := validacao.ErrosDeCampo(err)
+	if formato == formatoJSON {
+		resposta := struct {
+			Erro   string          `json:"erro"`
+			Campos validacao.Erros `json:"campos,omitempty"`
+		}{Erro: err.Error(), Campos: campos}
+		if temCampos {
+			resposta.Erro = "dados inválidos"
+		}
+		json.NewEncoder(os.Stderr).Encode(resposta)
+		return codigoSaida(err)
+	}
+	if !temCampos {
+		fmt.Fprintf(os.Stderr, "ERRO: %v\n", err)
+		return codigoSaida(err)
+	}
+	fmt.Fprintf(os.Stderr, "ERRO: %d problema(s) encontrado(s):\n", len(campos))
+	for _, e := range campos {
+		fmt.Fprintf(os.Stderr, "  - %s: %s\n", e.Campo, e.Mensagem)
+	}
+	return codigoSaida(err)
+}
+
+// remocao é o resultado dos comandos delete
+type remocao struct {
+	Entidade string `json:"-"`
+	Removido string `json:"removido"`
+}
+
+// senhaDefinida é o resultado de "usuario senha"
+type senhaDefinida struct {
+	CPF string `json:"senha_definida"`
+}
+
+func imprimirResultado(w io.Writer, formato string, v any) error {
+	if formato == formatoJSON {
+		// listagens vazias saem como [] e não null
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+			v = []any{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	if r, ok := v.(remocao); ok {
+		_, err := fmt.Fprintf(w, "SUCESSO: %s %s removido.\n", r.Entidade, r.Removido)
+		return err
+	}
+	if s, ok := v.(senhaDefinida); ok {
+		_, err := fmt.Fprintf(w, "SUCESSO: senha do portal definida para o CPF %s.\n", s.CPF)
+		return err
+	}
+	if p, ok := v.(eventosPublicados); ok {
+		_, err := fmt.Fprintf(w, "SUCESSO: %d evento(s) publicado(s).\n", p.Publicados)
+		return err
+	}
+	if e, ok := v.(entregasTentadas); ok {
+		_, err := fmt.Fprintf(w, "SUCESSO: %d aviso(s) de vencimento ou atraso enfileirado(s), %d entrega(s) tentada(s).\n", e.Avisos, e.Tentativas)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if err := tabela(tw, v); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// tabela escreve o resultado em colunas, uma linha por registro
+func tabela(w io.Writer, v any) error {
+	switch r := v.(type) {
+	case *model.Usuario:
+		tabelaUsuarios(w, []model.Usuario{*r})
+	case []model.Usuario:
+		tabelaUsuarios(w, r)
+	case *model.Livro:
+		tabelaLivros(w, []model.Livro{*r})
+	case []model.Livro:
+		tabelaLivros(w, r)
+	case *model.Autor:
+		tabelaAutores(w, []model.Autor{*r})
+	case []model.Autor:
+		tabelaAutores(w, r)
+	case *model.Emprestimo:
+		tabelaEmprestimos(w, []model.Emprestimo{*r})
+	case []model.Emprestimo:
+		tabelaEmprestimos(w, r)
+	case *model.Webhook:
+		tabelaWebhooks(w, []model.Webhook{*r})
+		// o segredo só vem preenchido na criação
+		if r.Segredo != "" {
+			fmt.Fprintf(w, "\nSegredo da assinatura (guarde-o, não será mostrado de novo): %s\n", r.Segredo)
+		}
+	case []model.Webhook:
+		tabelaWebhooks(w, r)
+	case []model.EntregaWebhook:
+		tabelaEntregas(w, r)
+	case []model.EventoDominio:
+		tabelaEventos(w, r)
+	case *relatorioLote:
+		tabelaLote(w, r)
+	default:
+		return fmt.Errorf("resultado sem formato de tabela: %T", v)
+	}
+	return nil
+}
+
+// campo liga uma flag a um campo do registro T. aplicar só é chamado para as
+// flags informadas, o que permite usar a mesma lista na criação e na
+// atualização parcial
+type campo[T any] struct {
+	nome    string
+	uso     string
+	aplicar func(r *T, valor string) error
+}
+
+func registrarCampos[T any](fs *flag.FlagSet, campos []campo[T]) {
+	for _, c := range campos {
+		fs.String(c.nome, "", c.uso)
+	}
+}
+
+func aplicarCampos[T any](fs *flag.FlagSet, campos []campo[T], r *T) error {
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		i := slices.IndexFunc(campos, func(c campo[T]) bool { return c.nome == f.Name })
+		if i < 0 || err != nil {
+			return
+		}
+		err = campos[i].aplicar(r, f.Value.String())
+	})
+	return err
+}
+
+// lerData interpreta datas no formato AAAA-MM-DD, o mesmo do menu
+func lerData(nome, valor string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", strings.TrimSpace(valor))
+	if err != nil {
+		return time.Time{}, usoInvalido("--%s: data inválida '%s', use o formato AAAA-MM-DD", nome, valor)
+	}
+	return t, nil
+}
+
+func lerInteiro(nome, valor string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(valor))
+	if err != nil {
+		return 0, usoInvalido("%s deve ser um número inteiro: '%s'", nome, valor)
+	}
+	return n, nil
+}
+
+// formatarData formata datas para as tabelas; datas zeradas ficam em branco
+func formatarData(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
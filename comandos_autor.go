@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"flag"
+	"fmt"
+	"io"
+)
+
+var camposAutor = []campo[model.Autor]{
+	{"nome", "primeiro nome", func(a *model.Autor, v string) error { a.PrimeiroNome = v; return nil }},
+	{"sobrenome", "sobrenome", func(a *model.Autor, v string) error { a.Sobrenome = v; return nil }},
+}
+
+func comandosAutor() []comando {
+	return []comando{
+		{"autor", "create", nil, "Cadastra um autor", func(fs *flag.FlagSet) executor {
+			id := fs.Int("id", 0, "ID do autor")
+			registrarCampos(fs, camposAutor)
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				a := model.Autor{ID: *id}
+				if err := aplicarCampos(fs, camposAutor, &a); err != nil {
+					return nil, err
+				}
+				return b.CriarAutor(ctx, a)
+			}
+		}},
+		{"autor", "get", []string{"id"}, "Mostra um autor", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do autor", args[0])
+				if err != nil {
+					return nil, err
+				}
+				return b.ObterAutor(ctx, id)
+			}
+		}},
+		{"autor", "update", []string{"id"}, "Altera só os dados informados nas flags", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposAutor)
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do autor", args[0])
+				if err != nil {
+					return nil, err
+				}
+				a, err := b.ObterAutor(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				if err := aplicarCampos(fs, camposAutor, a); err != nil {
+					return nil, err
+				}
+				return b.AtualizarAutor(ctx, *a)
+			}
+		}},
+		{"autor", "delete", []string{"id"}, "Remove um autor", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do autor", args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := b.DeletarAutor(ctx, id); err != nil {
+					return nil, err
+				}
+				return remocao{"autor", args[0]}, nil
+			}
+		}},
+		{"autor", "list", nil, "Lista os autores", func(fs *flag.FlagSet) executor {
+			nome := fs.String("nome", "", "parte do nome")
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				return b.ListarAutores(ctx, *nome)
+			}
+		}},
+	}
+}
+
+func tabelaAutores(w io.Writer, autores []model.Autor) {
+	fmt.Fprintln(w, "ID\tNOME")
+	for _, a := range autores {
+		fmt.Fprintf(w, "%d\t%s %s\n", a.ID, a.PrimeiroNome, a.Sobrenome)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// camposEmprestimo são as flags de create e update; o ID identifica o empréstimo
+var camposEmprestimo = []campo[model.Emprestimo]{
+	{"cpf", "CPF do usuário", func(e *model.Emprestimo, v string) error { e.ClienteUsuarioCPF = v; return nil }},
+	{"quant", "quantidade de livros", func(e *model.Emprestimo, v string) (err error) {
+		e.QuantLivros, err = lerInteiro("--quant", v)
+		return err
+	}},
+	{"status", "status: A (ativo), D (devolvido) ou C (cancelado)", func(e *model.Emprestimo, v string) error { e.Status = v; return nil }},
+	{"data", "data do empréstimo (AAAA-MM-DD); sem ela, o momento atual", func(e *model.Emprestimo, v string) (err error) {
+		e.DataEmprestimo, err = lerData("data", v)
+		return err
+	}},
+}
+
+func comandosEmprestimo() []comando {
+	return []comando{
+		{"emprestimo", "create", nil, "Registra um empréstimo", func(fs *flag.FlagSet) executor {
+			id := fs.Int("id", 0, "ID do empréstimo")
+			livros := fs.String("livros", "", "ISBNs dos livros, separados por vírgula; sem --quant, a quantidade é a dos ISBNs")
+			registrarCampos(fs, camposEmprestimo)
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				e := model.Emprestimo{ID: *id, Livros: servico.ListaISBNs(*livros)}
+				if err := aplicarCampos(fs, camposEmprestimo, &e); err != nil {
+					return nil, err
+				}
+				return b.CriarEmprestimo(ctx, e)
+			}
+		}},
+		{"emprestimo", "get", []string{"id"}, "Mostra um empréstimo", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do empréstimo", args[0])
+				if err != nil {
+					return nil, err
+				}
+				return b.ObterEmprestimo(ctx, id)
+			}
+		}},
+		{"emprestimo", "update", []string{"id"}, "Altera só os dados informados nas flags", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposEmprestimo)
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do empréstimo", args[0])
+				if err != nil {
+					return nil, err
+				}
+				e, err := b.ObterEmprestimo(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				if err := aplicarCampos(fs, camposEmprestimo, e); err != nil {
+					return nil, err
+				}
+				return b.AtualizarEmprestimo(ctx, *e)
+			}
+		}},
+		{"emprestimo", "delete", []string{"id"}, "Remove um empréstimo", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do empréstimo", args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := b.DeletarEmprestimo(ctx, id); err != nil {
+					return nil, err
+				}
+				return remocao{"empréstimo", args[0]}, nil
+			}
+		}},
+		{"emprestimo", "list", nil, "Lista os empréstimos, dos mais recentes aos mais antigos", func(fs *flag.FlagSet) executor {
+			cpf := fs.String("cpf", "", "CPF do usuário")
+			status := fs.String("status", "", "status: A, D ou C")
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				return b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{CPF: *cpf, Status: *status})
+			}
+		}},
+	}
+}
+
+func tabelaEmprestimos(w io.Writer, emprestimos []model.Emprestimo) {
+	fmt.Fprintln(w, "ID\tDATA\tSTATUS\tLIVROS\tCPF")
+	for _, e := range emprestimos {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", e.ID, formatarData(e.DataEmprestimo), e.Status, e.QuantLivros,
+			validacao.FormatarCPF(e.ClienteUsuarioCPF))
+	}
+}
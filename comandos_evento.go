@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/outbox"
+	"crud-biblioteca/servico"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// eventosPublicados é o resultado de "evento publicar"
+type eventosPublicados struct {
+	Publicados int `json:"publicados"`
+}
+
+func comandosEvento() []comando {
+	return []comando{
+		{"evento", "list", nil, "Lista os eventos do outbox, dos mais recentes", func(fs *flag.FlagSet) executor {
+			limite := fs.Int("limite", 0, "quantidade máxima de eventos (padrão 50)")
+			pendentes := fs.Bool("pendentes", false, "só os ainda não publicados, dos mais antigos")
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				if *pendentes {
+					return b.EventosPendentes(ctx, *limite)
+				}
+				return b.ListarEventos(ctx, *limite)
+			}
+		}},
+		{"evento", "publicar", nil, "Publica os eventos pendentes uma vez nos destinos de EVENTOS_DESTINOS, sem o relay", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				destinos, err := outbox.DestinosFromEnv()
+				if err != nil {
+					return nil, err
+				}
+				n, err := outbox.New(b, destinos).Processar(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("%d evento(s) publicado(s) antes da falha: %w", n, err)
+				}
+				return eventosPublicados{n}, nil
+			}
+		}},
+	}
+}
+
+func tabelaEventos(w io.Writer, eventos []model.EventoDominio) {
+	fmt.Fprintln(w, "ID\tTIPO\tCHAVE\tOCORRIDO\tPUBLICADO\tTENTATIVAS\tERRO")
+	for _, e := range eventos {
+		publicado := ""
+		if e.PublicadoEm != nil {
+			publicado = e.PublicadoEm.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", e.ID, e.Tipo, e.Chave, e.OcorridoEm.Local().Format("2006-01-02 15:04:05"),
+			publicado, e.Tentativas, e.UltimoErro)
+	}
+}
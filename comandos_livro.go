@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// camposLivro são as flags de create e update; o ISBN identifica o livro e
+// os autores têm comandos próprios
+var camposLivro = []campo[model.Livro]{
+	{"titulo", "título", func(l *model.Livro, v string) error { l.Titulo = v; return nil }},
+	{"edicao", "edição", func(l *model.Livro, v string) error { l.Edicao = v; return nil }},
+	{"paginas", "número de páginas", func(l *model.Livro, v string) (err error) {
+		l.NumPaginas, err = lerInteiro("--paginas", v)
+		return err
+	}},
+	{"editora", "CNPJ da editora", func(l *model.Livro, v string) error { l.EditoraCNPJ = v; return nil }},
+	{"funcionario", "matrícula do funcionário que cadastrou o livro", func(l *model.Livro, v string) (err error) {
+		l.FuncionarioMatricula, err = lerInteiro("--funcionario", v)
+		return err
+	}},
+	{"sistema", "sistema de classificação: CDD ou CDU", func(l *model.Livro, v string) error { l.SistemaClassificacao = v; return nil }},
+	{"classificacao", "número de classificação (ex.: 005.74)", func(l *model.Livro, v string) error { l.NumeroClassificacao = v; return nil }},
+	{"obra", "ID da obra da qual o livro é edição ou tradução (0 desvincula)", func(l *model.Livro, v string) error {
+		id, err := lerInteiro("--obra", v)
+		if err != nil {
+			return err
+		}
+		l.ObraID = nil
+		if id != 0 {
+			l.ObraID = &id
+		}
+		return nil
+	}},
+	{"idioma", "código ISO 639-1 do idioma (ex.: pt)", func(l *model.Livro, v string) error { l.Idioma = v; return nil }},
+}
+
+func comandosLivro() []comando {
+	return []comando{
+		{"livro", "create", nil, "Cadastra um livro", func(fs *flag.FlagSet) executor {
+			isbn := fs.String("isbn", "", "ISBN-10 ou ISBN-13")
+			registrarCampos(fs, camposLivro)
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				l := model.Livro{ISBN: *isbn}
+				if err := aplicarCampos(fs, camposLivro, &l); err != nil {
+					return nil, err
+				}
+				return b.CriarLivro(ctx, l)
+			}
+		}},
+		{"livro", "get", []string{"isbn"}, "Mostra um livro", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				return b.ObterLivro(ctx, args[0])
+			}
+		}},
+		{"livro", "update", []string{"isbn"}, "Altera só os dados informados nas flags", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposLivro)
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				l, err := b.ObterLivro(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := aplicarCampos(fs, camposLivro, l); err != nil {
+					return nil, err
+				}
+				return b.AtualizarLivro(ctx, *l)
+			}
+		}},
+		{"livro", "delete", []string{"isbn"}, "Remove um livro", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				if err := b.DeletarLivro(ctx, args[0]); err != nil {
+					return nil, err
+				}
+				return remocao{"livro", args[0]}, nil
+			}
+		}},
+		{"livro", "list", nil, "Lista os livros", func(fs *flag.FlagSet) executor {
+			titulo := fs.String("titulo", "", "parte do título")
+			categoria := fs.Int("categoria", 0, "ID da categoria de assunto (inclui as subcategorias)")
+			obra := fs.Int("obra", 0, "ID da obra")
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				return b.ListarLivros(ctx, servico.FiltroLivro{Titulo: *titulo, CategoriaID: *categoria, ObraID: *obra})
+			}
+		}},
+		{"livro", "autores", []string{"isbn"}, "Lista os autores de um livro", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				l, err := b.ObterLivro(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return l.Autores, nil
+			}
+		}},
+		{"livro", "autor-add", []string{"isbn"}, "Vincula um autor ao livro, cadastrando-o se preciso", func(fs *flag.FlagSet) executor {
+			id := fs.Int("id", 0, "ID do autor")
+			nome := fs.String("nome", "", "primeiro nome, para autores ainda não cadastrados")
+			sobrenome := fs.String("sobrenome", "", "sobrenome, para autores ainda não cadastrados")
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				return b.VincularAutor(ctx, args[0], model.Autor{ID: *id, PrimeiroNome: *nome, Sobrenome: *sobrenome})
+			}
+		}},
+		{"livro", "autor-remove", []string{"isbn", "id-autor"}, "Desvincula um autor do livro", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do autor", args[1])
+				if err != nil {
+					return nil, err
+				}
+				if err := b.DesvincularAutor(ctx, args[0], id); err != nil {
+					return nil, err
+				}
+				return remocao{"vínculo com o autor", args[1]}, nil
+			}
+		}},
+	}
+}
+
+func tabelaLivros(w io.Writer, livros []model.Livro) {
+	fmt.Fprintln(w, "ISBN\tTÍTULO\tEDIÇÃO\tIDIOMA\tEDITORA\tAUTORES")
+	for _, l := range livros {
+		autores := make([]string, len(l.Autores))
+		for i, a := range l.Autores {
+			autores[i] = a.PrimeiroNome + " " + a.Sobrenome
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", l.ISBN, l.Titulo, l.Edicao, l.Idioma,
+			validacao.FormatarCNPJ(l.EditoraCNPJ), strings.Join(autores, "; "))
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// situação de cada linha no relatório do lote
+const (
+	loteOK       = "ok"
+	loteErro     = "erro"
+	loteIgnorada = "ignorada" // não executada porque o lote parou em um erro anterior
+	loteDesfeita = "desfeita" // executada, mas desfeita junto com a transação
+)
+
+// operacaoLote é uma operação aceita no arquivo de lote. executar recebe os
+// campos da linha em JSON, com os mesmos nomes da API REST, e retorna a chave
+// do registro gravado
+type operacaoLote struct {
+	nome     string
+	executar func(ctx context.Context, b *servico.Biblioteca, dados []byte) (string, error)
+}
+
+// operacao decodifica os campos da linha em T antes de chamar f
+func operacao[T any](nome string, f func(ctx context.Context, b *servico.Biblioteca, v T) (string, error)) operacaoLote {
+	return operacaoLote{nome, func(ctx context.Context, b *servico.Biblioteca, dados []byte) (string, error) {
+		var v T
+		dec := json.NewDecoder(strings.NewReader(string(dados)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&v); err != nil {
+			return "", usoInvalido("campos inválidos para '%s': %v", nome, err)
+		}
+		return f(ctx, b, v)
+	}}
+}
+
+var operacoesLote = []operacaoLote{
+	operacao("usuario create", func(ctx context.Context, b *servico.Biblioteca, u model.Usuario) (string, error) {
+		criado, err := b.CriarUsuario(ctx, u)
+		if err != nil {
+			return "", err
+		}
+		return criado.CPF, nil
+	}),
+	operacao("autor create", func(ctx context.Context, b *servico.Biblioteca, a model.Autor) (string, error) {
+		criado, err := b.CriarAutor(ctx, a)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprint(criado.ID), nil
+	}),
+	operacao("livro create", func(ctx context.Context, b *servico.Biblioteca, l model.Livro) (string, error) {
+		criado, err := b.CriarLivro(ctx, l)
+		if err != nil {
+			return "", err
+		}
+		return criado.ISBN, nil
+	}),
+	// o autor vem nos campos da própria linha: {"isbn": ..., "id": ..., "primeiro_nome": ...}
+	operacao("livro autor-add", func(ctx context.Context, b *servico.Biblioteca, v struct {
+		ISBN string `json:"isbn"`
+		model.Autor
+	}) (string, error) {
+		a, err := b.VincularAutor(ctx, v.ISBN, v.Autor)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/%d", v.ISBN, a.ID), nil
+	}),
+	operacao("emprestimo create", func(ctx context.Context, b *servico.Biblioteca, e model.Emprestimo) (string, error) {
+		criado, err := b.CriarEmprestimo(ctx, e)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprint(criado.ID), nil
+	}),
+}
+
+func comandosLote() []comando {
+	return []comando{
+		{"lote", "", []string{"arquivo"}, "Executa as operações de um arquivo JSON Lines ou YAML", func(fs *flag.FlagSet) executor {
+			parar := fs.Bool("stop-on-error", false, "para na primeira operação com erro (padrão)")
+			continuar := fs.Bool("continue", false, "executa as operações seguintes mesmo após um erro")
+			transacao := fs.Bool("transaction", false, "tudo ou nada: um erro desfaz todas as operações do arquivo")
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				if *parar && *continuar {
+					return nil, usoInvalido("use apenas uma entre --stop-on-error e --continue")
+				}
+				if *transacao && *continuar {
+					return nil, usoInvalido("--continue não pode ser usado com --transaction")
+				}
+				entradas, err := lerLote(args[0])
+				if err != nil {
+					return nil, err
+				}
+				if !*transacao {
+					return novoRelatorioLote(executarLote(ctx, b, entradas, *continuar), ""), nil
+				}
+				return executarLoteEmTransacao(ctx, b, entradas)
+			}
+		}},
+	}
+}
+
+// entradaLote é uma operação lida do arquivo, com a linha em que começa
+type entradaLote struct {
+	linha   int
+	valores map[string]any
+}
+
+// lerLote lê um arquivo YAML (.yaml ou .yml, uma lista de operações) ou JSON
+// Lines (um objeto por linha; linhas vazias e iniciadas por # são ignoradas)
+func lerLote(caminho string) ([]entradaLote, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, usoInvalido("não foi possível abrir o arquivo de lote: %v", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(caminho)) {
+	case ".yaml", ".yml":
+		return lerLoteYAML(f)
+	}
+	return lerLoteJSON(f)
+}
+
+func lerLoteJSON(r io.Reader) ([]entradaLote, error) {
+	var entradas []entradaLote
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), limiteLinhaLote)
+	for n := 1; scanner.Scan(); n++ {
+		linha := strings.TrimSpace(scanner.Text())
+		if linha == "" || strings.HasPrefix(linha, "#") {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(linha))
+		dec.UseNumber()
+		var valores map[string]any
+		if err := dec.Decode(&valores); err != nil {
+			return nil, usoInvalido("linha %d: JSON inválido: %v", n, err)
+		}
+		entradas = append(entradas, entradaLote{n, valores})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler o arquivo de lote: %w", err)
+	}
+	return entradas, nil
+}
+
+// limiteLinhaLote é o tamanho máximo de uma linha do arquivo JSON Lines
+const limiteLinhaLote = 1 << 20
+
+func lerLoteYAML(r io.Reader) ([]entradaLote, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, usoInvalido("YAML inválido: %v", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, usoInvalido("o arquivo YAML deve ser uma lista de operações")
+	}
+	var entradas []entradaLote
+	for _, item := range doc.Content[0].Content {
+		var valores map[string]any
+		if err := item.Decode(&valores); err != nil {
+			return nil, usoInvalido("linha %d: operação inválida: %v", item.Line, err)
+		}
+		entradas = append(entradas, entradaLote{item.Line, valores})
+	}
+	return entradas, nil
+}
+
+// executarLote executa as entradas em ordem. Sem continuar, as operações
+// seguintes a um erro são marcadas como ignoradas
+func executarLote(ctx context.Context, b *servico.Biblioteca, entradas []entradaLote, continuar bool) []resultadoLinha {
+	resultados := make([]resultadoLinha, len(entradas))
+	parou := false
+	for i, e := range entradas {
+		r := &resultados[i]
+		r.Linha = e.linha
+		r.Operacao, _ = e.valores["op"].(string)
+		if parou {
+			r.Status = loteIgnorada
+			continue
+		}
+		r.Chave, r.err = executarEntrada(ctx, b, e)
+		if r.err == nil {
+			r.Status = loteOK
+			continue
+		}
+		r.Status, r.Erro = loteErro, r.err.Error()
+		if campos, ok := validacao.ErrosDeCampo(r.err); ok {
+			r.Erro, r.Campos = "dados inválidos", campos
+		}
+		parou = !continuar
+	}
+	return resultados
+}
+
+// errLoteDesfeito desfaz a transação quando alguma operação do lote falha
+var errLoteDesfeito = errors.New("lote desfeito")
+
+func executarLoteEmTransacao(ctx context.Context, b *servico.Biblioteca, entradas []entradaLote) (*relatorioLote, error) {
+	var resultados []resultadoLinha
+	err := b.EmTransacao(ctx, func(ctx context.Context, b *servico.Biblioteca) error {
+		// no MongoDB a função é repetida em erros transitórios; vale a última execução
+		resultados = executarLote(ctx, b, entradas, false)
+		for _, r := range resultados {
+			if r.err != nil {
+				return errLoteDesfeito
+			}
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errLoteDesfeito):
+		for i := range resultados {
+			if resultados[i].Status == loteOK {
+				resultados[i].Status = loteDesfeita
+			}
+		}
+		return novoRelatorioLote(resultados, "desfeita"), nil
+	case err != nil:
+		return nil, fmt.Errorf("transação do lote não concluída, nada foi gravado: %w", err)
+	}
+	return novoRelatorioLote(resultados, "confirmada"), nil
+}
+
+func executarEntrada(ctx context.Context, b *servico.Biblioteca, e entradaLote) (string, error) {
+	nome, _ := e.valores["op"].(string)
+	var op *operacaoLote
+	for i := range operacoesLote {
+		if operacoesLote[i].nome == nome {
+			op = &operacoesLote[i]
+		}
+	}
+	if op == nil {
+		nomes := make([]string, len(operacoesLote))
+		for i, o := range operacoesLote {
+			nomes[i] = o.nome
+		}
+		return "", usoInvalido("operação desconhecida '%s' (use %s)", nome, strings.Join(nomes, ", "))
+	}
+
+	campos := make(map[string]any, len(e.valores))
+	for k, v := range e.valores {
+		if k != "op" {
+			campos[k] = datasCompletas(v)
+		}
+	}
+	dados, err := json.Marshal(campos)
+	if err != nil {
+		return "", usoInvalido("campos inválidos para '%s': %v", nome, err)
+	}
+	return op.executar(ctx, b, dados)
+}
+
+var dataSimples = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// datasCompletas aceita datas no formato AAAA-MM-DD, o mesmo do menu,
+// completando-as para o formato RFC 3339 esperado nos campos de data
+func datasCompletas(v any) any {
+	switch v := v.(type) {
+	case string:
+		if dataSimples.MatchString(v) {
+			return v + "T00:00:00Z"
+		}
+	case map[string]any:
+		for k, x := range v {
+			v[k] = datasCompletas(x)
+		}
+	case []any:
+		for i, x := range v {
+			v[i] = datasCompletas(x)
+		}
+	}
+	return v
+}
+
+type resultadoLinha struct {
+	Linha    int             `json:"linha"`
+	Operacao string          `json:"operacao"`
+	Status   string          `json:"status"`
+	Chave    string          `json:"chave,omitempty"`
+	Erro     string          `json:"erro,omitempty"`
+	Campos   validacao.Erros `json:"campos,omitempty"`
+	err      error
+}
+
+// relatorioLote é o resultado do comando lote, uma linha por operação
+type relatorioLote struct {
+	Resultados []resultadoLinha `json:"resultados"`
+	Transacao  string           `json:"transacao,omitempty"` // confirmada ou desfeita, com --transaction
+	OK         int              `json:"ok"`
+	Erros      int              `json:"erros"`
+	Ignoradas  int              `json:"ignoradas"`
+	Desfeitas  int              `json:"desfeitas"`
+}
+
+func novoRelatorioLote(resultados []resultadoLinha, transacao string) *relatorioLote {
+	r := &relatorioLote{Resultados: resultados, Transacao: transacao}
+	for _, l := range resultados {
+		switch l.Status {
+		case loteOK:
+			r.OK++
+		case loteErro:
+			r.Erros++
+		case loteIgnorada:
+			r.Ignoradas++
+		case loteDesfeita:
+			r.Desfeitas++
+		}
+	}
+	return r
+}
+
+// codigoSaida é o da primeira operação com erro, ou 0 se todas deram certo
+func (r *relatorioLote) codigoSaida() int {
+	for _, l := range r.Resultados {
+		if l.err != nil {
+			return codigoSaida(l.err)
+		}
+	}
+	return saidaOK
+}
+
+func tabelaLote(w io.Writer, r *relatorioLote) {
+	fmt.Fprintln(w, "LINHA\tOPERAÇÃO\tSTATUS\tCHAVE\tERRO")
+	for _, l := range r.Resultados {
+		erro := l.Erro
+		for _, c := range l.Campos {
+			erro += fmt.Sprintf("; %s: %s", c.Campo, c.Mensagem)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", l.Linha, l.Operacao, l.Status, l.Chave, erro)
+	}
+	fmt.Fprintf(w, "\n%d operação(ões): %d ok, %d com erro, %d ignorada(s)", len(r.Resultados), r.OK, r.Erros, r.Ignoradas)
+	if r.Transacao != "" {
+		fmt.Fprintf(w, "; transação %s", r.Transacao)
+		if r.Desfeitas > 0 {
+			fmt.Fprintf(w, " (%d operação(ões) desfeita(s))", r.Desfeitas)
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// camposUsuario são as flags de create e update; o CPF fica de fora porque
+// identifica o usuário e não pode ser alterado
+var camposUsuario = []campo[model.Usuario]{
+	{"nome", "primeiro nome", func(u *model.Usuario, v string) error { u.PrimeiroNome = v; return nil }},
+	{"sobrenome", "sobrenome", func(u *model.Usuario, v string) error { u.Sobrenome = v; return nil }},
+	{"nascimento", "data de nascimento (AAAA-MM-DD)", func(u *model.Usuario, v string) (err error) {
+		u.DataNascimento, err = lerData("nascimento", v)
+		return err
+	}},
+	{"email", "e-mail", func(u *model.Usuario, v string) error { u.Email = v; return nil }},
+	{"telefone", "telefone", func(u *model.Usuario, v string) error { u.Telefone = v; return nil }},
+	{"matricula", "matrícula UFS (vazia para usuários externos)", func(u *model.Usuario, v string) error { u.Matricula = v; return nil }},
+	{"categoria", "categoria: graduacao, pos, docente, tecnico ou externo", func(u *model.Usuario, v string) error {
+		u.Categoria = model.CategoriaUsuario(v)
+		return nil
+	}},
+	{"validade", "validade do vínculo (AAAA-MM-DD)", func(u *model.Usuario, v string) (err error) {
+		u.ValidadeVinculo, err = lerData("validade", v)
+		return err
+	}},
+	{"responsavel", "CPF do responsável, para menores de idade", func(u *model.Usuario, v string) error { u.ResponsavelCPF = v; return nil }},
+	{"logradouro", "logradouro do endereço", func(u *model.Usuario, v string) error { u.Endereco.Logradouro = v; return nil }},
+	{"numero", "número do endereço", func(u *model.Usuario, v string) error { u.Endereco.Numero = v; return nil }},
+	{"bairro", "bairro", func(u *model.Usuario, v string) error { u.Endereco.Bairro = v; return nil }},
+	{"cidade", "cidade", func(u *model.Usuario, v string) error { u.Endereco.Cidade = v; return nil }},
+	{"uf", "UF", func(u *model.Usuario, v string) error { u.Endereco.UF = v; return nil }},
+	{"cep", "CEP", func(u *model.Usuario, v string) error { u.Endereco.CEP = v; return nil }},
+}
+
+func comandosUsuario() []comando {
+	return []comando{
+		{"usuario", "create", nil, "Cadastra um usuário", func(fs *flag.FlagSet) executor {
+			cpf := fs.String("cpf", "", "CPF do usuário")
+			registrarCampos(fs, camposUsuario)
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				u := model.Usuario{CPF: *cpf}
+				if err := aplicarCampos(fs, camposUsuario, &u); err != nil {
+					return nil, err
+				}
+				return b.CriarUsuario(ctx, u)
+			}
+		}},
+		{"usuario", "get", []string{"cpf"}, "Mostra um usuário", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				return b.ObterUsuario(ctx, args[0])
+			}
+		}},
+		{"usuario", "update", []string{"cpf"}, "Altera só os dados informados nas flags", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposUsuario)
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				u, err := b.ObterUsuario(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := aplicarCampos(fs, camposUsuario, u); err != nil {
+					return nil, err
+				}
+				return b.AtualizarUsuario(ctx, *u)
+			}
+		}},
+		{"usuario", "delete", []string{"cpf"}, "Remove um usuário", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				if err := b.DeletarUsuario(ctx, args[0]); err != nil {
+					return nil, err
+				}
+				return remocao{"usuário", args[0]}, nil
+			}
+		}},
+		{"usuario", "list", nil, "Lista os usuários", func(fs *flag.FlagSet) executor {
+			nome := fs.String("nome", "", "parte do nome")
+			categoria := fs.String("categoria", "", "categoria do usuário")
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				return b.ListarUsuarios(ctx, repository.FiltroUsuario{Nome: *nome, Categoria: model.CategoriaUsuario(*categoria)})
+			}
+		}},
+		{"usuario", "dependentes", []string{"cpf"}, "Lista os menores pelos quais o usuário responde", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				return b.Dependentes(ctx, args[0])
+			}
+		}},
+		// a senha vem da entrada padrão para não ficar no histórico do shell
+		// nem na lista de processos
+		{"usuario", "senha", []string{"cpf"}, "Define a senha do portal do usuário, lida da primeira linha da entrada padrão", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				senha, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return nil, err
+				}
+				if err := b.DefinirSenha(ctx, args[0], strings.TrimRight(senha, "\r\n")); err != nil {
+					return nil, err
+				}
+				return senhaDefinida{args[0]}, nil
+			}
+		}},
+	}
+}
+
+func tabelaUsuarios(w io.Writer, usuarios []model.Usuario) {
+	fmt.Fprintln(w, "CPF\tNOME\tCATEGORIA\tMATRÍCULA\tVALIDADE\tRESPONSÁVEL")
+	for _, u := range usuarios {
+		responsavel := ""
+		if u.ResponsavelCPF != "" {
+			responsavel = validacao.FormatarCPF(u.ResponsavelCPF)
+		}
+		fmt.Fprintf(w, "%s\t%s %s\t%s\t%s\t%s\t%s\n", validacao.FormatarCPF(u.CPF), u.PrimeiroNome, u.Sobrenome,
+			u.Categoria, u.Matricula, formatarData(u.ValidadeVinculo), responsavel)
+	}
+}
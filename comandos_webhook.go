@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/webhooks"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var camposWebhook = []campo[model.Webhook]{
+	{"url", "URL que recebe os eventos (http ou https)", func(w *model.Webhook, v string) error { w.URL = v; return nil }},
+	{"eventos", "eventos assinados, separados por vírgula (ex.: emprestimo.criado,emprestimo.devolvido)", func(w *model.Webhook, v string) error {
+		w.Eventos = strings.Split(v, ",")
+		return nil
+	}},
+	{"segredo", "segredo da assinatura HMAC; sem ele, um é gerado na criação", func(w *model.Webhook, v string) error { w.Segredo = v; return nil }},
+	{"ativo", "true ou false; desativada, a assinatura não recebe eventos", func(w *model.Webhook, v string) error {
+		ativo, err := strconv.ParseBool(v)
+		if err != nil {
+			return usoInvalido("--ativo deve ser true ou false: '%s'", v)
+		}
+		w.Ativo = ativo
+		return nil
+	}},
+}
+
+// entregasTentadas é o resultado de "webhook entregar"
+type entregasTentadas struct {
+	Avisos     int `json:"avisos_enfileirados"`
+	Tentativas int `json:"entregas_tentadas"`
+}
+
+func comandosWebhook() []comando {
+	return []comando{
+		{"webhook", "create", nil, "Cadastra uma assinatura de webhook", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposWebhook)
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				w := model.Webhook{Ativo: true}
+				if err := aplicarCampos(fs, camposWebhook, &w); err != nil {
+					return nil, err
+				}
+				return b.CriarWebhook(ctx, w)
+			}
+		}},
+		{"webhook", "get", []string{"id"}, "Mostra uma assinatura", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do webhook", args[0])
+				if err != nil {
+					return nil, err
+				}
+				return b.ObterWebhook(ctx, id)
+			}
+		}},
+		{"webhook", "update", []string{"id"}, "Altera só os dados informados nas flags", func(fs *flag.FlagSet) executor {
+			registrarCampos(fs, camposWebhook)
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do webhook", args[0])
+				if err != nil {
+					return nil, err
+				}
+				w, err := b.ObterWebhook(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				if err := aplicarCampos(fs, camposWebhook, w); err != nil {
+					return nil, err
+				}
+				return b.AtualizarWebhook(ctx, *w)
+			}
+		}},
+		{"webhook", "delete", []string{"id"}, "Remove uma assinatura e o seu registro de entregas", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do webhook", args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := b.DeletarWebhook(ctx, id); err != nil {
+					return nil, err
+				}
+				return remocao{"webhook", args[0]}, nil
+			}
+		}},
+		{"webhook", "list", nil, "Lista as assinaturas", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				return b.ListarWebhooks(ctx)
+			}
+		}},
+		{"webhook", "entregas", []string{"id"}, "Mostra o registro de entregas da assinatura", func(fs *flag.FlagSet) executor {
+			limite := fs.Int("limite", 0, "quantidade máxima de entregas (padrão 50)")
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do webhook", args[0])
+				if err != nil {
+					return nil, err
+				}
+				return b.ListarEntregas(ctx, id, *limite)
+			}
+		}},
+		{"webhook", "testar", []string{"id"}, "Enfileira um evento de teste (enviado pelo servidor ou por 'webhook entregar')", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, args []string) (any, error) {
+				id, err := lerInteiro("o ID do webhook", args[0])
+				if err != nil {
+					return nil, err
+				}
+				e, err := b.TestarWebhook(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				return []model.EntregaWebhook{*e}, nil
+			}
+		}},
+		{"webhook", "entregar", nil, "Procura vencimentos e atrasos e tenta as entregas pendentes uma vez, sem subir o servidor", func(fs *flag.FlagSet) executor {
+			return func(ctx context.Context, b *servico.Biblioteca, _ []string) (any, error) {
+				cfg, err := webhooks.ConfigFromEnv()
+				if err != nil {
+					return nil, err
+				}
+				avisos, err := b.NotificarPrazos(ctx)
+				if err != nil {
+					return nil, err
+				}
+				n, err := webhooks.New(b, cfg).Processar(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return entregasTentadas{avisos, n}, nil
+			}
+		}},
+	}
+}
+
+func tabelaWebhooks(w io.Writer, lista []model.Webhook) {
+	fmt.Fprintln(w, "ID\tURL\tEVENTOS\tATIVO")
+	for _, h := range lista {
+		ativo := "sim"
+		if !h.Ativo {
+			ativo = "não"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", h.ID, h.URL, strings.Join(h.Eventos, ","), ativo)
+	}
+}
+
+func tabelaEntregas(w io.Writer, entregas []model.EntregaWebhook) {
+	fmt.Fprintln(w, "ENTREGA\tEVENTO\tSITUACAO\tTENTATIVAS\tSTATUS\tQUANDO\tERRO")
+	for _, e := range entregas {
+		// entregues mostram quando chegaram; pendentes, a próxima tentativa
+		quando := e.ProximaTentativa
+		if e.EntregueEm != nil {
+			quando = *e.EntregueEm
+		} else if e.Situacao != model.EntregaPendente {
+			quando = time.Time{}
+		}
+		status := ""
+		if e.UltimoStatus != 0 {
+			status = strconv.Itoa(e.UltimoStatus)
+		}
+		horario := ""
+		if !quando.IsZero() {
+			horario = quando.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", e.ID, e.Evento, e.Situacao, e.Tentativas, status, horario, e.UltimoErro)
+	}
+}
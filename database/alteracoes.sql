@@ -0,0 +1,195 @@
+-- Alterações no esquema "Projeto Logico" do PostgreSQL exigidas pelas
+-- funcionalidades adicionadas após o modelo lógico original.
+-- Execute este script depois de criar as tabelas base.
+
+-- Classificação por assunto (CDD/CDU) e categorias
+ALTER TABLE "Projeto Logico".Livro
+    ADD COLUMN IF NOT EXISTS sistema_classificacao VARCHAR(3) NOT NULL DEFAULT ''
+        CHECK (sistema_classificacao IN ('', 'CDD', 'CDU')),
+    ADD COLUMN IF NOT EXISTS numero_classificacao VARCHAR(40) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Categoria (
+    id               INTEGER PRIMARY KEY,
+    nome             VARCHAR(120) NOT NULL,
+    codigo           VARCHAR(40) NOT NULL DEFAULT '',
+    categoria_pai_id INTEGER REFERENCES "Projeto Logico".Categoria (id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Classifica (
+    livro_isbn   VARCHAR(13) REFERENCES "Projeto Logico".Livro (isbn) ON DELETE CASCADE,
+    categoria_id INTEGER REFERENCES "Projeto Logico".Categoria (id) ON DELETE CASCADE,
+    PRIMARY KEY (livro_isbn, categoria_id)
+);
+
+-- Perfis estendidos e categorias de usuário
+ALTER TABLE "Projeto Logico".Usuario
+    ADD COLUMN IF NOT EXISTS email               VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS telefone            VARCHAR(20)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_logradouro VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_numero     VARCHAR(10)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_bairro     VARCHAR(60)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_cidade     VARCHAR(60)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_uf         CHAR(2)      NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS endereco_cep        VARCHAR(8)   NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS matricula           VARCHAR(20)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS categoria           VARCHAR(10)  NOT NULL DEFAULT 'externo'
+        CHECK (categoria IN ('graduacao', 'pos', 'docente', 'tecnico', 'externo')),
+    ADD COLUMN IF NOT EXISTS validade_vinculo    DATE NOT NULL DEFAULT (CURRENT_DATE + INTERVAL '1 year');
+
+-- Recursos digitais vinculados aos livros (o conteúdo fica em RECURSOS_DIR)
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Recurso_Digital (
+    id           INTEGER PRIMARY KEY,
+    livro_isbn   VARCHAR(13) NOT NULL REFERENCES "Projeto Logico".Livro (isbn) ON DELETE CASCADE,
+    tipo         VARCHAR(12) NOT NULL CHECK (tipo IN ('ebook', 'pdf', 'suplementar')),
+    nome_arquivo VARCHAR(255) NOT NULL,
+    caminho      VARCHAR(255) NOT NULL,
+    mime         VARCHAR(100) NOT NULL,
+    tamanho      BIGINT NOT NULL CHECK (tamanho >= 0),
+    sha256       CHAR(64) NOT NULL,
+    enviado_em   TIMESTAMP NOT NULL
+);
+
+-- Agrupamento de edições e traduções em obras (FRBR) e reservas
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Obra (
+    id              INTEGER PRIMARY KEY,
+    titulo          VARCHAR(255) NOT NULL,
+    idioma_original VARCHAR(10) NOT NULL DEFAULT ''
+);
+
+ALTER TABLE "Projeto Logico".Livro
+    ADD COLUMN IF NOT EXISTS obra_id INTEGER REFERENCES "Projeto Logico".Obra (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS idioma  VARCHAR(10) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Reserva (
+    id           INTEGER PRIMARY KEY,
+    usuario_cpf  VARCHAR(11) NOT NULL REFERENCES "Projeto Logico".Usuario (cpf) ON DELETE CASCADE,
+    livro_isbn   VARCHAR(13) REFERENCES "Projeto Logico".Livro (isbn) ON DELETE CASCADE,
+    obra_id      INTEGER REFERENCES "Projeto Logico".Obra (id) ON DELETE CASCADE,
+    data_reserva TIMESTAMP NOT NULL,
+    status       CHAR(1) NOT NULL CHECK (status IN ('A', 'T', 'C')),
+    -- a reserva é de uma edição específica ou de qualquer edição de uma obra
+    CHECK (livro_isbn IS NOT NULL OR obra_id IS NOT NULL)
+);
+
+-- Periódicos (publicações seriadas) e fascículos
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Periodico (
+    issn               CHAR(9) PRIMARY KEY,
+    titulo             VARCHAR(255) NOT NULL,
+    editora_cnpj       VARCHAR(14) NOT NULL DEFAULT '',
+    fasciculos_por_ano INTEGER NOT NULL CHECK (fasciculos_por_ano > 0),
+    prazo_reclamacao   INTEGER NOT NULL DEFAULT 30 CHECK (prazo_reclamacao >= 0),
+    inicio_assinatura  DATE NOT NULL,
+    ativo              BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Fasciculo (
+    id               VARCHAR(40) PRIMARY KEY,
+    periodico_issn   CHAR(9) NOT NULL REFERENCES "Projeto Logico".Periodico (issn) ON DELETE CASCADE,
+    volume           INTEGER NOT NULL,
+    numero           INTEGER NOT NULL,
+    data_prevista    DATE NOT NULL,
+    data_recebimento DATE,
+    data_reclamacao  DATE,
+    status           VARCHAR(10) NOT NULL CHECK (status IN ('esperado', 'recebido', 'reclamado')),
+    emprestado_cpf   VARCHAR(11) NOT NULL DEFAULT '',
+    data_emprestimo  TIMESTAMP,
+    UNIQUE (periodico_issn, volume, numero)
+);
+
+-- Editoras: o CNPJ passa a aceitar o formato alfanumérico (12 posições com
+-- letras ou dígitos + 2 dígitos verificadores), sempre gravado sem máscara
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Editora (
+    cnpj VARCHAR(14) PRIMARY KEY,
+    nome VARCHAR(120) NOT NULL DEFAULT ''
+);
+
+ALTER TABLE "Projeto Logico".Editora
+    ADD COLUMN IF NOT EXISTS nome VARCHAR(120) NOT NULL DEFAULT '',
+    ALTER COLUMN cnpj TYPE VARCHAR(14);
+
+ALTER TABLE "Projeto Logico".Livro
+    ALTER COLUMN editora_cnpj TYPE VARCHAR(14);
+
+-- Responsáveis por usuários menores de idade e categorias de material restrito
+ALTER TABLE "Projeto Logico".Usuario
+    ADD COLUMN IF NOT EXISTS responsavel_cpf VARCHAR(11)
+        REFERENCES "Projeto Logico".Usuario (cpf) ON DELETE RESTRICT
+        CHECK (responsavel_cpf <> cpf);
+
+CREATE INDEX IF NOT EXISTS usuario_responsavel_idx ON "Projeto Logico".Usuario (responsavel_cpf);
+
+ALTER TABLE "Projeto Logico".Categoria
+    ADD COLUMN IF NOT EXISTS restrita BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Portal do usuário: prazos, renovações e multas dos empréstimos e senha de
+-- acesso dos usuários (hash bcrypt; NULL enquanto a equipe não definir)
+ALTER TABLE "Projeto Logico".Emprestimo
+    ADD COLUMN IF NOT EXISTS renovacoes INTEGER NOT NULL DEFAULT 0 CHECK (renovacoes >= 0),
+    ADD COLUMN IF NOT EXISTS data_devolucao TIMESTAMP;
+
+ALTER TABLE "Projeto Logico".Usuario
+    ADD COLUMN IF NOT EXISTS senha_hash VARCHAR(100);
+
+-- Webhooks: assinaturas de sistemas externos e a fila/registro de entregas.
+-- O ID da entrega combina a assinatura e o evento, o que impede enfileirar o
+-- mesmo evento duas vezes para a mesma assinatura
+CREATE TABLE IF NOT EXISTS "Projeto Logico".Webhook (
+    id        INTEGER PRIMARY KEY,
+    url       VARCHAR(2000) NOT NULL,
+    eventos   TEXT[] NOT NULL,
+    segredo   VARCHAR(200) NOT NULL,
+    ativo     BOOLEAN NOT NULL DEFAULT TRUE,
+    criado_em TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "Projeto Logico".EntregaWebhook (
+    id                VARCHAR(100) PRIMARY KEY,
+    webhook_id        INTEGER NOT NULL REFERENCES "Projeto Logico".Webhook (id) ON DELETE CASCADE,
+    evento_id         VARCHAR(80) NOT NULL,
+    evento            VARCHAR(40) NOT NULL,
+    corpo             TEXT NOT NULL,
+    situacao          VARCHAR(10) NOT NULL CHECK (situacao IN ('pendente', 'entregue', 'desistida')),
+    tentativas        INTEGER NOT NULL DEFAULT 0,
+    proxima_tentativa TIMESTAMP NOT NULL,
+    ultimo_status     INTEGER NOT NULL DEFAULT 0,
+    ultimo_erro       TEXT NOT NULL DEFAULT '',
+    criada_em         TIMESTAMP NOT NULL,
+    entregue_em       TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS entrega_webhook_pendente_idx
+    ON "Projeto Logico".EntregaWebhook (proxima_tentativa) WHERE situacao = 'pendente';
+CREATE INDEX IF NOT EXISTS entrega_webhook_assinatura_idx
+    ON "Projeto Logico".EntregaWebhook (webhook_id, criada_em);
+
+-- Outbox dos eventos de domínio: gravados na mesma transação da alteração e
+-- publicados depois pelo relay (go run . -relay). O ID começa pelo momento
+-- do evento, e a ordem dos IDs é a ordem de publicação
+CREATE TABLE IF NOT EXISTS "Projeto Logico".EventoDominio (
+    id           VARCHAR(40) PRIMARY KEY,
+    tipo         VARCHAR(40) NOT NULL,
+    agregado     VARCHAR(20) NOT NULL,
+    chave        VARCHAR(40) NOT NULL,
+    dados        JSONB NOT NULL,
+    ocorrido_em  TIMESTAMP NOT NULL,
+    publicado_em TIMESTAMP,
+    tentativas   INTEGER NOT NULL DEFAULT 0,
+    ultimo_erro  TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS evento_dominio_pendente_idx
+    ON "Projeto Logico".EventoDominio (id) WHERE publicado_em IS NULL;
+
+-- Acompanhamento ao vivo da circulação: cada evento gravado no outbox é
+-- anunciado no canal eventos_dominio (entregue quando a transação é
+-- confirmada); o ID vai no aviso e os dados são lidos da tabela
+CREATE OR REPLACE FUNCTION "Projeto Logico".avisar_evento_dominio() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('eventos_dominio', NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS evento_dominio_aviso ON "Projeto Logico".EventoDominio;
+CREATE TRIGGER evento_dominio_aviso AFTER INSERT ON "Projeto Logico".EventoDominio
+    FOR EACH ROW EXECUTE FUNCTION "Projeto Logico".avisar_evento_dominio();
@@ -3,27 +3,34 @@ package database
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func ConnectPostgres() (*pgx.Conn, error) {
+// ConnectPostgres abre um pool de conexões, que pode ser usado ao mesmo tempo
+// pelo menu e pelas requisições da API
+func ConnectPostgres() (*pgxpool.Pool, error) {
 	// string de conexão agora vem da variável de ambiente
 	connStr := os.Getenv("POSTGRES_CONN")
 	if connStr == "" {
 		fmt.Fprintf(os.Stderr, "Variável de ambiente POSTGRES_CONN não definida.\n")
 		return nil, fmt.Errorf("variável de ambiente POSTGRES_CONN não definida")
 	}
-	conn, err := pgx.Connect(context.Background(), connStr)
+	pool, err := pgxpool.New(context.Background(), connStr)
+	if err == nil {
+		// pgxpool só conecta sob demanda; o ping confirma a string de conexão
+		err = pool.Ping(context.Background())
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Não foi possível conectar ao PostgreSQL: %v\n", err)
 		return nil, err
 	}
-	fmt.Println("Conectado ao PostgreSQL com sucesso!")
-	return conn, nil
+	log.Println("Conectado ao PostgreSQL com sucesso!")
+	return pool, nil
 }
 
 func ConnectMongoDB() (*mongo.Client, error) {
@@ -40,6 +47,6 @@ func ConnectMongoDB() (*mongo.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("Conectado ao MongoDB com sucesso!")
+	log.Println("Conectado ao MongoDB com sucesso!")
 	return client, nil
 }
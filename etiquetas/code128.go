@@ -0,0 +1,83 @@
+package etiquetas
+
+import (
+	"errors"
+	"fmt"
+)
+
+// larguras de barras e espaços de cada símbolo do Code 128 (0 a 105) e do
+// símbolo de parada (106)
+var code128Padroes = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodigoB = 100
+	code128StartB  = 104
+	code128StartC  = 105
+	code128Stop    = 106
+)
+
+var ErrCode128Vazio = errors.New("não há dados para o código de barras")
+
+// Code128 gera um código de barras Code 128 para números de tombo,
+// matrículas e CPFs. Sequências só de dígitos usam o conjunto C (dois
+// dígitos por símbolo); os demais textos usam o conjunto B (ASCII 32 a 126)
+func Code128(dados string) (*CodigoBarras, error) {
+	if dados == "" {
+		return nil, ErrCode128Vazio
+	}
+
+	var simbolos []int
+	if apenasDigitos(dados) && len(dados) >= 2 {
+		simbolos = append(simbolos, code128StartC)
+		pares := len(dados) / 2 * 2
+		for i := 0; i < pares; i += 2 {
+			simbolos = append(simbolos, int(dados[i]-'0')*10+int(dados[i+1]-'0'))
+		}
+		// com quantidade ímpar de dígitos, o último vai no conjunto B
+		if pares < len(dados) {
+			simbolos = append(simbolos, code128CodigoB, int(dados[pares])-32)
+		}
+	} else {
+		simbolos = append(simbolos, code128StartB)
+		for _, r := range dados {
+			if r < 32 || r > 126 {
+				return nil, fmt.Errorf("caractere '%c' não pode ser representado no Code 128", r)
+			}
+			simbolos = append(simbolos, int(r)-32)
+		}
+	}
+
+	// dígito verificador: soma ponderada pela posição, módulo 103
+	soma := simbolos[0]
+	for i, s := range simbolos[1:] {
+		soma += s * (i + 1)
+	}
+	simbolos = append(simbolos, soma%103, code128Stop)
+
+	c := &CodigoBarras{Tipo: TipoCode128, Texto: dados, Margem: 10}
+	for _, s := range simbolos {
+		c.adicionarLarguras(code128Padroes[s])
+	}
+	return c, nil
+}
+
+func apenasDigitos(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package etiquetas
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// larguras converte os módulos nas larguras alternadas de barras e espaços,
+// um símbolo de 6 larguras por vez e o de parada com 7
+func larguras(c *CodigoBarras) []string {
+	var corridas []int
+	for i, m := range c.Modulos {
+		if i == 0 || m != c.Modulos[i-1] {
+			corridas = append(corridas, 0)
+		}
+		corridas[len(corridas)-1]++
+	}
+	var simbolos []string
+	for i := 0; i < len(corridas); i += 6 {
+		fim := min(i+6, len(corridas))
+		if len(corridas)-i == 7 {
+			fim = len(corridas)
+		}
+		var s strings.Builder
+		for _, l := range corridas[i:fim] {
+			s.WriteString(strconv.Itoa(l))
+		}
+		simbolos = append(simbolos, s.String())
+		if fim == len(corridas) {
+			break
+		}
+	}
+	return simbolos
+}
+
+func TestCode128(t *testing.T) {
+	// início B 211214, início C 211232 e parada 2331112 são os padrões da
+	// especificação; o verificador é a soma ponderada módulo 103
+	casos := []struct {
+		nome     string
+		dados    string
+		simbolos []int // valores esperados, do início ao verificador
+	}{
+		// 104 + 55 + 73*2 + 75*3 + 73*4 + 80*5 + 69*6 + 68*7 + 73*8 + 65*9 = 3281; 3281 % 103 = 88
+		{"texto no conjunto B", "Wikipedia", []int{104, 55, 73, 75, 73, 80, 69, 68, 73, 65, 88}},
+		// 105 + 12 + 34*2 + 56*3 = 353; 353 % 103 = 44
+		{"dígitos no conjunto C", "123456", []int{105, 12, 34, 56, 44}},
+		// 105 + 12 + 34*2 + 100*3 + 21*4 = 569; 569 % 103 = 54
+		{"dígitos em quantidade ímpar", "12345", []int{105, 12, 34, 100, 21, 54}},
+		// um dígito só não forma par e vai no conjunto B: 104 + 21 = 125; 125 % 103 = 22
+		{"um dígito só", "5", []int{104, 21, 22}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			cb, err := Code128(c.dados)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obtidos := larguras(cb)
+			if len(obtidos) != len(c.simbolos)+1 {
+				t.Fatalf("%d símbolos, esperados %d: %v", len(obtidos), len(c.simbolos)+1, obtidos)
+			}
+			for i, s := range c.simbolos {
+				if obtidos[i] != code128Padroes[s] {
+					t.Errorf("símbolo %d: %s, esperado %s (valor %d)", i, obtidos[i], code128Padroes[s], s)
+				}
+			}
+			inicio := map[int]string{104: "211214", 105: "211232"}[c.simbolos[0]]
+			if obtidos[0] != inicio {
+				t.Errorf("início %s, esperado %s", obtidos[0], inicio)
+			}
+			if parada := obtidos[len(obtidos)-1]; parada != "2331112" {
+				t.Errorf("parada %s, esperada 2331112", parada)
+			}
+			if len(cb.Modulos) != 11*len(c.simbolos)+13 {
+				t.Errorf("%d módulos, esperados %d", len(cb.Modulos), 11*len(c.simbolos)+13)
+			}
+		})
+	}
+
+	if _, err := Code128(""); !errors.Is(err, ErrCode128Vazio) {
+		t.Errorf("dados vazios: esperado ErrCode128Vazio, obtido %v", err)
+	}
+	if _, err := Code128("ação"); err == nil {
+		t.Error("caracteres fora do ASCII deveriam ser recusados")
+	}
+}
@@ -0,0 +1,108 @@
+package etiquetas
+
+import (
+	"crud-biblioteca/validacao"
+	"fmt"
+)
+
+// tipos de código de barras gerados
+const (
+	TipoEAN13   = "EAN-13"
+	TipoCode128 = "Code 128"
+)
+
+// CodigoBarras é a sequência de módulos (barras e espaços de largura
+// mínima) de um código de barras, já com os caracteres de início, fim e
+// verificação. A margem de silêncio é acrescentada pelos renderizadores
+type CodigoBarras struct {
+	Tipo    string
+	Texto   string // texto legível impresso abaixo das barras
+	Modulos []bool // true = barra, false = espaço
+	Margem  int    // margem de silêncio mínima, em módulos, de cada lado
+}
+
+// padrões do EAN-13: conjunto L (ímpar); R é o complemento de L e G é R invertido
+var ean13L = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// paridade (L ou G) dos seis primeiros dígitos, determinada pelo dígito inicial
+var ean13Paridade = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// EAN13 gera o código de barras do ISBN. O ISBN pode ser informado como
+// ISBN-10 ou ISBN-13, com ou sem hífens; o código sempre usa o ISBN-13
+func EAN13(isbn string) (*CodigoBarras, error) {
+	d, err := validacao.NormalizarISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CodigoBarras{Tipo: TipoEAN13, Texto: d, Margem: 11}
+	c.adicionar("101")
+	paridade := ean13Paridade[d[0]-'0']
+	for i := 1; i <= 6; i++ {
+		padrao := ean13L[d[i]-'0']
+		if paridade[i-1] == 'G' {
+			padrao = inverter(complemento(padrao))
+		}
+		c.adicionar(padrao)
+	}
+	c.adicionar("01010")
+	for i := 7; i <= 12; i++ {
+		c.adicionar(complemento(ean13L[d[i]-'0']))
+	}
+	c.adicionar("101")
+	return c, nil
+}
+
+// Largura retorna o total de módulos incluindo as margens de silêncio
+func (c *CodigoBarras) Largura() int {
+	return len(c.Modulos) + 2*c.Margem
+}
+
+// adicionar acrescenta módulos descritos como "0"/"1"
+func (c *CodigoBarras) adicionar(padrao string) {
+	for _, m := range padrao {
+		c.Modulos = append(c.Modulos, m == '1')
+	}
+}
+
+// adicionarLarguras acrescenta módulos descritos pelas larguras alternadas
+// de barras e espaços, começando por uma barra (ex.: "212222")
+func (c *CodigoBarras) adicionarLarguras(larguras string) {
+	barra := true
+	for _, l := range larguras {
+		for i := 0; i < int(l-'0'); i++ {
+			c.Modulos = append(c.Modulos, barra)
+		}
+		barra = !barra
+	}
+}
+
+func complemento(padrao string) string {
+	b := []byte(padrao)
+	for i := range b {
+		if b[i] == '0' {
+			b[i] = '1'
+		} else {
+			b[i] = '0'
+		}
+	}
+	return string(b)
+}
+
+func inverter(padrao string) string {
+	b := []byte(padrao)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+func (c *CodigoBarras) String() string {
+	return fmt.Sprintf("%s %s", c.Tipo, c.Texto)
+}
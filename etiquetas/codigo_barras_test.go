@@ -0,0 +1,70 @@
+package etiquetas
+
+import (
+	"crud-biblioteca/validacao"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// modulos converte os módulos em "0"/"1", para comparar com os padrões
+func modulos(c *CodigoBarras) string {
+	var s strings.Builder
+	for _, m := range c.Modulos {
+		if m {
+			s.WriteByte('1')
+		} else {
+			s.WriteByte('0')
+		}
+	}
+	return s.String()
+}
+
+func TestEAN13(t *testing.T) {
+	casos := []struct {
+		nome     string
+		isbn     string
+		esperado string // ISBN-13 impresso, com o dígito verificador do EAN
+	}{
+		{"ISBN-13", "978-0-306-40615-7", "9780306406157"},
+		{"ISBN-10 passa a 978", "0-306-40615-2", "9780306406157"},
+		{"ISBN-10 com X", "0-8044-2957-X", "9780804429573"},
+		{"ISBN-10 brasileiro", "85-359-0277-5", "9788535902778"},
+		{"prefixo 979", "979-10-90636-07-1", "9791090636071"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			cb, err := EAN13(c.isbn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cb.Texto != c.esperado {
+				t.Errorf("texto %s, esperado %s", cb.Texto, c.esperado)
+			}
+			if len(cb.Modulos) != 95 {
+				t.Errorf("%d módulos, esperados 95", len(cb.Modulos))
+			}
+		})
+	}
+
+	if _, err := EAN13("978-0-306-40615-8"); !errors.Is(err, validacao.ErrISBNDigito) {
+		t.Errorf("dígito verificador errado: esperado ErrISBNDigito, obtido %v", err)
+	}
+}
+
+func TestEAN13Modulos(t *testing.T) {
+	// 9780306406157: o 9 inicial define a paridade LGGLGL dos seis primeiros
+	// dígitos; os padrões são as tabelas L, G e R da especificação do EAN-13
+	esperado := "101" +
+		"0111011" + "0001001" + "0100111" + "0111101" + "0100111" + "0101111" + // 7L 8G 0G 3L 0G 6L
+		"01010" +
+		"1011100" + "1110010" + "1010000" + "1100110" + "1001110" + "1000100" + // 4 0 6 1 5 7 (R)
+		"101"
+	cb, err := EAN13("9780306406157")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obtido := modulos(cb); obtido != esperado {
+		t.Errorf("módulos\n%s\nesperados\n%s", obtido, esperado)
+	}
+}
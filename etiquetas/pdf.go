@@ -0,0 +1,137 @@
+package etiquetas
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dimensões em pontos (1 mm = 72/25,4 pt)
+const (
+	mm          = 72 / 25.4
+	a4Largura   = 210 * mm
+	a4Altura    = 297 * mm
+	colunas     = 3
+	linhas      = 8
+	etqLargura  = 70 * mm
+	etqAltura   = 37 * mm
+	etqMargem   = 4 * mm
+	barraAltura = 15 * mm
+	fonteTitulo = 8.0
+	fonteCodigo = 9.0
+)
+
+// EtiquetasPorFolha é a quantidade de etiquetas de uma folha A4 (3 x 8, 70 x 37 mm)
+const EtiquetasPorFolha = colunas * linhas
+
+// Etiqueta é uma etiqueta da folha: uma linha de título e o código de barras
+type Etiqueta struct {
+	Titulo string
+	Codigo *CodigoBarras
+}
+
+// FolhaPDF escreve um PDF A4 com as etiquetas em grade de 3 colunas por 8
+// linhas, usando quantas páginas forem necessárias
+func FolhaPDF(w io.Writer, etiquetas []Etiqueta) error {
+	var paginas []string
+	for inicio := 0; inicio < len(etiquetas) || inicio == 0; inicio += EtiquetasPorFolha {
+		fim := min(inicio+EtiquetasPorFolha, len(etiquetas))
+		var conteudo strings.Builder
+		for i, e := range etiquetas[inicio:fim] {
+			x := float64(i%colunas) * etqLargura
+			// a grade ocupa 296 mm; sobra meio milímetro em cima e embaixo
+			y := a4Altura - 0.5*mm - float64(i/colunas+1)*etqAltura
+			desenharEtiqueta(&conteudo, e, x, y)
+		}
+		paginas = append(paginas, conteudo.String())
+	}
+
+	var pdf pdfWriter
+	pdf.objeto("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(paginas))
+	for i := range paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	pdf.objeto(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(paginas)))
+	pdf.objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	pdf.objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, conteudo := range paginas {
+		pdf.objeto(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", a4Largura, a4Altura, 6+2*i))
+		pdf.objeto(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(conteudo), conteudo))
+	}
+	_, err := w.Write(pdf.finalizar())
+	return err
+}
+
+func desenharEtiqueta(b *strings.Builder, e Etiqueta, x, y float64) {
+	// título em Helvetica, cortado para caber na largura da etiqueta
+	titulo := []rune(e.Titulo)
+	if len(titulo) > 40 {
+		titulo = append(titulo[:39], '…')
+	}
+	fmt.Fprintf(b, "BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fonteTitulo, x+etqMargem, y+etqAltura-etqMargem-fonteTitulo, textoPDF(string(titulo)))
+	if e.Codigo == nil {
+		return
+	}
+
+	// as barras são reduzidas se o código não couber com o módulo nominal
+	c := e.Codigo
+	modulo := min(moduloMM*mm, (etqLargura-2*etqMargem)/float64(c.Largura()))
+	inicio := x + (etqLargura-modulo*float64(c.Largura()))/2 + modulo*float64(c.Margem)
+	base := y + etqMargem + fonteCodigo + 1
+	for _, barra := range c.barras() {
+		fmt.Fprintf(b, "%.3f %.3f %.3f %.3f re\n", inicio+modulo*float64(barra[0]), base, modulo*float64(barra[1]), barraAltura)
+	}
+	b.WriteString("f\n")
+
+	// texto legível centralizado; em Courier cada caractere ocupa 0,6 do corpo
+	larguraTexto := 0.6 * fonteCodigo * float64(len(c.Texto))
+	fmt.Fprintf(b, "BT /F2 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fonteCodigo, x+(etqLargura-larguraTexto)/2, y+etqMargem, textoPDF(c.Texto))
+}
+
+// textoPDF converte o texto para WinAnsi (que coincide com o Latin-1 nos
+// caracteres acentuados) e escapa os delimitadores de string do PDF
+func textoPDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '…':
+			b.WriteByte(0x85)
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
+
+// pdfWriter monta um PDF simples com objetos numerados a partir de 1 e a
+// tabela de referências cruzadas
+type pdfWriter struct {
+	buf      bytes.Buffer
+	posicoes []int
+}
+
+func (p *pdfWriter) objeto(corpo string) {
+	if p.buf.Len() == 0 {
+		p.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	p.posicoes = append(p.posicoes, p.buf.Len())
+	fmt.Fprintf(&p.buf, "%d 0 obj\n%s\nendobj\n", len(p.posicoes), corpo)
+}
+
+func (p *pdfWriter) finalizar() []byte {
+	xref := p.buf.Len()
+	fmt.Fprintf(&p.buf, "xref\n0 %d\n0000000000 65535 f \n", len(p.posicoes)+1)
+	for _, pos := range p.posicoes {
+		fmt.Fprintf(&p.buf, "%010d 00000 n \n", pos)
+	}
+	fmt.Fprintf(&p.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.posicoes)+1, xref)
+	return p.buf.Bytes()
+}
@@ -0,0 +1,70 @@
+package etiquetas
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// largura do módulo em milímetros usada no SVG (tamanho nominal do EAN-13)
+const moduloMM = 0.33
+
+// SVG escreve o código de barras em SVG, com o texto legível abaixo das
+// barras. A altura das barras é dada em módulos
+func (c *CodigoBarras) SVG(w io.Writer, altura int) error {
+	largura := c.Largura()
+	alturaTotal := altura + 12
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%.2fmm" height="%.2fmm" viewBox="0 0 %d %d">`+"\n",
+		float64(largura)*moduloMM, float64(alturaTotal)*moduloMM, largura, alturaTotal)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", largura, alturaTotal)
+	for _, b := range c.barras() {
+		fmt.Fprintf(bw, `<rect x="%d" y="0" width="%d" height="%d" fill="#000"/>`+"\n", c.Margem+b[0], b[1], altura)
+	}
+	fmt.Fprintf(bw, `<text x="%d" y="%d" font-family="monospace" font-size="10" text-anchor="middle">%s</text>`+"\n",
+		largura/2, alturaTotal-1, html.EscapeString(c.Texto))
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// PNG escreve somente as barras em PNG, com escala pixels por módulo e
+// altura em pixels
+func (c *CodigoBarras) PNG(w io.Writer, escala, altura int) error {
+	if escala < 1 {
+		escala = 1
+	}
+	img := image.NewGray(image.Rect(0, 0, c.Largura()*escala, altura))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, b := range c.barras() {
+		for x := (c.Margem + b[0]) * escala; x < (c.Margem+b[0]+b[1])*escala; x++ {
+			for y := 0; y < altura; y++ {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// barras agrupa os módulos consecutivos em barras, retornando o módulo
+// inicial e a largura de cada uma
+func (c *CodigoBarras) barras() [][2]int {
+	var barras [][2]int
+	for i := 0; i < len(c.Modulos); {
+		if !c.Modulos[i] {
+			i++
+			continue
+		}
+		inicio := i
+		for i < len(c.Modulos) && c.Modulos[i] {
+			i++
+		}
+		barras = append(barras, [2]int{inicio, i - inicio})
+	}
+	return barras
+}
@@ -0,0 +1,86 @@
+package graphqlapi
+
+// esquema descreve o catálogo para consultas aninhadas. Os nomes seguem a
+// convenção do GraphQL (camelCase) sobre os mesmos dados da API REST
+const esquema = `
+schema {
+	query: Query
+}
+
+"Data e hora no formato RFC 3339"
+scalar Time
+
+type Query {
+	"Livro pelo ISBN-10 ou ISBN-13, com ou sem hífens; null se não existir"
+	livro(isbn: String!): Livro
+	"Livros pelo título, pela categoria de assunto (inclui subcategorias) ou pela obra"
+	livros(titulo: String, categoria: Int, obra: Int): [Livro!]!
+	autor(id: Int!): Autor
+	autores(nome: String): [Autor!]!
+	"Usuário pelo CPF, com ou sem máscara"
+	usuario(cpf: String!): Usuario
+	usuarios(nome: String, categoria: String): [Usuario!]!
+	emprestimo(id: Int!): Emprestimo
+	"Empréstimos, os mais recentes primeiro; status A (ativo), D (devolvido) ou C (cancelado)"
+	emprestimos(cpf: String, status: String): [Emprestimo!]!
+}
+
+type Livro {
+	isbn: String!
+	titulo: String!
+	edicao: String!
+	numPaginas: Int!
+	idioma: String!
+	sistemaClassificacao: String!
+	numeroClassificacao: String!
+	"Autores pela tabela Escreve (PostgreSQL) ou embutidos no livro (MongoDB)"
+	autores: [Autor!]!
+	editora: Editora
+	"Obra da qual o livro é uma edição ou tradução"
+	obra: Obra
+}
+
+type Autor {
+	id: Int!
+	primeiroNome: String!
+	sobrenome: String!
+}
+
+type Editora {
+	cnpj: String!
+	nome: String!
+}
+
+type Obra {
+	id: Int!
+	titulo: String!
+	idiomaOriginal: String!
+}
+
+type Usuario {
+	cpf: String!
+	primeiroNome: String!
+	sobrenome: String!
+	dataNascimento: Time
+	email: String!
+	telefone: String!
+	matricula: String!
+	categoria: String!
+	validadeVinculo: Time
+	"Responsável por um menor de idade"
+	responsavel: Usuario
+	"Empréstimos do usuário; use status: \"A\" para os atuais"
+	emprestimos(status: String): [Emprestimo!]!
+}
+
+type Emprestimo {
+	id: Int!
+	dataEmprestimo: Time!
+	status: String!
+	quantLivros: Int!
+	renovacoes: Int!
+	"Data da devolução; null enquanto o empréstimo não é devolvido"
+	dataDevolucao: Time
+	usuario: Usuario
+}
+`
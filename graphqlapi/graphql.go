@@ -0,0 +1,27 @@
+// Package graphqlapi expõe o catálogo em GraphQL, para que o front-end busque
+// um livro com autores, editora e obra, ou um usuário com seus empréstimos,
+// em uma única requisição. Os relacionamentos são carregados em lote por
+// lista de resultados (ver lote.go).
+package graphqlapi
+
+import (
+	"crud-biblioteca/servico"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// Caminho onde o endpoint é atendido
+const Caminho = "/graphql"
+
+// profundidadeMaxima limita o aninhamento das consultas (usuario.responsavel
+// .emprestimos.usuario...), para que uma consulta não percorra o banco inteiro
+const profundidadeMaxima = 8
+
+// New retorna o handler que recebe consultas por POST, no formato
+// {"query": "...", "variables": {...}}
+func New(b *servico.Biblioteca) http.Handler {
+	schema := graphql.MustParseSchema(esquema, &raiz{b: b}, graphql.MaxDepth(profundidadeMaxima))
+	return &relay.Handler{Schema: schema}
+}
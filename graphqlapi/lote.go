@@ -0,0 +1,185 @@
+package graphqlapi
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"sync"
+)
+
+// Os resolvers de uma mesma lista compartilham um lote: o primeiro campo de
+// relacionamento pedido (autores, editora, usuário...) é carregado de uma vez
+// para todos os registros da lista, em vez de uma consulta por registro (N+1).
+
+// carga executa a consulta do lote uma única vez, mesmo com os resolvers
+// dos irmãos rodando em paralelo
+type carga[K comparable, V any] struct {
+	once    sync.Once
+	valores map[K]V
+	err     error
+}
+
+func (c *carga[K, V]) obter(carregar func() (map[K]V, error)) (map[K]V, error) {
+	c.once.Do(func() { c.valores, c.err = carregar() })
+	return c.valores, c.err
+}
+
+type loteLivros struct {
+	b      *servico.Biblioteca
+	livros []model.Livro
+
+	autores  carga[string, []model.Autor]
+	editoras carga[string, model.Editora]
+	obras    carga[int, model.Obra]
+}
+
+func novoLoteLivros(b *servico.Biblioteca, livros []model.Livro) []*livroResolver {
+	lote := &loteLivros{b: b, livros: livros}
+	resolvers := make([]*livroResolver, len(livros))
+	for i := range livros {
+		resolvers[i] = &livroResolver{l: &livros[i], lote: lote}
+	}
+	return resolvers
+}
+
+func (lote *loteLivros) carregarAutores(ctx context.Context) (map[string][]model.Autor, error) {
+	return lote.autores.obter(func() (map[string][]model.Autor, error) {
+		isbns := make([]string, len(lote.livros))
+		for i, l := range lote.livros {
+			isbns[i] = l.ISBN
+		}
+		return lote.b.Repos.Livros.AutoresPorLivro(ctx, isbns)
+	})
+}
+
+func (lote *loteLivros) carregarEditoras(ctx context.Context) (map[string]model.Editora, error) {
+	return lote.editoras.obter(func() (map[string]model.Editora, error) {
+		cnpjs := distintos(lote.livros, func(l model.Livro) (string, bool) { return l.EditoraCNPJ, l.EditoraCNPJ != "" })
+		editoras, err := lote.b.Repos.Editoras.ListByCNPJs(ctx, cnpjs)
+		return indexar(editoras, func(e model.Editora) string { return e.CNPJ }), err
+	})
+}
+
+func (lote *loteLivros) carregarObras(ctx context.Context) (map[int]model.Obra, error) {
+	return lote.obras.obter(func() (map[int]model.Obra, error) {
+		ids := distintos(lote.livros, func(l model.Livro) (int, bool) {
+			if l.ObraID == nil {
+				return 0, false
+			}
+			return *l.ObraID, true
+		})
+		obras, err := lote.b.Repos.Obras.ListByIDs(ctx, ids)
+		return indexar(obras, func(o model.Obra) int { return o.ID }), err
+	})
+}
+
+type loteUsuarios struct {
+	b        *servico.Biblioteca
+	usuarios []model.Usuario
+
+	responsaveis carga[string, *usuarioResolver]
+
+	mu          sync.Mutex
+	emprestimos map[string]*carga[string, []*emprestimoResolver] // por status pedido
+}
+
+func novoLoteUsuarios(b *servico.Biblioteca, usuarios []model.Usuario) []*usuarioResolver {
+	lote := &loteUsuarios{b: b, usuarios: usuarios, emprestimos: map[string]*carga[string, []*emprestimoResolver]{}}
+	resolvers := make([]*usuarioResolver, len(usuarios))
+	for i := range usuarios {
+		resolvers[i] = &usuarioResolver{u: &usuarios[i], lote: lote}
+	}
+	return resolvers
+}
+
+func (lote *loteUsuarios) cpfs() []string {
+	return distintos(lote.usuarios, func(u model.Usuario) (string, bool) { return u.CPF, true })
+}
+
+func (lote *loteUsuarios) carregarResponsaveis(ctx context.Context) (map[string]*usuarioResolver, error) {
+	return lote.responsaveis.obter(func() (map[string]*usuarioResolver, error) {
+		cpfs := distintos(lote.usuarios, func(u model.Usuario) (string, bool) { return u.ResponsavelCPF, u.ResponsavelCPF != "" })
+		if len(cpfs) == 0 {
+			return nil, nil
+		}
+		responsaveis, err := lote.b.Repos.Usuarios.List(ctx, repository.FiltroUsuario{CPFs: cpfs})
+		if err != nil {
+			return nil, err
+		}
+		// os responsáveis formam um novo lote, para seus próprios relacionamentos
+		return indexar(novoLoteUsuarios(lote.b, responsaveis), func(r *usuarioResolver) string { return r.u.CPF }), nil
+	})
+}
+
+// carregarEmprestimos busca os empréstimos de todos os usuários do lote com
+// o status pedido e os agrupa por CPF
+func (lote *loteUsuarios) carregarEmprestimos(ctx context.Context, status string) (map[string][]*emprestimoResolver, error) {
+	lote.mu.Lock()
+	c, ok := lote.emprestimos[status]
+	if !ok {
+		c = &carga[string, []*emprestimoResolver]{}
+		lote.emprestimos[status] = c
+	}
+	lote.mu.Unlock()
+
+	return c.obter(func() (map[string][]*emprestimoResolver, error) {
+		emprestimos, err := lote.b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{CPFs: lote.cpfs(), Status: status})
+		if err != nil {
+			return nil, err
+		}
+		porCPF := map[string][]*emprestimoResolver{}
+		for _, r := range novoLoteEmprestimos(lote.b, emprestimos) {
+			porCPF[r.e.ClienteUsuarioCPF] = append(porCPF[r.e.ClienteUsuarioCPF], r)
+		}
+		return porCPF, nil
+	})
+}
+
+type loteEmprestimos struct {
+	b           *servico.Biblioteca
+	emprestimos []model.Emprestimo
+
+	usuarios carga[string, *usuarioResolver]
+}
+
+func novoLoteEmprestimos(b *servico.Biblioteca, emprestimos []model.Emprestimo) []*emprestimoResolver {
+	lote := &loteEmprestimos{b: b, emprestimos: emprestimos}
+	resolvers := make([]*emprestimoResolver, len(emprestimos))
+	for i := range emprestimos {
+		resolvers[i] = &emprestimoResolver{e: &emprestimos[i], lote: lote}
+	}
+	return resolvers
+}
+
+func (lote *loteEmprestimos) carregarUsuarios(ctx context.Context) (map[string]*usuarioResolver, error) {
+	return lote.usuarios.obter(func() (map[string]*usuarioResolver, error) {
+		cpfs := distintos(lote.emprestimos, func(e model.Emprestimo) (string, bool) { return e.ClienteUsuarioCPF, true })
+		usuarios, err := lote.b.Repos.Usuarios.List(ctx, repository.FiltroUsuario{CPFs: cpfs})
+		if err != nil {
+			return nil, err
+		}
+		return indexar(novoLoteUsuarios(lote.b, usuarios), func(r *usuarioResolver) string { return r.u.CPF }), nil
+	})
+}
+
+// distintos extrai as chaves dos registros, sem repetição
+func distintos[T any, K comparable](registros []T, chave func(T) (K, bool)) []K {
+	vistos := map[K]bool{}
+	chaves := []K{}
+	for _, r := range registros {
+		if k, ok := chave(r); ok && !vistos[k] {
+			vistos[k] = true
+			chaves = append(chaves, k)
+		}
+	}
+	return chaves
+}
+
+func indexar[T any, K comparable](registros []T, chave func(T) K) map[K]T {
+	m := make(map[K]T, len(registros))
+	for _, r := range registros {
+		m[chave(r)] = r
+	}
+	return m
+}
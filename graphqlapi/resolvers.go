@@ -0,0 +1,268 @@
+package graphqlapi
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"errors"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type raiz struct {
+	b *servico.Biblioteca
+}
+
+// opcional trata o registro inexistente como null, como é usual no GraphQL
+func opcional[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, repository.ErrNaoEncontrado) {
+		return nil, nil
+	}
+	return v, err
+}
+
+func texto(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func inteiro(n *int32) int {
+	if n == nil {
+		return 0
+	}
+	return int(*n)
+}
+
+// data retorna null para datas não cadastradas
+func data(t time.Time) *graphql.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: t}
+}
+
+func (r *raiz) Livro(ctx context.Context, args struct{ ISBN string }) (*livroResolver, error) {
+	l, err := opcional(r.b.ObterLivro(ctx, args.ISBN))
+	if l == nil {
+		return nil, err
+	}
+	return novoLoteLivros(r.b, []model.Livro{*l})[0], nil
+}
+
+func (r *raiz) Livros(ctx context.Context, args struct {
+	Titulo    *string
+	Categoria *int32
+	Obra      *int32
+}) ([]*livroResolver, error) {
+	filtro := servico.FiltroLivro{Titulo: texto(args.Titulo), CategoriaID: inteiro(args.Categoria), ObraID: inteiro(args.Obra)}
+	livros, err := r.b.ListarLivros(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+	return novoLoteLivros(r.b, livros), nil
+}
+
+func (r *raiz) Autor(ctx context.Context, args struct{ ID int32 }) (*autorResolver, error) {
+	a, err := opcional(r.b.ObterAutor(ctx, int(args.ID)))
+	if a == nil {
+		return nil, err
+	}
+	return &autorResolver{*a}, nil
+}
+
+func (r *raiz) Autores(ctx context.Context, args struct{ Nome *string }) ([]*autorResolver, error) {
+	autores, err := r.b.ListarAutores(ctx, texto(args.Nome))
+	if err != nil {
+		return nil, err
+	}
+	return autoresResolver(autores), nil
+}
+
+func (r *raiz) Usuario(ctx context.Context, args struct{ CPF string }) (*usuarioResolver, error) {
+	u, err := opcional(r.b.ObterUsuario(ctx, args.CPF))
+	if u == nil {
+		return nil, err
+	}
+	return novoLoteUsuarios(r.b, []model.Usuario{*u})[0], nil
+}
+
+func (r *raiz) Usuarios(ctx context.Context, args struct {
+	Nome      *string
+	Categoria *string
+}) ([]*usuarioResolver, error) {
+	filtro := repository.FiltroUsuario{Nome: texto(args.Nome), Categoria: model.CategoriaUsuario(texto(args.Categoria))}
+	usuarios, err := r.b.ListarUsuarios(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+	return novoLoteUsuarios(r.b, usuarios), nil
+}
+
+func (r *raiz) Emprestimo(ctx context.Context, args struct{ ID int32 }) (*emprestimoResolver, error) {
+	e, err := opcional(r.b.ObterEmprestimo(ctx, int(args.ID)))
+	if e == nil {
+		return nil, err
+	}
+	return novoLoteEmprestimos(r.b, []model.Emprestimo{*e})[0], nil
+}
+
+func (r *raiz) Emprestimos(ctx context.Context, args struct {
+	CPF    *string
+	Status *string
+}) ([]*emprestimoResolver, error) {
+	filtro := repository.FiltroEmprestimo{CPF: texto(args.CPF), Status: texto(args.Status)}
+	emprestimos, err := r.b.ListarEmprestimos(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+	return novoLoteEmprestimos(r.b, emprestimos), nil
+}
+
+type livroResolver struct {
+	l    *model.Livro
+	lote *loteLivros
+}
+
+func (r *livroResolver) ISBN() string                 { return r.l.ISBN }
+func (r *livroResolver) Titulo() string               { return r.l.Titulo }
+func (r *livroResolver) Edicao() string               { return r.l.Edicao }
+func (r *livroResolver) NumPaginas() int32            { return int32(r.l.NumPaginas) }
+func (r *livroResolver) Idioma() string               { return r.l.Idioma }
+func (r *livroResolver) SistemaClassificacao() string { return r.l.SistemaClassificacao }
+func (r *livroResolver) NumeroClassificacao() string  { return r.l.NumeroClassificacao }
+
+func (r *livroResolver) Autores(ctx context.Context) ([]*autorResolver, error) {
+	autores, err := r.lote.carregarAutores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return autoresResolver(autores[r.l.ISBN]), nil
+}
+
+func (r *livroResolver) Editora(ctx context.Context) (*editoraResolver, error) {
+	editoras, err := r.lote.carregarEditoras(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := editoras[r.l.EditoraCNPJ]
+	if !ok {
+		return nil, nil
+	}
+	return &editoraResolver{e}, nil
+}
+
+func (r *livroResolver) Obra(ctx context.Context) (*obraResolver, error) {
+	if r.l.ObraID == nil {
+		return nil, nil
+	}
+	obras, err := r.lote.carregarObras(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := obras[*r.l.ObraID]
+	if !ok {
+		return nil, nil
+	}
+	return &obraResolver{o}, nil
+}
+
+type autorResolver struct {
+	a model.Autor
+}
+
+func autoresResolver(autores []model.Autor) []*autorResolver {
+	resolvers := make([]*autorResolver, len(autores))
+	for i, a := range autores {
+		resolvers[i] = &autorResolver{a}
+	}
+	return resolvers
+}
+
+func (r *autorResolver) ID() int32            { return int32(r.a.ID) }
+func (r *autorResolver) PrimeiroNome() string { return r.a.PrimeiroNome }
+func (r *autorResolver) Sobrenome() string    { return r.a.Sobrenome }
+
+type editoraResolver struct {
+	e model.Editora
+}
+
+func (r *editoraResolver) CNPJ() string { return r.e.CNPJ }
+func (r *editoraResolver) Nome() string { return r.e.Nome }
+
+type obraResolver struct {
+	o model.Obra
+}
+
+func (r *obraResolver) ID() int32              { return int32(r.o.ID) }
+func (r *obraResolver) Titulo() string         { return r.o.Titulo }
+func (r *obraResolver) IdiomaOriginal() string { return r.o.IdiomaOriginal }
+
+type usuarioResolver struct {
+	u    *model.Usuario
+	lote *loteUsuarios
+}
+
+func (r *usuarioResolver) CPF() string                   { return r.u.CPF }
+func (r *usuarioResolver) PrimeiroNome() string          { return r.u.PrimeiroNome }
+func (r *usuarioResolver) Sobrenome() string             { return r.u.Sobrenome }
+func (r *usuarioResolver) DataNascimento() *graphql.Time { return data(r.u.DataNascimento) }
+func (r *usuarioResolver) Email() string                 { return r.u.Email }
+func (r *usuarioResolver) Telefone() string              { return r.u.Telefone }
+func (r *usuarioResolver) Matricula() string             { return r.u.Matricula }
+func (r *usuarioResolver) Categoria() string             { return string(r.u.Categoria) }
+func (r *usuarioResolver) ValidadeVinculo() *graphql.Time {
+	return data(r.u.ValidadeVinculo)
+}
+
+func (r *usuarioResolver) Responsavel(ctx context.Context) (*usuarioResolver, error) {
+	if r.u.ResponsavelCPF == "" {
+		return nil, nil
+	}
+	responsaveis, err := r.lote.carregarResponsaveis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return responsaveis[r.u.ResponsavelCPF], nil
+}
+
+func (r *usuarioResolver) Emprestimos(ctx context.Context, args struct{ Status *string }) ([]*emprestimoResolver, error) {
+	emprestimos, err := r.lote.carregarEmprestimos(ctx, texto(args.Status))
+	if err != nil {
+		return nil, err
+	}
+	if emprestimos[r.u.CPF] == nil {
+		return []*emprestimoResolver{}, nil
+	}
+	return emprestimos[r.u.CPF], nil
+}
+
+type emprestimoResolver struct {
+	e    *model.Emprestimo
+	lote *loteEmprestimos
+}
+
+func (r *emprestimoResolver) ID() int32                    { return int32(r.e.ID) }
+func (r *emprestimoResolver) DataEmprestimo() graphql.Time { return graphql.Time{Time: r.e.DataEmprestimo} }
+func (r *emprestimoResolver) Status() string               { return r.e.Status }
+func (r *emprestimoResolver) QuantLivros() int32           { return int32(r.e.QuantLivros) }
+func (r *emprestimoResolver) Renovacoes() int32            { return int32(r.e.Renovacoes) }
+
+// DataDevolucao é null enquanto o empréstimo não é devolvido
+func (r *emprestimoResolver) DataDevolucao() *graphql.Time {
+	if r.e.DataDevolucao == nil {
+		return nil
+	}
+	return data(*r.e.DataDevolucao)
+}
+
+func (r *emprestimoResolver) Usuario(ctx context.Context) (*usuarioResolver, error) {
+	usuarios, err := r.lote.carregarUsuarios(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return usuarios[r.e.ClienteUsuarioCPF], nil
+}
@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Conferidor confere o login e a senha de uma conta da equipe
+type Conferidor func(login, senha string) bool
+
+// credenciais lê o cabeçalho authorization no formato do HTTP Basic
+// ("Basic " e login:senha em base64), o mesmo da API REST
+func credenciais(ctx context.Context) (login, senha string, ok bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	valores := md.Get("authorization")
+	if len(valores) != 1 {
+		return "", "", false
+	}
+	esquema, codificado, ok := strings.Cut(valores[0], " ")
+	if !ok || !strings.EqualFold(esquema, "Basic") {
+		return "", "", false
+	}
+	decodificado, err := base64.StdEncoding.DecodeString(strings.TrimSpace(codificado))
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decodificado), ":")
+}
+
+func (c Conferidor) autorizar(ctx context.Context) error {
+	login, senha, ok := credenciais(ctx)
+	if !ok || !c(login, senha) {
+		return status.Error(codes.Unauthenticated, "informe o login e a senha de uma conta da equipe")
+	}
+	return nil
+}
+
+// interceptadores exigem as credenciais em toda chamada, inclusive nas da
+// reflexão, que descrevem o serviço
+func (c Conferidor) interceptadores() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if err := c.autorizar(ctx); err != nil {
+				return nil, err
+			}
+			return h(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if err := c.autorizar(ss.Context()); err != nil {
+				return err
+			}
+			return h(srv, ss)
+		}),
+	}
+}
@@ -0,0 +1,1301 @@
+// Contrato gRPC da biblioteca para integração com outros sistemas da UFS
+// (registro acadêmico, catracas...). Os campos seguem os structs do pacote
+// model e as tags JSON da API REST.
+//
+// Para regenerar o código Go em grpcapi/bibliotecapb:
+//   protoc --go_out=. --go_opt=module=crud-biblioteca \
+//          --go-grpc_out=. --go-grpc_opt=module=crud-biblioteca proto/biblioteca.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/biblioteca.proto
+
+package bibliotecapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StatusEmprestimo int32
+
+const (
+	StatusEmprestimo_STATUS_EMPRESTIMO_NAO_INFORMADO StatusEmprestimo = 0
+	StatusEmprestimo_STATUS_EMPRESTIMO_ATIVO         StatusEmprestimo = 1 // "A"
+	StatusEmprestimo_STATUS_EMPRESTIMO_DEVOLVIDO     StatusEmprestimo = 2 // "D"
+	StatusEmprestimo_STATUS_EMPRESTIMO_CANCELADO     StatusEmprestimo = 3 // "C"
+)
+
+// Enum value maps for StatusEmprestimo.
+var (
+	StatusEmprestimo_name = map[int32]string{
+		0: "STATUS_EMPRESTIMO_NAO_INFORMADO",
+		1: "STATUS_EMPRESTIMO_ATIVO",
+		2: "STATUS_EMPRESTIMO_DEVOLVIDO",
+		3: "STATUS_EMPRESTIMO_CANCELADO",
+	}
+	StatusEmprestimo_value = map[string]int32{
+		"STATUS_EMPRESTIMO_NAO_INFORMADO": 0,
+		"STATUS_EMPRESTIMO_ATIVO":         1,
+		"STATUS_EMPRESTIMO_DEVOLVIDO":     2,
+		"STATUS_EMPRESTIMO_CANCELADO":     3,
+	}
+)
+
+func (x StatusEmprestimo) Enum() *StatusEmprestimo {
+	p := new(StatusEmprestimo)
+	*p = x
+	return p
+}
+
+func (x StatusEmprestimo) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatusEmprestimo) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_biblioteca_proto_enumTypes[0].Descriptor()
+}
+
+func (StatusEmprestimo) Type() protoreflect.EnumType {
+	return &file_proto_biblioteca_proto_enumTypes[0]
+}
+
+func (x StatusEmprestimo) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatusEmprestimo.Descriptor instead.
+func (StatusEmprestimo) EnumDescriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{0}
+}
+
+type Endereco struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logradouro    string                 `protobuf:"bytes,1,opt,name=logradouro,proto3" json:"logradouro,omitempty"`
+	Numero        string                 `protobuf:"bytes,2,opt,name=numero,proto3" json:"numero,omitempty"`
+	Bairro        string                 `protobuf:"bytes,3,opt,name=bairro,proto3" json:"bairro,omitempty"`
+	Cidade        string                 `protobuf:"bytes,4,opt,name=cidade,proto3" json:"cidade,omitempty"`
+	Uf            string                 `protobuf:"bytes,5,opt,name=uf,proto3" json:"uf,omitempty"`
+	Cep           string                 `protobuf:"bytes,6,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Endereco) Reset() {
+	*x = Endereco{}
+	mi := &file_proto_biblioteca_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Endereco) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Endereco) ProtoMessage() {}
+
+func (x *Endereco) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Endereco.ProtoReflect.Descriptor instead.
+func (*Endereco) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Endereco) GetLogradouro() string {
+	if x != nil {
+		return x.Logradouro
+	}
+	return ""
+}
+
+func (x *Endereco) GetNumero() string {
+	if x != nil {
+		return x.Numero
+	}
+	return ""
+}
+
+func (x *Endereco) GetBairro() string {
+	if x != nil {
+		return x.Bairro
+	}
+	return ""
+}
+
+func (x *Endereco) GetCidade() string {
+	if x != nil {
+		return x.Cidade
+	}
+	return ""
+}
+
+func (x *Endereco) GetUf() string {
+	if x != nil {
+		return x.Uf
+	}
+	return ""
+}
+
+func (x *Endereco) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type Usuario struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Cpf             string                 `protobuf:"bytes,1,opt,name=cpf,proto3" json:"cpf,omitempty"` // 11 dígitos, sem máscara
+	PrimeiroNome    string                 `protobuf:"bytes,2,opt,name=primeiro_nome,json=primeiroNome,proto3" json:"primeiro_nome,omitempty"`
+	Sobrenome       string                 `protobuf:"bytes,3,opt,name=sobrenome,proto3" json:"sobrenome,omitempty"`
+	DataNascimento  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=data_nascimento,json=dataNascimento,proto3" json:"data_nascimento,omitempty"`
+	Email           string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Telefone        string                 `protobuf:"bytes,6,opt,name=telefone,proto3" json:"telefone,omitempty"`
+	Endereco        *Endereco              `protobuf:"bytes,7,opt,name=endereco,proto3" json:"endereco,omitempty"`
+	Matricula       string                 `protobuf:"bytes,8,opt,name=matricula,proto3" json:"matricula,omitempty"`                                     // vazia para usuários externos
+	Categoria       string                 `protobuf:"bytes,9,opt,name=categoria,proto3" json:"categoria,omitempty"`                                     // graduacao, pos, docente, tecnico ou externo
+	ValidadeVinculo *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=validade_vinculo,json=validadeVinculo,proto3" json:"validade_vinculo,omitempty"` // ausente: vínculo sem validade
+	ResponsavelCpf  string                 `protobuf:"bytes,11,opt,name=responsavel_cpf,json=responsavelCpf,proto3" json:"responsavel_cpf,omitempty"`    // responsável por um menor de idade
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Usuario) Reset() {
+	*x = Usuario{}
+	mi := &file_proto_biblioteca_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usuario) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usuario) ProtoMessage() {}
+
+func (x *Usuario) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usuario.ProtoReflect.Descriptor instead.
+func (*Usuario) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Usuario) GetCpf() string {
+	if x != nil {
+		return x.Cpf
+	}
+	return ""
+}
+
+func (x *Usuario) GetPrimeiroNome() string {
+	if x != nil {
+		return x.PrimeiroNome
+	}
+	return ""
+}
+
+func (x *Usuario) GetSobrenome() string {
+	if x != nil {
+		return x.Sobrenome
+	}
+	return ""
+}
+
+func (x *Usuario) GetDataNascimento() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DataNascimento
+	}
+	return nil
+}
+
+func (x *Usuario) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Usuario) GetTelefone() string {
+	if x != nil {
+		return x.Telefone
+	}
+	return ""
+}
+
+func (x *Usuario) GetEndereco() *Endereco {
+	if x != nil {
+		return x.Endereco
+	}
+	return nil
+}
+
+func (x *Usuario) GetMatricula() string {
+	if x != nil {
+		return x.Matricula
+	}
+	return ""
+}
+
+func (x *Usuario) GetCategoria() string {
+	if x != nil {
+		return x.Categoria
+	}
+	return ""
+}
+
+func (x *Usuario) GetValidadeVinculo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidadeVinculo
+	}
+	return nil
+}
+
+func (x *Usuario) GetResponsavelCpf() string {
+	if x != nil {
+		return x.ResponsavelCpf
+	}
+	return ""
+}
+
+type Autor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PrimeiroNome  string                 `protobuf:"bytes,2,opt,name=primeiro_nome,json=primeiroNome,proto3" json:"primeiro_nome,omitempty"`
+	Sobrenome     string                 `protobuf:"bytes,3,opt,name=sobrenome,proto3" json:"sobrenome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Autor) Reset() {
+	*x = Autor{}
+	mi := &file_proto_biblioteca_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Autor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Autor) ProtoMessage() {}
+
+func (x *Autor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Autor.ProtoReflect.Descriptor instead.
+func (*Autor) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Autor) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Autor) GetPrimeiroNome() string {
+	if x != nil {
+		return x.PrimeiroNome
+	}
+	return ""
+}
+
+func (x *Autor) GetSobrenome() string {
+	if x != nil {
+		return x.Sobrenome
+	}
+	return ""
+}
+
+type Livro struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Isbn                 string                 `protobuf:"bytes,1,opt,name=isbn,proto3" json:"isbn,omitempty"` // ISBN-13 sem hífens
+	Titulo               string                 `protobuf:"bytes,2,opt,name=titulo,proto3" json:"titulo,omitempty"`
+	Edicao               string                 `protobuf:"bytes,3,opt,name=edicao,proto3" json:"edicao,omitempty"`
+	NumPaginas           int32                  `protobuf:"varint,4,opt,name=num_paginas,json=numPaginas,proto3" json:"num_paginas,omitempty"`
+	EditoraCnpj          string                 `protobuf:"bytes,5,opt,name=editora_cnpj,json=editoraCnpj,proto3" json:"editora_cnpj,omitempty"`
+	FuncionarioMatricula int32                  `protobuf:"varint,6,opt,name=funcionario_matricula,json=funcionarioMatricula,proto3" json:"funcionario_matricula,omitempty"`
+	Autores              []*Autor               `protobuf:"bytes,7,rep,name=autores,proto3" json:"autores,omitempty"`
+	SistemaClassificacao string                 `protobuf:"bytes,8,opt,name=sistema_classificacao,json=sistemaClassificacao,proto3" json:"sistema_classificacao,omitempty"` // CDD ou CDU
+	NumeroClassificacao  string                 `protobuf:"bytes,9,opt,name=numero_classificacao,json=numeroClassificacao,proto3" json:"numero_classificacao,omitempty"`
+	Categorias           []int32                `protobuf:"varint,10,rep,packed,name=categorias,proto3" json:"categorias,omitempty"`
+	ObraId               *int32                 `protobuf:"varint,11,opt,name=obra_id,json=obraId,proto3,oneof" json:"obra_id,omitempty"`
+	Idioma               string                 `protobuf:"bytes,12,opt,name=idioma,proto3" json:"idioma,omitempty"` // ISO 639-1
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Livro) Reset() {
+	*x = Livro{}
+	mi := &file_proto_biblioteca_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Livro) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Livro) ProtoMessage() {}
+
+func (x *Livro) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Livro.ProtoReflect.Descriptor instead.
+func (*Livro) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Livro) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Livro) GetTitulo() string {
+	if x != nil {
+		return x.Titulo
+	}
+	return ""
+}
+
+func (x *Livro) GetEdicao() string {
+	if x != nil {
+		return x.Edicao
+	}
+	return ""
+}
+
+func (x *Livro) GetNumPaginas() int32 {
+	if x != nil {
+		return x.NumPaginas
+	}
+	return 0
+}
+
+func (x *Livro) GetEditoraCnpj() string {
+	if x != nil {
+		return x.EditoraCnpj
+	}
+	return ""
+}
+
+func (x *Livro) GetFuncionarioMatricula() int32 {
+	if x != nil {
+		return x.FuncionarioMatricula
+	}
+	return 0
+}
+
+func (x *Livro) GetAutores() []*Autor {
+	if x != nil {
+		return x.Autores
+	}
+	return nil
+}
+
+func (x *Livro) GetSistemaClassificacao() string {
+	if x != nil {
+		return x.SistemaClassificacao
+	}
+	return ""
+}
+
+func (x *Livro) GetNumeroClassificacao() string {
+	if x != nil {
+		return x.NumeroClassificacao
+	}
+	return ""
+}
+
+func (x *Livro) GetCategorias() []int32 {
+	if x != nil {
+		return x.Categorias
+	}
+	return nil
+}
+
+func (x *Livro) GetObraId() int32 {
+	if x != nil && x.ObraId != nil {
+		return *x.ObraId
+	}
+	return 0
+}
+
+func (x *Livro) GetIdioma() string {
+	if x != nil {
+		return x.Idioma
+	}
+	return ""
+}
+
+type Emprestimo struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DataEmprestimo    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=data_emprestimo,json=dataEmprestimo,proto3" json:"data_emprestimo,omitempty"`
+	Status            StatusEmprestimo       `protobuf:"varint,3,opt,name=status,proto3,enum=biblioteca.v1.StatusEmprestimo" json:"status,omitempty"`
+	QuantLivros       int32                  `protobuf:"varint,4,opt,name=quant_livros,json=quantLivros,proto3" json:"quant_livros,omitempty"`
+	ClienteUsuarioCpf string                 `protobuf:"bytes,5,opt,name=cliente_usuario_cpf,json=clienteUsuarioCpf,proto3" json:"cliente_usuario_cpf,omitempty"`
+	Renovacoes        int32                  `protobuf:"varint,6,opt,name=renovacoes,proto3" json:"renovacoes,omitempty"`
+	DataDevolucao     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=data_devolucao,json=dataDevolucao,proto3" json:"data_devolucao,omitempty"` // ausente enquanto o empréstimo não é devolvido
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Emprestimo) Reset() {
+	*x = Emprestimo{}
+	mi := &file_proto_biblioteca_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Emprestimo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Emprestimo) ProtoMessage() {}
+
+func (x *Emprestimo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Emprestimo.ProtoReflect.Descriptor instead.
+func (*Emprestimo) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Emprestimo) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Emprestimo) GetDataEmprestimo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DataEmprestimo
+	}
+	return nil
+}
+
+func (x *Emprestimo) GetStatus() StatusEmprestimo {
+	if x != nil {
+		return x.Status
+	}
+	return StatusEmprestimo_STATUS_EMPRESTIMO_NAO_INFORMADO
+}
+
+func (x *Emprestimo) GetQuantLivros() int32 {
+	if x != nil {
+		return x.QuantLivros
+	}
+	return 0
+}
+
+func (x *Emprestimo) GetClienteUsuarioCpf() string {
+	if x != nil {
+		return x.ClienteUsuarioCpf
+	}
+	return ""
+}
+
+func (x *Emprestimo) GetRenovacoes() int32 {
+	if x != nil {
+		return x.Renovacoes
+	}
+	return 0
+}
+
+func (x *Emprestimo) GetDataDevolucao() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DataDevolucao
+	}
+	return nil
+}
+
+type ObterUsuarioRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpf           string                 `protobuf:"bytes,1,opt,name=cpf,proto3" json:"cpf,omitempty"` // com ou sem máscara
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObterUsuarioRequest) Reset() {
+	*x = ObterUsuarioRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObterUsuarioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObterUsuarioRequest) ProtoMessage() {}
+
+func (x *ObterUsuarioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObterUsuarioRequest.ProtoReflect.Descriptor instead.
+func (*ObterUsuarioRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ObterUsuarioRequest) GetCpf() string {
+	if x != nil {
+		return x.Cpf
+	}
+	return ""
+}
+
+type ListarUsuariosRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nome          string                 `protobuf:"bytes,1,opt,name=nome,proto3" json:"nome,omitempty"` // parte do primeiro nome ou do sobrenome
+	Categoria     string                 `protobuf:"bytes,2,opt,name=categoria,proto3" json:"categoria,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListarUsuariosRequest) Reset() {
+	*x = ListarUsuariosRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListarUsuariosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListarUsuariosRequest) ProtoMessage() {}
+
+func (x *ListarUsuariosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListarUsuariosRequest.ProtoReflect.Descriptor instead.
+func (*ListarUsuariosRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListarUsuariosRequest) GetNome() string {
+	if x != nil {
+		return x.Nome
+	}
+	return ""
+}
+
+func (x *ListarUsuariosRequest) GetCategoria() string {
+	if x != nil {
+		return x.Categoria
+	}
+	return ""
+}
+
+type VerificarUsuarioRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpf           string                 `protobuf:"bytes,1,opt,name=cpf,proto3" json:"cpf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerificarUsuarioRequest) Reset() {
+	*x = VerificarUsuarioRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerificarUsuarioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificarUsuarioRequest) ProtoMessage() {}
+
+func (x *VerificarUsuarioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificarUsuarioRequest.ProtoReflect.Descriptor instead.
+func (*VerificarUsuarioRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *VerificarUsuarioRequest) GetCpf() string {
+	if x != nil {
+		return x.Cpf
+	}
+	return ""
+}
+
+type VerificarUsuarioResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Usuario           *Usuario               `protobuf:"bytes,1,opt,name=usuario,proto3" json:"usuario,omitempty"`
+	VinculoAtivo      bool                   `protobuf:"varint,2,opt,name=vinculo_ativo,json=vinculoAtivo,proto3" json:"vinculo_ativo,omitempty"`
+	MenorDeIdade      bool                   `protobuf:"varint,3,opt,name=menor_de_idade,json=menorDeIdade,proto3" json:"menor_de_idade,omitempty"`
+	EmprestimosAtivos int32                  `protobuf:"varint,4,opt,name=emprestimos_ativos,json=emprestimosAtivos,proto3" json:"emprestimos_ativos,omitempty"`
+	LimiteLivros      int32                  `protobuf:"varint,5,opt,name=limite_livros,json=limiteLivros,proto3" json:"limite_livros,omitempty"` // livros que a categoria pode ter emprestados ao mesmo tempo
+	// pode_emprestar é falso com o vínculo expirado ou, para menores, sem um
+	// responsável apto; motivo explica a recusa
+	PodeEmprestar bool   `protobuf:"varint,6,opt,name=pode_emprestar,json=podeEmprestar,proto3" json:"pode_emprestar,omitempty"`
+	Motivo        string `protobuf:"bytes,7,opt,name=motivo,proto3" json:"motivo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerificarUsuarioResponse) Reset() {
+	*x = VerificarUsuarioResponse{}
+	mi := &file_proto_biblioteca_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerificarUsuarioResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificarUsuarioResponse) ProtoMessage() {}
+
+func (x *VerificarUsuarioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificarUsuarioResponse.ProtoReflect.Descriptor instead.
+func (*VerificarUsuarioResponse) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VerificarUsuarioResponse) GetUsuario() *Usuario {
+	if x != nil {
+		return x.Usuario
+	}
+	return nil
+}
+
+func (x *VerificarUsuarioResponse) GetVinculoAtivo() bool {
+	if x != nil {
+		return x.VinculoAtivo
+	}
+	return false
+}
+
+func (x *VerificarUsuarioResponse) GetMenorDeIdade() bool {
+	if x != nil {
+		return x.MenorDeIdade
+	}
+	return false
+}
+
+func (x *VerificarUsuarioResponse) GetEmprestimosAtivos() int32 {
+	if x != nil {
+		return x.EmprestimosAtivos
+	}
+	return 0
+}
+
+func (x *VerificarUsuarioResponse) GetLimiteLivros() int32 {
+	if x != nil {
+		return x.LimiteLivros
+	}
+	return 0
+}
+
+func (x *VerificarUsuarioResponse) GetPodeEmprestar() bool {
+	if x != nil {
+		return x.PodeEmprestar
+	}
+	return false
+}
+
+func (x *VerificarUsuarioResponse) GetMotivo() string {
+	if x != nil {
+		return x.Motivo
+	}
+	return ""
+}
+
+type ObterLivroRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Isbn          string                 `protobuf:"bytes,1,opt,name=isbn,proto3" json:"isbn,omitempty"` // ISBN-10 ou ISBN-13, com ou sem hífens
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObterLivroRequest) Reset() {
+	*x = ObterLivroRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObterLivroRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObterLivroRequest) ProtoMessage() {}
+
+func (x *ObterLivroRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObterLivroRequest.ProtoReflect.Descriptor instead.
+func (*ObterLivroRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ObterLivroRequest) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+type ListarLivrosRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Titulo        string                 `protobuf:"bytes,1,opt,name=titulo,proto3" json:"titulo,omitempty"`
+	CategoriaId   int32                  `protobuf:"varint,2,opt,name=categoria_id,json=categoriaId,proto3" json:"categoria_id,omitempty"` // inclui as subcategorias
+	ObraId        int32                  `protobuf:"varint,3,opt,name=obra_id,json=obraId,proto3" json:"obra_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListarLivrosRequest) Reset() {
+	*x = ListarLivrosRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListarLivrosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListarLivrosRequest) ProtoMessage() {}
+
+func (x *ListarLivrosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListarLivrosRequest.ProtoReflect.Descriptor instead.
+func (*ListarLivrosRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListarLivrosRequest) GetTitulo() string {
+	if x != nil {
+		return x.Titulo
+	}
+	return ""
+}
+
+func (x *ListarLivrosRequest) GetCategoriaId() int32 {
+	if x != nil {
+		return x.CategoriaId
+	}
+	return 0
+}
+
+func (x *ListarLivrosRequest) GetObraId() int32 {
+	if x != nil {
+		return x.ObraId
+	}
+	return 0
+}
+
+type ObterAutorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObterAutorRequest) Reset() {
+	*x = ObterAutorRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObterAutorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObterAutorRequest) ProtoMessage() {}
+
+func (x *ObterAutorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObterAutorRequest.ProtoReflect.Descriptor instead.
+func (*ObterAutorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ObterAutorRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListarAutoresRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nome          string                 `protobuf:"bytes,1,opt,name=nome,proto3" json:"nome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListarAutoresRequest) Reset() {
+	*x = ListarAutoresRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListarAutoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListarAutoresRequest) ProtoMessage() {}
+
+func (x *ListarAutoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListarAutoresRequest.ProtoReflect.Descriptor instead.
+func (*ListarAutoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListarAutoresRequest) GetNome() string {
+	if x != nil {
+		return x.Nome
+	}
+	return ""
+}
+
+type ObterEmprestimoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObterEmprestimoRequest) Reset() {
+	*x = ObterEmprestimoRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObterEmprestimoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObterEmprestimoRequest) ProtoMessage() {}
+
+func (x *ObterEmprestimoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObterEmprestimoRequest.ProtoReflect.Descriptor instead.
+func (*ObterEmprestimoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ObterEmprestimoRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListarEmprestimosRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpf           string                 `protobuf:"bytes,1,opt,name=cpf,proto3" json:"cpf,omitempty"`
+	Status        StatusEmprestimo       `protobuf:"varint,2,opt,name=status,proto3,enum=biblioteca.v1.StatusEmprestimo" json:"status,omitempty"` // NAO_INFORMADO lista todos
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListarEmprestimosRequest) Reset() {
+	*x = ListarEmprestimosRequest{}
+	mi := &file_proto_biblioteca_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListarEmprestimosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListarEmprestimosRequest) ProtoMessage() {}
+
+func (x *ListarEmprestimosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_biblioteca_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListarEmprestimosRequest.ProtoReflect.Descriptor instead.
+func (*ListarEmprestimosRequest) Descriptor() ([]byte, []int) {
+	return file_proto_biblioteca_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListarEmprestimosRequest) GetCpf() string {
+	if x != nil {
+		return x.Cpf
+	}
+	return ""
+}
+
+func (x *ListarEmprestimosRequest) GetStatus() StatusEmprestimo {
+	if x != nil {
+		return x.Status
+	}
+	return StatusEmprestimo_STATUS_EMPRESTIMO_NAO_INFORMADO
+}
+
+var File_proto_biblioteca_proto protoreflect.FileDescriptor
+
+const file_proto_biblioteca_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/biblioteca.proto\x12\rbiblioteca.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x94\x01\n" +
+	"\bEndereco\x12\x1e\n" +
+	"\n" +
+	"logradouro\x18\x01 \x01(\tR\n" +
+	"logradouro\x12\x16\n" +
+	"\x06numero\x18\x02 \x01(\tR\x06numero\x12\x16\n" +
+	"\x06bairro\x18\x03 \x01(\tR\x06bairro\x12\x16\n" +
+	"\x06cidade\x18\x04 \x01(\tR\x06cidade\x12\x0e\n" +
+	"\x02uf\x18\x05 \x01(\tR\x02uf\x12\x10\n" +
+	"\x03cep\x18\x06 \x01(\tR\x03cep\"\xb6\x03\n" +
+	"\aUsuario\x12\x10\n" +
+	"\x03cpf\x18\x01 \x01(\tR\x03cpf\x12#\n" +
+	"\rprimeiro_nome\x18\x02 \x01(\tR\fprimeiroNome\x12\x1c\n" +
+	"\tsobrenome\x18\x03 \x01(\tR\tsobrenome\x12C\n" +
+	"\x0fdata_nascimento\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0edataNascimento\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x1a\n" +
+	"\btelefone\x18\x06 \x01(\tR\btelefone\x123\n" +
+	"\bendereco\x18\a \x01(\v2\x17.biblioteca.v1.EnderecoR\bendereco\x12\x1c\n" +
+	"\tmatricula\x18\b \x01(\tR\tmatricula\x12\x1c\n" +
+	"\tcategoria\x18\t \x01(\tR\tcategoria\x12E\n" +
+	"\x10validade_vinculo\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x0fvalidadeVinculo\x12'\n" +
+	"\x0fresponsavel_cpf\x18\v \x01(\tR\x0eresponsavelCpf\"Z\n" +
+	"\x05Autor\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12#\n" +
+	"\rprimeiro_nome\x18\x02 \x01(\tR\fprimeiroNome\x12\x1c\n" +
+	"\tsobrenome\x18\x03 \x01(\tR\tsobrenome\"\xbe\x03\n" +
+	"\x05Livro\x12\x12\n" +
+	"\x04isbn\x18\x01 \x01(\tR\x04isbn\x12\x16\n" +
+	"\x06titulo\x18\x02 \x01(\tR\x06titulo\x12\x16\n" +
+	"\x06edicao\x18\x03 \x01(\tR\x06edicao\x12\x1f\n" +
+	"\vnum_paginas\x18\x04 \x01(\x05R\n" +
+	"numPaginas\x12!\n" +
+	"\feditora_cnpj\x18\x05 \x01(\tR\veditoraCnpj\x123\n" +
+	"\x15funcionario_matricula\x18\x06 \x01(\x05R\x14funcionarioMatricula\x12.\n" +
+	"\aautores\x18\a \x03(\v2\x14.biblioteca.v1.AutorR\aautores\x123\n" +
+	"\x15sistema_classificacao\x18\b \x01(\tR\x14sistemaClassificacao\x121\n" +
+	"\x14numero_classificacao\x18\t \x01(\tR\x13numeroClassificacao\x12\x1e\n" +
+	"\n" +
+	"categorias\x18\n" +
+	" \x03(\x05R\n" +
+	"categorias\x12\x1c\n" +
+	"\aobra_id\x18\v \x01(\x05H\x00R\x06obraId\x88\x01\x01\x12\x16\n" +
+	"\x06idioma\x18\f \x01(\tR\x06idiomaB\n" +
+	"\n" +
+	"\b_obra_id\"\xd0\x02\n" +
+	"\n" +
+	"Emprestimo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12C\n" +
+	"\x0fdata_emprestimo\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0edataEmprestimo\x127\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1f.biblioteca.v1.StatusEmprestimoR\x06status\x12!\n" +
+	"\fquant_livros\x18\x04 \x01(\x05R\vquantLivros\x12.\n" +
+	"\x13cliente_usuario_cpf\x18\x05 \x01(\tR\x11clienteUsuarioCpf\x12\x1e\n" +
+	"\n" +
+	"renovacoes\x18\x06 \x01(\x05R\n" +
+	"renovacoes\x12A\n" +
+	"\x0edata_devolucao\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rdataDevolucao\"'\n" +
+	"\x13ObterUsuarioRequest\x12\x10\n" +
+	"\x03cpf\x18\x01 \x01(\tR\x03cpf\"I\n" +
+	"\x15ListarUsuariosRequest\x12\x12\n" +
+	"\x04nome\x18\x01 \x01(\tR\x04nome\x12\x1c\n" +
+	"\tcategoria\x18\x02 \x01(\tR\tcategoria\"+\n" +
+	"\x17VerificarUsuarioRequest\x12\x10\n" +
+	"\x03cpf\x18\x01 \x01(\tR\x03cpf\"\xaa\x02\n" +
+	"\x18VerificarUsuarioResponse\x120\n" +
+	"\ausuario\x18\x01 \x01(\v2\x16.biblioteca.v1.UsuarioR\ausuario\x12#\n" +
+	"\rvinculo_ativo\x18\x02 \x01(\bR\fvinculoAtivo\x12$\n" +
+	"\x0emenor_de_idade\x18\x03 \x01(\bR\fmenorDeIdade\x12-\n" +
+	"\x12emprestimos_ativos\x18\x04 \x01(\x05R\x11emprestimosAtivos\x12#\n" +
+	"\rlimite_livros\x18\x05 \x01(\x05R\flimiteLivros\x12%\n" +
+	"\x0epode_emprestar\x18\x06 \x01(\bR\rpodeEmprestar\x12\x16\n" +
+	"\x06motivo\x18\a \x01(\tR\x06motivo\"'\n" +
+	"\x11ObterLivroRequest\x12\x12\n" +
+	"\x04isbn\x18\x01 \x01(\tR\x04isbn\"i\n" +
+	"\x13ListarLivrosRequest\x12\x16\n" +
+	"\x06titulo\x18\x01 \x01(\tR\x06titulo\x12!\n" +
+	"\fcategoria_id\x18\x02 \x01(\x05R\vcategoriaId\x12\x17\n" +
+	"\aobra_id\x18\x03 \x01(\x05R\x06obraId\"#\n" +
+	"\x11ObterAutorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"*\n" +
+	"\x14ListarAutoresRequest\x12\x12\n" +
+	"\x04nome\x18\x01 \x01(\tR\x04nome\"(\n" +
+	"\x16ObterEmprestimoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"e\n" +
+	"\x18ListarEmprestimosRequest\x12\x10\n" +
+	"\x03cpf\x18\x01 \x01(\tR\x03cpf\x127\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1f.biblioteca.v1.StatusEmprestimoR\x06status*\x96\x01\n" +
+	"\x10StatusEmprestimo\x12#\n" +
+	"\x1fSTATUS_EMPRESTIMO_NAO_INFORMADO\x10\x00\x12\x1b\n" +
+	"\x17STATUS_EMPRESTIMO_ATIVO\x10\x01\x12\x1f\n" +
+	"\x1bSTATUS_EMPRESTIMO_DEVOLVIDO\x10\x02\x12\x1f\n" +
+	"\x1bSTATUS_EMPRESTIMO_CANCELADO\x10\x032\xe5\x05\n" +
+	"\n" +
+	"Biblioteca\x12J\n" +
+	"\fObterUsuario\x12\".biblioteca.v1.ObterUsuarioRequest\x1a\x16.biblioteca.v1.Usuario\x12P\n" +
+	"\x0eListarUsuarios\x12$.biblioteca.v1.ListarUsuariosRequest\x1a\x16.biblioteca.v1.Usuario0\x01\x12c\n" +
+	"\x10VerificarUsuario\x12&.biblioteca.v1.VerificarUsuarioRequest\x1a'.biblioteca.v1.VerificarUsuarioResponse\x12D\n" +
+	"\n" +
+	"ObterLivro\x12 .biblioteca.v1.ObterLivroRequest\x1a\x14.biblioteca.v1.Livro\x12J\n" +
+	"\fListarLivros\x12\".biblioteca.v1.ListarLivrosRequest\x1a\x14.biblioteca.v1.Livro0\x01\x12D\n" +
+	"\n" +
+	"ObterAutor\x12 .biblioteca.v1.ObterAutorRequest\x1a\x14.biblioteca.v1.Autor\x12L\n" +
+	"\rListarAutores\x12#.biblioteca.v1.ListarAutoresRequest\x1a\x14.biblioteca.v1.Autor0\x01\x12S\n" +
+	"\x0fObterEmprestimo\x12%.biblioteca.v1.ObterEmprestimoRequest\x1a\x19.biblioteca.v1.Emprestimo\x12Y\n" +
+	"\x11ListarEmprestimos\x12'.biblioteca.v1.ListarEmprestimosRequest\x1a\x19.biblioteca.v1.Emprestimo0\x01B&Z$crud-biblioteca/grpcapi/bibliotecapbb\x06proto3"
+
+var (
+	file_proto_biblioteca_proto_rawDescOnce sync.Once
+	file_proto_biblioteca_proto_rawDescData []byte
+)
+
+func file_proto_biblioteca_proto_rawDescGZIP() []byte {
+	file_proto_biblioteca_proto_rawDescOnce.Do(func() {
+		file_proto_biblioteca_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_biblioteca_proto_rawDesc), len(file_proto_biblioteca_proto_rawDesc)))
+	})
+	return file_proto_biblioteca_proto_rawDescData
+}
+
+var file_proto_biblioteca_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_biblioteca_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_biblioteca_proto_goTypes = []any{
+	(StatusEmprestimo)(0),            // 0: biblioteca.v1.StatusEmprestimo
+	(*Endereco)(nil),                 // 1: biblioteca.v1.Endereco
+	(*Usuario)(nil),                  // 2: biblioteca.v1.Usuario
+	(*Autor)(nil),                    // 3: biblioteca.v1.Autor
+	(*Livro)(nil),                    // 4: biblioteca.v1.Livro
+	(*Emprestimo)(nil),               // 5: biblioteca.v1.Emprestimo
+	(*ObterUsuarioRequest)(nil),      // 6: biblioteca.v1.ObterUsuarioRequest
+	(*ListarUsuariosRequest)(nil),    // 7: biblioteca.v1.ListarUsuariosRequest
+	(*VerificarUsuarioRequest)(nil),  // 8: biblioteca.v1.VerificarUsuarioRequest
+	(*VerificarUsuarioResponse)(nil), // 9: biblioteca.v1.VerificarUsuarioResponse
+	(*ObterLivroRequest)(nil),        // 10: biblioteca.v1.ObterLivroRequest
+	(*ListarLivrosRequest)(nil),      // 11: biblioteca.v1.ListarLivrosRequest
+	(*ObterAutorRequest)(nil),        // 12: biblioteca.v1.ObterAutorRequest
+	(*ListarAutoresRequest)(nil),     // 13: biblioteca.v1.ListarAutoresRequest
+	(*ObterEmprestimoRequest)(nil),   // 14: biblioteca.v1.ObterEmprestimoRequest
+	(*ListarEmprestimosRequest)(nil), // 15: biblioteca.v1.ListarEmprestimosRequest
+	(*timestamppb.Timestamp)(nil),    // 16: google.protobuf.Timestamp
+}
+var file_proto_biblioteca_proto_depIdxs = []int32{
+	16, // 0: biblioteca.v1.Usuario.data_nascimento:type_name -> google.protobuf.Timestamp
+	1,  // 1: biblioteca.v1.Usuario.endereco:type_name -> biblioteca.v1.Endereco
+	16, // 2: biblioteca.v1.Usuario.validade_vinculo:type_name -> google.protobuf.Timestamp
+	3,  // 3: biblioteca.v1.Livro.autores:type_name -> biblioteca.v1.Autor
+	16, // 4: biblioteca.v1.Emprestimo.data_emprestimo:type_name -> google.protobuf.Timestamp
+	0,  // 5: biblioteca.v1.Emprestimo.status:type_name -> biblioteca.v1.StatusEmprestimo
+	16, // 6: biblioteca.v1.Emprestimo.data_devolucao:type_name -> google.protobuf.Timestamp
+	2,  // 7: biblioteca.v1.VerificarUsuarioResponse.usuario:type_name -> biblioteca.v1.Usuario
+	0,  // 8: biblioteca.v1.ListarEmprestimosRequest.status:type_name -> biblioteca.v1.StatusEmprestimo
+	6,  // 9: biblioteca.v1.Biblioteca.ObterUsuario:input_type -> biblioteca.v1.ObterUsuarioRequest
+	7,  // 10: biblioteca.v1.Biblioteca.ListarUsuarios:input_type -> biblioteca.v1.ListarUsuariosRequest
+	8,  // 11: biblioteca.v1.Biblioteca.VerificarUsuario:input_type -> biblioteca.v1.VerificarUsuarioRequest
+	10, // 12: biblioteca.v1.Biblioteca.ObterLivro:input_type -> biblioteca.v1.ObterLivroRequest
+	11, // 13: biblioteca.v1.Biblioteca.ListarLivros:input_type -> biblioteca.v1.ListarLivrosRequest
+	12, // 14: biblioteca.v1.Biblioteca.ObterAutor:input_type -> biblioteca.v1.ObterAutorRequest
+	13, // 15: biblioteca.v1.Biblioteca.ListarAutores:input_type -> biblioteca.v1.ListarAutoresRequest
+	14, // 16: biblioteca.v1.Biblioteca.ObterEmprestimo:input_type -> biblioteca.v1.ObterEmprestimoRequest
+	15, // 17: biblioteca.v1.Biblioteca.ListarEmprestimos:input_type -> biblioteca.v1.ListarEmprestimosRequest
+	2,  // 18: biblioteca.v1.Biblioteca.ObterUsuario:output_type -> biblioteca.v1.Usuario
+	2,  // 19: biblioteca.v1.Biblioteca.ListarUsuarios:output_type -> biblioteca.v1.Usuario
+	9,  // 20: biblioteca.v1.Biblioteca.VerificarUsuario:output_type -> biblioteca.v1.VerificarUsuarioResponse
+	4,  // 21: biblioteca.v1.Biblioteca.ObterLivro:output_type -> biblioteca.v1.Livro
+	4,  // 22: biblioteca.v1.Biblioteca.ListarLivros:output_type -> biblioteca.v1.Livro
+	3,  // 23: biblioteca.v1.Biblioteca.ObterAutor:output_type -> biblioteca.v1.Autor
+	3,  // 24: biblioteca.v1.Biblioteca.ListarAutores:output_type -> biblioteca.v1.Autor
+	5,  // 25: biblioteca.v1.Biblioteca.ObterEmprestimo:output_type -> biblioteca.v1.Emprestimo
+	5,  // 26: biblioteca.v1.Biblioteca.ListarEmprestimos:output_type -> biblioteca.v1.Emprestimo
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_proto_biblioteca_proto_init() }
+func file_proto_biblioteca_proto_init() {
+	if File_proto_biblioteca_proto != nil {
+		return
+	}
+	file_proto_biblioteca_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_biblioteca_proto_rawDesc), len(file_proto_biblioteca_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_biblioteca_proto_goTypes,
+		DependencyIndexes: file_proto_biblioteca_proto_depIdxs,
+		EnumInfos:         file_proto_biblioteca_proto_enumTypes,
+		MessageInfos:      file_proto_biblioteca_proto_msgTypes,
+	}.Build()
+	File_proto_biblioteca_proto = out.File
+	file_proto_biblioteca_proto_goTypes = nil
+	file_proto_biblioteca_proto_depIdxs = nil
+}
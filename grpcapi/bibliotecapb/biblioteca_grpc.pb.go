@@ -0,0 +1,454 @@
+// Contrato gRPC da biblioteca para integração com outros sistemas da UFS
+// (registro acadêmico, catracas...). Os campos seguem os structs do pacote
+// model e as tags JSON da API REST.
+//
+// Para regenerar o código Go em grpcapi/bibliotecapb:
+//   protoc --go_out=. --go_opt=module=crud-biblioteca \
+//          --go-grpc_out=. --go-grpc_opt=module=crud-biblioteca proto/biblioteca.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/biblioteca.proto
+
+package bibliotecapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Biblioteca_ObterUsuario_FullMethodName      = "/biblioteca.v1.Biblioteca/ObterUsuario"
+	Biblioteca_ListarUsuarios_FullMethodName    = "/biblioteca.v1.Biblioteca/ListarUsuarios"
+	Biblioteca_VerificarUsuario_FullMethodName  = "/biblioteca.v1.Biblioteca/VerificarUsuario"
+	Biblioteca_ObterLivro_FullMethodName        = "/biblioteca.v1.Biblioteca/ObterLivro"
+	Biblioteca_ListarLivros_FullMethodName      = "/biblioteca.v1.Biblioteca/ListarLivros"
+	Biblioteca_ObterAutor_FullMethodName        = "/biblioteca.v1.Biblioteca/ObterAutor"
+	Biblioteca_ListarAutores_FullMethodName     = "/biblioteca.v1.Biblioteca/ListarAutores"
+	Biblioteca_ObterEmprestimo_FullMethodName   = "/biblioteca.v1.Biblioteca/ObterEmprestimo"
+	Biblioteca_ListarEmprestimos_FullMethodName = "/biblioteca.v1.Biblioteca/ListarEmprestimos"
+)
+
+// BibliotecaClient is the client API for Biblioteca service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Biblioteca expõe consultas de usuários, livros, autores e empréstimos.
+// As listagens são server-streaming: cada registro é enviado em uma mensagem.
+type BibliotecaClient interface {
+	ObterUsuario(ctx context.Context, in *ObterUsuarioRequest, opts ...grpc.CallOption) (*Usuario, error)
+	ListarUsuarios(ctx context.Context, in *ListarUsuariosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Usuario], error)
+	// VerificarUsuario resume a situação do usuário para liberar acesso ou empréstimo
+	VerificarUsuario(ctx context.Context, in *VerificarUsuarioRequest, opts ...grpc.CallOption) (*VerificarUsuarioResponse, error)
+	ObterLivro(ctx context.Context, in *ObterLivroRequest, opts ...grpc.CallOption) (*Livro, error)
+	ListarLivros(ctx context.Context, in *ListarLivrosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Livro], error)
+	ObterAutor(ctx context.Context, in *ObterAutorRequest, opts ...grpc.CallOption) (*Autor, error)
+	ListarAutores(ctx context.Context, in *ListarAutoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Autor], error)
+	ObterEmprestimo(ctx context.Context, in *ObterEmprestimoRequest, opts ...grpc.CallOption) (*Emprestimo, error)
+	ListarEmprestimos(ctx context.Context, in *ListarEmprestimosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Emprestimo], error)
+}
+
+type bibliotecaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBibliotecaClient(cc grpc.ClientConnInterface) BibliotecaClient {
+	return &bibliotecaClient{cc}
+}
+
+func (c *bibliotecaClient) ObterUsuario(ctx context.Context, in *ObterUsuarioRequest, opts ...grpc.CallOption) (*Usuario, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Usuario)
+	err := c.cc.Invoke(ctx, Biblioteca_ObterUsuario_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bibliotecaClient) ListarUsuarios(ctx context.Context, in *ListarUsuariosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Usuario], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Biblioteca_ServiceDesc.Streams[0], Biblioteca_ListarUsuarios_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListarUsuariosRequest, Usuario]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarUsuariosClient = grpc.ServerStreamingClient[Usuario]
+
+func (c *bibliotecaClient) VerificarUsuario(ctx context.Context, in *VerificarUsuarioRequest, opts ...grpc.CallOption) (*VerificarUsuarioResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerificarUsuarioResponse)
+	err := c.cc.Invoke(ctx, Biblioteca_VerificarUsuario_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bibliotecaClient) ObterLivro(ctx context.Context, in *ObterLivroRequest, opts ...grpc.CallOption) (*Livro, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Livro)
+	err := c.cc.Invoke(ctx, Biblioteca_ObterLivro_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bibliotecaClient) ListarLivros(ctx context.Context, in *ListarLivrosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Livro], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Biblioteca_ServiceDesc.Streams[1], Biblioteca_ListarLivros_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListarLivrosRequest, Livro]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarLivrosClient = grpc.ServerStreamingClient[Livro]
+
+func (c *bibliotecaClient) ObterAutor(ctx context.Context, in *ObterAutorRequest, opts ...grpc.CallOption) (*Autor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Autor)
+	err := c.cc.Invoke(ctx, Biblioteca_ObterAutor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bibliotecaClient) ListarAutores(ctx context.Context, in *ListarAutoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Autor], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Biblioteca_ServiceDesc.Streams[2], Biblioteca_ListarAutores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListarAutoresRequest, Autor]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarAutoresClient = grpc.ServerStreamingClient[Autor]
+
+func (c *bibliotecaClient) ObterEmprestimo(ctx context.Context, in *ObterEmprestimoRequest, opts ...grpc.CallOption) (*Emprestimo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Emprestimo)
+	err := c.cc.Invoke(ctx, Biblioteca_ObterEmprestimo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bibliotecaClient) ListarEmprestimos(ctx context.Context, in *ListarEmprestimosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Emprestimo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Biblioteca_ServiceDesc.Streams[3], Biblioteca_ListarEmprestimos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListarEmprestimosRequest, Emprestimo]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarEmprestimosClient = grpc.ServerStreamingClient[Emprestimo]
+
+// BibliotecaServer is the server API for Biblioteca service.
+// All implementations must embed UnimplementedBibliotecaServer
+// for forward compatibility.
+//
+// Biblioteca expõe consultas de usuários, livros, autores e empréstimos.
+// As listagens são server-streaming: cada registro é enviado em uma mensagem.
+type BibliotecaServer interface {
+	ObterUsuario(context.Context, *ObterUsuarioRequest) (*Usuario, error)
+	ListarUsuarios(*ListarUsuariosRequest, grpc.ServerStreamingServer[Usuario]) error
+	// VerificarUsuario resume a situação do usuário para liberar acesso ou empréstimo
+	VerificarUsuario(context.Context, *VerificarUsuarioRequest) (*VerificarUsuarioResponse, error)
+	ObterLivro(context.Context, *ObterLivroRequest) (*Livro, error)
+	ListarLivros(*ListarLivrosRequest, grpc.ServerStreamingServer[Livro]) error
+	ObterAutor(context.Context, *ObterAutorRequest) (*Autor, error)
+	ListarAutores(*ListarAutoresRequest, grpc.ServerStreamingServer[Autor]) error
+	ObterEmprestimo(context.Context, *ObterEmprestimoRequest) (*Emprestimo, error)
+	ListarEmprestimos(*ListarEmprestimosRequest, grpc.ServerStreamingServer[Emprestimo]) error
+	mustEmbedUnimplementedBibliotecaServer()
+}
+
+// UnimplementedBibliotecaServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBibliotecaServer struct{}
+
+func (UnimplementedBibliotecaServer) ObterUsuario(context.Context, *ObterUsuarioRequest) (*Usuario, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObterUsuario not implemented")
+}
+func (UnimplementedBibliotecaServer) ListarUsuarios(*ListarUsuariosRequest, grpc.ServerStreamingServer[Usuario]) error {
+	return status.Errorf(codes.Unimplemented, "method ListarUsuarios not implemented")
+}
+func (UnimplementedBibliotecaServer) VerificarUsuario(context.Context, *VerificarUsuarioRequest) (*VerificarUsuarioResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerificarUsuario not implemented")
+}
+func (UnimplementedBibliotecaServer) ObterLivro(context.Context, *ObterLivroRequest) (*Livro, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObterLivro not implemented")
+}
+func (UnimplementedBibliotecaServer) ListarLivros(*ListarLivrosRequest, grpc.ServerStreamingServer[Livro]) error {
+	return status.Errorf(codes.Unimplemented, "method ListarLivros not implemented")
+}
+func (UnimplementedBibliotecaServer) ObterAutor(context.Context, *ObterAutorRequest) (*Autor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObterAutor not implemented")
+}
+func (UnimplementedBibliotecaServer) ListarAutores(*ListarAutoresRequest, grpc.ServerStreamingServer[Autor]) error {
+	return status.Errorf(codes.Unimplemented, "method ListarAutores not implemented")
+}
+func (UnimplementedBibliotecaServer) ObterEmprestimo(context.Context, *ObterEmprestimoRequest) (*Emprestimo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObterEmprestimo not implemented")
+}
+func (UnimplementedBibliotecaServer) ListarEmprestimos(*ListarEmprestimosRequest, grpc.ServerStreamingServer[Emprestimo]) error {
+	return status.Errorf(codes.Unimplemented, "method ListarEmprestimos not implemented")
+}
+func (UnimplementedBibliotecaServer) mustEmbedUnimplementedBibliotecaServer() {}
+func (UnimplementedBibliotecaServer) testEmbeddedByValue()                    {}
+
+// UnsafeBibliotecaServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BibliotecaServer will
+// result in compilation errors.
+type UnsafeBibliotecaServer interface {
+	mustEmbedUnimplementedBibliotecaServer()
+}
+
+func RegisterBibliotecaServer(s grpc.ServiceRegistrar, srv BibliotecaServer) {
+	// If the following call pancis, it indicates UnimplementedBibliotecaServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Biblioteca_ServiceDesc, srv)
+}
+
+func _Biblioteca_ObterUsuario_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObterUsuarioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BibliotecaServer).ObterUsuario(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Biblioteca_ObterUsuario_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BibliotecaServer).ObterUsuario(ctx, req.(*ObterUsuarioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Biblioteca_ListarUsuarios_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListarUsuariosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BibliotecaServer).ListarUsuarios(m, &grpc.GenericServerStream[ListarUsuariosRequest, Usuario]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarUsuariosServer = grpc.ServerStreamingServer[Usuario]
+
+func _Biblioteca_VerificarUsuario_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerificarUsuarioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BibliotecaServer).VerificarUsuario(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Biblioteca_VerificarUsuario_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BibliotecaServer).VerificarUsuario(ctx, req.(*VerificarUsuarioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Biblioteca_ObterLivro_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObterLivroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BibliotecaServer).ObterLivro(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Biblioteca_ObterLivro_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BibliotecaServer).ObterLivro(ctx, req.(*ObterLivroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Biblioteca_ListarLivros_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListarLivrosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BibliotecaServer).ListarLivros(m, &grpc.GenericServerStream[ListarLivrosRequest, Livro]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarLivrosServer = grpc.ServerStreamingServer[Livro]
+
+func _Biblioteca_ObterAutor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObterAutorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BibliotecaServer).ObterAutor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Biblioteca_ObterAutor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BibliotecaServer).ObterAutor(ctx, req.(*ObterAutorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Biblioteca_ListarAutores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListarAutoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BibliotecaServer).ListarAutores(m, &grpc.GenericServerStream[ListarAutoresRequest, Autor]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarAutoresServer = grpc.ServerStreamingServer[Autor]
+
+func _Biblioteca_ObterEmprestimo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObterEmprestimoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BibliotecaServer).ObterEmprestimo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Biblioteca_ObterEmprestimo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BibliotecaServer).ObterEmprestimo(ctx, req.(*ObterEmprestimoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Biblioteca_ListarEmprestimos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListarEmprestimosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BibliotecaServer).ListarEmprestimos(m, &grpc.GenericServerStream[ListarEmprestimosRequest, Emprestimo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Biblioteca_ListarEmprestimosServer = grpc.ServerStreamingServer[Emprestimo]
+
+// Biblioteca_ServiceDesc is the grpc.ServiceDesc for Biblioteca service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Biblioteca_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "biblioteca.v1.Biblioteca",
+	HandlerType: (*BibliotecaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ObterUsuario",
+			Handler:    _Biblioteca_ObterUsuario_Handler,
+		},
+		{
+			MethodName: "VerificarUsuario",
+			Handler:    _Biblioteca_VerificarUsuario_Handler,
+		},
+		{
+			MethodName: "ObterLivro",
+			Handler:    _Biblioteca_ObterLivro_Handler,
+		},
+		{
+			MethodName: "ObterAutor",
+			Handler:    _Biblioteca_ObterAutor_Handler,
+		},
+		{
+			MethodName: "ObterEmprestimo",
+			Handler:    _Biblioteca_ObterEmprestimo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListarUsuarios",
+			Handler:       _Biblioteca_ListarUsuarios_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListarLivros",
+			Handler:       _Biblioteca_ListarLivros_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListarAutores",
+			Handler:       _Biblioteca_ListarAutores_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListarEmprestimos",
+			Handler:       _Biblioteca_ListarEmprestimos_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/biblioteca.proto",
+}
@@ -0,0 +1,101 @@
+package grpcapi
+
+import (
+	"crud-biblioteca/grpcapi/bibliotecapb"
+	"crud-biblioteca/model"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// data converte uma data do modelo; a data zero (não cadastrada) fica ausente
+func data(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+var statusPB = map[string]bibliotecapb.StatusEmprestimo{
+	"A": bibliotecapb.StatusEmprestimo_STATUS_EMPRESTIMO_ATIVO,
+	"D": bibliotecapb.StatusEmprestimo_STATUS_EMPRESTIMO_DEVOLVIDO,
+	"C": bibliotecapb.StatusEmprestimo_STATUS_EMPRESTIMO_CANCELADO,
+}
+
+// statusModelo converte o status do protobuf para a letra usada no banco;
+// NAO_INFORMADO vira "" (sem filtro)
+func statusModelo(s bibliotecapb.StatusEmprestimo) string {
+	for letra, pb := range statusPB {
+		if pb == s {
+			return letra
+		}
+	}
+	return ""
+}
+
+func usuarioPB(u model.Usuario) *bibliotecapb.Usuario {
+	return &bibliotecapb.Usuario{
+		Cpf:            u.CPF,
+		PrimeiroNome:   u.PrimeiroNome,
+		Sobrenome:      u.Sobrenome,
+		DataNascimento: data(u.DataNascimento),
+		Email:          u.Email,
+		Telefone:       u.Telefone,
+		Endereco: &bibliotecapb.Endereco{
+			Logradouro: u.Endereco.Logradouro,
+			Numero:     u.Endereco.Numero,
+			Bairro:     u.Endereco.Bairro,
+			Cidade:     u.Endereco.Cidade,
+			Uf:         u.Endereco.UF,
+			Cep:        u.Endereco.CEP,
+		},
+		Matricula:       u.Matricula,
+		Categoria:       string(u.Categoria),
+		ValidadeVinculo: data(u.ValidadeVinculo),
+		ResponsavelCpf:  u.ResponsavelCPF,
+	}
+}
+
+func autorPB(a model.Autor) *bibliotecapb.Autor {
+	return &bibliotecapb.Autor{Id: int32(a.ID), PrimeiroNome: a.PrimeiroNome, Sobrenome: a.Sobrenome}
+}
+
+func livroPB(l model.Livro) *bibliotecapb.Livro {
+	pb := &bibliotecapb.Livro{
+		Isbn:                 l.ISBN,
+		Titulo:               l.Titulo,
+		Edicao:               l.Edicao,
+		NumPaginas:           int32(l.NumPaginas),
+		EditoraCnpj:          l.EditoraCNPJ,
+		FuncionarioMatricula: int32(l.FuncionarioMatricula),
+		SistemaClassificacao: l.SistemaClassificacao,
+		NumeroClassificacao:  l.NumeroClassificacao,
+		Idioma:               l.Idioma,
+	}
+	for _, a := range l.Autores {
+		pb.Autores = append(pb.Autores, autorPB(a))
+	}
+	for _, c := range l.Categorias {
+		pb.Categorias = append(pb.Categorias, int32(c))
+	}
+	if l.ObraID != nil {
+		obra := int32(*l.ObraID)
+		pb.ObraId = &obra
+	}
+	return pb
+}
+
+func emprestimoPB(e model.Emprestimo) *bibliotecapb.Emprestimo {
+	pb := &bibliotecapb.Emprestimo{
+		Id:                int32(e.ID),
+		DataEmprestimo:    data(e.DataEmprestimo),
+		Status:            statusPB[e.Status],
+		QuantLivros:       int32(e.QuantLivros),
+		ClienteUsuarioCpf: e.ClienteUsuarioCPF,
+		Renovacoes:        int32(e.Renovacoes),
+	}
+	if e.DataDevolucao != nil {
+		pb.DataDevolucao = data(*e.DataDevolucao)
+	}
+	return pb
+}
@@ -0,0 +1,172 @@
+// Package grpcapi implementa o serviço gRPC definido em proto/biblioteca.proto,
+// para integração com outros sistemas da universidade. As consultas passam
+// pelo pacote servico, como na API REST.
+package grpcapi
+
+import (
+	"context"
+	"crud-biblioteca/grpcapi/bibliotecapb"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"errors"
+	"log"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type Servidor struct {
+	bibliotecapb.UnimplementedBibliotecaServer
+	biblioteca *servico.Biblioteca
+}
+
+func New(b *servico.Biblioteca) *Servidor {
+	return &Servidor{biblioteca: b}
+}
+
+// NovoServidorGRPC cria o servidor com o serviço Biblioteca registrado. A
+// reflexão permite que ferramentas como o grpcurl descubram o contrato.
+// Com conferir, toda chamada exige uma conta da equipe; sem ele, o servidor
+// não pede login e deve ficar acessível só pela própria máquina
+func NovoServidorGRPC(b *servico.Biblioteca, conferir Conferidor) *grpc.Server {
+	var opcoes []grpc.ServerOption
+	if conferir != nil {
+		opcoes = conferir.interceptadores()
+	}
+	srv := grpc.NewServer(opcoes...)
+	bibliotecapb.RegisterBibliotecaServer(srv, New(b))
+	reflection.Register(srv)
+	return srv
+}
+
+// erroGRPC converte os erros do serviço para os códigos de status do gRPC
+func erroGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		return status.Error(codes.InvalidArgument, campos.Error())
+	}
+	switch {
+	case errors.Is(err, repository.ErrNaoEncontrado):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrDuplicado):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, repository.ErrReferencia), errors.Is(err, servico.ErrRegra):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrInvalido):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	log.Printf("ERRO: %v\n", err)
+	return status.Error(codes.Internal, "erro interno do servidor")
+}
+
+func (s *Servidor) ObterUsuario(ctx context.Context, req *bibliotecapb.ObterUsuarioRequest) (*bibliotecapb.Usuario, error) {
+	u, err := s.biblioteca.ObterUsuario(ctx, req.GetCpf())
+	if err != nil {
+		return nil, erroGRPC(err)
+	}
+	return usuarioPB(*u), nil
+}
+
+func (s *Servidor) ListarUsuarios(req *bibliotecapb.ListarUsuariosRequest, stream grpc.ServerStreamingServer[bibliotecapb.Usuario]) error {
+	filtro := repository.FiltroUsuario{Nome: req.GetNome(), Categoria: model.CategoriaUsuario(strings.TrimSpace(req.GetCategoria()))}
+	usuarios, err := s.biblioteca.ListarUsuarios(stream.Context(), filtro)
+	if err != nil {
+		return erroGRPC(err)
+	}
+	for _, u := range usuarios {
+		if err := stream.Send(usuarioPB(u)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Servidor) VerificarUsuario(ctx context.Context, req *bibliotecapb.VerificarUsuarioRequest) (*bibliotecapb.VerificarUsuarioResponse, error) {
+	sit, err := s.biblioteca.SituacaoUsuario(ctx, req.GetCpf())
+	if err != nil {
+		return nil, erroGRPC(err)
+	}
+	return &bibliotecapb.VerificarUsuarioResponse{
+		Usuario:           usuarioPB(sit.Usuario),
+		VinculoAtivo:      sit.VinculoAtivo,
+		MenorDeIdade:      sit.MenorDeIdade,
+		EmprestimosAtivos: int32(sit.EmprestimosAtivos),
+		LimiteLivros:      int32(sit.LimiteLivros),
+		PodeEmprestar:     sit.PodeEmprestar,
+		Motivo:            sit.Motivo,
+	}, nil
+}
+
+func (s *Servidor) ObterLivro(ctx context.Context, req *bibliotecapb.ObterLivroRequest) (*bibliotecapb.Livro, error) {
+	l, err := s.biblioteca.ObterLivro(ctx, req.GetIsbn())
+	if err != nil {
+		return nil, erroGRPC(err)
+	}
+	return livroPB(*l), nil
+}
+
+func (s *Servidor) ListarLivros(req *bibliotecapb.ListarLivrosRequest, stream grpc.ServerStreamingServer[bibliotecapb.Livro]) error {
+	filtro := servico.FiltroLivro{Titulo: req.GetTitulo(), CategoriaID: int(req.GetCategoriaId()), ObraID: int(req.GetObraId())}
+	livros, err := s.biblioteca.ListarLivros(stream.Context(), filtro)
+	if err != nil {
+		return erroGRPC(err)
+	}
+	for _, l := range livros {
+		if err := stream.Send(livroPB(l)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Servidor) ObterAutor(ctx context.Context, req *bibliotecapb.ObterAutorRequest) (*bibliotecapb.Autor, error) {
+	a, err := s.biblioteca.ObterAutor(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, erroGRPC(err)
+	}
+	return autorPB(*a), nil
+}
+
+func (s *Servidor) ListarAutores(req *bibliotecapb.ListarAutoresRequest, stream grpc.ServerStreamingServer[bibliotecapb.Autor]) error {
+	autores, err := s.biblioteca.ListarAutores(stream.Context(), req.GetNome())
+	if err != nil {
+		return erroGRPC(err)
+	}
+	for _, a := range autores {
+		if err := stream.Send(autorPB(a)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Servidor) ObterEmprestimo(ctx context.Context, req *bibliotecapb.ObterEmprestimoRequest) (*bibliotecapb.Emprestimo, error) {
+	e, err := s.biblioteca.ObterEmprestimo(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, erroGRPC(err)
+	}
+	return emprestimoPB(*e), nil
+}
+
+func (s *Servidor) ListarEmprestimos(req *bibliotecapb.ListarEmprestimosRequest, stream grpc.ServerStreamingServer[bibliotecapb.Emprestimo]) error {
+	filtro := repository.FiltroEmprestimo{CPF: req.GetCpf(), Status: statusModelo(req.GetStatus())}
+	emprestimos, err := s.biblioteca.ListarEmprestimos(stream.Context(), filtro)
+	if err != nil {
+		return erroGRPC(err)
+	}
+	for _, e := range emprestimos {
+		if err := stream.Send(emprestimoPB(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// CRUD de Categoria e classificação por assunto
+func handleCreateCategoria(ctx context.Context, repo repository.CategoriaRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da categoria (número inteiro): ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	fmt.Print("Digite o nome do assunto: ")
+	nome, _ := reader.ReadString('\n')
+
+	fmt.Print("Digite o código de classificação do assunto (ex.: 005.7): ")
+	codigo, _ := reader.ReadString('\n')
+
+	fmt.Print("Digite o ID da categoria superior (vazio para categoria raiz): ")
+	paiStr, _ := reader.ReadString('\n')
+	paiStr = strings.TrimSpace(paiStr)
+
+	fmt.Print("Material restrito a maiores de 18 anos? (s/N): ")
+	restrita, _ := reader.ReadString('\n')
+
+	categoria := model.Categoria{
+		ID:       id,
+		Nome:     strings.TrimSpace(nome),
+		Codigo:   strings.TrimSpace(codigo),
+		Restrita: strings.EqualFold(strings.TrimSpace(restrita), "s"),
+	}
+	if paiStr != "" {
+		paiID, err := strconv.Atoi(paiStr)
+		if err != nil {
+			log.Printf("ERRO: ID da categoria superior inválido. %v\n", err)
+			return
+		}
+		if _, err := repo.GetByID(ctx, paiID); err != nil {
+			log.Printf("ERRO: Categoria superior com ID '%d' não encontrada. %v\n", paiID, err)
+			return
+		}
+		categoria.PaiID = &paiID
+	}
+
+	if !validar(validacao.ValidarCategoria(categoria)) {
+		return
+	}
+
+	if err := repo.Create(ctx, categoria); err != nil {
+		log.Printf("ERRO: Não foi possível criar a categoria. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Categoria criada. Verifique o banco de dados.")
+	}
+}
+
+// handleNavegarCategorias desce pela hierarquia de assuntos a partir das categorias raiz
+func handleNavegarCategorias(ctx context.Context, repo repository.CategoriaRepository, reader *bufio.Reader) {
+	var paiID *int
+	for {
+		categorias, err := repo.ListSubcategorias(ctx, paiID)
+		if err != nil {
+			log.Printf("ERRO: Não foi possível listar as categorias. %v\n", err)
+			return
+		}
+		if len(categorias) == 0 {
+			log.Println("Nenhuma subcategoria encontrada.")
+			return
+		}
+
+		for _, c := range categorias {
+			restrita := ""
+			if c.Restrita {
+				restrita = " (restrita a maiores de idade)"
+			}
+			fmt.Printf("  [%d] %s - %s%s\n", c.ID, c.Codigo, c.Nome, restrita)
+		}
+		fmt.Print("Digite o ID de uma categoria para ver suas subcategorias (vazio para voltar): ")
+		idStr, _ := reader.ReadString('\n')
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			return
+		}
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			log.Printf("ERRO: ID inválido. %v\n", err)
+			return
+		}
+		paiID = &id
+	}
+}
+
+func handleClassificarLivro(ctx context.Context, livroRepo repository.LivroRepository, categoriaRepo repository.CategoriaRepository, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, livroRepo, reader, "Digite o ISBN do livro a ser classificado: ")
+	if !ok {
+		return
+	}
+
+	fmt.Print("Digite o ID da categoria: ")
+	categoriaIDStr, _ := reader.ReadString('\n')
+	categoriaID, err := strconv.Atoi(strings.TrimSpace(categoriaIDStr))
+	if err != nil {
+		log.Printf("ERRO: ID da categoria inválido. %v\n", err)
+		return
+	}
+
+	if _, err := categoriaRepo.GetByID(ctx, categoriaID); err != nil {
+		log.Printf("ERRO: Categoria com ID '%d' não encontrada. %v\n", categoriaID, err)
+		return
+	}
+
+	if err := livroRepo.AddCategoria(ctx, isbn, categoriaID); err != nil {
+		log.Printf("ERRO: Não foi possível classificar o livro. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Livro classificado. Verifique o banco de dados.")
+	}
+}
+
+func handleDesclassificarLivro(ctx context.Context, livroRepo repository.LivroRepository, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, livroRepo, reader, "Digite o ISBN do livro: ")
+	if !ok {
+		return
+	}
+
+	fmt.Print("Digite o ID da categoria a ser removida: ")
+	categoriaIDStr, _ := reader.ReadString('\n')
+	categoriaID, err := strconv.Atoi(strings.TrimSpace(categoriaIDStr))
+	if err != nil {
+		log.Printf("ERRO: ID da categoria inválido. %v\n", err)
+		return
+	}
+
+	if err := livroRepo.RemoveCategoria(ctx, isbn, categoriaID); err != nil {
+		log.Printf("ERRO: Não foi possível remover a classificação. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Classificação removida. Verifique o banco de dados.")
+	}
+}
+
+func handleListLivrosPorCategoria(ctx context.Context, livroRepo repository.LivroRepository, categoriaRepo repository.CategoriaRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da categoria: ")
+	categoriaIDStr, _ := reader.ReadString('\n')
+	categoriaID, err := strconv.Atoi(strings.TrimSpace(categoriaIDStr))
+	if err != nil {
+		log.Printf("ERRO: ID da categoria inválido. %v\n", err)
+		return
+	}
+
+	categoria, err := categoriaRepo.GetByID(ctx, categoriaID)
+	if err != nil {
+		log.Printf("ERRO: Categoria com ID '%d' não encontrada. %v\n", categoriaID, err)
+		return
+	}
+
+	livros, err := livroRepo.ListByCategoria(ctx, categoriaID)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar os livros. %v\n", err)
+		return
+	}
+
+	log.Printf("SUCESSO: %d livro(s) em '%s' e suas subcategorias:\n", len(livros), categoria.Nome)
+	for _, l := range livros {
+		fmt.Printf("  %s %s - %s (%s)\n", l.SistemaClassificacao, l.NumeroClassificacao, l.Titulo, l.ISBN)
+	}
+}
+
+func handleContagemCategorias(ctx context.Context, repo repository.CategoriaRepository) {
+	contagens, err := repo.ContarLivros(ctx)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível contar os livros por categoria. %v\n", err)
+		return
+	}
+
+	for _, c := range contagens {
+		fmt.Printf("  %-10s %-40s %d\n", c.Categoria.Codigo, c.Categoria.Nome, c.Quantidade)
+	}
+}
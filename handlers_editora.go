@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// CRUD de Editora
+func handleCreateEditora(ctx context.Context, repo repository.EditoraRepository, reader *bufio.Reader) {
+	cnpj, ok := lerCNPJ(reader, "Digite o CNPJ da editora (numérico ou alfanumérico): ")
+	if !ok {
+		return
+	}
+
+	fmt.Print("Digite o nome da editora: ")
+	nome, _ := reader.ReadString('\n')
+
+	editora := model.Editora{
+		CNPJ: cnpj,
+		Nome: strings.TrimSpace(nome),
+	}
+
+	if !validar(validacao.ValidarEditora(editora)) {
+		return
+	}
+
+	if err := repo.Create(ctx, editora); err != nil {
+		log.Printf("ERRO: Não foi possível criar a editora. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Editora criada. Verifique o banco de dados.")
+	}
+}
+
+func handleReadEditora(ctx context.Context, repo repository.EditoraRepository, reader *bufio.Reader) {
+	cnpj, ok := lerCNPJ(reader, "Digite o CNPJ da editora a ser lida: ")
+	if !ok {
+		return
+	}
+
+	editora, err := repo.GetByCNPJ(ctx, cnpj)
+	if err != nil {
+		log.Printf("ERRO: Editora com CNPJ '%s' não encontrada. %v\n", validacao.FormatarCNPJ(cnpj), err)
+	} else {
+		log.Printf("SUCESSO: Editora encontrada: %s - %s\n", validacao.FormatarCNPJ(editora.CNPJ), editora.Nome)
+	}
+}
+
+func handleUpdateEditora(ctx context.Context, repo repository.EditoraRepository, reader *bufio.Reader) {
+	cnpj, ok := lerCNPJ(reader, "Digite o CNPJ da editora a ser atualizada: ")
+	if !ok {
+		return
+	}
+
+	editora, err := repo.GetByCNPJ(ctx, cnpj)
+	if err != nil {
+		log.Printf("ERRO: Editora com CNPJ '%s' não encontrada para atualizar. %v\n", validacao.FormatarCNPJ(cnpj), err)
+		return
+	}
+
+	fmt.Printf("Digite o novo nome (atual: %s): ", editora.Nome)
+	nome, _ := reader.ReadString('\n')
+	nome = strings.TrimSpace(nome)
+	if nome != "" {
+		editora.Nome = nome
+	}
+
+	if !validar(validacao.ValidarEditora(*editora)) {
+		return
+	}
+
+	if err := repo.Update(ctx, *editora); err != nil {
+		log.Printf("ERRO: Não foi possível atualizar a editora. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Editora atualizada. Verifique o banco de dados.")
+	}
+}
+
+func handleDeleteEditora(ctx context.Context, repo repository.EditoraRepository, reader *bufio.Reader) {
+	cnpj, ok := lerCNPJ(reader, "Digite o CNPJ da editora a ser deletada: ")
+	if !ok {
+		return
+	}
+
+	if err := repo.Delete(ctx, cnpj); err != nil {
+		log.Printf("ERRO: Não foi possível deletar a editora. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Editora deletada. Verifique o banco de dados.")
+	}
+}
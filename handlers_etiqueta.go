@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/etiquetas"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// altura das barras: em módulos no SVG e em pixels no PNG (escala 3)
+const (
+	alturaBarrasSVG = 60
+	alturaBarrasPNG = 180
+	escalaPNG       = 3
+)
+
+// etiquetas e códigos de barras de livros, exemplares (tombo) e carteirinhas
+func handleCodigoBarrasLivro(ctx context.Context, livroRepo repository.LivroRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ISBN do livro: ")
+	isbn, _ := reader.ReadString('\n')
+	isbn = strings.TrimSpace(isbn)
+
+	livro, err := livroRepo.GetByISBN(ctx, isbn)
+	if err != nil {
+		log.Printf("ERRO: Livro com ISBN '%s' não encontrado. %v\n", isbn, err)
+		return
+	}
+
+	fmt.Print("Digite o número de tombo do exemplar (vazio para o código EAN-13 do ISBN): ")
+	tombo, _ := reader.ReadString('\n')
+	codigo, err := codigoLivro(livro.ISBN, strings.TrimSpace(tombo))
+	if err != nil {
+		log.Printf("ERRO: Não foi possível gerar o código de barras. %v\n", err)
+		return
+	}
+
+	destino := lerDestino(reader, codigo.Texto+".svg", ".svg/.png")
+	if err := salvarCodigo(codigo, destino); err != nil {
+		log.Printf("ERRO: Não foi possível salvar o código de barras. %v\n", err)
+	} else {
+		log.Printf("SUCESSO: Código %s de '%s' salvo em '%s'.\n", codigo.Tipo, livro.Titulo, destino)
+	}
+}
+
+// handleCarteirinha gera o código de barras da carteirinha a partir da
+// matrícula UFS ou, para usuários externos, do CPF
+func handleCarteirinha(ctx context.Context, userRepo repository.UsuarioRepository, reader *bufio.Reader) {
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário: ")
+	if !ok {
+		return
+	}
+
+	usuario, err := userRepo.GetByCPF(ctx, cpf)
+	if err != nil {
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(cpf), err)
+		return
+	}
+
+	dados := usuario.Matricula
+	if dados == "" {
+		dados = usuario.CPF
+	}
+	codigo, err := etiquetas.Code128(dados)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível gerar o código de barras. %v\n", err)
+		return
+	}
+
+	destino := lerDestino(reader, "carteirinha-"+usuario.CPF+".pdf", ".svg/.png/.pdf")
+	if strings.EqualFold(filepath.Ext(destino), ".pdf") {
+		etiqueta := etiquetas.Etiqueta{Titulo: usuario.PrimeiroNome + " " + usuario.Sobrenome, Codigo: codigo}
+		err = salvarFolha([]etiquetas.Etiqueta{etiqueta}, destino)
+	} else {
+		err = salvarCodigo(codigo, destino)
+	}
+	if err != nil {
+		log.Printf("ERRO: Não foi possível salvar a carteirinha. %v\n", err)
+	} else {
+		log.Printf("SUCESSO: Carteirinha de %s %s salva em '%s'.\n", usuario.PrimeiroNome, usuario.Sobrenome, destino)
+	}
+}
+
+// handleFolhaEtiquetas monta uma folha A4 em PDF para um lote de livros.
+// Cada linha traz o ISBN e, opcionalmente, o número de tombo do exemplar
+func handleFolhaEtiquetas(ctx context.Context, livroRepo repository.LivroRepository, reader *bufio.Reader) {
+	fmt.Println("Digite um livro por linha no formato 'ISBN [tombo]' (linha vazia para terminar):")
+	var lote []etiquetas.Etiqueta
+	for {
+		linha, _ := reader.ReadString('\n')
+		campos := strings.Fields(linha)
+		if len(campos) == 0 {
+			break
+		}
+
+		livro, err := livroRepo.GetByISBN(ctx, campos[0])
+		if err != nil {
+			log.Printf("AVISO: Livro com ISBN '%s' não encontrado; linha ignorada. %v\n", campos[0], err)
+			continue
+		}
+		tombo := ""
+		if len(campos) > 1 {
+			tombo = campos[1]
+		}
+		codigo, err := codigoLivro(livro.ISBN, tombo)
+		if err != nil {
+			log.Printf("AVISO: Não foi possível gerar o código de '%s'; linha ignorada. %v\n", strings.TrimSpace(linha), err)
+			continue
+		}
+		lote = append(lote, etiquetas.Etiqueta{Titulo: livro.Titulo, Codigo: codigo})
+	}
+	if len(lote) == 0 {
+		log.Println("Nenhuma etiqueta a gerar.")
+		return
+	}
+
+	destino := lerDestino(reader, "etiquetas.pdf", ".pdf")
+	if err := salvarFolha(lote, destino); err != nil {
+		log.Printf("ERRO: Não foi possível salvar a folha de etiquetas. %v\n", err)
+	} else {
+		folhas := (len(lote) + etiquetas.EtiquetasPorFolha - 1) / etiquetas.EtiquetasPorFolha
+		log.Printf("SUCESSO: %d etiqueta(s) em %d folha(s) A4 salvas em '%s'.\n", len(lote), folhas, destino)
+	}
+}
+
+// codigoLivro usa o Code 128 com o número de tombo do exemplar, quando
+// informado, e o EAN-13 do ISBN caso contrário
+func codigoLivro(isbn, tombo string) (*etiquetas.CodigoBarras, error) {
+	if tombo != "" {
+		return etiquetas.Code128(tombo)
+	}
+	return etiquetas.EAN13(isbn)
+}
+
+func lerDestino(reader *bufio.Reader, padrao, formatos string) string {
+	fmt.Printf("Digite o arquivo de destino [%s] (vazio para %s): ", formatos, padrao)
+	destino, _ := reader.ReadString('\n')
+	if destino = strings.TrimSpace(destino); destino == "" {
+		return padrao
+	}
+	return destino
+}
+
+// salvarCodigo grava o código em SVG ou PNG, conforme a extensão do destino
+func salvarCodigo(codigo *etiquetas.CodigoBarras, destino string) error {
+	ext := strings.ToLower(filepath.Ext(destino))
+	if ext != ".svg" && ext != ".png" {
+		return fmt.Errorf("formato '%s' não suportado; use .svg ou .png", ext)
+	}
+
+	f, err := os.Create(destino)
+	if err != nil {
+		return err
+	}
+	if ext == ".png" {
+		err = codigo.PNG(f, escalaPNG, alturaBarrasPNG)
+	} else {
+		err = codigo.SVG(f, alturaBarrasSVG)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func salvarFolha(lote []etiquetas.Etiqueta, destino string) error {
+	f, err := os.Create(destino)
+	if err != nil {
+		return err
+	}
+	err = etiquetas.FolhaPDF(f, lote)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// obras (agrupamento de edições e traduções) e reservas
+func handleCreateObra(ctx context.Context, repo repository.ObraRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da obra (número inteiro): ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	fmt.Print("Digite o título uniforme da obra: ")
+	titulo, _ := reader.ReadString('\n')
+
+	fmt.Print("Digite o idioma original (ex.: pt, en): ")
+	idioma, _ := reader.ReadString('\n')
+
+	obra := model.Obra{
+		ID:             id,
+		Titulo:         strings.TrimSpace(titulo),
+		IdiomaOriginal: strings.ToLower(strings.TrimSpace(idioma)),
+	}
+
+	if !validar(validacao.ValidarObra(obra)) {
+		return
+	}
+
+	if err := repo.Create(ctx, obra); err != nil {
+		log.Printf("ERRO: Não foi possível criar a obra. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Obra criada. Verifique o banco de dados.")
+	}
+}
+
+// handleVincularEdicao associa um livro existente a uma obra como edição ou tradução
+func handleVincularEdicao(ctx context.Context, livroRepo repository.LivroRepository, obraRepo repository.ObraRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ISBN do livro: ")
+	isbn, _ := reader.ReadString('\n')
+	isbn = strings.TrimSpace(isbn)
+
+	livro, err := livroRepo.GetByISBN(ctx, isbn)
+	if err != nil {
+		log.Printf("ERRO: Livro com ISBN '%s' não encontrado. %v\n", isbn, err)
+		return
+	}
+
+	fmt.Print("Digite o ID da obra: ")
+	obraIDStr, _ := reader.ReadString('\n')
+	obraID, err := strconv.Atoi(strings.TrimSpace(obraIDStr))
+	if err != nil {
+		log.Printf("ERRO: ID da obra inválido. %v\n", err)
+		return
+	}
+	if _, err := obraRepo.GetByID(ctx, obraID); err != nil {
+		log.Printf("ERRO: Obra com ID '%d' não encontrada. %v\n", obraID, err)
+		return
+	}
+
+	fmt.Printf("Digite o idioma desta edição (atual: %s): ", livro.Idioma)
+	idioma, _ := reader.ReadString('\n')
+	idioma = strings.ToLower(strings.TrimSpace(idioma))
+	if idioma != "" {
+		livro.Idioma = idioma
+	}
+	livro.ObraID = &obraID
+
+	if !validar(validacao.ValidarLivro(*livro)) {
+		return
+	}
+
+	if err := livroRepo.Update(ctx, *livro); err != nil {
+		log.Printf("ERRO: Não foi possível vincular o livro à obra. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Livro vinculado à obra. Verifique o banco de dados.")
+	}
+}
+
+func handleListEdicoes(ctx context.Context, livroRepo repository.LivroRepository, obraRepo repository.ObraRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da obra: ")
+	obraIDStr, _ := reader.ReadString('\n')
+	obraID, err := strconv.Atoi(strings.TrimSpace(obraIDStr))
+	if err != nil {
+		log.Printf("ERRO: ID da obra inválido. %v\n", err)
+		return
+	}
+
+	obra, err := obraRepo.GetByID(ctx, obraID)
+	if err != nil {
+		log.Printf("ERRO: Obra com ID '%d' não encontrada. %v\n", obraID, err)
+		return
+	}
+
+	edicoes, err := livroRepo.ListByObra(ctx, obraID)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar as edições. %v\n", err)
+		return
+	}
+
+	log.Printf("SUCESSO: %d edição(ões) de '%s':\n", len(edicoes), obra.Titulo)
+	for _, l := range edicoes {
+		fmt.Printf("  %s [%s] %s - %s\n", l.ISBN, l.Idioma, l.Titulo, l.Edicao)
+	}
+}
+
+// handleBuscarLivros busca pelo título e mostra as edições de uma mesma obra juntas
+func handleBuscarLivros(ctx context.Context, livroRepo repository.LivroRepository, obraRepo repository.ObraRepository, reader *bufio.Reader) {
+	fmt.Print("Digite parte do título: ")
+	termo, _ := reader.ReadString('\n')
+	termo = strings.TrimSpace(termo)
+
+	livros, err := livroRepo.Search(ctx, termo)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível buscar os livros. %v\n", err)
+		return
+	}
+
+	grupos := model.AgruparPorObra(livros)
+	log.Printf("SUCESSO: %d resultado(s) para '%s':\n", len(grupos), termo)
+	for _, g := range grupos {
+		if g.ObraID == nil {
+			l := g.Edicoes[0]
+			fmt.Printf("  %s - %s (%s)\n", l.Titulo, l.Edicao, l.ISBN)
+			continue
+		}
+
+		titulo := g.Edicoes[0].Titulo
+		if obra, err := obraRepo.GetByID(ctx, *g.ObraID); err == nil {
+			titulo = obra.Titulo
+		}
+		fmt.Printf("  [obra %d] %s - %d edição(ões)\n", *g.ObraID, titulo, len(g.Edicoes))
+		for _, l := range g.Edicoes {
+			fmt.Printf("      %s [%s] %s - %s\n", l.ISBN, l.Idioma, l.Titulo, l.Edicao)
+		}
+	}
+}
+
+// handleCreateReserva reserva uma edição específica (ISBN) ou qualquer edição de uma obra
+func handleCreateReserva(ctx context.Context, repo repository.ReservaRepository, userRepo repository.UsuarioRepository, livroRepo repository.LivroRepository, obraRepo repository.ObraRepository, categoriaRepo repository.CategoriaRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da reserva (número inteiro): ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário: ")
+	if !ok {
+		return
+	}
+
+	usuario, err := userRepo.GetByCPF(ctx, cpf)
+	if err != nil {
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(cpf), err)
+		return
+	}
+	if !usuario.VinculoAtivo(time.Now()) {
+		log.Printf("ERRO: O vínculo do usuário expirou em %s.\n", usuario.ValidadeVinculo.Format("2006-01-02"))
+		return
+	}
+	if !verificarResponsavel(ctx, userRepo, usuario) {
+		return
+	}
+
+	reserva := model.Reserva{
+		ID:          id,
+		UsuarioCPF:  cpf,
+		DataReserva: time.Now(),
+		Status:      model.ReservaAtiva,
+	}
+
+	fmt.Print("Digite o ISBN da edição desejada (vazio para reservar qualquer edição de uma obra): ")
+	isbn, _ := reader.ReadString('\n')
+	isbn = strings.TrimSpace(isbn)
+
+	if isbn != "" {
+		livro, err := livroRepo.GetByISBN(ctx, isbn)
+		if err != nil {
+			log.Printf("ERRO: Livro com ISBN '%s' não encontrado. %v\n", isbn, err)
+			return
+		}
+		if !verificarMaterial(ctx, categoriaRepo, usuario, *livro) {
+			return
+		}
+		reserva.LivroISBN = livro.ISBN
+	} else {
+		fmt.Print("Digite o ID da obra: ")
+		obraIDStr, _ := reader.ReadString('\n')
+		obraID, err := strconv.Atoi(strings.TrimSpace(obraIDStr))
+		if err != nil {
+			log.Printf("ERRO: ID da obra inválido. %v\n", err)
+			return
+		}
+		if _, err := obraRepo.GetByID(ctx, obraID); err != nil {
+			log.Printf("ERRO: Obra com ID '%d' não encontrada. %v\n", obraID, err)
+			return
+		}
+		// qualquer edição pode atender a reserva, então todas precisam ser permitidas
+		edicoes, err := livroRepo.ListByObra(ctx, obraID)
+		if err != nil {
+			log.Printf("ERRO: Não foi possível listar as edições da obra. %v\n", err)
+			return
+		}
+		for _, l := range edicoes {
+			if !verificarMaterial(ctx, categoriaRepo, usuario, l) {
+				return
+			}
+		}
+		reserva.ObraID = &obraID
+	}
+
+	if !validar(validacao.ValidarReserva(reserva)) {
+		return
+	}
+
+	if err := repo.Create(ctx, reserva); err != nil {
+		log.Printf("ERRO: Não foi possível criar a reserva. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Reserva criada. Verifique o banco de dados.")
+	}
+}
+
+func handleFilaReservas(ctx context.Context, repo repository.ReservaRepository, livroRepo repository.LivroRepository, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, livroRepo, reader, "Digite o ISBN do exemplar disponível: ")
+	if !ok {
+		return
+	}
+
+	fila, err := repo.FilaPorLivro(ctx, isbn)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível consultar a fila de reservas. %v\n", err)
+		return
+	}
+
+	log.Printf("SUCESSO: %d reserva(s) ativa(s) atendíveis pelo livro '%s':\n", len(fila), isbn)
+	for i, r := range fila {
+		alvo := "edição " + r.LivroISBN
+		if r.QualquerEdicao() {
+			alvo = fmt.Sprintf("qualquer edição da obra %d", *r.ObraID)
+		}
+		fmt.Printf("  %d. reserva %d - CPF %s - %s - desde %s\n", i+1, r.ID, validacao.FormatarCPF(r.UsuarioCPF), alvo, r.DataReserva.Format("2006-01-02 15:04"))
+	}
+}
+
+func handleCancelarReserva(ctx context.Context, repo repository.ReservaRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da reserva a ser cancelada: ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	reserva, err := repo.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("ERRO: Reserva com ID '%d' não encontrada. %v\n", id, err)
+		return
+	}
+	reserva.Status = model.ReservaCancelada
+
+	if err := repo.Update(ctx, *reserva); err != nil {
+		log.Printf("ERRO: Não foi possível cancelar a reserva. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Reserva cancelada. Verifique o banco de dados.")
+	}
+}
+
+// handleAtenderReserva registra a retirada do livro reservado, tirando a
+// reserva da fila
+func handleAtenderReserva(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	fmt.Print("Digite o ID da reserva atendida: ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	if _, err := b.AtenderReserva(ctx, id); err != nil {
+		log.Printf("ERRO: Não foi possível atender a reserva. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Reserva atendida. Verifique o banco de dados.")
+	}
+}
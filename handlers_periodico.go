@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// periódicos, fascículos, reclamação de atrasos e circulação por fascículo
+func handleCreatePeriodico(ctx context.Context, repo repository.PeriodicoRepository, reader *bufio.Reader) {
+	fmt.Print("Digite o ISSN (ex.: 1234-5679): ")
+	issn, _ := reader.ReadString('\n')
+
+	fmt.Print("Digite o Título: ")
+	titulo, _ := reader.ReadString('\n')
+
+	editoraCNPJ, ok := lerCNPJ(reader, "Digite o CNPJ da Editora: ")
+	if !ok {
+		return
+	}
+
+	fmt.Print("Digite a quantidade de fascículos por ano (12 = mensal, 4 = trimestral...): ")
+	porAnoStr, _ := reader.ReadString('\n')
+	porAno, err := strconv.Atoi(strings.TrimSpace(porAnoStr))
+	if err != nil || porAno <= 0 {
+		log.Printf("ERRO: Periodicidade inválida: '%s'\n", strings.TrimSpace(porAnoStr))
+		return
+	}
+
+	fmt.Print("Digite o prazo de tolerância para reclamação, em dias (vazio para 30): ")
+	prazoStr, _ := reader.ReadString('\n')
+	prazo := 30
+	if prazoStr = strings.TrimSpace(prazoStr); prazoStr != "" {
+		prazo, err = strconv.Atoi(prazoStr)
+		if err != nil || prazo < 0 {
+			log.Printf("ERRO: Prazo de reclamação inválido: '%s'\n", prazoStr)
+			return
+		}
+	}
+
+	fmt.Print("Digite a data do primeiro fascículo da assinatura (AAAA-MM-DD): ")
+	inicioStr, _ := reader.ReadString('\n')
+	inicio, err := time.Parse("2006-01-02", strings.TrimSpace(inicioStr))
+	if err != nil {
+		log.Printf("Formato de data inválido: %v\n", err)
+		return
+	}
+
+	// grava o ISSN no formato 0000-000X; um ISSN inválido é mantido como
+	// digitado para ser apontado pela validação
+	issn = strings.ToUpper(strings.TrimSpace(issn))
+	if normalizado, err := validacao.NormalizarISSN(issn); err == nil {
+		issn = normalizado
+	}
+
+	periodico := model.Periodico{
+		ISSN:             issn,
+		Titulo:           strings.TrimSpace(titulo),
+		EditoraCNPJ:      editoraCNPJ,
+		FasciculosPorAno: porAno,
+		PrazoReclamacao:  prazo,
+		InicioAssinatura: inicio,
+		Ativo:            true,
+	}
+
+	if !validar(validacao.ValidarPeriodico(periodico)) {
+		return
+	}
+
+	if err := repo.Create(ctx, periodico); err != nil {
+		log.Printf("ERRO: Não foi possível criar o periódico. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Periódico criado. Verifique o banco de dados.")
+	}
+}
+
+// handleGerarPrevisao cria os fascículos esperados a partir do último registrado
+func handleGerarPrevisao(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository, reader *bufio.Reader) {
+	periodico, ok := lerPeriodico(ctx, periodicoRepo, reader)
+	if !ok {
+		return
+	}
+
+	fmt.Print("Gerar previsão até a data (AAAA-MM-DD, vazio para hoje): ")
+	ateStr, _ := reader.ReadString('\n')
+	ate := time.Now()
+	if ateStr = strings.TrimSpace(ateStr); ateStr != "" {
+		var err error
+		ate, err = time.Parse("2006-01-02", ateStr)
+		if err != nil {
+			log.Printf("Formato de data inválido: %v\n", err)
+			return
+		}
+	}
+
+	fasciculos, err := fasciculoRepo.ListByPeriodico(ctx, periodico.ISSN)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar os fascículos. %v\n", err)
+		return
+	}
+	var ultimo *model.Fasciculo
+	if len(fasciculos) > 0 {
+		ultimo = &fasciculos[len(fasciculos)-1]
+	}
+
+	previstos := model.GerarFasciculosEsperados(*periodico, ultimo, ate)
+	for _, f := range previstos {
+		if err := fasciculoRepo.Create(ctx, f); err != nil {
+			log.Printf("ERRO: Não foi possível registrar o fascículo v.%d n.%d. %v\n", f.Volume, f.Numero, err)
+			return
+		}
+	}
+	log.Printf("SUCESSO: %d fascículo(s) esperado(s) registrado(s).\n", len(previstos))
+}
+
+// handleRegistrarRecebimento marca um fascículo como recebido, criando-o se não estava previsto
+func handleRegistrarRecebimento(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository, reader *bufio.Reader) {
+	periodico, ok := lerPeriodico(ctx, periodicoRepo, reader)
+	if !ok {
+		return
+	}
+	volume, numero, ok := lerVolumeNumero(reader)
+	if !ok {
+		return
+	}
+
+	agora := time.Now()
+	id := model.FasciculoID(periodico.ISSN, volume, numero)
+	fasciculo, err := fasciculoRepo.GetByID(ctx, id)
+	if err != nil {
+		// fascículo fora da previsão (ex.: número especial)
+		novo := model.Fasciculo{
+			ID:              id,
+			PeriodicoISSN:   periodico.ISSN,
+			Volume:          volume,
+			Numero:          numero,
+			DataPrevista:    agora,
+			DataRecebimento: &agora,
+			Status:          model.FasciculoRecebido,
+		}
+		if !validar(validacao.ValidarFasciculo(novo)) {
+			return
+		}
+		if err := fasciculoRepo.Create(ctx, novo); err != nil {
+			log.Printf("ERRO: Não foi possível registrar o fascículo. %v\n", err)
+		} else {
+			log.Println("SUCESSO: Fascículo não previsto registrado como recebido.")
+		}
+		return
+	}
+
+	if fasciculo.Status == model.FasciculoRecebido {
+		log.Printf("AVISO: O fascículo v.%d n.%d já havia sido recebido.\n", volume, numero)
+		return
+	}
+	fasciculo.Status = model.FasciculoRecebido
+	fasciculo.DataRecebimento = &agora
+
+	if err := fasciculoRepo.Update(ctx, *fasciculo); err != nil {
+		log.Printf("ERRO: Não foi possível registrar o recebimento. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Recebimento registrado. Verifique o banco de dados.")
+	}
+}
+
+// handleReclamarAtrasados marca como reclamados os fascículos esperados que
+// passaram do prazo de tolerância e lista as reclamações a enviar às editoras
+func handleReclamarAtrasados(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository) {
+	esperados, err := fasciculoRepo.ListByStatus(ctx, model.FasciculoEsperado)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar os fascículos esperados. %v\n", err)
+		return
+	}
+
+	hoje := time.Now()
+	periodicos := make(map[string]*model.Periodico)
+	reclamados := 0
+	for _, f := range esperados {
+		p, ok := periodicos[f.PeriodicoISSN]
+		if !ok {
+			p, err = periodicoRepo.GetByISSN(ctx, f.PeriodicoISSN)
+			if err != nil {
+				log.Printf("ERRO: Periódico '%s' não encontrado. %v\n", f.PeriodicoISSN, err)
+				continue
+			}
+			periodicos[f.PeriodicoISSN] = p
+		}
+		if !f.Atrasado(*p, hoje) {
+			continue
+		}
+
+		f.Status = model.FasciculoReclamado
+		f.DataReclamacao = &hoje
+		if err := fasciculoRepo.Update(ctx, f); err != nil {
+			log.Printf("ERRO: Não foi possível reclamar o fascículo '%s'. %v\n", f.ID, err)
+			continue
+		}
+		reclamados++
+		fmt.Printf("  RECLAMAR: %s (ISSN %s, editora %s) v.%d n.%d - previsto para %s\n",
+			p.Titulo, p.ISSN, validacao.FormatarCNPJ(p.EditoraCNPJ), f.Volume, f.Numero, f.DataPrevista.Format("2006-01-02"))
+	}
+	log.Printf("SUCESSO: %d fascículo(s) reclamado(s).\n", reclamados)
+}
+
+func handleListFasciculos(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository, reader *bufio.Reader) {
+	periodico, ok := lerPeriodico(ctx, periodicoRepo, reader)
+	if !ok {
+		return
+	}
+
+	fasciculos, err := fasciculoRepo.ListByPeriodico(ctx, periodico.ISSN)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar os fascículos. %v\n", err)
+		return
+	}
+
+	log.Printf("SUCESSO: %d fascículo(s) de '%s':\n", len(fasciculos), periodico.Titulo)
+	for _, f := range fasciculos {
+		situacao := f.Status
+		if f.EmprestadoCPF != "" {
+			situacao += ", emprestado para " + validacao.FormatarCPF(f.EmprestadoCPF)
+		}
+		fmt.Printf("  v.%d n.%d - previsto %s - %s\n", f.Volume, f.Numero, f.DataPrevista.Format("2006-01-02"), situacao)
+	}
+}
+
+func handleEmprestarFasciculo(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository, userRepo repository.UsuarioRepository, reader *bufio.Reader) {
+	periodico, ok := lerPeriodico(ctx, periodicoRepo, reader)
+	if !ok {
+		return
+	}
+	volume, numero, ok := lerVolumeNumero(reader)
+	if !ok {
+		return
+	}
+
+	fasciculo, err := fasciculoRepo.GetByID(ctx, model.FasciculoID(periodico.ISSN, volume, numero))
+	if err != nil {
+		log.Printf("ERRO: Fascículo v.%d n.%d não encontrado. %v\n", volume, numero, err)
+		return
+	}
+	if fasciculo.EmprestadoCPF != "" {
+		log.Printf("ERRO: Fascículo já emprestado para o CPF '%s'.\n", validacao.FormatarCPF(fasciculo.EmprestadoCPF))
+		return
+	}
+	if !fasciculo.Disponivel() {
+		log.Printf("ERRO: Fascículo indisponível (status: %s).\n", fasciculo.Status)
+		return
+	}
+
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário: ")
+	if !ok {
+		return
+	}
+
+	agora := time.Now()
+	usuario, err := userRepo.GetByCPF(ctx, cpf)
+	if err != nil {
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(cpf), err)
+		return
+	}
+	if err := usuario.PodeEmprestar(0, 0, agora); err != nil {
+		log.Printf("ERRO: Empréstimo não permitido. %v\n", err)
+		return
+	}
+	if !verificarResponsavel(ctx, userRepo, usuario) {
+		return
+	}
+
+	fasciculo.EmprestadoCPF = cpf
+	fasciculo.DataEmprestimo = &agora
+	if err := fasciculoRepo.Update(ctx, *fasciculo); err != nil {
+		log.Printf("ERRO: Não foi possível emprestar o fascículo. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Fascículo emprestado. Verifique o banco de dados.")
+	}
+}
+
+func handleDevolverFasciculo(ctx context.Context, periodicoRepo repository.PeriodicoRepository, fasciculoRepo repository.FasciculoRepository, reader *bufio.Reader) {
+	periodico, ok := lerPeriodico(ctx, periodicoRepo, reader)
+	if !ok {
+		return
+	}
+	volume, numero, ok := lerVolumeNumero(reader)
+	if !ok {
+		return
+	}
+
+	fasciculo, err := fasciculoRepo.GetByID(ctx, model.FasciculoID(periodico.ISSN, volume, numero))
+	if err != nil {
+		log.Printf("ERRO: Fascículo v.%d n.%d não encontrado. %v\n", volume, numero, err)
+		return
+	}
+	if fasciculo.EmprestadoCPF == "" {
+		log.Println("AVISO: O fascículo não está emprestado.")
+		return
+	}
+
+	fasciculo.EmprestadoCPF = ""
+	fasciculo.DataEmprestimo = nil
+	if err := fasciculoRepo.Update(ctx, *fasciculo); err != nil {
+		log.Printf("ERRO: Não foi possível registrar a devolução. %v\n", err)
+	} else {
+		log.Println("SUCESSO: Devolução registrada. Verifique o banco de dados.")
+	}
+}
+
+func lerPeriodico(ctx context.Context, repo repository.PeriodicoRepository, reader *bufio.Reader) (*model.Periodico, bool) {
+	fmt.Print("Digite o ISSN do periódico: ")
+	issn, _ := reader.ReadString('\n')
+	issn = strings.ToUpper(strings.TrimSpace(issn))
+	if normalizado, err := validacao.NormalizarISSN(issn); err == nil {
+		issn = normalizado
+	}
+
+	periodico, err := repo.GetByISSN(ctx, issn)
+	if err != nil {
+		log.Printf("ERRO: Periódico com ISSN '%s' não encontrado. %v\n", issn, err)
+		return nil, false
+	}
+	return periodico, true
+}
+
+func lerVolumeNumero(reader *bufio.Reader) (int, int, bool) {
+	fmt.Print("Digite o volume: ")
+	volumeStr, _ := reader.ReadString('\n')
+	volume, err := strconv.Atoi(strings.TrimSpace(volumeStr))
+	if err != nil {
+		log.Printf("ERRO: Volume inválido. %v\n", err)
+		return 0, 0, false
+	}
+
+	fmt.Print("Digite o número: ")
+	numeroStr, _ := reader.ReadString('\n')
+	numero, err := strconv.Atoi(strings.TrimSpace(numeroStr))
+	if err != nil {
+		log.Printf("ERRO: Número inválido. %v\n", err)
+		return 0, 0, false
+	}
+	return volume, numero, true
+}
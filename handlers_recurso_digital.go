@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/armazenamento"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recursos digitais: os arquivos ficam no armazenamento local e os metadados no banco
+func handleAnexarRecurso(ctx context.Context, repo repository.RecursoDigitalRepository, livroRepo repository.LivroRepository, arm *armazenamento.Local, reader *bufio.Reader) {
+	fmt.Print("Digite o ID do recurso (número inteiro): ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	isbn, ok := lerISBNCadastrado(ctx, livroRepo, reader, "Digite o ISBN do livro: ")
+	if !ok {
+		return
+	}
+
+	fmt.Printf("Digite o tipo do recurso [%s]: ", strings.Join(model.TiposRecursoDigital, "/"))
+	tipo, _ := reader.ReadString('\n')
+	tipo = strings.ToLower(strings.TrimSpace(tipo))
+	tipoValido := false
+	for _, t := range model.TiposRecursoDigital {
+		if t == tipo {
+			tipoValido = true
+		}
+	}
+	if !tipoValido {
+		log.Printf("ERRO: Tipo de recurso inválido: '%s'\n", tipo)
+		return
+	}
+
+	fmt.Print("Digite o caminho do arquivo a ser anexado: ")
+	origem, _ := reader.ReadString('\n')
+	origem = strings.TrimSpace(origem)
+
+	f, err := os.Open(origem)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível abrir o arquivo. %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	salvo, err := arm.Salvar(f, filepath.Base(origem), isbn)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível armazenar o arquivo. %v\n", err)
+		return
+	}
+
+	recurso := model.RecursoDigital{
+		ID:          id,
+		LivroISBN:   isbn,
+		Tipo:        tipo,
+		NomeArquivo: filepath.Base(origem),
+		Caminho:     salvo.Caminho,
+		MIME:        salvo.MIME,
+		Tamanho:     salvo.Tamanho,
+		SHA256:      salvo.SHA256,
+		EnviadoEm:   time.Now(),
+	}
+
+	if !validar(validacao.ValidarRecursoDigital(recurso)) {
+		removerArquivoOrfao(ctx, repo, arm, recurso)
+		return
+	}
+
+	if err := repo.Create(ctx, recurso); err != nil {
+		log.Printf("ERRO: Não foi possível registrar o recurso. %v\n", err)
+		removerArquivoOrfao(ctx, repo, arm, recurso)
+	} else {
+		log.Printf("SUCESSO: Recurso anexado (%s, %d bytes, sha256 %s).\n", salvo.MIME, salvo.Tamanho, salvo.SHA256)
+	}
+}
+
+func handleListRecursos(ctx context.Context, repo repository.RecursoDigitalRepository, livroRepo repository.LivroRepository, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, livroRepo, reader, "Digite o ISBN do livro: ")
+	if !ok {
+		return
+	}
+
+	recursos, err := repo.ListByLivro(ctx, isbn)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível listar os recursos. %v\n", err)
+		return
+	}
+
+	log.Printf("SUCESSO: %d recurso(s) digital(is) para o livro '%s':\n", len(recursos), isbn)
+	for _, r := range recursos {
+		fmt.Printf("  [%d] %-12s %-40s %-22s %d bytes\n", r.ID, r.Tipo, r.NomeArquivo, r.MIME, r.Tamanho)
+	}
+}
+
+// handleBaixarRecurso copia o arquivo para o destino informado; somente
+// usuários com vínculo ativo podem baixar recursos digitais
+func handleBaixarRecurso(ctx context.Context, repo repository.RecursoDigitalRepository, userRepo repository.UsuarioRepository, livroRepo repository.LivroRepository, categoriaRepo repository.CategoriaRepository, arm *armazenamento.Local, reader *bufio.Reader) {
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário: ")
+	if !ok {
+		return
+	}
+
+	usuario, err := userRepo.GetByCPF(ctx, cpf)
+	if err != nil {
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(cpf), err)
+		return
+	}
+	if !usuario.VinculoAtivo(time.Now()) {
+		log.Printf("ERRO: Acesso negado. O vínculo do usuário expirou em %s.\n", usuario.ValidadeVinculo.Format("2006-01-02"))
+		return
+	}
+	if !verificarResponsavel(ctx, userRepo, usuario) {
+		return
+	}
+
+	fmt.Print("Digite o ID do recurso: ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	recurso, err := repo.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("ERRO: Recurso com ID '%d' não encontrado. %v\n", id, err)
+		return
+	}
+	livro, err := livroRepo.GetByISBN(ctx, recurso.LivroISBN)
+	if err != nil {
+		log.Printf("ERRO: Livro do recurso não encontrado. %v\n", err)
+		return
+	}
+	if !verificarMaterial(ctx, categoriaRepo, usuario, *livro) {
+		return
+	}
+
+	fmt.Printf("Digite o caminho de destino (vazio para ./%s): ", recurso.NomeArquivo)
+	destino, _ := reader.ReadString('\n')
+	destino = strings.TrimSpace(destino)
+	if destino == "" {
+		destino = recurso.NomeArquivo
+	}
+
+	out, err := os.Create(destino)
+	if err != nil {
+		log.Printf("ERRO: Não foi possível criar o arquivo de destino. %v\n", err)
+		return
+	}
+	err = arm.Copiar(recurso.Caminho, recurso.SHA256, out)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(destino)
+		log.Printf("ERRO: Não foi possível baixar o recurso. %v\n", err)
+	} else {
+		log.Printf("SUCESSO: Recurso salvo em '%s'.\n", destino)
+	}
+}
+
+func handleDeleteRecurso(ctx context.Context, repo repository.RecursoDigitalRepository, arm *armazenamento.Local, reader *bufio.Reader) {
+	fmt.Print("Digite o ID do recurso a ser deletado: ")
+	idStr, _ := reader.ReadString('\n')
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil {
+		log.Printf("ERRO: ID inválido. %v\n", err)
+		return
+	}
+
+	recurso, err := repo.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("ERRO: Recurso com ID '%d' não encontrado. %v\n", id, err)
+		return
+	}
+
+	if err := repo.Delete(ctx, id); err != nil {
+		log.Printf("ERRO: Não foi possível deletar o recurso. %v\n", err)
+		return
+	}
+	removerArquivoOrfao(ctx, repo, arm, *recurso)
+	log.Println("SUCESSO: Recurso deletado. Verifique o banco de dados.")
+}
+
+// removerArquivoOrfao apaga o arquivo do recurso se nenhum outro registro do
+// mesmo livro aponta para ele (arquivos iguais compartilham o mesmo caminho)
+func removerArquivoOrfao(ctx context.Context, repo repository.RecursoDigitalRepository, arm *armazenamento.Local, recurso model.RecursoDigital) {
+	restantes, err := repo.ListByLivro(ctx, recurso.LivroISBN)
+	if err != nil {
+		log.Printf("AVISO: Não foi possível verificar o uso do arquivo '%s'. %v\n", recurso.Caminho, err)
+		return
+	}
+	for _, r := range restantes {
+		if r.Caminho == recurso.Caminho {
+			return
+		}
+	}
+	if err := arm.Remover(recurso.Caminho); err != nil {
+		log.Printf("AVISO: Não foi possível remover o arquivo '%s'. %v\n", recurso.Caminho, err)
+	}
+}
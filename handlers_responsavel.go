@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// responsáveis por usuários menores de idade e restrição de material
+
+// lerResponsavel pede o CPF do responsável por um menor de idade e confere se
+// ele está cadastrado e é maior de idade. Em branco mantém o responsável atual
+func lerResponsavel(ctx context.Context, repo repository.UsuarioRepository, reader *bufio.Reader, usuario model.Usuario) (string, bool) {
+	if usuario.ResponsavelCPF != "" {
+		fmt.Printf("Digite o CPF do responsável pelo menor (atual: %s): ", validacao.FormatarCPF(usuario.ResponsavelCPF))
+	} else {
+		fmt.Print("Usuário menor de idade. Digite o CPF do responsável: ")
+	}
+	cpf, _ := reader.ReadString('\n')
+	cpf = strings.TrimSpace(cpf)
+	if cpf == "" && usuario.ResponsavelCPF != "" {
+		return usuario.ResponsavelCPF, true
+	}
+
+	normalizado, err := validacao.NormalizarCPF(cpf)
+	if err != nil {
+		log.Printf("ERRO: CPF inválido: '%s'. %v\n", cpf, err)
+		return "", false
+	}
+	if normalizado == usuario.CPF {
+		log.Println("ERRO: O usuário não pode ser o próprio responsável.")
+		return "", false
+	}
+	responsavel, err := repo.GetByCPF(ctx, normalizado)
+	if err != nil {
+		log.Printf("ERRO: Responsável com CPF '%s' não encontrado. Cadastre o responsável antes do menor. %v\n", validacao.FormatarCPF(normalizado), err)
+		return "", false
+	}
+	if responsavel.MenorDeIdade(time.Now()) {
+		log.Println("ERRO: O responsável deve ser maior de idade.")
+		return "", false
+	}
+	return normalizado, true
+}
+
+// verificarResponsavel confere, antes de empréstimos, reservas e downloads, se
+// o menor de idade tem um responsável apto e informa quem responde por ele
+func verificarResponsavel(ctx context.Context, repo repository.UsuarioRepository, usuario *model.Usuario) bool {
+	agora := time.Now()
+	if !usuario.MenorDeIdade(agora) {
+		return true
+	}
+
+	var responsavel *model.Usuario
+	if usuario.ResponsavelCPF != "" {
+		var err error
+		responsavel, err = repo.GetByCPF(ctx, usuario.ResponsavelCPF)
+		if err != nil {
+			log.Printf("ERRO: Responsável com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(usuario.ResponsavelCPF), err)
+			return false
+		}
+	}
+	if err := usuario.ValidarResponsavel(responsavel, agora); err != nil {
+		log.Printf("ERRO: Operação não permitida. %v\n", err)
+		return false
+	}
+	log.Printf("Usuário menor de idade. Responsável pelos empréstimos e multas: %s %s (CPF %s)\n",
+		responsavel.PrimeiroNome, responsavel.Sobrenome, validacao.FormatarCPF(responsavel.CPF))
+	return true
+}
+
+// exibirResponsavel mostra o responsável de um menor de idade e, para um
+// responsável, os menores pelos quais ele responde
+func exibirResponsavel(ctx context.Context, repo repository.UsuarioRepository, usuario model.Usuario) {
+	agora := time.Now()
+	if usuario.MenorDeIdade(agora) {
+		if usuario.ResponsavelCPF == "" {
+			log.Printf("AVISO: Usuário menor de idade (%d anos) sem responsável cadastrado.\n", usuario.Idade(agora))
+		} else if responsavel, err := repo.GetByCPF(ctx, usuario.ResponsavelCPF); err != nil {
+			log.Printf("AVISO: Responsável com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(usuario.ResponsavelCPF), err)
+		} else {
+			fmt.Printf("  Menor de idade (%d anos). Responsável: %s %s (CPF %s, telefone %s)\n", usuario.Idade(agora),
+				responsavel.PrimeiroNome, responsavel.Sobrenome, validacao.FormatarCPF(responsavel.CPF), responsavel.Telefone)
+		}
+	}
+
+	dependentes, err := repo.ListByResponsavel(ctx, usuario.CPF)
+	if err != nil {
+		log.Printf("AVISO: Não foi possível listar os dependentes. %v\n", err)
+		return
+	}
+	for _, d := range dependentes {
+		fmt.Printf("  Responsável por: %s %s (CPF %s)\n", d.PrimeiroNome, d.Sobrenome, validacao.FormatarCPF(d.CPF))
+	}
+}
+
+// verificarMaterial impede que menores de idade retirem livros de categorias
+// restritas, considerando também as categorias superiores
+func verificarMaterial(ctx context.Context, repo repository.CategoriaRepository, usuario *model.Usuario, livro model.Livro) bool {
+	if !usuario.MenorDeIdade(time.Now()) {
+		return true
+	}
+
+	var categorias []model.Categoria
+	visitadas := make(map[int]bool)
+	pendentes := append([]int(nil), livro.Categorias...)
+	for len(pendentes) > 0 {
+		id := pendentes[0]
+		pendentes = pendentes[1:]
+		if visitadas[id] {
+			continue
+		}
+		visitadas[id] = true
+
+		categoria, err := repo.GetByID(ctx, id)
+		if err != nil {
+			log.Printf("ERRO: Não foi possível verificar a categoria '%d' do livro. %v\n", id, err)
+			return false
+		}
+		categorias = append(categorias, *categoria)
+		if categoria.PaiID != nil {
+			pendentes = append(pendentes, *categoria.PaiID)
+		}
+	}
+
+	if err := usuario.PodeRetirar(categorias, time.Now()); err != nil {
+		log.Printf("ERRO: Material não permitido para '%s'. %v\n", livro.Titulo, err)
+		return false
+	}
+	return true
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"bufio"
 	"context"
-	"crud-biblioteca/database"
+	"crud-biblioteca/armazenamento"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repl"
 	"crud-biblioteca/repository"
-	mongoRepo "crud-biblioteca/repository/mongo"
-	postgresRepo "crud-biblioteca/repository/postgres"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/tui"
+	"crud-biblioteca/validacao"
+	"crud-biblioteca/webhooks"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -25,48 +31,117 @@ func main() {
 	ctx := context.Background()
 	reader := bufio.NewReader(os.Stdin)
 
+	// -banco dispensa a pergunta inicial; -http e -grpc sobem os servidores em vez do menu
+	banco := flag.String("banco", "", "banco de dados: postgres ou mongo")
+	enderecoHTTP := flag.String("http", "", "endereço da API REST (ex.: :8080); sem ele o menu interativo é aberto")
+	enderecoGRPC := flag.String("grpc", "", "endereço do serviço gRPC (ex.: :9090)")
+	menuNumerado := flag.Bool("menu", false, "abre o menu numerado em vez da interface de tela cheia")
+	receptor := flag.String("receptor", "", "sobe um destino local de testes para os webhooks no endereço (ex.: :9099)")
+	segredoReceptor := flag.String("segredo", "", "com -receptor, segredo da assinatura usado para conferir as entregas")
+	falhasReceptor := flag.Int("falhas", 0, "com -receptor, responde 503 às primeiras n tentativas de cada entrega")
+	modoRelay := flag.Bool("relay", false, "publica os eventos do outbox nos destinos de EVENTOS_DESTINOS em vez de abrir o menu")
+	modoComandos := flag.Bool("repl", false, "abre a linha de comandos (emprestar, devolver, buscar...) em vez da interface de tela cheia")
+	flag.Parse()
+
+	// o receptor não usa o banco: só mostra o que os webhooks enviam
+	if *receptor != "" {
+		log.Printf("Receptor de webhooks em http://%s/\n", *receptor)
+		log.Fatal(http.ListenAndServe(*receptor, webhooks.NovoReceptor(*segredoReceptor, *falhasReceptor, os.Stdout)))
+	}
+
+	// "biblioteca usuario get <cpf>" e afins rodam um único comando, sem o menu
+	if flag.NArg() > 0 {
+		os.Exit(executarComando(ctx, *banco, flag.Args()))
+	}
+
+	modoServidor := *enderecoHTTP != "" || *enderecoGRPC != ""
+	if *banco == "" && modoServidor {
+		log.Fatal("Informe o banco de dados do servidor com -banco postgres ou -banco mongo.")
+	}
+	if *banco == "" && *modoRelay {
+		log.Fatal("Informe o banco de dados do relay com -banco postgres ou -banco mongo.")
+	}
+
 	// escolha do banco de dados
-	var userRepo repository.UsuarioRepository
-	var livroRepo repository.LivroRepository
-	var autorRepo repository.AutorRepository
-	var emprestimoRepo repository.EmprestimoRepository
-
-	fmt.Println("Bem-vindo ao sistema de gerenciamento da biblioteca!")
-	fmt.Println("Qual banco de dados você deseja usar?")
-	fmt.Println("1: PostgreSQL")
-	fmt.Println("2: MongoDB")
-	fmt.Print("Escolha uma opção: ")
-
-	choice, _ := reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
-
-	switch choice {
-	case "1":
-		log.Println("Conectando ao PostgreSQL...")
-		pgConn, err := database.ConnectPostgres()
+	if *banco == "" {
+		fmt.Println("Bem-vindo ao sistema de gerenciamento da biblioteca!")
+		fmt.Println("Qual banco de dados você deseja usar?")
+		fmt.Println("1: PostgreSQL")
+		fmt.Println("2: MongoDB")
+		fmt.Print("Escolha uma opção: ")
+
+		choice, _ := reader.ReadString('\n')
+		switch strings.TrimSpace(choice) {
+		case "1":
+			*banco = bancoPostgres
+		case "2":
+			*banco = bancoMongo
+		default:
+			log.Fatal("Opção inválida. Saindo.")
+			return
+		}
+	}
+
+	repos, fechar, err := conectar(ctx, *banco)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fechar()
+
+	if modoServidor {
+		if err := servir(ctx, repos, *enderecoHTTP, *enderecoGRPC); err != nil {
+			log.Printf("ERRO: %v\n", err)
+		}
+		return
+	}
+	if *modoRelay {
+		if err := executarRelay(ctx, repos); err != nil {
+			log.Printf("ERRO: %v\n", err)
+		}
+		return
+	}
+
+	// em um terminal a interface de tela cheia substitui o menu, e -repl o
+	// troca pela linha de comandos; o menu numerado continua disponível com
+	// -menu, pela tecla "m", pelo comando "menu" ou com a entrada redirecionada
+	if *modoComandos {
+		menu, err := repl.Executar(ctx, servico.New(repos))
 		if err != nil {
-			os.Exit(1)
+			log.Printf("ERRO: %v\n", err)
 		}
-		defer pgConn.Close(ctx)
-		userRepo = postgresRepo.NewUsuarioRepository(pgConn)
-		livroRepo = postgresRepo.NewLivroRepository(pgConn)
-		autorRepo = postgresRepo.NewAutorRepository(pgConn)
-		emprestimoRepo = postgresRepo.NewEmprestimoRepository(pgConn)
-	case "2":
-		log.Println("Conectando ao MongoDB...")
-		mongoClient, err := database.ConnectMongoDB()
+		if !menu {
+			return
+		}
+	} else if !*menuNumerado && terminal(os.Stdin) && terminal(os.Stdout) {
+		// mensagens de log desenhariam por cima da tela
+		saidaLog := log.Writer()
+		log.SetOutput(io.Discard)
+		menu, err := tui.Executar(ctx, servico.New(repos))
+		log.SetOutput(saidaLog)
 		if err != nil {
-			os.Exit(1)
+			log.Printf("ERRO: %v\n", err)
 		}
-		defer mongoClient.Disconnect(ctx)
-		db := mongoClient.Database("bibliotecaDB")
-		userRepo = mongoRepo.NewUsuarioRepository(db)
-		livroRepo = mongoRepo.NewLivroRepository(db)
-		autorRepo = mongoRepo.NewAutorRepository(db)
-		emprestimoRepo = mongoRepo.NewEmprestimoRepository(db)
-	default:
-		log.Fatal("Opção inválida. Saindo.")
-		return
+		if !menu {
+			return
+		}
+	}
+
+	userRepo := repos.Usuarios
+	livroRepo := repos.Livros
+	emprestimoRepo := repos.Emprestimos
+	categoriaRepo := repos.Categorias
+	recursoRepo := repos.RecursosDigitais
+	obraRepo := repos.Obras
+	reservaRepo := repos.Reservas
+	periodicoRepo := repos.Periodicos
+	fasciculoRepo := repos.Fasciculos
+	editoraRepo := repos.Editoras
+	biblioteca := servico.New(repos)
+
+	// diretório onde ficam os arquivos dos recursos digitais
+	armazenamentoLocal, err := armazenamento.NewLocalFromEnv()
+	if err != nil {
+		log.Fatalf("Não foi possível configurar o armazenamento de recursos digitais: %v", err)
 	}
 
 	// menu principal
@@ -88,6 +163,45 @@ func main() {
 		fmt.Println("11: Ler Empréstimo por ID")
 		fmt.Println("12: Atualizar Empréstimo")
 		fmt.Println("13: Deletar Empréstimo")
+		fmt.Println("--- Classificação por Assunto ---")
+		fmt.Println("14: Criar Categoria")
+		fmt.Println("15: Navegar pelas Categorias")
+		fmt.Println("16: Classificar Livro em uma Categoria")
+		fmt.Println("17: Remover Livro de uma Categoria")
+		fmt.Println("18: Listar Livros por Categoria")
+		fmt.Println("19: Contagem de Livros por Categoria")
+		fmt.Println("--- Recursos Digitais ---")
+		fmt.Println("20: Anexar Recurso Digital a um Livro")
+		fmt.Println("21: Listar Recursos Digitais de um Livro")
+		fmt.Println("22: Baixar Recurso Digital")
+		fmt.Println("23: Deletar Recurso Digital")
+		fmt.Println("--- Obras, Edições e Reservas ---")
+		fmt.Println("24: Criar Obra")
+		fmt.Println("25: Vincular Livro a uma Obra (edição/tradução)")
+		fmt.Println("26: Listar Edições de uma Obra")
+		fmt.Println("27: Buscar Livros por Título (edições agrupadas)")
+		fmt.Println("28: Criar Reserva (edição específica ou qualquer edição)")
+		fmt.Println("29: Fila de Reservas de um Livro")
+		fmt.Println("30: Cancelar Reserva")
+		fmt.Println("--- Periódicos e Fascículos ---")
+		fmt.Println("31: Criar Periódico")
+		fmt.Println("32: Gerar Previsão de Fascículos")
+		fmt.Println("33: Registrar Recebimento de Fascículo")
+		fmt.Println("34: Reclamar Fascículos Atrasados")
+		fmt.Println("35: Listar Fascículos de um Periódico")
+		fmt.Println("36: Emprestar Fascículo")
+		fmt.Println("37: Devolver Fascículo")
+		fmt.Println("--- Entidade: Editora ---")
+		fmt.Println("38: Criar Editora")
+		fmt.Println("39: Ler Editora por CNPJ")
+		fmt.Println("40: Atualizar Editora")
+		fmt.Println("41: Deletar Editora")
+		fmt.Println("--- Etiquetas e Códigos de Barras ---")
+		fmt.Println("42: Gerar Código de Barras de Livro/Exemplar (SVG/PNG)")
+		fmt.Println("43: Gerar Carteirinha de Usuário")
+		fmt.Println("44: Gerar Folha A4 de Etiquetas (PDF)")
+		fmt.Println("--- Atendimento de Reservas ---")
+		fmt.Println("45: Atender Reserva (retirada do livro reservado)")
 		fmt.Println("-------------------------------")
 		fmt.Println("0: Sair")
 		fmt.Print("Escolha uma opção: ")
@@ -97,31 +211,95 @@ func main() {
 
 		switch op {
 		case "1":
-			handleCreateUsuario(ctx, userRepo, reader)
+			handleCreateUsuario(ctx, biblioteca, reader)
 		case "2":
 			handleReadUsuario(ctx, userRepo, reader)
 		case "3":
-			handleUpdateUsuario(ctx, userRepo, reader)
+			handleUpdateUsuario(ctx, biblioteca, reader)
 		case "4":
-			handleDeleteUsuario(ctx, userRepo, reader)
+			handleDeleteUsuario(ctx, biblioteca, reader)
 		case "5":
-			handleCreateLivro(ctx, livroRepo, reader)
+			handleCreateLivro(ctx, biblioteca, reader)
 		case "6":
 			handleReadLivro(ctx, livroRepo, reader)
 		case "7":
-			handleDeleteLivro(ctx, livroRepo, reader)
+			handleDeleteLivro(ctx, biblioteca, reader)
 		case "8":
-			handleAddAutorRelacionamento(ctx, livroRepo, autorRepo, reader)
+			handleAddAutorRelacionamento(ctx, biblioteca, reader)
 		case "9":
-			handleRemoveAutorRelacionamento(ctx, livroRepo, autorRepo, reader)
+			handleRemoveAutorRelacionamento(ctx, biblioteca, reader)
 		case "10":
-			handleCreateEmprestimo(ctx, emprestimoRepo, reader)
+			handleCreateEmprestimo(ctx, biblioteca, reader)
 		case "11":
-			handleReadEmprestimo(ctx, emprestimoRepo, reader)
+			handleReadEmprestimo(ctx, emprestimoRepo, userRepo, reader)
 		case "12":
-			handleUpdateEmprestimo(ctx, emprestimoRepo, reader)
+			handleUpdateEmprestimo(ctx, biblioteca, reader)
 		case "13":
-			handleDeleteEmprestimo(ctx, emprestimoRepo, reader)
+			handleDeleteEmprestimo(ctx, biblioteca, reader)
+		case "14":
+			handleCreateCategoria(ctx, categoriaRepo, reader)
+		case "15":
+			handleNavegarCategorias(ctx, categoriaRepo, reader)
+		case "16":
+			handleClassificarLivro(ctx, livroRepo, categoriaRepo, reader)
+		case "17":
+			handleDesclassificarLivro(ctx, livroRepo, reader)
+		case "18":
+			handleListLivrosPorCategoria(ctx, livroRepo, categoriaRepo, reader)
+		case "19":
+			handleContagemCategorias(ctx, categoriaRepo)
+		case "20":
+			handleAnexarRecurso(ctx, recursoRepo, livroRepo, armazenamentoLocal, reader)
+		case "21":
+			handleListRecursos(ctx, recursoRepo, livroRepo, reader)
+		case "22":
+			handleBaixarRecurso(ctx, recursoRepo, userRepo, livroRepo, categoriaRepo, armazenamentoLocal, reader)
+		case "23":
+			handleDeleteRecurso(ctx, recursoRepo, armazenamentoLocal, reader)
+		case "24":
+			handleCreateObra(ctx, obraRepo, reader)
+		case "25":
+			handleVincularEdicao(ctx, livroRepo, obraRepo, reader)
+		case "26":
+			handleListEdicoes(ctx, livroRepo, obraRepo, reader)
+		case "27":
+			handleBuscarLivros(ctx, livroRepo, obraRepo, reader)
+		case "28":
+			handleCreateReserva(ctx, reservaRepo, userRepo, livroRepo, obraRepo, categoriaRepo, reader)
+		case "29":
+			handleFilaReservas(ctx, reservaRepo, livroRepo, reader)
+		case "30":
+			handleCancelarReserva(ctx, reservaRepo, reader)
+		case "31":
+			handleCreatePeriodico(ctx, periodicoRepo, reader)
+		case "32":
+			handleGerarPrevisao(ctx, periodicoRepo, fasciculoRepo, reader)
+		case "33":
+			handleRegistrarRecebimento(ctx, periodicoRepo, fasciculoRepo, reader)
+		case "34":
+			handleReclamarAtrasados(ctx, periodicoRepo, fasciculoRepo)
+		case "35":
+			handleListFasciculos(ctx, periodicoRepo, fasciculoRepo, reader)
+		case "36":
+			handleEmprestarFasciculo(ctx, periodicoRepo, fasciculoRepo, userRepo, reader)
+		case "37":
+			handleDevolverFasciculo(ctx, periodicoRepo, fasciculoRepo, reader)
+		case "38":
+			handleCreateEditora(ctx, editoraRepo, reader)
+		case "39":
+			handleReadEditora(ctx, editoraRepo, reader)
+		case "40":
+			handleUpdateEditora(ctx, editoraRepo, reader)
+		case "41":
+			handleDeleteEditora(ctx, editoraRepo, reader)
+		case "42":
+			handleCodigoBarrasLivro(ctx, livroRepo, reader)
+		case "43":
+			handleCarteirinha(ctx, userRepo, reader)
+		case "44":
+			handleFolhaEtiquetas(ctx, livroRepo, reader)
+		case "45":
+			handleAtenderReserva(ctx, biblioteca, reader)
 		case "0":
 			log.Println("Saindo do sistema. Até logo!")
 			return
@@ -133,7 +311,8 @@ func main() {
 
 // funções auxiliares
 // CRUD de Empréstimo
-func handleCreateEmprestimo(ctx context.Context, repo repository.EmprestimoRepository, reader *bufio.Reader) {
+// as regras do perfil do usuário e do responsável são conferidas pelo pacote servico
+func handleCreateEmprestimo(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
 	fmt.Print("Digite o ID do empréstimo (número inteiro): ")
 	idStr, _ := reader.ReadString('\n')
 	idStr = strings.TrimSpace(idStr)
@@ -149,35 +328,32 @@ func handleCreateEmprestimo(ctx context.Context, repo repository.EmprestimoRepos
 	status, _ := reader.ReadString('\n')
 	status = strings.TrimSpace(status)
 
-	fmt.Print("Digite a quantidade de livros: ")
-	quantLivrosStr, _ := reader.ReadString('\n')
-	quantLivrosStr = strings.TrimSpace(quantLivrosStr)
-	quantLivros, err := strconv.Atoi(quantLivrosStr)
-	if err != nil {
-		log.Printf("ERRO: Quantidade de livros inválida. %v\n", err)
+	// a quantidade de livros é a dos ISBNs, que o servico confere
+	fmt.Print("Digite os ISBNs dos livros, separados por vírgula: ")
+	isbns, _ := reader.ReadString('\n')
+	livros := servico.ListaISBNs(isbns)
+	if len(livros) == 0 {
+		log.Println("ERRO: Informe ao menos um livro.")
 		return
 	}
 
-	fmt.Print("Digite o CPF do cliente/usuário: ")
-	clienteCPF, _ := reader.ReadString('\n')
-	clienteCPF = strings.TrimSpace(clienteCPF)
+	clienteCPF, ok := lerCPF(reader, "Digite o CPF do cliente/usuário: ")
+	if !ok {
+		return
+	}
 
-	novoEmprestimo := model.Emprestimo{
+	_, err = b.CriarEmprestimo(ctx, model.Emprestimo{
 		ID:                id,
 		DataEmprestimo:    dataEmprestimo,
 		Status:            status,
-		QuantLivros:       quantLivros,
+		QuantLivros:       len(livros),
+		Livros:            livros,
 		ClienteUsuarioCPF: clienteCPF,
-	}
-
-	if err := repo.Create(ctx, novoEmprestimo); err != nil {
-		log.Printf("ERRO: Não foi possível criar o empréstimo. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Empréstimo criado. Verifique o banco de dados.")
-	}
+	})
+	concluir(err, "Não foi possível criar o empréstimo", "Empréstimo criado.")
 }
 
-func handleReadEmprestimo(ctx context.Context, repo repository.EmprestimoRepository, reader *bufio.Reader) {
+func handleReadEmprestimo(ctx context.Context, repo repository.EmprestimoRepository, userRepo repository.UsuarioRepository, reader *bufio.Reader) {
 	fmt.Print("Digite o ID do empréstimo a ser lido (número inteiro): ")
 	idStr, _ := reader.ReadString('\n')
 	idStr = strings.TrimSpace(idStr)
@@ -192,10 +368,13 @@ func handleReadEmprestimo(ctx context.Context, repo repository.EmprestimoReposit
 		log.Printf("ERRO: Empréstimo com ID '%d' não encontrado. %v\n", id, err)
 	} else {
 		log.Printf("SUCESSO: Empréstimo encontrado: %+v\n", *emprestimo)
+		if usuario, err := userRepo.GetByCPF(ctx, emprestimo.ClienteUsuarioCPF); err == nil {
+			exibirResponsavel(ctx, userRepo, *usuario)
+		}
 	}
 }
 
-func handleUpdateEmprestimo(ctx context.Context, repo repository.EmprestimoRepository, reader *bufio.Reader) {
+func handleUpdateEmprestimo(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
 	fmt.Print("Digite o ID do empréstimo a ser atualizado (número inteiro): ")
 	idStr, _ := reader.ReadString('\n')
 	idStr = strings.TrimSpace(idStr)
@@ -205,7 +384,7 @@ func handleUpdateEmprestimo(ctx context.Context, repo repository.EmprestimoRepos
 		return
 	}
 
-	emprestimo, err := repo.GetByID(ctx, id)
+	emprestimo, err := b.ObterEmprestimo(ctx, id)
 	if err != nil {
 		log.Printf("ERRO: Empréstimo com ID '%d' não encontrado para atualizar. %v\n", id, err)
 		return
@@ -229,24 +408,25 @@ func handleUpdateEmprestimo(ctx context.Context, repo repository.EmprestimoRepos
 		}
 	}
 
-	fmt.Printf("Digite o novo CPF do cliente/usuário (atual: %s): ", emprestimo.ClienteUsuarioCPF)
+	fmt.Printf("Digite o novo CPF do cliente/usuário (atual: %s): ", validacao.FormatarCPF(emprestimo.ClienteUsuarioCPF))
 	clienteCPF, _ := reader.ReadString('\n')
 	clienteCPF = strings.TrimSpace(clienteCPF)
 	if clienteCPF != "" {
-		emprestimo.ClienteUsuarioCPF = clienteCPF
+		normalizado, err := validacao.NormalizarCPF(clienteCPF)
+		if err != nil {
+			log.Printf("ERRO: CPF inválido: '%s'. %v\n", clienteCPF, err)
+			return
+		}
+		emprestimo.ClienteUsuarioCPF = normalizado
 	}
 
-	// Atualiza data do empréstimo para agora
-	emprestimo.DataEmprestimo = time.Now()
-
-	if err := repo.Update(ctx, *emprestimo); err != nil {
-		log.Printf("ERRO: Não foi possível atualizar o empréstimo. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Empréstimo atualizado. Verifique o banco de dados.")
-	}
+	// as regras de empréstimo só valem enquanto o empréstimo continua ativo e a
+	// data de devolução é registrada pelo pacote servico
+	_, err = b.AtualizarEmprestimo(ctx, *emprestimo)
+	concluir(err, "Não foi possível atualizar o empréstimo", "Empréstimo atualizado.")
 }
 
-func handleDeleteEmprestimo(ctx context.Context, repo repository.EmprestimoRepository, reader *bufio.Reader) {
+func handleDeleteEmprestimo(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
 	fmt.Print("Digite o ID do empréstimo a ser deletado (número inteiro): ")
 	idStr, _ := reader.ReadString('\n')
 	idStr = strings.TrimSpace(idStr)
@@ -256,16 +436,14 @@ func handleDeleteEmprestimo(ctx context.Context, repo repository.EmprestimoRepos
 		return
 	}
 
-	if err := repo.Delete(ctx, id); err != nil {
-		log.Printf("ERRO: Não foi possível deletar o empréstimo. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Empréstimo deletado. Verifique o banco de dados.")
-	}
+	concluir(b.DeletarEmprestimo(ctx, id), "Não foi possível deletar o empréstimo", "Empréstimo deletado.")
 }
 
-func handleCreateUsuario(ctx context.Context, repo repository.UsuarioRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o CPF: ")
-	cpf, _ := reader.ReadString('\n')
+func handleCreateUsuario(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	cpf, ok := lerCPF(reader, "Digite o CPF: ")
+	if !ok {
+		return
+	}
 
 	fmt.Print("Digite o Primeiro Nome: ")
 	primeiroNome, _ := reader.ReadString('\n')
@@ -281,42 +459,82 @@ func handleCreateUsuario(ctx context.Context, repo repository.UsuarioRepository,
 		return
 	}
 
-	novoUsuario := model.Usuario{
-		CPF:            strings.TrimSpace(cpf),
-		PrimeiroNome:   strings.TrimSpace(primeiroNome),
-		Sobrenome:      strings.TrimSpace(sobrenome),
-		DataNascimento: dataNasc,
+	fmt.Print("Digite o E-mail: ")
+	email, _ := reader.ReadString('\n')
+
+	fmt.Print("Digite o Telefone: ")
+	telefone, _ := reader.ReadString('\n')
+
+	endereco := lerEndereco(reader, model.Endereco{})
+
+	categoria, ok := lerCategoriaUsuario(reader, "")
+	if !ok {
+		return
+	}
+
+	var matricula string
+	if categoria != model.CategoriaExterno {
+		fmt.Print("Digite a Matrícula UFS: ")
+		matricula, _ = reader.ReadString('\n')
 	}
 
-	if err := repo.Create(ctx, novoUsuario); err != nil {
-		log.Printf("ERRO: Não foi possível criar o usuário. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Usuário criado. Verifique o banco de dados.")
+	fmt.Print("Digite a Validade do Vínculo (AAAA-MM-DD): ")
+	validadeStr, _ := reader.ReadString('\n')
+	validade, err := time.Parse("2006-01-02", strings.TrimSpace(validadeStr))
+	if err != nil {
+		log.Printf("Formato de data inválido: %v\n", err)
+		return
+	}
+
+	var responsavelCPF string
+	if (model.Usuario{DataNascimento: dataNasc}).MenorDeIdade(time.Now()) {
+		responsavelCPF, ok = lerResponsavel(ctx, b.Repos.Usuarios, reader, model.Usuario{CPF: cpf})
+		if !ok {
+			return
+		}
 	}
+
+	_, err = b.CriarUsuario(ctx, model.Usuario{
+		CPF:             cpf,
+		PrimeiroNome:    strings.TrimSpace(primeiroNome),
+		Sobrenome:       strings.TrimSpace(sobrenome),
+		DataNascimento:  dataNasc,
+		Email:           strings.TrimSpace(email),
+		Telefone:        strings.TrimSpace(telefone),
+		Endereco:        endereco,
+		Matricula:       strings.TrimSpace(matricula),
+		Categoria:       categoria,
+		ValidadeVinculo: validade,
+		ResponsavelCPF:  responsavelCPF,
+	})
+	concluir(err, "Não foi possível criar o usuário", "Usuário criado.")
 }
 
 func handleReadUsuario(ctx context.Context, repo repository.UsuarioRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o CPF do usuário a ser lido: ")
-	cpf, _ := reader.ReadString('\n')
-	cpf = strings.TrimSpace(cpf)
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário a ser lido: ")
+	if !ok {
+		return
+	}
 
 	usuario, err := repo.GetByCPF(ctx, cpf)
 	if err != nil {
-		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", cpf, err)
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado. %v\n", validacao.FormatarCPF(cpf), err)
 	} else {
-		log.Printf("SUCESSO: Usuário encontrado: %+v\n", *usuario)
+		log.Printf("SUCESSO: Usuário encontrado (CPF %s): %+v\n", validacao.FormatarCPF(usuario.CPF), *usuario)
+		exibirResponsavel(ctx, repo, *usuario)
 	}
 }
 
-func handleUpdateUsuario(ctx context.Context, repo repository.UsuarioRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o CPF do usuário a ser atualizado: ")
-	cpf, _ := reader.ReadString('\n')
-	cpf = strings.TrimSpace(cpf)
+func handleUpdateUsuario(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário a ser atualizado: ")
+	if !ok {
+		return
+	}
 
 	// 1buscar o usuário existente para obter os dados atuais
-	usuario, err := repo.GetByCPF(ctx, cpf)
+	usuario, err := b.ObterUsuario(ctx, cpf)
 	if err != nil {
-		log.Printf("ERRO: Usuário com CPF '%s' não encontrado para atualizar.\n", cpf)
+		log.Printf("ERRO: Usuário com CPF '%s' não encontrado para atualizar.\n", validacao.FormatarCPF(cpf))
 		return
 	}
 	log.Printf("Atualizando usuário: %+v\n", *usuario)
@@ -349,31 +567,227 @@ func handleUpdateUsuario(ctx context.Context, repo repository.UsuarioRepository,
 		}
 	}
 
-	// chamar o método de atualização do repositório com o objeto modificado
-	if err := repo.Update(ctx, *usuario); err != nil {
-		log.Printf("ERRO: Não foi possível atualizar o usuário. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Usuário atualizado. Verifique o banco de dados.")
+	fmt.Printf("Digite o novo E-mail (atual: %s): ", usuario.Email)
+	email, _ := reader.ReadString('\n')
+	email = strings.TrimSpace(email)
+	if email != "" {
+		usuario.Email = email
+	}
+
+	fmt.Printf("Digite o novo Telefone (atual: %s): ", usuario.Telefone)
+	telefone, _ := reader.ReadString('\n')
+	telefone = strings.TrimSpace(telefone)
+	if telefone != "" {
+		usuario.Telefone = telefone
+	}
+
+	usuario.Endereco = lerEndereco(reader, usuario.Endereco)
+
+	if categoria, ok := lerCategoriaUsuario(reader, usuario.Categoria); ok {
+		usuario.Categoria = categoria
+	}
+
+	fmt.Printf("Digite a nova Matrícula UFS (atual: %s): ", usuario.Matricula)
+	matricula, _ := reader.ReadString('\n')
+	matricula = strings.TrimSpace(matricula)
+	if matricula != "" {
+		usuario.Matricula = matricula
+	}
+
+	fmt.Printf("Digite a nova Validade do Vínculo (AAAA-MM-DD) (atual: %s): ", usuario.ValidadeVinculo.Format("2006-01-02"))
+	validadeStr, _ := reader.ReadString('\n')
+	validadeStr = strings.TrimSpace(validadeStr)
+	if validadeStr != "" {
+		validade, err := time.Parse("2006-01-02", validadeStr)
+		if err != nil {
+			log.Printf("ERRO: Formato de data inválido. A validade do vínculo não foi alterada: %v\n", err)
+		} else {
+			usuario.ValidadeVinculo = validade
+		}
 	}
+
+	if !validar(servico.ValidarUsuario(*usuario)) {
+		return
+	}
+
+	if usuario.MenorDeIdade(time.Now()) {
+		responsavelCPF, ok := lerResponsavel(ctx, b.Repos.Usuarios, reader, *usuario)
+		if !ok {
+			return
+		}
+		usuario.ResponsavelCPF = responsavelCPF
+	}
+
+	_, err = b.AtualizarUsuario(ctx, *usuario)
+	concluir(err, "Não foi possível atualizar o usuário", "Usuário atualizado.")
 }
 
-func handleDeleteUsuario(ctx context.Context, repo repository.UsuarioRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o CPF do usuário a ser deletado: ")
+// lerCPF lê um CPF, aceito com ou sem máscara, e o retorna normalizado
+// (apenas dígitos), que é o formato gravado nos bancos
+func lerCPF(reader *bufio.Reader, prompt string) (string, bool) {
+	fmt.Print(prompt)
 	cpf, _ := reader.ReadString('\n')
 	cpf = strings.TrimSpace(cpf)
 
-	if err := repo.Delete(ctx, cpf); err != nil {
-		log.Printf("ERRO: Não foi possível deletar o usuário. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Usuário deletado. Verifique o banco de dados.")
+	normalizado, err := validacao.NormalizarCPF(cpf)
+	if err != nil {
+		log.Printf("ERRO: CPF inválido: '%s'. %v\n", cpf, err)
+		return "", false
+	}
+	return normalizado, true
+}
+
+// lerCNPJ lê um CNPJ numérico ou alfanumérico, com ou sem máscara, e o
+// retorna normalizado (14 caracteres, sem pontuação)
+func lerCNPJ(reader *bufio.Reader, prompt string) (string, bool) {
+	fmt.Print(prompt)
+	cnpj, _ := reader.ReadString('\n')
+	cnpj = strings.TrimSpace(cnpj)
+
+	normalizado, err := validacao.NormalizarCNPJ(cnpj)
+	if err != nil {
+		log.Printf("ERRO: CNPJ inválido: '%s'. %v\n", cnpj, err)
+		return "", false
+	}
+	return normalizado, true
+}
+
+// lerISBN lê um ISBN-10 ou ISBN-13, com ou sem hífens, e o retorna como
+// ISBN-13 somente com dígitos, formato usado como chave do livro
+func lerISBN(reader *bufio.Reader, prompt string) (string, bool) {
+	fmt.Print(prompt)
+	isbn, _ := reader.ReadString('\n')
+	isbn = strings.TrimSpace(isbn)
+
+	normalizado, err := validacao.NormalizarISBN(isbn)
+	if err != nil {
+		log.Printf("ERRO: ISBN inválido: '%s'. %v\n", isbn, err)
+		return "", false
 	}
+	return normalizado, true
 }
 
-func handleCreateLivro(ctx context.Context, repo repository.LivroRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o ISBN do livro: ")
+// lerISBNCadastrado lê o ISBN em qualquer forma, localiza o livro e retorna a
+// chave com que ele está gravado, o que também cobre registros anteriores à
+// normalização do ISBN
+func lerISBNCadastrado(ctx context.Context, repo repository.LivroRepository, reader *bufio.Reader, prompt string) (string, bool) {
+	fmt.Print(prompt)
 	isbn, _ := reader.ReadString('\n')
 	isbn = strings.TrimSpace(isbn)
 
+	livro, err := repo.GetByISBN(ctx, isbn)
+	if err != nil {
+		log.Printf("ERRO: Livro com ISBN '%s' não encontrado. %v\n", isbn, err)
+		return "", false
+	}
+	return livro.ISBN, true
+}
+
+// lerEndereco coleta os campos do endereço; campos em branco mantêm o valor atual
+func lerEndereco(reader *bufio.Reader, atual model.Endereco) model.Endereco {
+	campos := []struct {
+		rotulo string
+		valor  *string
+	}{
+		{"Logradouro", &atual.Logradouro},
+		{"Número", &atual.Numero},
+		{"Bairro", &atual.Bairro},
+		{"Cidade", &atual.Cidade},
+		{"UF", &atual.UF},
+		{"CEP", &atual.CEP},
+	}
+	for _, c := range campos {
+		if *c.valor != "" {
+			fmt.Printf("Digite o %s (atual: %s): ", c.rotulo, *c.valor)
+		} else {
+			fmt.Printf("Digite o %s: ", c.rotulo)
+		}
+		valor, _ := reader.ReadString('\n')
+		valor = strings.TrimSpace(valor)
+		if valor != "" {
+			*c.valor = valor
+		}
+	}
+	atual.UF = strings.ToUpper(atual.UF)
+	return atual
+}
+
+// lerCategoriaUsuario pede a categoria do usuário. Retorna false se nada
+// válido foi escolhido; nesse caso o chamador mantém o valor atual
+func lerCategoriaUsuario(reader *bufio.Reader, atual model.CategoriaUsuario) (model.CategoriaUsuario, bool) {
+	opcoes := make([]string, len(model.CategoriasUsuario))
+	for i, c := range model.CategoriasUsuario {
+		opcoes[i] = string(c)
+	}
+	if atual != "" {
+		fmt.Printf("Digite a nova Categoria [%s] (atual: %s): ", strings.Join(opcoes, "/"), atual)
+	} else {
+		fmt.Printf("Digite a Categoria [%s]: ", strings.Join(opcoes, "/"))
+	}
+	valor, _ := reader.ReadString('\n')
+	valor = strings.ToLower(strings.TrimSpace(valor))
+	if valor == "" && atual != "" {
+		return atual, false
+	}
+	for _, c := range model.CategoriasUsuario {
+		if string(c) == valor {
+			return c, true
+		}
+	}
+	log.Printf("ERRO: Categoria de usuário inválida: '%s'\n", valor)
+	return atual, false
+}
+
+// validar exibe os erros de validação campo a campo; retorna false se o
+// modelo tiver algum problema e não deve ser gravado
+func validar(err error) bool {
+	if err == nil {
+		return true
+	}
+	erros, ok := validacao.ErrosDeCampo(err)
+	if !ok {
+		log.Printf("ERRO: %v\n", err)
+		return false
+	}
+	log.Printf("ERRO: %d problema(s) encontrado(s):\n", len(erros))
+	for _, e := range erros {
+		log.Printf("  - %s: %s\n", e.Campo, e.Mensagem)
+	}
+	return false
+}
+
+// concluir informa o resultado de uma gravação feita pelo pacote servico,
+// listando os erros de validação campo a campo
+func concluir(err error, falha, sucesso string) {
+	if err == nil {
+		log.Printf("SUCESSO: %s Verifique o banco de dados.\n", sucesso)
+		return
+	}
+	if _, ok := validacao.ErrosDeCampo(err); ok {
+		validar(err)
+		return
+	}
+	log.Printf("ERRO: %s. %v\n", falha, err)
+}
+
+// um responsável só pode ser removido depois de substituído nos cadastros dos
+// menores; a conferência é feita pelo pacote servico
+func handleDeleteUsuario(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	cpf, ok := lerCPF(reader, "Digite o CPF do usuário a ser deletado: ")
+	if !ok {
+		return
+	}
+
+	concluir(b.DeletarUsuario(ctx, cpf), "Não foi possível deletar o usuário", "Usuário deletado.")
+}
+
+// a editora precisa estar cadastrada; a conferência é feita pelo pacote servico
+func handleCreateLivro(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	isbn, ok := lerISBN(reader, "Digite o ISBN do livro (ISBN-10 ou ISBN-13): ")
+	if !ok {
+		return
+	}
+
 	fmt.Print("Digite o Título: ")
 	titulo, _ := reader.ReadString('\n')
 	titulo = strings.TrimSpace(titulo)
@@ -390,25 +804,47 @@ func handleCreateLivro(ctx context.Context, repo repository.LivroRepository, rea
 		return
 	}
 
-	const editoraCNPJFixo = "11222333000144"
+	fmt.Print("Digite o Idioma da edição (ex.: pt, en): ")
+	idioma, _ := reader.ReadString('\n')
+	idioma = strings.ToLower(strings.TrimSpace(idioma))
+
+	fmt.Print("Digite o sistema de classificação (CDD/CDU, vazio para nenhum): ")
+	sistema, _ := reader.ReadString('\n')
+	sistema = strings.ToUpper(strings.TrimSpace(sistema))
+	if sistema != "" && sistema != model.ClassificacaoCDD && sistema != model.ClassificacaoCDU {
+		log.Printf("ERRO: Sistema de classificação inválido: %s\n", sistema)
+		return
+	}
+
+	var numeroClassificacao string
+	if sistema != "" {
+		fmt.Printf("Digite o número de classificação %s: ", sistema)
+		numeroClassificacao, _ = reader.ReadString('\n')
+		numeroClassificacao = strings.TrimSpace(numeroClassificacao)
+	}
+
+	editoraCNPJ, ok := lerCNPJ(reader, "Digite o CNPJ da Editora: ")
+	if !ok {
+		return
+	}
+
 	const funcMatriculaFixo = 100
-	log.Printf("Usando valores fixos para teste: CNPJ da Editora=%s, Matrícula do Funcionário=%d\n", editoraCNPJFixo, funcMatriculaFixo)
+	log.Printf("Usando valor fixo para teste: Matrícula do Funcionário=%d\n", funcMatriculaFixo)
 
-	novoLivro := model.Livro{
+	_, err = b.CriarLivro(ctx, model.Livro{
 		ISBN:                 isbn,
 		Titulo:               titulo,
 		Edicao:               edicao,
 		NumPaginas:           numPaginas,
-		EditoraCNPJ:          editoraCNPJFixo,   // valor fixo para teste
+		EditoraCNPJ:          editoraCNPJ,
 		FuncionarioMatricula: funcMatriculaFixo, // valor fixo para teste
 		Autores:              []model.Autor{},
-	}
-
-	if err := repo.Create(ctx, novoLivro); err != nil {
-		log.Printf("ERRO: Não foi possível criar o livro. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Livro criado. Verifique o banco de dados.")
-	}
+		SistemaClassificacao: sistema,
+		NumeroClassificacao:  numeroClassificacao,
+		Categorias:           []int{},
+		Idioma:               idioma,
+	})
+	concluir(err, "Não foi possível criar o livro", "Livro criado.")
 }
 
 func handleReadLivro(ctx context.Context, repo repository.LivroRepository, reader *bufio.Reader) {
@@ -424,22 +860,22 @@ func handleReadLivro(ctx context.Context, repo repository.LivroRepository, reade
 	}
 }
 
-func handleDeleteLivro(ctx context.Context, repo repository.LivroRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o ISBN do livro a ser deletado: ")
-	isbn, _ := reader.ReadString('\n')
-	isbn = strings.TrimSpace(isbn)
-
-	if err := repo.Delete(ctx, isbn); err != nil {
-		log.Printf("ERRO: Não foi possível deletar o livro. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Livro deletado. Verifique o banco de dados.")
+func handleDeleteLivro(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, b.Repos.Livros, reader, "Digite o ISBN do livro a ser deletado: ")
+	if !ok {
+		return
 	}
+
+	concluir(b.DeletarLivro(ctx, isbn), "Não foi possível deletar o livro", "Livro deletado.")
 }
 
-func handleAddAutorRelacionamento(ctx context.Context, livroRepo repository.LivroRepository, autorRepo repository.AutorRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o ISBN do livro para adicionar um autor: ")
-	isbn, _ := reader.ReadString('\n')
-	isbn = strings.TrimSpace(isbn)
+// o autor é cadastrado junto com o vínculo se ainda não existir; um autor já
+// cadastrado mantém os nomes do cadastro
+func handleAddAutorRelacionamento(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, b.Repos.Livros, reader, "Digite o ISBN do livro para adicionar um autor: ")
+	if !ok {
+		return
+	}
 
 	fmt.Print("Digite o ID do autor: ")
 	autorIDStr, _ := reader.ReadString('\n')
@@ -457,29 +893,22 @@ func handleAddAutorRelacionamento(ctx context.Context, livroRepo repository.Livr
 		Sobrenome:    strings.TrimSpace(autorSobrenome),
 	}
 
-	if err := autorRepo.Create(ctx, autor); err != nil {
-		// ignora o erro se o autor já existir
-		log.Printf("AVISO: Autor pode já existir. Continuando... (%v)\n", err)
-	}
-
-	if err := livroRepo.AddAutor(ctx, isbn, autor); err != nil {
-		log.Printf("ERRO: Não foi possível adicionar o relacionamento. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Relacionamento criado. Verifique o banco de dados.")
-	}
+	_, err := b.VincularAutor(ctx, isbn, autor)
+	concluir(err, "Não foi possível adicionar o relacionamento", "Relacionamento criado.")
 }
 
-func handleRemoveAutorRelacionamento(ctx context.Context, livroRepo repository.LivroRepository, autorRepo repository.AutorRepository, reader *bufio.Reader) {
-	fmt.Print("Digite o ISBN do livro para remover um autor: ")
-	isbn, _ := reader.ReadString('\n')
-	isbn = strings.TrimSpace(isbn)
+func handleRemoveAutorRelacionamento(ctx context.Context, b *servico.Biblioteca, reader *bufio.Reader) {
+	isbn, ok := lerISBNCadastrado(ctx, b.Repos.Livros, reader, "Digite o ISBN do livro para remover um autor: ")
+	if !ok {
+		return
+	}
 
 	fmt.Print("Digite o ID do autor a ser removido: ")
 	autorIDStr, _ := reader.ReadString('\n')
 	autorID, _ := strconv.Atoi(strings.TrimSpace(autorIDStr))
 
 	// remove o relacionamento do livro.
-	if err := livroRepo.RemoveAutor(ctx, isbn, autorID); err != nil {
+	if err := b.DesvincularAutor(ctx, isbn, autorID); err != nil {
 		log.Printf("ERRO: Não foi possível remover o relacionamento. %v\n", err)
 		return // se não conseguir remover a relação, não deleta o autor.
 	}
@@ -487,9 +916,11 @@ func handleRemoveAutorRelacionamento(ctx context.Context, livroRepo repository.L
 
 	// deleta o autor da tabela/coleção principal 'Autor'.
 	log.Printf("Deletando autor com ID %d da tabela principal 'Autor'...", autorID)
-	if err := autorRepo.Delete(ctx, autorID); err != nil {
-		log.Printf("ERRO: Não foi possível deletar o autor da tabela principal. %v\n", err)
-	} else {
-		log.Println("SUCESSO: Autor deletado da tabela principal. Verifique o banco de dados.")
-	}
+	concluir(b.DeletarAutor(ctx, autorID), "Não foi possível deletar o autor da tabela principal", "Autor deletado da tabela principal.")
+}
+
+// terminal indica se o arquivo é um terminal interativo
+func terminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// EventoDominio registra uma alteração feita pelas operações da biblioteca.
+// Os eventos são gravados no outbox na mesma transação da alteração, de modo
+// que só existem se ela foi confirmada, e depois publicados pelo relay
+type EventoDominio struct {
+	// ID começa pelo momento do evento, para que a ordem dos IDs seja a ordem
+	// em que os eventos aconteceram
+	ID          string     `bson:"_id" json:"id"`
+	Tipo        string     `bson:"tipo" json:"tipo"`
+	Agregado    string     `bson:"agregado" json:"agregado"` // usuario, livro, autor, emprestimo ou reserva
+	Chave       string     `bson:"chave" json:"chave"`       // CPF, ISBN ou ID do registro alterado
+	Dados       string     `bson:"dados" json:"dados"`       // JSON do registro ou da alteração
+	OcorridoEm  time.Time  `bson:"ocorrido_em" json:"ocorrido_em"`
+	PublicadoEm *time.Time `bson:"publicado_em" json:"publicado_em,omitempty"` // nil enquanto o relay não o publicar
+	Tentativas  int        `bson:"tentativas" json:"tentativas"`               // publicações que falharam
+	UltimoErro  string     `bson:"ultimo_erro" json:"ultimo_erro,omitempty"`
+}
+
+// tipos de evento de domínio
+const (
+	TipoUsuarioCriado          = "UsuarioCriado"
+	TipoUsuarioAtualizado      = "UsuarioAtualizado"
+	TipoUsuarioRemovido        = "UsuarioRemovido"
+	TipoLivroCriado            = "LivroCriado"
+	TipoLivroAtualizado        = "LivroAtualizado"
+	TipoLivroRemovido          = "LivroRemovido"
+	TipoLivroAutorVinculado    = "LivroAutorVinculado"
+	TipoLivroAutorDesvinculado = "LivroAutorDesvinculado"
+	TipoAutorCriado            = "AutorCriado"
+	TipoAutorAtualizado        = "AutorAtualizado"
+	TipoAutorRemovido          = "AutorRemovido"
+	TipoEmprestimoCriado       = "EmprestimoCriado"
+	TipoEmprestimoAtualizado   = "EmprestimoAtualizado"
+	TipoEmprestimoDevolvido    = "EmprestimoDevolvido"
+	TipoEmprestimoRenovado     = "EmprestimoRenovado"
+	TipoEmprestimoRemovido     = "EmprestimoRemovido"
+	TipoReservaCriada          = "ReservaCriada"
+	TipoReservaAtendida        = "ReservaAtendida"
+	TipoReservaCancelada       = "ReservaCancelada"
+)
@@ -4,36 +4,200 @@ import "time"
 
 // Usuario representa a tabela/coleção Usuario
 type Usuario struct {
-	CPF            string    `bson:"_id"` // CPF como ID no Mongo
-	DataNascimento time.Time `bson:"data_nascimento"`
-	Sobrenome      string    `bson:"sobrenome"`
-	PrimeiroNome   string    `bson:"primeiro_nome"`
+	CPF             string           `bson:"_id" json:"cpf"` // CPF como ID no Mongo
+	DataNascimento  time.Time        `bson:"data_nascimento" json:"data_nascimento"`
+	Sobrenome       string           `bson:"sobrenome" json:"sobrenome"`
+	PrimeiroNome    string           `bson:"primeiro_nome" json:"primeiro_nome"`
+	Email           string           `bson:"email" json:"email"`
+	Telefone        string           `bson:"telefone" json:"telefone"`
+	Endereco        Endereco         `bson:"endereco" json:"endereco"`
+	Matricula       string           `bson:"matricula" json:"matricula"` // matrícula UFS; vazia para usuários externos
+	Categoria       CategoriaUsuario `bson:"categoria" json:"categoria"`
+	ValidadeVinculo time.Time        `bson:"validade_vinculo" json:"validade_vinculo"` // data até a qual o vínculo com a biblioteca é válido
+	ResponsavelCPF  string           `bson:"responsavel_cpf" json:"responsavel_cpf"`   // usuário responsável por um menor de idade
+}
+
+// Endereco é embutido no documento do usuário no MongoDB
+type Endereco struct {
+	Logradouro string `bson:"logradouro" json:"logradouro"`
+	Numero     string `bson:"numero" json:"numero"`
+	Bairro     string `bson:"bairro" json:"bairro"`
+	Cidade     string `bson:"cidade" json:"cidade"`
+	UF         string `bson:"uf" json:"uf"`
+	CEP        string `bson:"cep" json:"cep"`
+}
+
+// CategoriaUsuario indica o vínculo do usuário com a universidade
+type CategoriaUsuario string
+
+const (
+	CategoriaGraduacao CategoriaUsuario = "graduacao"
+	CategoriaPos       CategoriaUsuario = "pos"
+	CategoriaDocente   CategoriaUsuario = "docente"
+	CategoriaTecnico   CategoriaUsuario = "tecnico"
+	CategoriaExterno   CategoriaUsuario = "externo"
+)
+
+// CategoriasUsuario lista as categorias válidas, na ordem em que são apresentadas
+var CategoriasUsuario = []CategoriaUsuario{
+	CategoriaGraduacao,
+	CategoriaPos,
+	CategoriaDocente,
+	CategoriaTecnico,
+	CategoriaExterno,
 }
 
 // Autor representa um autor, que será embutido no Livro no modelo NoSQL
 type Autor struct {
-	ID           int    `bson:"_id"`
-	PrimeiroNome string `bson:"primeiro_nome"`
-	Sobrenome    string `bson:"sobrenome"`
+	ID           int    `bson:"_id" json:"id"`
+	PrimeiroNome string `bson:"primeiro_nome" json:"primeiro_nome"`
+	Sobrenome    string `bson:"sobrenome" json:"sobrenome"`
+}
+
+// Editora representa a tabela/coleção Editora
+type Editora struct {
+	CNPJ string `bson:"_id" json:"cnpj"` // CNPJ sem máscara, numérico ou alfanumérico
+	Nome string `bson:"nome" json:"nome"`
 }
 
 // Livro representa a tabela/coleção Livro
 type Livro struct {
-	ISBN                 string  `bson:"_id"` // ISBN como ID
-	Titulo               string  `bson:"titulo"`
-	Edicao               string  `bson:"edicao"`
-	NumPaginas           int     `bson:"num_paginas"`
-	EditoraCNPJ          string  `bson:"editora_cnpj"`
-	FuncionarioMatricula int     `bson:"funcionario_matricula"`
-	Autores              []Autor `bson:"autores"` // relacionamento embutido para NoSQL
+	ISBN                 string  `bson:"_id" json:"isbn"` // ISBN como ID
+	Titulo               string  `bson:"titulo" json:"titulo"`
+	Edicao               string  `bson:"edicao" json:"edicao"`
+	NumPaginas           int     `bson:"num_paginas" json:"num_paginas"`
+	EditoraCNPJ          string  `bson:"editora_cnpj" json:"editora_cnpj"`
+	FuncionarioMatricula int     `bson:"funcionario_matricula" json:"funcionario_matricula"`
+	Autores              []Autor `bson:"autores" json:"autores"`                             // relacionamento embutido para NoSQL
+	SistemaClassificacao string  `bson:"sistema_classificacao" json:"sistema_classificacao"` // "CDD" ou "CDU"
+	NumeroClassificacao  string  `bson:"numero_classificacao" json:"numero_classificacao"`   // ex.: "005.74" (CDD) ou "004.65" (CDU)
+	Categorias           []int   `bson:"categorias" json:"categorias"`                       // IDs das categorias de assunto
+	ObraID               *int    `bson:"obra_id,omitempty" json:"obra_id,omitempty"`         // obra da qual o livro é uma edição ou tradução
+	Idioma               string  `bson:"idioma" json:"idioma"`                               // código ISO 639-1 (ex.: "pt", "en")
+}
+
+// Obra agrupa as diferentes edições e traduções de um mesmo trabalho
+// (nível "obra" do modelo FRBR). Cada Livro é uma manifestação da obra
+type Obra struct {
+	ID             int    `bson:"_id" json:"id"`
+	Titulo         string `bson:"titulo" json:"titulo"` // título uniforme da obra
+	IdiomaOriginal string `bson:"idioma_original" json:"idioma_original"`
+}
+
+// Reserva representa um pedido de reserva de um usuário. A reserva pode ser
+// feita para uma edição específica (LivroISBN) ou para qualquer edição de uma
+// obra (ObraID com LivroISBN vazio)
+type Reserva struct {
+	ID          int       `bson:"_id" json:"id"`
+	UsuarioCPF  string    `bson:"usuario_cpf" json:"usuario_cpf"`
+	LivroISBN   string    `bson:"livro_isbn" json:"livro_isbn"`
+	ObraID      *int      `bson:"obra_id,omitempty" json:"obra_id,omitempty"`
+	DataReserva time.Time `bson:"data_reserva" json:"data_reserva"`
+	Status      string    `bson:"status" json:"status"` // "A" (ativa), "T" (atendida) ou "C" (cancelada)
+}
+
+// situações de uma reserva
+const (
+	ReservaAtiva     = "A"
+	ReservaAtendida  = "T"
+	ReservaCancelada = "C"
+)
+
+// QualquerEdicao informa se a reserva aceita qualquer edição da obra
+func (r Reserva) QualquerEdicao() bool {
+	return r.LivroISBN == "" && r.ObraID != nil
 }
 
+// sistemas de classificação bibliográfica aceitos em Livro.SistemaClassificacao
+const (
+	ClassificacaoCDD = "CDD"
+	ClassificacaoCDU = "CDU"
+)
+
+// Categoria representa um cabeçalho de assunto. As categorias formam uma
+// hierarquia através de PaiID (nil para as categorias raiz)
+type Categoria struct {
+	ID     int    `bson:"_id" json:"id"`
+	Nome   string `bson:"nome" json:"nome"`
+	Codigo string `bson:"codigo" json:"codigo"` // número de classificação que identifica o assunto
+	PaiID  *int   `bson:"pai_id,omitempty" json:"pai_id,omitempty"`
+	// material restrito a maiores de idade; vale também para as subcategorias
+	Restrita bool `bson:"restrita" json:"restrita"`
+}
+
+// ContagemCategoria é o resultado da contagem de livros por categoria
+type ContagemCategoria struct {
+	Categoria  Categoria `json:"categoria"`
+	Quantidade int       `json:"quantidade"`
+}
+
+// RecursoDigital representa um arquivo (e-book, PDF, material suplementar)
+// vinculado a um livro. O conteúdo fica no diretório de armazenamento local;
+// apenas os metadados são gravados no banco
+type RecursoDigital struct {
+	ID          int       `bson:"_id" json:"id"`
+	LivroISBN   string    `bson:"livro_isbn" json:"livro_isbn"`
+	Tipo        string    `bson:"tipo" json:"tipo"` // "ebook", "pdf" ou "suplementar"
+	NomeArquivo string    `bson:"nome_arquivo" json:"nome_arquivo"`
+	Caminho     string    `bson:"caminho" json:"caminho"` // relativo ao diretório de armazenamento
+	MIME        string    `bson:"mime" json:"mime"`
+	Tamanho     int64     `bson:"tamanho" json:"tamanho"`
+	SHA256      string    `bson:"sha256" json:"sha256"`
+	EnviadoEm   time.Time `bson:"enviado_em" json:"enviado_em"`
+}
+
+// tipos de recurso digital aceitos em RecursoDigital.Tipo
+var TiposRecursoDigital = []string{"ebook", "pdf", "suplementar"}
+
+// Periodico representa uma publicação seriada (revista, jornal, periódico
+// científico) identificada pelo ISSN
+type Periodico struct {
+	ISSN             string    `bson:"_id" json:"issn"` // ISSN com hífen (ex.: "1234-5679")
+	Titulo           string    `bson:"titulo" json:"titulo"`
+	EditoraCNPJ      string    `bson:"editora_cnpj" json:"editora_cnpj"`
+	FasciculosPorAno int       `bson:"fasciculos_por_ano" json:"fasciculos_por_ano"` // 12 = mensal, 4 = trimestral, 52 = semanal...
+	PrazoReclamacao  int       `bson:"prazo_reclamacao" json:"prazo_reclamacao"`     // dias de tolerância antes de reclamar um fascículo atrasado
+	InicioAssinatura time.Time `bson:"inicio_assinatura" json:"inicio_assinatura"`
+	Ativo            bool      `bson:"ativo" json:"ativo"`
+}
+
+// Fasciculo representa um número (issue) de um periódico. Fascículos
+// previstos são gerados a partir da periodicidade e ficam com status
+// "esperado" até o recebimento
+type Fasciculo struct {
+	ID              string     `bson:"_id" json:"id"` // ver FasciculoID
+	PeriodicoISSN   string     `bson:"periodico_issn" json:"periodico_issn"`
+	Volume          int        `bson:"volume" json:"volume"`
+	Numero          int        `bson:"numero" json:"numero"`
+	DataPrevista    time.Time  `bson:"data_prevista" json:"data_prevista"`
+	DataRecebimento *time.Time `bson:"data_recebimento,omitempty" json:"data_recebimento,omitempty"`
+	DataReclamacao  *time.Time `bson:"data_reclamacao,omitempty" json:"data_reclamacao,omitempty"`
+	Status          string     `bson:"status" json:"status"`
+
+	// circulação do fascículo
+	EmprestadoCPF  string     `bson:"emprestado_cpf" json:"emprestado_cpf"`
+	DataEmprestimo *time.Time `bson:"data_emprestimo,omitempty" json:"data_emprestimo,omitempty"`
+}
+
+// situações de um fascículo
+const (
+	FasciculoEsperado  = "esperado"
+	FasciculoRecebido  = "recebido"
+	FasciculoReclamado = "reclamado"
+)
+
 // Emprestimo representa a tabela no banco de dados
 // Atualizado para refletir os campos reais
 type Emprestimo struct {
-	ID                 int       `bson:"_id" json:"id"`
-	DataEmprestimo     time.Time `bson:"data_emprestimo" json:"data_emprestimo"`
-	Status             string    `bson:"status" json:"status"`
-	QuantLivros        int       `bson:"quant_livros" json:"quant_livros"`
-	ClienteUsuarioCPF  string    `bson:"cliente_usuario_cpf" json:"cliente_usuario_cpf"`
+	ID                int        `bson:"_id" json:"id"`
+	DataEmprestimo    time.Time  `bson:"data_emprestimo" json:"data_emprestimo"`
+	Status            string     `bson:"status" json:"status"`
+	QuantLivros       int        `bson:"quant_livros" json:"quant_livros"`
+	ClienteUsuarioCPF string     `bson:"cliente_usuario_cpf" json:"cliente_usuario_cpf"`
+	Renovacoes        int        `bson:"renovacoes" json:"renovacoes"`
+	DataDevolucao     *time.Time `bson:"data_devolucao,omitempty" json:"data_devolucao,omitempty"` // registrada quando o empréstimo é devolvido
+	// Livros são os ISBNs levados, exigidos ao registrar o empréstimo para
+	// conferir que existem e não são de material restrito. Não são gravados:
+	// o empréstimo guarda só a quantidade
+	Livros []string `bson:"-" json:"livros,omitempty"`
 }
@@ -0,0 +1,29 @@
+package model
+
+// GrupoObra reúne as edições de uma mesma obra retornadas por uma busca
+type GrupoObra struct {
+	ObraID  *int // nil para livros ainda não vinculados a uma obra
+	Edicoes []Livro
+}
+
+// AgruparPorObra colapsa o resultado de uma busca, juntando as edições e
+// traduções de uma mesma obra. A ordem de primeira ocorrência é mantida e
+// livros sem obra formam um grupo cada
+func AgruparPorObra(livros []Livro) []GrupoObra {
+	var grupos []GrupoObra
+	indice := make(map[int]int)
+	for _, l := range livros {
+		if l.ObraID == nil {
+			grupos = append(grupos, GrupoObra{Edicoes: []Livro{l}})
+			continue
+		}
+		if i, ok := indice[*l.ObraID]; ok {
+			grupos[i].Edicoes = append(grupos[i].Edicoes, l)
+			continue
+		}
+		indice[*l.ObraID] = len(grupos)
+		id := *l.ObraID
+		grupos = append(grupos, GrupoObra{ObraID: &id, Edicoes: []Livro{l}})
+	}
+	return grupos
+}
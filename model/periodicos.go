@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// FasciculoID monta o identificador de um fascículo a partir do ISSN, volume e número
+func FasciculoID(issn string, volume, numero int) string {
+	return fmt.Sprintf("%s-v%d-n%d", issn, volume, numero)
+}
+
+// ProximaData avança a data de um fascículo para o próximo
+// conforme a periodicidade. Periodicidades que dividem o ano em meses inteiros
+// (mensal, bimestral, trimestral, semestral, anual) avançam por meses; as
+// demais (semanal, quinzenal...) por dias
+func (p Periodico) ProximaData(data time.Time) time.Time {
+	if p.FasciculosPorAno <= 0 {
+		return data.AddDate(1, 0, 0)
+	}
+	if 12%p.FasciculosPorAno == 0 {
+		return data.AddDate(0, 12/p.FasciculosPorAno, 0)
+	}
+	// mais de um fascículo por dia ainda avança um dia, para que a previsão termine
+	return data.AddDate(0, 0, max(365/p.FasciculosPorAno, 1))
+}
+
+// ProximoNumero retorna volume e número do fascículo seguinte. Cada volume
+// corresponde a um ano de publicação
+func (p Periodico) ProximoNumero(volume, numero int) (int, int) {
+	if p.FasciculosPorAno > 0 && numero >= p.FasciculosPorAno {
+		return volume + 1, 1
+	}
+	return volume, numero + 1
+}
+
+// GerarFasciculosEsperados cria os fascículos previstos após o último
+// registrado, até a data limite. Sem nenhum fascículo registrado (ultimo nil),
+// a previsão começa no volume 1, número 1, no início da assinatura
+func GerarFasciculosEsperados(p Periodico, ultimo *Fasciculo, ate time.Time) []Fasciculo {
+	var previstos []Fasciculo
+	volume, numero, data := 1, 1, p.InicioAssinatura
+	if ultimo != nil {
+		volume, numero = p.ProximoNumero(ultimo.Volume, ultimo.Numero)
+		data = p.ProximaData(ultimo.DataPrevista)
+	}
+	for !data.After(ate) {
+		previstos = append(previstos, Fasciculo{
+			ID:            FasciculoID(p.ISSN, volume, numero),
+			PeriodicoISSN: p.ISSN,
+			Volume:        volume,
+			Numero:        numero,
+			DataPrevista:  data,
+			Status:        FasciculoEsperado,
+		})
+		volume, numero = p.ProximoNumero(volume, numero)
+		data = p.ProximaData(data)
+	}
+	return previstos
+}
+
+// Atrasado informa se um fascículo esperado já passou do prazo de reclamação
+func (f Fasciculo) Atrasado(p Periodico, hoje time.Time) bool {
+	return f.Status == FasciculoEsperado && hoje.After(f.DataPrevista.AddDate(0, 0, p.PrazoReclamacao))
+}
+
+// Disponivel informa se o fascículo pode ser emprestado
+func (f Fasciculo) Disponivel() bool {
+	return f.Status == FasciculoRecebido && f.EmprestadoCPF == ""
+}
@@ -0,0 +1,211 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimiteLivrosPorCategoria define quantos livros cada categoria de usuário
+// pode ter emprestados ao mesmo tempo, somados todos os empréstimos ativos
+var LimiteLivrosPorCategoria = map[CategoriaUsuario]int{
+	CategoriaGraduacao: 3,
+	CategoriaPos:       5,
+	CategoriaDocente:   10,
+	CategoriaTecnico:   5,
+	CategoriaExterno:   2,
+}
+
+// PrazoDiasPorCategoria define por quantos dias cada categoria de usuário
+// fica com os livros; cada renovação estende o prazo pelo mesmo período
+var PrazoDiasPorCategoria = map[CategoriaUsuario]int{
+	CategoriaGraduacao: 15,
+	CategoriaPos:       30,
+	CategoriaDocente:   60,
+	CategoriaTecnico:   30,
+	CategoriaExterno:   7,
+}
+
+// MaxRenovacoes é quantas vezes um empréstimo pode ser renovado
+const MaxRenovacoes = 2
+
+// MultaDiariaCentavos é a multa cobrada por livro e por dia de atraso
+const MultaDiariaCentavos = 100
+
+// DiasAvisoVencimento é com quantos dias de antecedência o usuário é avisado
+// de que o empréstimo vai vencer
+const DiasAvisoVencimento = 2
+
+// VinculoAtivo informa se o vínculo do usuário ainda é válido na data informada.
+// Usuários sem validade cadastrada (registros anteriores aos perfis estendidos)
+// são considerados ativos.
+func (u Usuario) VinculoAtivo(data time.Time) bool {
+	if u.ValidadeVinculo.IsZero() {
+		return true
+	}
+	// o vínculo vale até o fim do dia da validade
+	return data.Before(u.ValidadeVinculo.AddDate(0, 0, 1))
+}
+
+// LimiteLivros retorna quantos livros o usuário pode ter emprestados;
+// categorias desconhecidas seguem o limite de usuários externos
+func (u Usuario) LimiteLivros() int {
+	if limite, ok := LimiteLivrosPorCategoria[u.Categoria]; ok {
+		return limite
+	}
+	return LimiteLivrosPorCategoria[CategoriaExterno]
+}
+
+// PrazoDias retorna por quantos dias o usuário fica com os livros;
+// categorias desconhecidas seguem o prazo de usuários externos
+func (u Usuario) PrazoDias() int {
+	if prazo, ok := PrazoDiasPorCategoria[u.Categoria]; ok {
+		return prazo
+	}
+	return PrazoDiasPorCategoria[CategoriaExterno]
+}
+
+// Vencimento é o último dia para devolver os livros sem multa, já
+// considerando as renovações
+func (e Emprestimo) Vencimento(prazoDias int) time.Time {
+	d := e.DataEmprestimo.AddDate(0, 0, prazoDias*(1+e.Renovacoes))
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+}
+
+// DiasAtraso conta os dias após o vencimento até a devolução ou, enquanto o
+// empréstimo está ativo, até hoje. Empréstimos cancelados e devoluções sem
+// data registrada não têm atraso
+func (e Emprestimo) DiasAtraso(prazoDias int, hoje time.Time) int {
+	fim := hoje
+	switch {
+	case e.DataDevolucao != nil:
+		fim = *e.DataDevolucao
+	case e.Status != "A":
+		return 0
+	}
+	vencimento := e.Vencimento(prazoDias)
+	fim = time.Date(fim.Year(), fim.Month(), fim.Day(), 0, 0, 0, 0, vencimento.Location())
+	if !fim.After(vencimento) {
+		return 0
+	}
+	// arredonda para absorver as horas a mais ou a menos do horário de verão
+	return int((fim.Sub(vencimento) + 12*time.Hour) / (24 * time.Hour))
+}
+
+// DiasParaVencer conta os dias de hoje até o vencimento do empréstimo: 0 no
+// próprio dia e negativo depois dele
+func (e Emprestimo) DiasParaVencer(prazoDias int, hoje time.Time) int {
+	vencimento := e.Vencimento(prazoDias)
+	hoje = time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, vencimento.Location())
+	// arredonda para absorver as horas a mais ou a menos do horário de verão
+	d := vencimento.Sub(hoje)
+	if d < 0 {
+		return -int((-d + 12*time.Hour) / (24 * time.Hour))
+	}
+	return int((d + 12*time.Hour) / (24 * time.Hour))
+}
+
+// Multa calcula a multa do empréstimo em centavos
+func (e Emprestimo) Multa(prazoDias int, hoje time.Time) int {
+	return e.DiasAtraso(prazoDias, hoje) * e.QuantLivros * MultaDiariaCentavos
+}
+
+// PodeRenovar aplica as regras de renovação do empréstimo: só empréstimos
+// ativos, em dia e abaixo do limite de renovações. As regras do usuário
+// (vínculo, responsável) são as de PodeEmprestar
+func (e Emprestimo) PodeRenovar(prazoDias int, hoje time.Time) error {
+	switch {
+	case e.Status != "A":
+		return fmt.Errorf("o empréstimo %d não está ativo", e.ID)
+	case e.DiasAtraso(prazoDias, hoje) > 0:
+		return fmt.Errorf("o empréstimo %d venceu em %s; devolva os livros na biblioteca", e.ID, e.Vencimento(prazoDias).Format("2006-01-02"))
+	case e.Renovacoes >= MaxRenovacoes:
+		return fmt.Errorf("o empréstimo %d já foi renovado %d vez(es), o máximo permitido", e.ID, MaxRenovacoes)
+	}
+	return nil
+}
+
+// PodeEmprestar aplica as regras de empréstimo do perfil do usuário.
+// emPosse são os livros dos empréstimos ativos e quantLivros, os que o
+// usuário quer levar; juntos não podem passar do limite da categoria
+func (u Usuario) PodeEmprestar(emPosse, quantLivros int, data time.Time) error {
+	if !u.VinculoAtivo(data) {
+		return fmt.Errorf("vínculo do usuário expirou em %s", u.ValidadeVinculo.Format("2006-01-02"))
+	}
+	limite := u.LimiteLivros()
+	if emPosse+quantLivros > limite {
+		return fmt.Errorf("categoria '%s' permite no máximo %d livro(s) emprestado(s) ao mesmo tempo; o usuário já tem %d", u.Categoria, limite, emPosse)
+	}
+	return nil
+}
+
+// LivrosEmPosse soma os livros dos empréstimos ativos da lista
+func LivrosEmPosse(emprestimos []Emprestimo) int {
+	total := 0
+	for _, e := range emprestimos {
+		if e.Status == "A" {
+			total += e.QuantLivros
+		}
+	}
+	return total
+}
+
+// MaioridadeAnos é a idade a partir da qual o usuário não precisa de responsável
+const MaioridadeAnos = 18
+
+// Idade calcula a idade do usuário, em anos completos, na data informada
+func (u Usuario) Idade(data time.Time) int {
+	nasc := u.DataNascimento
+	idade := data.Year() - nasc.Year()
+	if data.Month() < nasc.Month() || (data.Month() == nasc.Month() && data.Day() < nasc.Day()) {
+		idade--
+	}
+	return idade
+}
+
+// MenorDeIdade informa se o usuário é menor de idade na data informada.
+// Sem data de nascimento cadastrada o usuário é tratado como maior
+func (u Usuario) MenorDeIdade(data time.Time) bool {
+	return !u.DataNascimento.IsZero() && u.Idade(data) < MaioridadeAnos
+}
+
+// CPFResponsavel retorna o CPF de quem responde pelos empréstimos e multas
+// do usuário: o responsável, para menores de idade, ou o próprio usuário
+func (u Usuario) CPFResponsavel(data time.Time) string {
+	if u.MenorDeIdade(data) && u.ResponsavelCPF != "" {
+		return u.ResponsavelCPF
+	}
+	return u.CPF
+}
+
+// ValidarResponsavel verifica se um menor de idade tem um responsável apto:
+// maior de idade e com vínculo ativo. Para maiores de idade não há exigência
+func (u Usuario) ValidarResponsavel(responsavel *Usuario, data time.Time) error {
+	if !u.MenorDeIdade(data) {
+		return nil
+	}
+	if responsavel == nil || u.ResponsavelCPF == "" {
+		return fmt.Errorf("usuário menor de idade sem responsável cadastrado")
+	}
+	if responsavel.MenorDeIdade(data) {
+		return fmt.Errorf("o responsável também é menor de idade")
+	}
+	if !responsavel.VinculoAtivo(data) {
+		return fmt.Errorf("vínculo do responsável expirou em %s", responsavel.ValidadeVinculo.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// PodeRetirar verifica se o usuário pode retirar material das categorias
+// informadas (as do livro e as categorias superiores). Menores de idade não
+// podem retirar material de categorias restritas
+func (u Usuario) PodeRetirar(categorias []Categoria, data time.Time) error {
+	if !u.MenorDeIdade(data) {
+		return nil
+	}
+	for _, c := range categorias {
+		if c.Restrita {
+			return fmt.Errorf("a categoria '%s' é restrita a maiores de %d anos", c.Nome, MaioridadeAnos)
+		}
+	}
+	return nil
+}
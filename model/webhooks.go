@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Webhook é a assinatura de um sistema externo: a cada evento assinado, a
+// biblioteca envia um POST com o JSON do evento para a URL, assinado com o
+// segredo (HMAC-SHA256)
+type Webhook struct {
+	ID       int       `bson:"_id" json:"id"`
+	URL      string    `bson:"url" json:"url"`
+	Eventos  []string  `bson:"eventos" json:"eventos"` // ver EventosWebhook
+	Segredo  string    `bson:"segredo" json:"segredo,omitempty"`
+	Ativo    bool      `bson:"ativo" json:"ativo"`
+	CriadoEm time.Time `bson:"criado_em" json:"criado_em"`
+}
+
+// eventos que podem ser assinados
+const (
+	EventoEmprestimoCriado    = "emprestimo.criado"
+	EventoEmprestimoDevolvido = "emprestimo.devolvido"
+	EventoEmprestimoVencendo  = "emprestimo.vencendo"
+	EventoEmprestimoAtrasado  = "emprestimo.atrasado"
+	EventoUsuarioCriado       = "usuario.criado"
+	EventoUsuarioRemovido     = "usuario.removido"
+	// EventoTeste é enviado a pedido, para conferir a integração; não precisa ser assinado
+	EventoTeste = "webhook.teste"
+)
+
+var EventosWebhook = []string{
+	EventoEmprestimoCriado, EventoEmprestimoDevolvido, EventoEmprestimoVencendo, EventoEmprestimoAtrasado,
+	EventoUsuarioCriado, EventoUsuarioRemovido,
+}
+
+// Recebe informa se a assinatura está ativa e inclui o evento
+func (w Webhook) Recebe(evento string) bool {
+	if !w.Ativo {
+		return false
+	}
+	for _, e := range w.Eventos {
+		if e == evento {
+			return true
+		}
+	}
+	return false
+}
+
+// EntregaWebhook é o envio de um evento a uma assinatura. As entregas ficam
+// gravadas: as pendentes formam a fila de envio e as demais, o registro do
+// que foi entregue ou desistido
+type EntregaWebhook struct {
+	// ID combina a assinatura e o evento, para que o mesmo evento nunca seja
+	// enfileirado duas vezes para a mesma assinatura
+	ID               string     `bson:"_id" json:"id"`
+	WebhookID        int        `bson:"webhook_id" json:"webhook_id"`
+	EventoID         string     `bson:"evento_id" json:"evento_id"`
+	Evento           string     `bson:"evento" json:"evento"`
+	Corpo            string     `bson:"corpo" json:"corpo"` // JSON enviado, o mesmo em todas as tentativas
+	Situacao         string     `bson:"situacao" json:"situacao"`
+	Tentativas       int        `bson:"tentativas" json:"tentativas"`
+	ProximaTentativa time.Time  `bson:"proxima_tentativa" json:"proxima_tentativa"`
+	UltimoStatus     int        `bson:"ultimo_status" json:"ultimo_status"` // status HTTP da última resposta; 0 sem resposta
+	UltimoErro       string     `bson:"ultimo_erro" json:"ultimo_erro"`
+	CriadaEm         time.Time  `bson:"criada_em" json:"criada_em"`
+	EntregueEm       *time.Time `bson:"entregue_em,omitempty" json:"entregue_em,omitempty"`
+}
+
+// situações de uma entrega
+const (
+	EntregaPendente  = "pendente"
+	EntregaEntregue  = "entregue"
+	EntregaDesistida = "desistida" // esgotou as tentativas ou o destino recusou o evento
+)
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Destino recebe os eventos publicados pelo relay. Publicar só deve retornar
+// sem erro depois que o evento estiver guardado ou entregue
+type Destino interface {
+	Publicar(ctx context.Context, m Mensagem) error
+	String() string
+}
+
+// DestinosFromEnv lê EVENTOS_DESTINOS, uma lista separada por vírgulas com:
+//   - stdout: uma linha JSON por evento na saída padrão
+//   - arquivo:<caminho>: uma linha JSON por evento, acrescentada ao arquivo
+//   - http://... ou https://...: um POST com o JSON de cada evento
+//
+// Sem a variável, os eventos vão para a saída padrão
+func DestinosFromEnv() ([]Destino, error) {
+	v := os.Getenv("EVENTOS_DESTINOS")
+	if strings.TrimSpace(v) == "" {
+		v = "stdout"
+	}
+	var destinos []Destino
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "stdout":
+			destinos = append(destinos, NovaSaida(os.Stdout, "stdout"))
+		case strings.HasPrefix(item, "arquivo:"):
+			caminho := strings.TrimPrefix(item, "arquivo:")
+			if caminho == "" {
+				return nil, fmt.Errorf("EVENTOS_DESTINOS: informe o caminho em 'arquivo:<caminho>'")
+			}
+			destinos = append(destinos, NovoArquivo(caminho))
+		case strings.HasPrefix(item, "http://") || strings.HasPrefix(item, "https://"):
+			if u, err := url.Parse(item); err != nil || u.Host == "" {
+				return nil, fmt.Errorf("EVENTOS_DESTINOS: URL inválida '%s'", item)
+			}
+			destinos = append(destinos, NovoHTTP(item, 10*time.Second))
+		default:
+			return nil, fmt.Errorf("EVENTOS_DESTINOS: destino desconhecido '%s' (use stdout, arquivo:<caminho> ou uma URL http)", item)
+		}
+	}
+	return destinos, nil
+}
+
+// Saida escreve cada evento como uma linha JSON
+type Saida struct {
+	nome string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func NovaSaida(w io.Writer, nome string) *Saida {
+	return &Saida{nome: nome, w: w}
+}
+
+func (s *Saida) Publicar(ctx context.Context, m Mensagem) error {
+	linha, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(linha, '\n'))
+	return err
+}
+
+func (s *Saida) String() string { return s.nome }
+
+// Arquivo acrescenta cada evento como uma linha JSON ao arquivo, que é aberto
+// a cada evento para acompanhar rotações feitas por outros programas
+type Arquivo struct {
+	caminho string
+	mu      sync.Mutex
+}
+
+func NovoArquivo(caminho string) *Arquivo {
+	return &Arquivo{caminho: caminho}
+}
+
+func (a *Arquivo) Publicar(ctx context.Context, m Mensagem) error {
+	linha, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, err := os.OpenFile(a.caminho, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(linha, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	// o evento só é marcado como publicado depois de chegar ao disco
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *Arquivo) String() string { return "arquivo " + a.caminho }
+
+// HTTP envia cada evento em um POST; respostas fora da faixa 2xx são falhas
+type HTTP struct {
+	url     string
+	cliente *http.Client
+}
+
+func NovoHTTP(url string, timeout time.Duration) *HTTP {
+	return &HTTP{url: url, cliente: &http.Client{Timeout: timeout}}
+}
+
+func (h *HTTP) Publicar(ctx context.Context, m Mensagem) error {
+	corpo, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(corpo))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "crud-biblioteca-outbox")
+	resp, err := h.cliente.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	trecho, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("o destino respondeu %s: %s", resp.Status, bytes.TrimSpace(trecho))
+	}
+	return nil
+}
+
+func (h *HTTP) String() string { return h.url }
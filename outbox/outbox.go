@@ -0,0 +1,120 @@
+// Package outbox publica os eventos de domínio gravados pelo pacote servico.
+// O relay lê os eventos ainda não publicados, na ordem em que aconteceram, e
+// os envia a todos os destinos configurados; só depois de todos aceitarem o
+// evento ele é marcado como publicado. Um destino que falha interrompe a
+// publicação até a próxima tentativa, para não trocar a ordem dos eventos.
+// A entrega é "pelo menos uma vez": depois de uma falha, os destinos que já
+// tinham aceitado o evento o recebem de novo, e quem consome deve descartar
+// os IDs repetidos.
+package outbox
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Mensagem é o que os destinos recebem: o evento com os dados em JSON
+type Mensagem struct {
+	ID         string          `json:"id"`
+	Tipo       string          `json:"tipo"`
+	Agregado   string          `json:"agregado"`
+	Chave      string          `json:"chave"`
+	OcorridoEm time.Time       `json:"ocorrido_em"`
+	Dados      json.RawMessage `json:"dados"`
+}
+
+// NovaMensagem monta a mensagem do evento gravado no outbox
+func NovaMensagem(e model.EventoDominio) Mensagem {
+	return Mensagem{e.ID, e.Tipo, e.Agregado, e.Chave, e.OcorridoEm, json.RawMessage(e.Dados)}
+}
+
+// tamanhoLote é quantos eventos são lidos a cada consulta ao outbox
+const tamanhoLote = 50
+
+// IntervaloFromEnv lê EVENTOS_INTERVALO, de quanto em quanto tempo o relay
+// consulta o outbox (padrão 2s, no formato de time.ParseDuration)
+func IntervaloFromEnv() (time.Duration, error) {
+	v := os.Getenv("EVENTOS_INTERVALO")
+	if v == "" {
+		return 2 * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("EVENTOS_INTERVALO deve ser uma duração positiva, como 2s ou 1m: '%s'", v)
+	}
+	return d, nil
+}
+
+type Relay struct {
+	biblioteca *servico.Biblioteca
+	destinos   []Destino
+}
+
+func New(b *servico.Biblioteca, destinos []Destino) *Relay {
+	return &Relay{biblioteca: b, destinos: destinos}
+}
+
+// Executar publica os eventos pendentes a cada intervalo até o contexto ser
+// cancelado
+func (r *Relay) Executar(ctx context.Context, intervalo time.Duration) {
+	t := time.NewTicker(intervalo)
+	defer t.Stop()
+	for {
+		if n, err := r.Processar(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("ERRO: relay: %v\n", err)
+		} else if n > 0 {
+			log.Printf("Relay: %d evento(s) publicado(s).\n", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Processar publica os eventos pendentes até esvaziar o outbox ou um destino
+// falhar, e retorna quantos foram publicados
+func (r *Relay) Processar(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		pendentes, err := r.biblioteca.EventosPendentes(ctx, tamanhoLote)
+		if err != nil {
+			return total, err
+		}
+		for _, e := range pendentes {
+			if err := r.publicar(ctx, e); err != nil {
+				if ctx.Err() != nil {
+					return total, ctx.Err()
+				}
+				if err := r.biblioteca.RegistrarFalhaPublicacao(ctx, e.ID, err); err != nil {
+					return total, err
+				}
+				return total, fmt.Errorf("evento %s (%s): %w", e.ID, e.Tipo, err)
+			}
+			if err := r.biblioteca.MarcarPublicado(ctx, e.ID); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(pendentes) < tamanhoLote {
+			return total, nil
+		}
+	}
+}
+
+func (r *Relay) publicar(ctx context.Context, e model.EventoDominio) error {
+	m := NovaMensagem(e)
+	for _, d := range r.destinos {
+		if err := d.Publicar(ctx, m); err != nil {
+			return fmt.Errorf("%s: %w", d, err)
+		}
+	}
+	return nil
+}
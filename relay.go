@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/outbox"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"log"
+	"os"
+	"os/signal"
+)
+
+// executarRelay publica os eventos do outbox nos destinos de EVENTOS_DESTINOS
+// até receber Ctrl+C. Deve haver um único relay por banco, para que os
+// eventos não sejam publicados em dobro
+func executarRelay(ctx context.Context, repos repository.Repositorios) error {
+	destinos, err := outbox.DestinosFromEnv()
+	if err != nil {
+		return err
+	}
+	intervalo, err := outbox.IntervaloFromEnv()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	log.Printf("Relay de eventos publicando em %v a cada %s.\n", destinos, intervalo)
+	outbox.New(servico.New(repos), destinos).Executar(ctx, intervalo)
+	log.Println("Relay encerrado.")
+	return nil
+}
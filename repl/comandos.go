@@ -0,0 +1,372 @@
+package repl
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// comando é uma ação do REPL. minimo e maximo limitam a quantidade de
+// argumentos (maximo -1 não limita); completar sugere valores para cada
+// argumento, e o último completador vale também para os argumentos seguintes
+type comando struct {
+	nome      string
+	args      string // sintaxe dos argumentos, para a ajuda
+	resumo    string
+	detalhes  string // explicação mostrada por "ajuda <comando>"
+	minimo    int
+	maximo    int
+	completar []completador
+	executar  func(r *REPL, args []string) error
+}
+
+func (c *comando) uso() string {
+	return strings.TrimSpace(c.nome + " " + c.args)
+}
+
+// entidades aceitas por "buscar"
+var entidades = []string{"livro", "usuario", "autor", "emprestimo"}
+
+func todosComandos() []comando {
+	return []comando{
+		{
+			nome: "emprestar", args: "<cpf> <isbn>...", resumo: "empresta um ou mais livros ao usuário",
+			detalhes: "Registra um único empréstimo com todos os livros informados, aplicando o limite de\n" +
+				"livros e o vínculo da categoria do usuário, as regras de responsável para menores e as\n" +
+				"restrições de material. O prazo de devolução é o da categoria do usuário. Livros com\n" +
+				"reservas de outros usuários são emprestados com um aviso; a reserva do próprio usuário\n" +
+				"que está à frente da fila do livro é marcada como atendida.",
+			minimo: 2, maximo: -1, completar: []completador{completarCPF, completarISBN},
+			executar: (*REPL).emprestar,
+		},
+		{
+			nome: "devolver", args: "<id|cpf>", resumo: "registra a devolução e calcula a multa",
+			detalhes: "Com o ID, devolve o empréstimo; com o CPF, todos os empréstimos ativos do usuário.\n" +
+				"O atraso e a multa são calculados na data da devolução.",
+			minimo: 1, maximo: 1, completar: []completador{completarDevolucao},
+			executar: (*REPL).devolver,
+		},
+		{
+			nome: "buscar", args: "livro|usuario|autor|emprestimo [termo...]", resumo: "lista os registros encontrados",
+			detalhes: "livro busca pelo título, usuario e autor pelo nome e emprestimo pelo CPF do usuário.\n" +
+				"Sem termo, emprestimo lista os empréstimos ativos de todos os usuários.",
+			minimo: 1, maximo: -1, completar: []completador{completarEntidade, completarTermo},
+			executar: (*REPL).buscar,
+		},
+		{
+			nome: "usuario", args: "<cpf>", resumo: "mostra a situação do usuário",
+			detalhes: "Mostra a categoria, o vínculo, se o usuário pode pegar livros e, se não puder, o\n" +
+				"motivo, além dos empréstimos ativos com vencimento e multa e das reservas.",
+			minimo: 1, maximo: 1, completar: []completador{completarCPF},
+			executar: (*REPL).usuario,
+		},
+		{
+			nome: "livro", args: "<isbn>", resumo: "mostra os dados do livro",
+			minimo: 1, maximo: 1, completar: []completador{completarISBN},
+			executar: (*REPL).livro,
+		},
+		{
+			nome: "ajuda", args: "[comando]", resumo: "lista os comandos ou explica um deles",
+			minimo: 0, maximo: 1, completar: []completador{completarComando},
+			executar: (*REPL).ajuda,
+		},
+		{
+			nome: "menu", resumo: "encerra os comandos e abre o menu numerado",
+			executar: func(r *REPL, _ []string) error { r.menu = true; return nil },
+		},
+		{
+			nome: "sair", resumo: "encerra o programa (também ctrl+d)",
+			executar: func(*REPL, []string) error { return nil },
+		},
+	}
+}
+
+func buscarComando(nome string) (*comando, bool) {
+	comandos := todosComandos()
+	for i := range comandos {
+		if comandos[i].nome == nome {
+			return &comandos[i], true
+		}
+	}
+	return nil, false
+}
+
+func (r *REPL) ajuda(args []string) error {
+	if len(args) == 1 {
+		c, ok := buscarComando(args[0])
+		if !ok {
+			return fmt.Errorf("comando desconhecido: %s", args[0])
+		}
+		fmt.Fprintf(r.saida, "uso: %s\n\n%s\n", c.uso(), c.resumo)
+		if c.detalhes != "" {
+			fmt.Fprintf(r.saida, "\n%s\n", c.detalhes)
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+	for _, c := range todosComandos() {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.uso(), c.resumo)
+	}
+	tw.Flush()
+	fmt.Fprintln(r.saida, "\nUse \"ajuda <comando>\" para os detalhes e tab para completar comandos, CPFs e ISBNs.")
+	return nil
+}
+
+func (r *REPL) emprestar(args []string) error {
+	ret, err := r.b.Emprestar(r.ctx, args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	e := ret.Emprestimo
+	u, err := r.b.ObterUsuario(r.ctx, e.ClienteUsuarioCPF)
+	if err != nil {
+		return err
+	}
+	for _, aviso := range ret.Avisos {
+		fmt.Fprintf(r.saida, "AVISO: %s\n", aviso)
+	}
+	fmt.Fprintf(r.saida, "SUCESSO: empréstimo %d registrado para %s %s; devolver até %s.\n",
+		e.ID, u.PrimeiroNome, u.Sobrenome, data(ret.Vencimento))
+	for _, l := range ret.Livros {
+		fmt.Fprintf(r.saida, "  - %s  %s\n", l.ISBN, l.Titulo)
+	}
+	return nil
+}
+
+// devolver aceita o ID do empréstimo ou o CPF do usuário; um número só é
+// tratado como CPF se for um CPF válido
+func (r *REPL) devolver(args []string) error {
+	var ids []int
+	if cpf, err := validacao.NormalizarCPF(args[0]); err == nil {
+		ativos, err := r.b.ListarEmprestimos(r.ctx, repository.FiltroEmprestimo{CPF: cpf, Status: servico.StatusAtivo})
+		if err != nil {
+			return err
+		}
+		if len(ativos) == 0 {
+			fmt.Fprintf(r.saida, "AVISO: o CPF %s não tem empréstimos ativos.\n", validacao.FormatarCPF(cpf))
+			return nil
+		}
+		for _, e := range ativos {
+			ids = append(ids, e.ID)
+		}
+	} else {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			c, _ := buscarComando("devolver")
+			return erroUso{c, fmt.Sprintf("'%s' não é um ID de empréstimo nem um CPF válido", args[0])}
+		}
+		ids = []int{id}
+	}
+
+	multa := 0
+	for _, id := range ids {
+		s, err := r.b.Devolver(r.ctx, id)
+		if err != nil {
+			return err
+		}
+		multa += s.Multa
+		if s.DiasAtraso == 0 {
+			fmt.Fprintf(r.saida, "SUCESSO: empréstimo %d devolvido (%d livro(s)) no prazo.\n", s.ID, s.QuantLivros)
+			continue
+		}
+		fmt.Fprintf(r.saida, "SUCESSO: empréstimo %d devolvido (%d livro(s)) com %d dia(s) de atraso; multa de %s.\n",
+			s.ID, s.QuantLivros, s.DiasAtraso, reais(s.Multa))
+	}
+	if len(ids) > 1 && multa > 0 {
+		fmt.Fprintf(r.saida, "Multa total: %s\n", reais(multa))
+	}
+	return nil
+}
+
+func (r *REPL) buscar(args []string) error {
+	termo := strings.Join(args[1:], " ")
+	tw := tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	switch args[0] {
+	case "livro":
+		livros, err := r.b.ListarLivros(r.ctx, servico.FiltroLivro{Titulo: termo})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(tw, "ISBN\tTÍTULO\tEDIÇÃO\tAUTORES")
+		for _, l := range livros {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", l.ISBN, l.Titulo, l.Edicao, nomesAutores(l.Autores))
+		}
+		fmt.Fprintf(tw, "(%d encontrado(s))\n", len(livros))
+		return nil
+	case "usuario":
+		usuarios, err := r.b.ListarUsuarios(r.ctx, repository.FiltroUsuario{Nome: termo})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(tw, "CPF\tNOME\tCATEGORIA\tVALIDADE")
+		for _, u := range usuarios {
+			fmt.Fprintf(tw, "%s\t%s %s\t%s\t%s\n", validacao.FormatarCPF(u.CPF), u.PrimeiroNome, u.Sobrenome, u.Categoria, data(u.ValidadeVinculo))
+		}
+		fmt.Fprintf(tw, "(%d encontrado(s))\n", len(usuarios))
+		return nil
+	case "autor":
+		autores, err := r.b.ListarAutores(r.ctx, termo)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(tw, "ID\tNOME")
+		for _, a := range autores {
+			fmt.Fprintf(tw, "%d\t%s %s\n", a.ID, a.PrimeiroNome, a.Sobrenome)
+		}
+		fmt.Fprintf(tw, "(%d encontrado(s))\n", len(autores))
+		return nil
+	case "emprestimo":
+		filtro := repository.FiltroEmprestimo{CPF: termo}
+		if termo == "" {
+			filtro.Status = servico.StatusAtivo
+		}
+		emprestimos, err := r.b.ListarEmprestimos(r.ctx, filtro)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(tw, "ID\tCPF\tDATA\tLIVROS\tSTATUS\tRENOVAÇÕES")
+		for _, e := range emprestimos {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%d\n", e.ID, validacao.FormatarCPF(e.ClienteUsuarioCPF), data(e.DataEmprestimo),
+				e.QuantLivros, e.Status, e.Renovacoes)
+		}
+		fmt.Fprintf(tw, "(%d encontrado(s))\n", len(emprestimos))
+		return nil
+	}
+	c, _ := buscarComando("buscar")
+	return erroUso{c, fmt.Sprintf("não é possível buscar '%s'", args[0])}
+}
+
+func (r *REPL) usuario(args []string) error {
+	p, err := r.b.PainelUsuario(r.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	s := p.Situacao
+	u := s.Usuario
+	tw := tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Nome:\t%s %s\n", u.PrimeiroNome, u.Sobrenome)
+	fmt.Fprintf(tw, "CPF:\t%s\n", validacao.FormatarCPF(u.CPF))
+	fmt.Fprintf(tw, "Categoria:\t%s (prazo de %d dias)\n", u.Categoria, u.PrazoDias())
+	if !u.ValidadeVinculo.IsZero() {
+		fmt.Fprintf(tw, "Vínculo até:\t%s\n", data(u.ValidadeVinculo))
+	}
+	if u.ResponsavelCPF != "" {
+		fmt.Fprintf(tw, "Responsável:\t%s\n", validacao.FormatarCPF(u.ResponsavelCPF))
+	}
+	fmt.Fprintf(tw, "Empréstimos ativos:\t%d (%d de %d livros)\n", s.EmprestimosAtivos, s.LivrosEmPosse, s.LimiteLivros)
+	if s.PodeEmprestar {
+		fmt.Fprintf(tw, "Situação:\tpode pegar livros\n")
+	} else {
+		fmt.Fprintf(tw, "Situação:\tbloqueado: %s\n", s.Motivo)
+	}
+	if p.MultaTotal > 0 {
+		fmt.Fprintf(tw, "Multas:\t%s\n", reais(p.MultaTotal))
+	}
+	tw.Flush()
+
+	if len(p.Ativos) > 0 {
+		fmt.Fprintln(r.saida)
+		tw = tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tDATA\tVENCIMENTO\tLIVROS\tRENOVAÇÕES\tATRASO\tMULTA")
+		for _, e := range p.Ativos {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%d\t%s\n", e.ID, data(e.DataEmprestimo), data(e.Vencimento),
+				e.QuantLivros, e.Renovacoes, e.DiasAtraso, reais(e.Multa))
+		}
+		tw.Flush()
+	}
+	for _, d := range p.Dependentes {
+		fmt.Fprintf(r.saida, "\nDependente: %s %s (%s)", d.Usuario.PrimeiroNome, d.Usuario.Sobrenome, validacao.FormatarCPF(d.Usuario.CPF))
+		if d.MultaTotal > 0 {
+			fmt.Fprintf(r.saida, ", multas de %s", reais(d.MultaTotal))
+		}
+		fmt.Fprintln(r.saida)
+		if len(d.Ativos) > 0 {
+			tw = tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tDATA\tVENCIMENTO\tLIVROS\tRENOVAÇÕES\tATRASO\tMULTA")
+			for _, e := range d.Ativos {
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%d\t%s\n", e.ID, data(e.DataEmprestimo), data(e.Vencimento),
+					e.QuantLivros, e.Renovacoes, e.DiasAtraso, reais(e.Multa))
+			}
+			tw.Flush()
+		}
+	}
+	var reservas []servico.PosicaoReserva
+	for _, rv := range p.Reservas {
+		if rv.Status == model.ReservaAtiva {
+			reservas = append(reservas, rv)
+		}
+	}
+	if len(reservas) > 0 {
+		fmt.Fprintln(r.saida)
+		tw = tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "RESERVA\tLIVRO\tDATA\tPOSIÇÃO")
+		for _, rv := range reservas {
+			livro := rv.LivroISBN
+			if rv.QualquerEdicao() {
+				livro = fmt.Sprintf("obra %d", *rv.ObraID)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\n", rv.ID, livro, data(rv.DataReserva), rv.Posicao)
+		}
+		tw.Flush()
+	}
+	return nil
+}
+
+func (r *REPL) livro(args []string) error {
+	l, err := r.b.ObterLivro(r.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(r.saida, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Título:\t%s\n", l.Titulo)
+	fmt.Fprintf(tw, "ISBN:\t%s\n", l.ISBN)
+	if l.Edicao != "" {
+		fmt.Fprintf(tw, "Edição:\t%s\n", l.Edicao)
+	}
+	if len(l.Autores) > 0 {
+		fmt.Fprintf(tw, "Autores:\t%s\n", nomesAutores(l.Autores))
+	}
+	if l.EditoraCNPJ != "" {
+		fmt.Fprintf(tw, "Editora:\t%s\n", l.EditoraCNPJ)
+	}
+	if l.NumPaginas > 0 {
+		fmt.Fprintf(tw, "Páginas:\t%d\n", l.NumPaginas)
+	}
+	if l.NumeroClassificacao != "" {
+		fmt.Fprintf(tw, "Classificação:\t%s %s\n", l.SistemaClassificacao, l.NumeroClassificacao)
+	}
+	if l.Idioma != "" {
+		fmt.Fprintf(tw, "Idioma:\t%s\n", l.Idioma)
+	}
+	return tw.Flush()
+}
+
+func nomesAutores(autores []model.Autor) string {
+	nomes := make([]string, len(autores))
+	for i, a := range autores {
+		nomes[i] = strings.TrimSpace(a.PrimeiroNome + " " + a.Sobrenome)
+	}
+	return strings.Join(nomes, ", ")
+}
+
+func data(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// reais formata um valor em centavos; zero não é mostrado
+func reais(centavos int) string {
+	if centavos == 0 {
+		return ""
+	}
+	return fmt.Sprintf("R$ %d,%02d", centavos/100, centavos%100)
+}
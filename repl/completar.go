@@ -0,0 +1,166 @@
+package repl
+
+import (
+	"context"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// completador sugere valores para o argumento que está sendo digitado; args
+// são os argumentos anteriores do comando
+type completador func(r *REPL, args []string, prefixo string) []string
+
+// maxSugestoes limita as sugestões de CPFs e ISBNs em acervos grandes
+const maxSugestoes = 50
+
+// tempoCompletar limita a espera pelo banco a cada tab, para que uma conexão
+// lenta não trave a linha de comando
+const tempoCompletar = 2 * time.Second
+
+// completar é o WordCompleter do liner: completa a palavra sob o cursor com
+// o nome do comando, na primeira palavra, ou com o completador do argumento
+func (r *REPL) completar(linha string, pos int) (inicio string, sugestoes []string, fim string) {
+	inicio, fim = linha[:pos], linha[pos:]
+	palavra := inicio[strings.LastIndexFunc(inicio, unicode.IsSpace)+1:]
+	inicio = inicio[:len(inicio)-len(palavra)]
+
+	anteriores := strings.Fields(inicio)
+	if len(anteriores) == 0 {
+		sugestoes = completarComando(r, nil, palavra)
+	} else if c, ok := buscarComando(anteriores[0]); ok && len(c.completar) > 0 {
+		args := anteriores[1:]
+		completar := c.completar[min(len(args), len(c.completar)-1)]
+		sugestoes = completar(r, args, palavra)
+	}
+	if len(sugestoes) == 1 && !strings.HasPrefix(fim, " ") {
+		sugestoes[0] += " "
+	}
+	return inicio, sugestoes, fim
+}
+
+func comPrefixo(opcoes []string, prefixo string) []string {
+	var sugestoes []string
+	for _, o := range opcoes {
+		if strings.HasPrefix(o, prefixo) {
+			sugestoes = append(sugestoes, o)
+		}
+	}
+	return sugestoes
+}
+
+func completarComando(_ *REPL, _ []string, prefixo string) []string {
+	var nomes []string
+	for _, c := range todosComandos() {
+		nomes = append(nomes, c.nome)
+	}
+	return comPrefixo(nomes, prefixo)
+}
+
+func completarEntidade(_ *REPL, _ []string, prefixo string) []string {
+	return comPrefixo(entidades, prefixo)
+}
+
+// completarTermo só completa a busca de empréstimos, que é pelo CPF
+func completarTermo(r *REPL, args []string, prefixo string) []string {
+	if len(args) == 1 && args[0] == "emprestimo" {
+		return completarCPF(r, args, prefixo)
+	}
+	return nil
+}
+
+// soDigitos remove a máscara para que "123.456" complete como "123456" e
+// informa se o que foi digitado é um documento e não parte de um nome. O X
+// aceito é o dígito verificador do ISBN-10
+func soDigitos(s string) (string, bool) {
+	digitos := strings.Map(func(c rune) rune {
+		if c == '.' || c == '-' {
+			return -1
+		}
+		return unicode.ToUpper(c)
+	}, s)
+	numero := strings.TrimSuffix(digitos, "X")
+	return digitos, strings.IndexFunc(numero, func(c rune) bool { return c < '0' || c > '9' }) < 0
+}
+
+// completarCPF completa pelo início do CPF ou, se o operador começou a
+// digitar um nome, troca o nome pelos CPFs dos usuários encontrados
+func completarCPF(r *REPL, _ []string, prefixo string) []string {
+	ctx, cancelar := context.WithTimeout(r.ctx, tempoCompletar)
+	defer cancelar()
+	digitos, numerico := soDigitos(prefixo)
+	filtro := repository.FiltroUsuario{}
+	if !numerico {
+		filtro.Nome = prefixo
+	}
+	usuarios, err := r.b.ListarUsuarios(ctx, filtro)
+	if err != nil {
+		return nil
+	}
+	var cpfs []string
+	for _, u := range usuarios {
+		if !numerico || strings.HasPrefix(u.CPF, digitos) {
+			cpfs = append(cpfs, u.CPF)
+		}
+	}
+	return limitar(cpfs)
+}
+
+// completarISBN completa pelo início do ISBN ou, com texto, pelos ISBNs dos
+// livros cujo título contém o que foi digitado
+func completarISBN(r *REPL, args []string, prefixo string) []string {
+	ctx, cancelar := context.WithTimeout(r.ctx, tempoCompletar)
+	defer cancelar()
+	digitos, numerico := soDigitos(prefixo)
+	filtro := servico.FiltroLivro{}
+	if !numerico {
+		filtro.Titulo = prefixo
+	}
+	livros, err := r.b.ListarLivros(ctx, filtro)
+	if err != nil {
+		return nil
+	}
+	var isbns []string
+	for _, l := range livros {
+		// livros já informados no mesmo empréstimo não são sugeridos de novo
+		if (!numerico || strings.HasPrefix(l.ISBN, digitos)) && !slices.Contains(args, l.ISBN) {
+			isbns = append(isbns, l.ISBN)
+		}
+	}
+	return limitar(isbns)
+}
+
+// completarDevolucao sugere os IDs dos empréstimos ativos e os CPFs de quem
+// tem empréstimos ativos
+func completarDevolucao(r *REPL, args []string, prefixo string) []string {
+	if _, numerico := soDigitos(prefixo); !numerico {
+		return completarCPF(r, args, prefixo)
+	}
+	ctx, cancelar := context.WithTimeout(r.ctx, tempoCompletar)
+	defer cancelar()
+	ativos, err := r.b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{Status: servico.StatusAtivo})
+	if err != nil {
+		return nil
+	}
+	var opcoes []string
+	for _, e := range ativos {
+		opcoes = append(opcoes, strconv.Itoa(e.ID))
+		if !slices.Contains(opcoes, e.ClienteUsuarioCPF) {
+			opcoes = append(opcoes, e.ClienteUsuarioCPF)
+		}
+	}
+	digitos, _ := soDigitos(prefixo)
+	return limitar(comPrefixo(opcoes, digitos))
+}
+
+func limitar(sugestoes []string) []string {
+	slices.Sort(sugestoes)
+	if len(sugestoes) > maxSugestoes {
+		return sugestoes[:maxSugestoes]
+	}
+	return sugestoes
+}
@@ -0,0 +1,152 @@
+// Package repl implementa o modo interativo orientado a comandos: o operador
+// digita "emprestar <cpf> <isbn>", "devolver <id>" ou "buscar livro <título>"
+// em uma linha com edição, histórico persistente entre sessões e
+// completação pela tecla tab dos comandos, CPFs e ISBNs cadastrados. As
+// operações são as do pacote servico, as mesmas da interface de tela cheia.
+package repl
+
+import (
+	"context"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+const prompt = "biblioteca> "
+
+// REPL é a sessão de comandos do operador
+type REPL struct {
+	ctx   context.Context
+	b     *servico.Biblioteca
+	saida io.Writer
+	// menu é marcado pelo comando "menu", que encerra a sessão e abre o menu numerado
+	menu bool
+}
+
+// ArquivoHistoricoFromEnv retorna o arquivo do histórico de comandos,
+// indicado por REPL_HISTORICO ou, sem a variável, ~/.biblioteca_historico.
+// Vazio desativa o histórico persistente
+func ArquivoHistoricoFromEnv() string {
+	if arquivo, ok := os.LookupEnv("REPL_HISTORICO"); ok {
+		return arquivo
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".biblioteca_historico")
+}
+
+// Executar lê e executa comandos até "sair" ou o fim da entrada (ctrl+d).
+// menu indica que o operador pediu o menu numerado, para as operações que os
+// comandos não cobrem
+func Executar(ctx context.Context, b *servico.Biblioteca) (menu bool, err error) {
+	r := &REPL{ctx: ctx, b: b, saida: os.Stdout}
+	linha := liner.NewLiner()
+	defer linha.Close()
+	linha.SetCtrlCAborts(true)
+	linha.SetTabCompletionStyle(liner.TabPrints)
+	linha.SetWordCompleter(r.completar)
+
+	historico := ArquivoHistoricoFromEnv()
+	if historico != "" {
+		if f, err := os.Open(historico); err == nil {
+			linha.ReadHistory(f)
+			f.Close()
+		}
+	}
+
+	fmt.Fprintln(r.saida, `Digite "ajuda" para ver os comandos, tab para completar e "sair" para terminar.`)
+	for !r.menu {
+		entrada, err := linha.Prompt(prompt)
+		if errors.Is(err, liner.ErrPromptAborted) {
+			// ctrl+c descarta a linha, como no shell
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(r.saida)
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+		campos := strings.Fields(entrada)
+		if len(campos) == 0 {
+			continue
+		}
+		linha.AppendHistory(strings.Join(campos, " "))
+		if campos[0] == "sair" {
+			break
+		}
+		r.executar(campos[0], campos[1:])
+	}
+
+	if historico != "" {
+		if err := salvarHistorico(linha, historico); err != nil {
+			fmt.Fprintf(os.Stderr, "AVISO: não foi possível salvar o histórico em %s: %v\n", historico, err)
+		}
+	}
+	return r.menu, nil
+}
+
+func salvarHistorico(linha *liner.State, arquivo string) error {
+	f, err := os.OpenFile(arquivo, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := linha.WriteHistory(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// executar roda um comando e escreve o resultado ou o erro; erros não
+// encerram a sessão
+func (r *REPL) executar(nome string, args []string) {
+	c, ok := buscarComando(nome)
+	if !ok {
+		fmt.Fprintf(r.saida, "ERRO: comando desconhecido: %s. Digite \"ajuda\" para ver os comandos.\n", nome)
+		return
+	}
+	if len(args) < c.minimo || (c.maximo >= 0 && len(args) > c.maximo) {
+		fmt.Fprintf(r.saida, "uso: %s\n", c.uso())
+		return
+	}
+	if err := c.executar(r, args); err != nil {
+		r.escreverErro(err)
+	}
+}
+
+// escreverErro lista os erros de campo um por linha, como os subcomandos
+func (r *REPL) escreverErro(err error) {
+	var uso erroUso
+	if errors.As(err, &uso) {
+		fmt.Fprintf(r.saida, "ERRO: %s\nuso: %s\n", uso.motivo, uso.comando.uso())
+		return
+	}
+	campos, ok := validacao.ErrosDeCampo(err)
+	if !ok {
+		fmt.Fprintf(r.saida, "ERRO: %v\n", err)
+		return
+	}
+	fmt.Fprintf(r.saida, "ERRO: %d problema(s) encontrado(s):\n", len(campos))
+	for _, e := range campos {
+		fmt.Fprintf(r.saida, "  - %s: %s\n", e.Campo, e.Mensagem)
+	}
+}
+
+// erroUso indica argumentos que não correspondem à sintaxe do comando
+type erroUso struct {
+	comando *comando
+	motivo  string
+}
+
+func (e erroUso) Error() string { return e.motivo }
@@ -0,0 +1,52 @@
+package repository
+
+// CondicaoLivro é uma expressão de busca no acervo, traduzida por cada banco
+// em uma única consulta. Uma condição simples compara um campo do livro com
+// Valor; uma condição composta (Operador preenchido) combina Esquerda e
+// Direita, e os demais campos são ignorados
+type CondicaoLivro struct {
+	Campo   CampoLivro
+	Relacao RelacaoBusca
+	Valor   string
+
+	Operador OperadorBusca
+	Esquerda *CondicaoLivro
+	Direita  *CondicaoLivro
+}
+
+// CampoLivro é o dado do livro comparado por uma condição simples
+type CampoLivro string
+
+const (
+	CampoTodos    CampoLivro = "todos"    // todos os livros; Relacao e Valor são ignorados
+	CampoQualquer CampoLivro = "qualquer" // título ou nome de um dos autores
+	CampoTitulo   CampoLivro = "titulo"
+	// nome de um dos autores, como "Machado de Assis" ou "Assis, Machado de"
+	CampoAutor CampoLivro = "autor"
+	// ISBN-10 ou ISBN-13, com ou sem hífens; RelacaoContem compara como RelacaoIgual
+	CampoISBN    CampoLivro = "isbn"
+	CampoEditora CampoLivro = "editora" // nome da editora
+	// nome de uma categoria de assunto; inclui os livros das subcategorias
+	CampoAssunto       CampoLivro = "assunto"
+	CampoClassificacao CampoLivro = "classificacao" // número de classificação (CDD ou CDU)
+	CampoIdioma        CampoLivro = "idioma"        // código ISO 639-1
+)
+
+// RelacaoBusca é a comparação feita entre o campo e o valor, sempre sem
+// diferenciar maiúsculas
+type RelacaoBusca string
+
+const (
+	RelacaoContem  RelacaoBusca = "contem" // o valor aparece em qualquer posição
+	RelacaoIgual   RelacaoBusca = "igual"
+	RelacaoPrefixo RelacaoBusca = "prefixo" // o campo começa com o valor
+)
+
+// OperadorBusca combina duas condições
+type OperadorBusca string
+
+const (
+	OperadorE    OperadorBusca = "e"
+	OperadorOu   OperadorBusca = "ou"
+	OperadorENao OperadorBusca = "e-nao" // atende à esquerda e não atende à direita
+)
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// classes de erro comuns aos dois bancos, usadas para escolher a resposta
+// adequada (ex.: status HTTP) sem depender do driver
+var (
+	ErrNaoEncontrado = errors.New("registro não encontrado")
+	ErrDuplicado     = errors.New("registro já existe")
+	ErrReferencia    = errors.New("referência a registro inexistente ou em uso")
+	ErrInvalido      = errors.New("dados rejeitados pelo banco")
+)
+
+// NaoEncontrado é o erro da classe ErrNaoEncontrado que identifica o
+// registro procurado
+func NaoEncontrado(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrNaoEncontrado, fmt.Sprintf(format, args...))
+}
+
+// códigos SQLSTATE do PostgreSQL tratados por Classificar
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgStringTooLong       = "22001"
+)
+
+// Classificar acrescenta ao erro retornado pelo pgx ou pelo driver do MongoDB
+// a classe correspondente (ErrNaoEncontrado, ErrDuplicado...), preservando o
+// erro original. Erros sem classe conhecida são retornados sem alteração
+func Classificar(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", ErrNaoEncontrado, err)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %w", ErrDuplicado, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w: %w", ErrDuplicado, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %w", ErrReferencia, err)
+		case pgCheckViolation, pgNotNullViolation, pgStringTooLong:
+			return fmt.Errorf("%w: %w", ErrInvalido, err)
+		}
+	}
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"crud-biblioteca/model"
+	"time"
 )
 
 type UsuarioRepository interface {
@@ -10,12 +11,40 @@ type UsuarioRepository interface {
 	GetByCPF(ctx context.Context, cpf string) (*model.Usuario, error)
 	Update(ctx context.Context, usuario model.Usuario) error
 	Delete(ctx context.Context, cpf string) error
+	// ListByResponsavel retorna os usuários (menores de idade) que têm o CPF informado como responsável
+	ListByResponsavel(ctx context.Context, cpf string) ([]model.Usuario, error)
+	List(ctx context.Context, filtro FiltroUsuario) ([]model.Usuario, error)
+
+	// SenhaHash retorna o hash bcrypt da senha do portal do usuário; vazio se
+	// a senha ainda não foi definida. A senha não faz parte de model.Usuario
+	// para não sair nas respostas da API nem nas exportações
+	SenhaHash(ctx context.Context, cpf string) (string, error)
+	DefinirSenha(ctx context.Context, cpf, hash string) error
+}
+
+// FiltroUsuario restringe a listagem de usuários; campos vazios não filtram
+type FiltroUsuario struct {
+	Nome      string // parte do primeiro nome ou do sobrenome, sem diferenciar maiúsculas
+	Categoria model.CategoriaUsuario
+	CPFs      []string // qualquer um dos CPFs; usado para carregar vários usuários em lote
 }
 
 type AutorRepository interface {
 	Create(ctx context.Context, autor model.Autor) error
 	GetByID(ctx context.Context, id int) (*model.Autor, error)
+	Update(ctx context.Context, autor model.Autor) error
 	Delete(ctx context.Context, id int) error
+	// List busca pelo nome ou sobrenome; vazio lista todos
+	List(ctx context.Context, nome string) ([]model.Autor, error)
+}
+
+type EditoraRepository interface {
+	Create(ctx context.Context, editora model.Editora) error
+	GetByCNPJ(ctx context.Context, cnpj string) (*model.Editora, error)
+	Update(ctx context.Context, editora model.Editora) error
+	Delete(ctx context.Context, cnpj string) error
+	// ListByCNPJs carrega várias editoras em uma consulta; CNPJs inexistentes são ignorados
+	ListByCNPJs(ctx context.Context, cnpjs []string) ([]model.Editora, error)
 }
 
 type LivroRepository interface {
@@ -23,9 +52,85 @@ type LivroRepository interface {
 	GetByISBN(ctx context.Context, isbn string) (*model.Livro, error)
 	Update(ctx context.Context, livro model.Livro) error
 	Delete(ctx context.Context, isbn string) error
-	
+
 	AddAutor(ctx context.Context, isbn string, autor model.Autor) error
 	RemoveAutor(ctx context.Context, isbn string, autorID int) error
+	// AutoresPorLivro carrega em uma consulta os autores de vários livros, por ISBN
+	AutoresPorLivro(ctx context.Context, isbns []string) (map[string][]model.Autor, error)
+
+	AddCategoria(ctx context.Context, isbn string, categoriaID int) error
+	RemoveCategoria(ctx context.Context, isbn string, categoriaID int) error
+	// ListByCategoria retorna os livros da categoria e de todas as suas subcategorias
+	ListByCategoria(ctx context.Context, categoriaID int) ([]model.Livro, error)
+
+	// Search busca livros pelo título; use model.AgruparPorObra para colapsar as edições
+	Search(ctx context.Context, termo string) ([]model.Livro, error)
+	ListByObra(ctx context.Context, obraID int) ([]model.Livro, error)
+	// Consultar retorna os livros que atendem à condição, ordenados por título,
+	// a partir da posição inicio (0 é o primeiro) e no máximo limite deles,
+	// além do total encontrado. Os livros vêm com autores e categorias
+	Consultar(ctx context.Context, c CondicaoLivro, inicio, limite int) ([]model.Livro, int, error)
+}
+
+type ObraRepository interface {
+	Create(ctx context.Context, obra model.Obra) error
+	GetByID(ctx context.Context, id int) (*model.Obra, error)
+	Update(ctx context.Context, obra model.Obra) error
+	Delete(ctx context.Context, id int) error
+	// ListByIDs carrega várias obras em uma consulta; IDs inexistentes são ignorados
+	ListByIDs(ctx context.Context, ids []int) ([]model.Obra, error)
+}
+
+type ReservaRepository interface {
+	Create(ctx context.Context, reserva model.Reserva) error
+	GetByID(ctx context.Context, id int) (*model.Reserva, error)
+	Update(ctx context.Context, reserva model.Reserva) error
+	Delete(ctx context.Context, id int) error
+
+	ListByUsuario(ctx context.Context, cpf string) ([]model.Reserva, error)
+	// FilaPorLivro retorna, por ordem de chegada, as reservas ativas que podem
+	// ser atendidas por um exemplar do livro: as feitas para essa edição e as
+	// feitas para qualquer edição da obra do livro
+	FilaPorLivro(ctx context.Context, isbn string) ([]model.Reserva, error)
+	// ProximoID retorna o maior ID cadastrado mais um
+	ProximoID(ctx context.Context) (int, error)
+}
+
+type CategoriaRepository interface {
+	Create(ctx context.Context, categoria model.Categoria) error
+	GetByID(ctx context.Context, id int) (*model.Categoria, error)
+	Update(ctx context.Context, categoria model.Categoria) error
+	Delete(ctx context.Context, id int) error
+
+	// ListSubcategorias retorna os filhos diretos de paiID (nil lista as categorias raiz)
+	ListSubcategorias(ctx context.Context, paiID *int) ([]model.Categoria, error)
+	ContarLivros(ctx context.Context) ([]model.ContagemCategoria, error)
+}
+
+type RecursoDigitalRepository interface {
+	Create(ctx context.Context, recurso model.RecursoDigital) error
+	GetByID(ctx context.Context, id int) (*model.RecursoDigital, error)
+	ListByLivro(ctx context.Context, isbn string) ([]model.RecursoDigital, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type PeriodicoRepository interface {
+	Create(ctx context.Context, periodico model.Periodico) error
+	GetByISSN(ctx context.Context, issn string) (*model.Periodico, error)
+	Update(ctx context.Context, periodico model.Periodico) error
+	Delete(ctx context.Context, issn string) error
+	ListAtivos(ctx context.Context) ([]model.Periodico, error)
+}
+
+type FasciculoRepository interface {
+	Create(ctx context.Context, fasciculo model.Fasciculo) error
+	GetByID(ctx context.Context, id string) (*model.Fasciculo, error)
+	Update(ctx context.Context, fasciculo model.Fasciculo) error
+	Delete(ctx context.Context, id string) error
+
+	// ListByPeriodico retorna os fascículos em ordem de volume e número
+	ListByPeriodico(ctx context.Context, issn string) ([]model.Fasciculo, error)
+	ListByStatus(ctx context.Context, status string) ([]model.Fasciculo, error)
 }
 
 type EmprestimoRepository interface {
@@ -33,4 +138,85 @@ type EmprestimoRepository interface {
 	GetByID(ctx context.Context, id int) (*model.Emprestimo, error)
 	Update(ctx context.Context, emprestimo model.Emprestimo) error
 	Delete(ctx context.Context, id int) error
+	// List retorna os empréstimos mais recentes primeiro
+	List(ctx context.Context, filtro FiltroEmprestimo) ([]model.Emprestimo, error)
+	// ProximoID retorna o maior ID cadastrado mais um
+	ProximoID(ctx context.Context) (int, error)
+}
+
+// FiltroEmprestimo restringe a listagem de empréstimos; campos vazios não filtram
+type FiltroEmprestimo struct {
+	CPF    string
+	Status string
+	CPFs   []string // qualquer um dos CPFs; usado para carregar os empréstimos de vários usuários em lote
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook model.Webhook) error
+	GetByID(ctx context.Context, id int) (*model.Webhook, error)
+	Update(ctx context.Context, webhook model.Webhook) error
+	// Delete remove a assinatura e o seu registro de entregas
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]model.Webhook, error)
+	// ProximoID retorna o maior ID cadastrado mais um
+	ProximoID(ctx context.Context) (int, error)
+
+	// CriarEntrega enfileira uma entrega; uma entrega com o mesmo ID já
+	// enfileirada resulta em erro de registro duplicado
+	CriarEntrega(ctx context.Context, entrega model.EntregaWebhook) error
+	AtualizarEntrega(ctx context.Context, entrega model.EntregaWebhook) error
+	// EntregasPendentes retorna, das mais antigas, as entregas pendentes cuja
+	// próxima tentativa é até a data informada
+	EntregasPendentes(ctx context.Context, ate time.Time, limite int) ([]model.EntregaWebhook, error)
+	// ListEntregas retorna as entregas da assinatura, das mais recentes
+	ListEntregas(ctx context.Context, webhookID int, limite int) ([]model.EntregaWebhook, error)
+}
+
+// OutboxRepository guarda os eventos de domínio até o relay publicá-los
+type OutboxRepository interface {
+	Registrar(ctx context.Context, evento model.EventoDominio) error
+	// Pendentes retorna, na ordem dos IDs, os eventos ainda não publicados
+	Pendentes(ctx context.Context, limite int) ([]model.EventoDominio, error)
+	MarcarPublicado(ctx context.Context, id string, em time.Time) error
+	// RegistrarFalha conta mais uma tentativa de publicação e guarda o erro
+	RegistrarFalha(ctx context.Context, id string, erro string) error
+	// List retorna os eventos mais recentes, publicados ou não
+	List(ctx context.Context, limite int) ([]model.EventoDominio, error)
+	// Depois retorna, na ordem dos IDs, os eventos dos tipos informados com
+	// ID maior que o informado, publicados ou não
+	Depois(ctx context.Context, id string, tipos []string, limite int) ([]model.EventoDominio, error)
+}
+
+// AvisosOutbox avisa quando novos eventos são gravados no outbox, para que
+// quem os acompanha não precise consultar o banco a todo instante
+type AvisosOutbox interface {
+	// Escutar chama aviso a cada evento confirmado até o contexto ser
+	// cancelado ou a conexão falhar
+	Escutar(ctx context.Context, aviso func()) error
+}
+
+// Repositorios reúne as implementações de um mesmo banco de dados
+type Repositorios struct {
+	Usuarios         UsuarioRepository
+	Livros           LivroRepository
+	Autores          AutorRepository
+	Emprestimos      EmprestimoRepository
+	Categorias       CategoriaRepository
+	RecursosDigitais RecursoDigitalRepository
+	Obras            ObraRepository
+	Reservas         ReservaRepository
+	Periodicos       PeriodicoRepository
+	Fasciculos       FasciculoRepository
+	Editoras         EditoraRepository
+	Webhooks         WebhookRepository
+	Outbox           OutboxRepository
+
+	// Avisos é nil nos repositórios de uma transação e nos bancos que não
+	// oferecem notificações
+	Avisos AvisosOutbox
+
+	// Transacao executa f com repositórios ligados a uma transação, confirmada
+	// se f terminar sem erro e desfeita caso contrário. É nil nos repositórios
+	// que já estão dentro de uma transação
+	Transacao func(ctx context.Context, f func(ctx context.Context, repos Repositorios) error) error
 }
@@ -3,8 +3,11 @@ package mongo
 import (
 	"context"
 	"crud-biblioteca/model"
+	"regexp"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AutorRepository struct {
@@ -29,3 +32,30 @@ func (r *AutorRepository) Delete(ctx context.Context, id int) error {
 	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
+
+func (r *AutorRepository) Update(ctx context.Context, autor model.Autor) error {
+	filter := bson.M{"_id": autor.ID}
+	update := bson.M{"$set": bson.M{
+		"primeiro_nome": autor.PrimeiroNome,
+		"sobrenome":     autor.Sobrenome,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// List busca pelo nome ou sobrenome, sem diferenciar maiúsculas; vazio lista todos
+func (r *AutorRepository) List(ctx context.Context, nome string) ([]model.Autor, error) {
+	filter := bson.M{}
+	if nome != "" {
+		regex := bson.M{"$regex": regexp.QuoteMeta(nome), "$options": "i"}
+		filter["$or"] = bson.A{bson.M{"primeiro_nome": regex}, bson.M{"sobrenome": regex}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "sobrenome", Value: 1}, {Key: "primeiro_nome", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var autores []model.Autor
+	err = cursor.All(ctx, &autores)
+	return autores, err
+}
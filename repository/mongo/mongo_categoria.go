@@ -0,0 +1,97 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoriaRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCategoriaRepository(db *mongo.Database) *CategoriaRepository {
+	return &CategoriaRepository{Collection: db.Collection("categorias")}
+}
+
+func (r *CategoriaRepository) Create(ctx context.Context, categoria model.Categoria) error {
+	_, err := r.Collection.InsertOne(ctx, categoria)
+	return err
+}
+
+func (r *CategoriaRepository) GetByID(ctx context.Context, id int) (*model.Categoria, error) {
+	var categoria model.Categoria
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&categoria)
+	if err != nil {
+		return nil, err
+	}
+	return &categoria, nil
+}
+
+func (r *CategoriaRepository) Update(ctx context.Context, categoria model.Categoria) error {
+	filter := bson.M{"_id": categoria.ID}
+	update := bson.M{"$set": bson.M{
+		"nome":     categoria.Nome,
+		"codigo":   categoria.Codigo,
+		"pai_id":   categoria.PaiID,
+		"restrita": categoria.Restrita,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *CategoriaRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *CategoriaRepository) ListSubcategorias(ctx context.Context, paiID *int) ([]model.Categoria, error) {
+	// nas categorias raiz o campo pai_id não existe (omitempty) ou é nulo após um Update
+	filter := bson.M{"pai_id": nil}
+	if paiID != nil {
+		filter = bson.M{"pai_id": *paiID}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "codigo", Value: 1}, {Key: "nome", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var categorias []model.Categoria
+	err = cursor.All(ctx, &categorias)
+	return categorias, err
+}
+
+func (r *CategoriaRepository) ContarLivros(ctx context.Context) ([]model.ContagemCategoria, error) {
+	// junta cada categoria com os livros que a referenciam no array "categorias"
+	pipeline := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "livros",
+			"localField":   "_id",
+			"foreignField": "categorias",
+			"as":           "livros",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"quantidade": bson.M{"$size": "$livros"}}}},
+		{{Key: "$project", Value: bson.M{"livros": 0}}},
+		{{Key: "$sort", Value: bson.D{{Key: "codigo", Value: 1}, {Key: "nome", Value: 1}}}},
+	}
+	cursor, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultado []struct {
+		model.Categoria `bson:",inline"`
+		Quantidade      int `bson:"quantidade"`
+	}
+	if err := cursor.All(ctx, &resultado); err != nil {
+		return nil, err
+	}
+	contagens := make([]model.ContagemCategoria, 0, len(resultado))
+	for _, c := range resultado {
+		contagens = append(contagens, model.ContagemCategoria{Categoria: c.Categoria, Quantidade: c.Quantidade})
+	}
+	return contagens, nil
+}
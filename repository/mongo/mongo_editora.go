@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type EditoraRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewEditoraRepository(db *mongo.Database) *EditoraRepository {
+	return &EditoraRepository{Collection: db.Collection("editoras")}
+}
+
+func (r *EditoraRepository) Create(ctx context.Context, editora model.Editora) error {
+	_, err := r.Collection.InsertOne(ctx, editora)
+	return err
+}
+
+func (r *EditoraRepository) GetByCNPJ(ctx context.Context, cnpj string) (*model.Editora, error) {
+	var editora model.Editora
+	err := r.Collection.FindOne(ctx, bson.M{"_id": cnpj}).Decode(&editora)
+	if err != nil {
+		return nil, err
+	}
+	return &editora, nil
+}
+
+func (r *EditoraRepository) Update(ctx context.Context, editora model.Editora) error {
+	filter := bson.M{"_id": editora.CNPJ}
+	update := bson.M{"$set": bson.M{"nome": editora.Nome}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *EditoraRepository) Delete(ctx context.Context, cnpj string) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": cnpj})
+	return err
+}
+
+func (r *EditoraRepository) ListByCNPJs(ctx context.Context, cnpjs []string) ([]model.Editora, error) {
+	cursor, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": cnpjs}})
+	if err != nil {
+		return nil, err
+	}
+	var editoras []model.Editora
+	err = cursor.All(ctx, &editoras)
+	return editoras, err
+}
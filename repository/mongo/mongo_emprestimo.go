@@ -3,9 +3,11 @@ package mongo
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type EmprestimoRepository struct {
@@ -33,10 +35,12 @@ func (r *EmprestimoRepository) GetByID(ctx context.Context, id int) (*model.Empr
 func (r *EmprestimoRepository) Update(ctx context.Context, emprestimo model.Emprestimo) error {
 	filter := bson.M{"_id": emprestimo.ID}
 	update := bson.M{"$set": bson.M{
-		"data_emprestimo":    emprestimo.DataEmprestimo,
+		"data_emprestimo":     emprestimo.DataEmprestimo,
 		"status":              emprestimo.Status,
 		"quant_livros":        emprestimo.QuantLivros,
 		"cliente_usuario_cpf": emprestimo.ClienteUsuarioCPF,
+		"renovacoes":          emprestimo.Renovacoes,
+		"data_devolucao":      emprestimo.DataDevolucao,
 	}}
 	_, err := r.Collection.UpdateOne(ctx, filter, update)
 	return err
@@ -46,3 +50,34 @@ func (r *EmprestimoRepository) Delete(ctx context.Context, id int) error {
 	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
+
+func (r *EmprestimoRepository) ProximoID(ctx context.Context) (int, error) {
+	var ultimo model.Emprestimo
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	err := r.Collection.FindOne(ctx, bson.M{}, opts).Decode(&ultimo)
+	if err == mongo.ErrNoDocuments {
+		return 1, nil
+	}
+	return ultimo.ID + 1, err
+}
+
+func (r *EmprestimoRepository) List(ctx context.Context, filtro repository.FiltroEmprestimo) ([]model.Emprestimo, error) {
+	filter := bson.M{}
+	if filtro.CPF != "" {
+		filter["cliente_usuario_cpf"] = filtro.CPF
+	}
+	if filtro.Status != "" {
+		filter["status"] = filtro.Status
+	}
+	if filtro.CPFs != nil {
+		filter["cliente_usuario_cpf"] = bson.M{"$in": filtro.CPFs}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "data_emprestimo", Value: -1}, {Key: "_id", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var emprestimos []model.Emprestimo
+	err = cursor.All(ctx, &emprestimos)
+	return emprestimos, err
+}
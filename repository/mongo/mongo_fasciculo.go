@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FasciculoRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewFasciculoRepository(db *mongo.Database) *FasciculoRepository {
+	return &FasciculoRepository{Collection: db.Collection("fasciculos")}
+}
+
+func (r *FasciculoRepository) Create(ctx context.Context, fasciculo model.Fasciculo) error {
+	_, err := r.Collection.InsertOne(ctx, fasciculo)
+	return err
+}
+
+func (r *FasciculoRepository) GetByID(ctx context.Context, id string) (*model.Fasciculo, error) {
+	var fasciculo model.Fasciculo
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&fasciculo)
+	if err != nil {
+		return nil, err
+	}
+	return &fasciculo, nil
+}
+
+func (r *FasciculoRepository) Update(ctx context.Context, fasciculo model.Fasciculo) error {
+	filter := bson.M{"_id": fasciculo.ID}
+	update := bson.M{"$set": bson.M{
+		"data_prevista":    fasciculo.DataPrevista,
+		"data_recebimento": fasciculo.DataRecebimento,
+		"data_reclamacao":  fasciculo.DataReclamacao,
+		"status":           fasciculo.Status,
+		"emprestado_cpf":   fasciculo.EmprestadoCPF,
+		"data_emprestimo":  fasciculo.DataEmprestimo,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *FasciculoRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *FasciculoRepository) ListByPeriodico(ctx context.Context, issn string) ([]model.Fasciculo, error) {
+	return r.find(ctx, bson.M{"periodico_issn": issn})
+}
+
+func (r *FasciculoRepository) ListByStatus(ctx context.Context, status string) ([]model.Fasciculo, error) {
+	return r.find(ctx, bson.M{"status": status})
+}
+
+func (r *FasciculoRepository) find(ctx context.Context, filter bson.M) ([]model.Fasciculo, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "periodico_issn", Value: 1}, {Key: "volume", Value: 1}, {Key: "numero", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var fasciculos []model.Fasciculo
+	err = cursor.All(ctx, &fasciculos)
+	return fasciculos, err
+}
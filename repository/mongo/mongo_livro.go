@@ -3,8 +3,14 @@ package mongo
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"regexp"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type LivroRepository struct {
@@ -19,16 +25,24 @@ func (r *LivroRepository) Create(ctx context.Context, livro model.Livro) error {
 	_, err := r.Collection.InsertOne(ctx, livro)
 	return err
 }
+
+// GetByISBN aceita o ISBN-10 ou ISBN-13, com ou sem hífens
 func (r *LivroRepository) GetByISBN(ctx context.Context, isbn string) (*model.Livro, error) {
 	var livro model.Livro
-	err := r.Collection.FindOne(ctx, bson.M{"_id": isbn}).Decode(&livro)
+	err := r.Collection.FindOne(ctx, bson.M{"_id": bson.M{"$in": validacao.FormasISBN(isbn)}}).Decode(&livro)
 	return &livro, err
 }
 func (r *LivroRepository) Update(ctx context.Context, livro model.Livro) error {
 	filter := bson.M{"_id": livro.ISBN}
 	update := bson.M{"$set": bson.M{
-		"titulo": livro.Titulo,
-		"edicao": livro.Edicao,
+		"titulo":                livro.Titulo,
+		"edicao":                livro.Edicao,
+		"num_paginas":           livro.NumPaginas,
+		"editora_cnpj":          livro.EditoraCNPJ,
+		"sistema_classificacao": livro.SistemaClassificacao,
+		"numero_classificacao":  livro.NumeroClassificacao,
+		"obra_id":               livro.ObraID,
+		"idioma":                livro.Idioma,
 	}}
 	_, err := r.Collection.UpdateOne(ctx, filter, update)
 	return err
@@ -38,6 +52,31 @@ func (r *LivroRepository) Delete(ctx context.Context, isbn string) error {
 	return err
 }
 
+// Search busca livros cujo título contenha o termo, sem diferenciar maiúsculas
+func (r *LivroRepository) Search(ctx context.Context, termo string) ([]model.Livro, error) {
+	filter := bson.M{"titulo": bson.M{"$regex": regexp.QuoteMeta(termo), "$options": "i"}}
+	opts := options.Find().SetSort(bson.D{{Key: "titulo", Value: 1}, {Key: "edicao", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var livros []model.Livro
+	err = cursor.All(ctx, &livros)
+	return livros, err
+}
+
+// ListByObra retorna todas as edições e traduções de uma obra
+func (r *LivroRepository) ListByObra(ctx context.Context, obraID int) ([]model.Livro, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "idioma", Value: 1}, {Key: "edicao", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, bson.M{"obra_id": obraID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var livros []model.Livro
+	err = cursor.All(ctx, &livros)
+	return livros, err
+}
+
 // CRUD do relacionamento embutido
 func (r *LivroRepository) AddAutor(ctx context.Context, isbn string, autor model.Autor) error {
 	filter := bson.M{"_id": isbn}
@@ -52,3 +91,224 @@ func (r *LivroRepository) RemoveAutor(ctx context.Context, isbn string, autorID
 	_, err := r.Collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// AutoresPorLivro lê os autores embutidos de todos os livros em uma única consulta
+func (r *LivroRepository) AutoresPorLivro(ctx context.Context, isbns []string) (map[string][]model.Autor, error) {
+	opts := options.Find().SetProjection(bson.M{"autores": 1})
+	cursor, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": isbns}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var livros []model.Livro
+	if err := cursor.All(ctx, &livros); err != nil {
+		return nil, err
+	}
+	autores := make(map[string][]model.Autor, len(livros))
+	for _, l := range livros {
+		autores[l.ISBN] = l.Autores
+	}
+	return autores, nil
+}
+
+// classificação por assunto: os IDs das categorias ficam embutidos no livro
+func (r *LivroRepository) AddCategoria(ctx context.Context, isbn string, categoriaID int) error {
+	filter := bson.M{"_id": isbn}
+	update := bson.M{"$addToSet": bson.M{"categorias": categoriaID}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *LivroRepository) RemoveCategoria(ctx context.Context, isbn string, categoriaID int) error {
+	filter := bson.M{"_id": isbn}
+	update := bson.M{"$pull": bson.M{"categorias": categoriaID}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *LivroRepository) ListByCategoria(ctx context.Context, categoriaID int) ([]model.Livro, error) {
+	ids, err := r.arvoreCategorias(ctx, bson.M{"_id": categoriaID})
+	if err != nil {
+		return nil, err
+	}
+	// a categoria informada conta mesmo que o cadastro dela tenha sido removido
+	ids = append(ids, categoriaID)
+
+	opts := options.Find().SetSort(bson.D{{Key: "numero_classificacao", Value: 1}, {Key: "titulo", Value: 1}})
+	livrosCursor, err := r.Collection.Find(ctx, bson.M{"categorias": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var livros []model.Livro
+	err = livrosCursor.All(ctx, &livros)
+	return livros, err
+}
+
+// arvoreCategorias retorna os IDs das categorias que atendem ao filtro e de
+// todas as suas subcategorias
+func (r *LivroRepository) arvoreCategorias(ctx context.Context, filtro bson.M) ([]int, error) {
+	// $graphLookup percorre a hierarquia de subcategorias a partir das categorias encontradas
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filtro}},
+		{{Key: "$graphLookup", Value: bson.M{
+			"from":             "categorias",
+			"startWith":        "$_id",
+			"connectFromField": "_id",
+			"connectToField":   "pai_id",
+			"as":               "descendentes",
+		}}},
+	}
+	cursor, err := r.Collection.Database().Collection("categorias").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var arvore []struct {
+		ID           int `bson:"_id"`
+		Descendentes []struct {
+			ID int `bson:"_id"`
+		} `bson:"descendentes"`
+	}
+	if err := cursor.All(ctx, &arvore); err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, c := range arvore {
+		ids = append(ids, c.ID)
+		for _, d := range c.Descendentes {
+			ids = append(ids, d.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Consultar traduz a condição em um filtro do find. Editora e assunto ficam
+// em outras coleções e são resolvidos antes, nos CNPJs e IDs correspondentes
+func (r *LivroRepository) Consultar(ctx context.Context, c repository.CondicaoLivro, inicio, limite int) ([]model.Livro, int, error) {
+	filtro, err := r.filtroCondicao(ctx, c)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := r.Collection.CountDocuments(ctx, filtro)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limite <= 0 || int64(inicio) >= total {
+		return []model.Livro{}, int(total), nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "titulo", Value: 1}, {Key: "edicao", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(inicio)).SetLimit(int64(limite))
+	cursor, err := r.Collection.Find(ctx, filtro, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var livros []model.Livro
+	err = cursor.All(ctx, &livros)
+	return livros, int(total), err
+}
+
+func (r *LivroRepository) filtroCondicao(ctx context.Context, c repository.CondicaoLivro) (bson.M, error) {
+	if c.Operador != "" {
+		if c.Esquerda == nil || c.Direita == nil {
+			return nil, fmt.Errorf("condição %q sem os dois operandos", c.Operador)
+		}
+		esquerda, err := r.filtroCondicao(ctx, *c.Esquerda)
+		if err != nil {
+			return nil, err
+		}
+		direita, err := r.filtroCondicao(ctx, *c.Direita)
+		if err != nil {
+			return nil, err
+		}
+		switch c.Operador {
+		case repository.OperadorE:
+			return bson.M{"$and": bson.A{esquerda, direita}}, nil
+		case repository.OperadorOu:
+			return bson.M{"$or": bson.A{esquerda, direita}}, nil
+		case repository.OperadorENao:
+			return bson.M{"$and": bson.A{esquerda, bson.M{"$nor": bson.A{direita}}}}, nil
+		}
+		return nil, fmt.Errorf("operador de busca desconhecido: %q", c.Operador)
+	}
+
+	if c.Campo == repository.CampoTodos {
+		return bson.M{}, nil
+	}
+	padrao, err := padraoRelacao(c.Relacao, c.Valor)
+	if err != nil {
+		return nil, err
+	}
+	regex := bson.M{"$regex": padrao, "$options": "i"}
+	switch c.Campo {
+	case repository.CampoTitulo:
+		return bson.M{"titulo": regex}, nil
+	case repository.CampoClassificacao:
+		return bson.M{"numero_classificacao": regex}, nil
+	case repository.CampoIdioma:
+		return bson.M{"idioma": regex}, nil
+	case repository.CampoISBN:
+		if c.Relacao == repository.RelacaoPrefixo {
+			return bson.M{"_id": regex}, nil
+		}
+		return bson.M{"_id": bson.M{"$in": validacao.FormasISBN(c.Valor)}}, nil
+	case repository.CampoAutor:
+		return filtroAutor(padrao), nil
+	case repository.CampoQualquer:
+		return bson.M{"$or": bson.A{bson.M{"titulo": regex}, filtroAutor(padrao)}}, nil
+	case repository.CampoEditora:
+		cursor, err := r.Collection.Database().Collection("editoras").Find(ctx, bson.M{"nome": regex},
+			options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return nil, err
+		}
+		var editoras []model.Editora
+		if err := cursor.All(ctx, &editoras); err != nil {
+			return nil, err
+		}
+		cnpjs := make([]string, len(editoras))
+		for i, e := range editoras {
+			cnpjs[i] = e.CNPJ
+		}
+		return bson.M{"editora_cnpj": bson.M{"$in": cnpjs}}, nil
+	case repository.CampoAssunto:
+		ids, err := r.arvoreCategorias(ctx, bson.M{"nome": regex})
+		if err != nil {
+			return nil, err
+		}
+		if ids == nil {
+			ids = []int{}
+		}
+		return bson.M{"categorias": bson.M{"$in": ids}}, nil
+	}
+	return nil, fmt.Errorf("campo de busca desconhecido: %q", c.Campo)
+}
+
+// filtroAutor compara o padrão com o nome dos autores embutidos, na ordem
+// direta ("Machado de Assis") e na invertida ("Assis, Machado de")
+func filtroAutor(padrao string) bson.M {
+	nome := func(partes ...any) bson.M {
+		return bson.M{"$regexMatch": bson.M{"input": bson.M{"$concat": bson.A(partes)}, "regex": padrao, "options": "i"}}
+	}
+	return bson.M{"$expr": bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$autores", bson.A{}}},
+		"as":    "a",
+		"in": bson.M{"$or": bson.A{
+			nome("$$a.primeiro_nome", " ", "$$a.sobrenome"),
+			nome("$$a.sobrenome", ", ", "$$a.primeiro_nome"),
+		}},
+	}}}}}
+}
+
+// padraoRelacao monta a expressão regular da relação, com o valor literal
+func padraoRelacao(relacao repository.RelacaoBusca, valor string) (string, error) {
+	switch relacao {
+	case repository.RelacaoContem:
+		return regexp.QuoteMeta(valor), nil
+	case repository.RelacaoIgual:
+		return "^" + regexp.QuoteMeta(valor) + "$", nil
+	case repository.RelacaoPrefixo:
+		return "^" + regexp.QuoteMeta(valor), nil
+	}
+	return "", fmt.Errorf("relação de busca desconhecida: %q", relacao)
+}
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/repository"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func simples(campo repository.CampoLivro, relacao repository.RelacaoBusca, valor string) *repository.CondicaoLivro {
+	return &repository.CondicaoLivro{Campo: campo, Relacao: relacao, Valor: valor}
+}
+
+func composta(operador repository.OperadorBusca, esquerda, direita *repository.CondicaoLivro) *repository.CondicaoLivro {
+	return &repository.CondicaoLivro{Operador: operador, Esquerda: esquerda, Direita: direita}
+}
+
+// os campos de editora e assunto consultam outras coleções e ficam de fora
+func TestFiltroCondicao(t *testing.T) {
+	regex := func(padrao string) bson.M { return bson.M{"$regex": padrao, "$options": "i"} }
+	titulo := simples(repository.CampoTitulo, repository.RelacaoContem, "dom")
+	idioma := simples(repository.CampoIdioma, repository.RelacaoIgual, "pt")
+	casos := []struct {
+		nome     string
+		condicao *repository.CondicaoLivro
+		esperado bson.M
+	}{
+		{"todos", &repository.CondicaoLivro{Campo: repository.CampoTodos}, bson.M{}},
+		{"contém", titulo, bson.M{"titulo": regex("dom")}},
+		{"igual", idioma, bson.M{"idioma": regex("^pt$")}},
+		{"prefixo", simples(repository.CampoClassificacao, repository.RelacaoPrefixo, "869.3"), bson.M{"numero_classificacao": regex(`^869\.3`)}},
+		{"metacaracteres como texto", simples(repository.CampoTitulo, repository.RelacaoContem, "(a+b)*"), bson.M{"titulo": regex(`\(a\+b\)\*`)}},
+		{"ISBN", simples(repository.CampoISBN, repository.RelacaoIgual, "8535902775"), bson.M{"_id": bson.M{"$in": []string{"9788535902778", "8535902775"}}}},
+		{"prefixo do ISBN", simples(repository.CampoISBN, repository.RelacaoPrefixo, "97885"), bson.M{"_id": regex("^97885")}},
+		{"autor", simples(repository.CampoAutor, repository.RelacaoContem, "assis"), filtroAutor("assis")},
+		{"qualquer campo", simples(repository.CampoQualquer, repository.RelacaoContem, "assis"),
+			bson.M{"$or": bson.A{bson.M{"titulo": regex("assis")}, filtroAutor("assis")}}},
+		{"e", composta(repository.OperadorE, titulo, idioma),
+			bson.M{"$and": bson.A{bson.M{"titulo": regex("dom")}, bson.M{"idioma": regex("^pt$")}}}},
+		{"ou", composta(repository.OperadorOu, titulo, idioma),
+			bson.M{"$or": bson.A{bson.M{"titulo": regex("dom")}, bson.M{"idioma": regex("^pt$")}}}},
+		{"e não", composta(repository.OperadorENao, &repository.CondicaoLivro{Campo: repository.CampoTodos}, idioma),
+			bson.M{"$and": bson.A{bson.M{}, bson.M{"$nor": bson.A{bson.M{"idioma": regex("^pt$")}}}}}},
+		{"aninhada à direita", composta(repository.OperadorE, titulo, composta(repository.OperadorOu, idioma, titulo)),
+			bson.M{"$and": bson.A{bson.M{"titulo": regex("dom")},
+				bson.M{"$or": bson.A{bson.M{"idioma": regex("^pt$")}, bson.M{"titulo": regex("dom")}}}}}},
+	}
+	r := &LivroRepository{}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			obtido, err := r.filtroCondicao(context.Background(), *c.condicao)
+			if err != nil {
+				t.Fatalf("filtroCondicao: %v", err)
+			}
+			if !reflect.DeepEqual(obtido, c.esperado) {
+				t.Errorf("filtroCondicao = %v, esperado %v", obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestFiltroCondicaoErros(t *testing.T) {
+	casos := []struct {
+		nome     string
+		condicao *repository.CondicaoLivro
+	}{
+		{"sem operando", &repository.CondicaoLivro{Operador: repository.OperadorOu, Direita: simples(repository.CampoTitulo, repository.RelacaoContem, "a")}},
+		{"operador desconhecido", composta("xor", simples(repository.CampoTitulo, repository.RelacaoContem, "a"), simples(repository.CampoTitulo, repository.RelacaoContem, "b"))},
+		{"campo desconhecido", simples("capa", repository.RelacaoContem, "azul")},
+		{"relação desconhecida", simples(repository.CampoTitulo, "parecido", "dom")},
+	}
+	r := &LivroRepository{}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if obtido, err := r.filtroCondicao(context.Background(), *c.condicao); err == nil {
+				t.Errorf("filtroCondicao = %v, esperado erro", obtido)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ObraRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewObraRepository(db *mongo.Database) *ObraRepository {
+	return &ObraRepository{Collection: db.Collection("obras")}
+}
+
+func (r *ObraRepository) Create(ctx context.Context, obra model.Obra) error {
+	_, err := r.Collection.InsertOne(ctx, obra)
+	return err
+}
+
+func (r *ObraRepository) GetByID(ctx context.Context, id int) (*model.Obra, error) {
+	var obra model.Obra
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&obra)
+	if err != nil {
+		return nil, err
+	}
+	return &obra, nil
+}
+
+func (r *ObraRepository) Update(ctx context.Context, obra model.Obra) error {
+	filter := bson.M{"_id": obra.ID}
+	update := bson.M{"$set": bson.M{
+		"titulo":          obra.Titulo,
+		"idioma_original": obra.IdiomaOriginal,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ObraRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *ObraRepository) ListByIDs(ctx context.Context, ids []int) ([]model.Obra, error) {
+	cursor, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var obras []model.Obra
+	err = cursor.All(ctx, &obras)
+	return obras, err
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{Collection: db.Collection("outbox")}
+}
+
+func (r *OutboxRepository) Registrar(ctx context.Context, e model.EventoDominio) error {
+	_, err := r.Collection.InsertOne(ctx, e)
+	return err
+}
+
+func (r *OutboxRepository) Pendentes(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limite))
+	return r.find(ctx, bson.M{"publicado_em": nil}, opts)
+}
+
+func (r *OutboxRepository) MarcarPublicado(ctx context.Context, id string, em time.Time) error {
+	update := bson.M{"$set": bson.M{"publicado_em": em, "ultimo_erro": ""}}
+	_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *OutboxRepository) RegistrarFalha(ctx context.Context, id string, erro string) error {
+	update := bson.M{"$inc": bson.M{"tentativas": 1}, "$set": bson.M{"ultimo_erro": erro}}
+	_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *OutboxRepository) List(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limite))
+	return r.find(ctx, bson.M{}, opts)
+}
+
+func (r *OutboxRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]model.EventoDominio, error) {
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var eventos []model.EventoDominio
+	err = cursor.All(ctx, &eventos)
+	return eventos, err
+}
+
+func (r *OutboxRepository) Depois(ctx context.Context, id string, tipos []string, limite int) ([]model.EventoDominio, error) {
+	filter := bson.M{"_id": bson.M{"$gt": id}, "tipo": bson.M{"$in": tipos}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limite))
+	return r.find(ctx, filter, opts)
+}
+
+// Avisos acompanha as inserções na coleção outbox por um change stream, que
+// só existe em replica sets
+type Avisos struct {
+	Collection *mongo.Collection
+}
+
+func NewAvisos(db *mongo.Database) *Avisos {
+	return &Avisos{Collection: db.Collection("outbox")}
+}
+
+func (a *Avisos) Escutar(ctx context.Context, aviso func()) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	stream, err := a.Collection.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+	for stream.Next(ctx) {
+		aviso()
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PeriodicoRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPeriodicoRepository(db *mongo.Database) *PeriodicoRepository {
+	return &PeriodicoRepository{Collection: db.Collection("periodicos")}
+}
+
+func (r *PeriodicoRepository) Create(ctx context.Context, periodico model.Periodico) error {
+	_, err := r.Collection.InsertOne(ctx, periodico)
+	return err
+}
+
+func (r *PeriodicoRepository) GetByISSN(ctx context.Context, issn string) (*model.Periodico, error) {
+	var periodico model.Periodico
+	err := r.Collection.FindOne(ctx, bson.M{"_id": issn}).Decode(&periodico)
+	if err != nil {
+		return nil, err
+	}
+	return &periodico, nil
+}
+
+func (r *PeriodicoRepository) Update(ctx context.Context, periodico model.Periodico) error {
+	filter := bson.M{"_id": periodico.ISSN}
+	update := bson.M{"$set": bson.M{
+		"titulo":             periodico.Titulo,
+		"editora_cnpj":       periodico.EditoraCNPJ,
+		"fasciculos_por_ano": periodico.FasciculosPorAno,
+		"prazo_reclamacao":   periodico.PrazoReclamacao,
+		"inicio_assinatura":  periodico.InicioAssinatura,
+		"ativo":              periodico.Ativo,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *PeriodicoRepository) Delete(ctx context.Context, issn string) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": issn})
+	return err
+}
+
+func (r *PeriodicoRepository) ListAtivos(ctx context.Context) ([]model.Periodico, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "titulo", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, bson.M{"ativo": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var periodicos []model.Periodico
+	err = cursor.All(ctx, &periodicos)
+	return periodicos, err
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecursoDigitalRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRecursoDigitalRepository(db *mongo.Database) *RecursoDigitalRepository {
+	return &RecursoDigitalRepository{Collection: db.Collection("recursos_digitais")}
+}
+
+func (r *RecursoDigitalRepository) Create(ctx context.Context, recurso model.RecursoDigital) error {
+	_, err := r.Collection.InsertOne(ctx, recurso)
+	return err
+}
+
+func (r *RecursoDigitalRepository) GetByID(ctx context.Context, id int) (*model.RecursoDigital, error) {
+	var recurso model.RecursoDigital
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&recurso)
+	if err != nil {
+		return nil, err
+	}
+	return &recurso, nil
+}
+
+func (r *RecursoDigitalRepository) ListByLivro(ctx context.Context, isbn string) ([]model.RecursoDigital, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, bson.M{"livro_isbn": isbn}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var recursos []model.RecursoDigital
+	err = cursor.All(ctx, &recursos)
+	return recursos, err
+}
+
+func (r *RecursoDigitalRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,90 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReservaRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewReservaRepository(db *mongo.Database) *ReservaRepository {
+	return &ReservaRepository{Collection: db.Collection("reservas")}
+}
+
+func (r *ReservaRepository) Create(ctx context.Context, reserva model.Reserva) error {
+	_, err := r.Collection.InsertOne(ctx, reserva)
+	return err
+}
+
+func (r *ReservaRepository) GetByID(ctx context.Context, id int) (*model.Reserva, error) {
+	var reserva model.Reserva
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&reserva)
+	if err != nil {
+		return nil, err
+	}
+	return &reserva, nil
+}
+
+func (r *ReservaRepository) Update(ctx context.Context, reserva model.Reserva) error {
+	filter := bson.M{"_id": reserva.ID}
+	update := bson.M{"$set": bson.M{
+		"usuario_cpf":  reserva.UsuarioCPF,
+		"livro_isbn":   reserva.LivroISBN,
+		"obra_id":      reserva.ObraID,
+		"data_reserva": reserva.DataReserva,
+		"status":       reserva.Status,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ReservaRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *ReservaRepository) ListByUsuario(ctx context.Context, cpf string) ([]model.Reserva, error) {
+	return r.find(ctx, bson.M{"usuario_cpf": cpf})
+}
+
+func (r *ReservaRepository) FilaPorLivro(ctx context.Context, isbn string) ([]model.Reserva, error) {
+	var livro model.Livro
+	err := r.Collection.Database().Collection("livros").FindOne(ctx, bson.M{"_id": isbn}).Decode(&livro)
+	if err != nil {
+		return nil, err
+	}
+
+	atendiveis := bson.A{bson.M{"livro_isbn": isbn}}
+	if livro.ObraID != nil {
+		atendiveis = append(atendiveis, bson.M{"livro_isbn": "", "obra_id": *livro.ObraID})
+	}
+	return r.find(ctx, bson.M{"status": model.ReservaAtiva, "$or": atendiveis})
+}
+
+func (r *ReservaRepository) ProximoID(ctx context.Context) (int, error) {
+	var ultima model.Reserva
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	err := r.Collection.FindOne(ctx, bson.M{}, opts).Decode(&ultima)
+	if err == mongo.ErrNoDocuments {
+		return 1, nil
+	}
+	return ultima.ID + 1, err
+}
+
+// find lista as reservas do filtro por ordem de chegada
+func (r *ReservaRepository) find(ctx context.Context, filter bson.M) ([]model.Reserva, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "data_reserva", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var reservas []model.Reserva
+	err = cursor.All(ctx, &reservas)
+	return reservas, err
+}
@@ -3,8 +3,12 @@ package mongo
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"regexp"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UsuarioRepository struct {
@@ -32,15 +36,73 @@ func (r *UsuarioRepository) GetByCPF(ctx context.Context, cpf string) (*model.Us
 func (r *UsuarioRepository) Update(ctx context.Context, usuario model.Usuario) error {
 	filter := bson.M{"_id": usuario.CPF}
 	update := bson.M{"$set": bson.M{
-		"data_nascimento": usuario.DataNascimento,
-		"sobrenome":       usuario.Sobrenome,
-		"primeiro_nome":   usuario.PrimeiroNome,
+		"data_nascimento":  usuario.DataNascimento,
+		"sobrenome":        usuario.Sobrenome,
+		"primeiro_nome":    usuario.PrimeiroNome,
+		"email":            usuario.Email,
+		"telefone":         usuario.Telefone,
+		"endereco":         usuario.Endereco,
+		"matricula":        usuario.Matricula,
+		"categoria":        usuario.Categoria,
+		"validade_vinculo": usuario.ValidadeVinculo,
+		"responsavel_cpf":  usuario.ResponsavelCPF,
 	}}
 	_, err := r.Collection.UpdateOne(ctx, filter, update)
 	return err
 }
 
+// a senha fica no mesmo documento, em um campo que model.Usuario não lê
+func (r *UsuarioRepository) SenhaHash(ctx context.Context, cpf string) (string, error) {
+	var doc struct {
+		SenhaHash string `bson:"senha_hash"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"senha_hash": 1})
+	err := r.Collection.FindOne(ctx, bson.M{"_id": cpf}, opts).Decode(&doc)
+	return doc.SenhaHash, err
+}
+
+func (r *UsuarioRepository) DefinirSenha(ctx context.Context, cpf, hash string) error {
+	res, err := r.Collection.UpdateOne(ctx, bson.M{"_id": cpf}, bson.M{"$set": bson.M{"senha_hash": hash}})
+	if err == nil && res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return err
+}
+
 func (r *UsuarioRepository) Delete(ctx context.Context, cpf string) error {
 	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": cpf})
 	return err
 }
+
+func (r *UsuarioRepository) ListByResponsavel(ctx context.Context, cpf string) ([]model.Usuario, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "primeiro_nome", Value: 1}, {Key: "sobrenome", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, bson.M{"responsavel_cpf": cpf}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var usuarios []model.Usuario
+	err = cursor.All(ctx, &usuarios)
+	return usuarios, err
+}
+
+func (r *UsuarioRepository) List(ctx context.Context, filtro repository.FiltroUsuario) ([]model.Usuario, error) {
+	filter := bson.M{}
+	if filtro.Nome != "" {
+		nome := bson.M{"$regex": regexp.QuoteMeta(filtro.Nome), "$options": "i"}
+		filter["$or"] = bson.A{bson.M{"primeiro_nome": nome}, bson.M{"sobrenome": nome}}
+	}
+	if filtro.Categoria != "" {
+		filter["categoria"] = filtro.Categoria
+	}
+	if filtro.CPFs != nil {
+		filter["_id"] = bson.M{"$in": filtro.CPFs}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "primeiro_nome", Value: 1}, {Key: "sobrenome", Value: 1}})
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var usuarios []model.Usuario
+	err = cursor.All(ctx, &usuarios)
+	return usuarios, err
+}
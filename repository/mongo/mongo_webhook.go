@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	Collection *mongo.Collection
+	Entregas   *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{Collection: db.Collection("webhooks"), Entregas: db.Collection("entregas_webhook")}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook model.Webhook) error {
+	_, err := r.Collection.InsertOne(ctx, webhook)
+	return err
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*model.Webhook, error) {
+	var webhook model.Webhook
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook model.Webhook) error {
+	filter := bson.M{"_id": webhook.ID}
+	update := bson.M{"$set": bson.M{
+		"url":     webhook.URL,
+		"eventos": webhook.Eventos,
+		"segredo": webhook.Segredo,
+		"ativo":   webhook.Ativo,
+	}}
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.Entregas.DeleteMany(ctx, bson.M{"webhook_id": id}); err != nil {
+		return err
+	}
+	_, err := r.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]model.Webhook, error) {
+	cursor, err := r.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []model.Webhook
+	err = cursor.All(ctx, &webhooks)
+	return webhooks, err
+}
+
+func (r *WebhookRepository) ProximoID(ctx context.Context) (int, error) {
+	var ultimo model.Webhook
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	err := r.Collection.FindOne(ctx, bson.M{}, opts).Decode(&ultimo)
+	if err == mongo.ErrNoDocuments {
+		return 1, nil
+	}
+	return ultimo.ID + 1, err
+}
+
+func (r *WebhookRepository) CriarEntrega(ctx context.Context, entrega model.EntregaWebhook) error {
+	_, err := r.Entregas.InsertOne(ctx, entrega)
+	return err
+}
+
+func (r *WebhookRepository) AtualizarEntrega(ctx context.Context, e model.EntregaWebhook) error {
+	filter := bson.M{"_id": e.ID}
+	update := bson.M{"$set": bson.M{
+		"situacao":          e.Situacao,
+		"tentativas":        e.Tentativas,
+		"proxima_tentativa": e.ProximaTentativa,
+		"ultimo_status":     e.UltimoStatus,
+		"ultimo_erro":       e.UltimoErro,
+		"entregue_em":       e.EntregueEm,
+	}}
+	_, err := r.Entregas.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *WebhookRepository) EntregasPendentes(ctx context.Context, ate time.Time, limite int) ([]model.EntregaWebhook, error) {
+	filter := bson.M{"situacao": model.EntregaPendente, "proxima_tentativa": bson.M{"$lte": ate}}
+	opts := options.Find().SetSort(bson.D{{Key: "proxima_tentativa", Value: 1}, {Key: "criada_em", Value: 1}}).SetLimit(int64(limite))
+	return r.findEntregas(ctx, filter, opts)
+}
+
+func (r *WebhookRepository) ListEntregas(ctx context.Context, webhookID int, limite int) ([]model.EntregaWebhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "criada_em", Value: -1}, {Key: "_id", Value: 1}}).SetLimit(int64(limite))
+	return r.findEntregas(ctx, bson.M{"webhook_id": webhookID}, opts)
+}
+
+func (r *WebhookRepository) findEntregas(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]model.EntregaWebhook, error) {
+	cursor, err := r.Entregas.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var entregas []model.EntregaWebhook
+	err = cursor.All(ctx, &entregas)
+	return entregas, err
+}
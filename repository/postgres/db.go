@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX é a parte da API do pgx usada pelos repositórios. É satisfeita por
+// *pgx.Conn, *pgxpool.Pool e pgx.Tx, de modo que os mesmos repositórios
+// funcionam com uma conexão, com o pool do servidor HTTP ou dentro de uma transação
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
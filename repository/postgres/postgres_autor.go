@@ -3,14 +3,13 @@ package postgres
 import (
 	"context"
 	"crud-biblioteca/model"
-	"github.com/jackc/pgx/v5"
 )
 
 type AutorRepository struct {
-	DB *pgx.Conn
+	DB DBTX
 }
 
-func NewAutorRepository(db *pgx.Conn) *AutorRepository {
+func NewAutorRepository(db DBTX) *AutorRepository {
 	return &AutorRepository{DB: db}
 }
 
@@ -28,8 +27,36 @@ func (r *AutorRepository) GetByID(ctx context.Context, id int) (*model.Autor, er
 	return &a, err
 }
 
+func (r *AutorRepository) Update(ctx context.Context, autor model.Autor) error {
+	query := `UPDATE "Projeto Logico".Autor SET primeiro_nome = $1, sobrenome = $2 WHERE id = $3`
+	_, err := r.DB.Exec(ctx, query, autor.PrimeiroNome, autor.Sobrenome, autor.ID)
+	return err
+}
+
 func (r *AutorRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM "Projeto Logico".Autor WHERE id = $1`
 	_, err := r.DB.Exec(ctx, query, id)
 	return err
 }
+
+// List busca pelo nome ou sobrenome, sem diferenciar maiúsculas; vazio lista todos
+func (r *AutorRepository) List(ctx context.Context, nome string) ([]model.Autor, error) {
+	query := `SELECT id, primeiro_nome, sobrenome FROM "Projeto Logico".Autor
+	          WHERE primeiro_nome || ' ' || sobrenome ILIKE '%' || $1 || '%'
+	          ORDER BY sobrenome, primeiro_nome`
+	rows, err := r.DB.Query(ctx, query, escaparLike(nome))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var autores []model.Autor
+	for rows.Next() {
+		var a model.Autor
+		if err := rows.Scan(&a.ID, &a.PrimeiroNome, &a.Sobrenome); err != nil {
+			return nil, err
+		}
+		autores = append(autores, a)
+	}
+	return autores, rows.Err()
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+)
+
+type CategoriaRepository struct {
+	DB DBTX
+}
+
+func NewCategoriaRepository(db DBTX) *CategoriaRepository {
+	return &CategoriaRepository{DB: db}
+}
+
+func (r *CategoriaRepository) Create(ctx context.Context, categoria model.Categoria) error {
+	query := `INSERT INTO "Projeto Logico".Categoria (id, nome, codigo, categoria_pai_id, restrita) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.DB.Exec(ctx, query, categoria.ID, categoria.Nome, categoria.Codigo, categoria.PaiID, categoria.Restrita)
+	return err
+}
+
+func (r *CategoriaRepository) GetByID(ctx context.Context, id int) (*model.Categoria, error) {
+	query := `SELECT id, nome, codigo, categoria_pai_id, restrita FROM "Projeto Logico".Categoria WHERE id = $1`
+	row := r.DB.QueryRow(ctx, query, id)
+	var c model.Categoria
+	err := row.Scan(&c.ID, &c.Nome, &c.Codigo, &c.PaiID, &c.Restrita)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CategoriaRepository) Update(ctx context.Context, categoria model.Categoria) error {
+	query := `UPDATE "Projeto Logico".Categoria SET nome = $1, codigo = $2, categoria_pai_id = $3, restrita = $4 WHERE id = $5`
+	_, err := r.DB.Exec(ctx, query, categoria.Nome, categoria.Codigo, categoria.PaiID, categoria.Restrita, categoria.ID)
+	return err
+}
+
+func (r *CategoriaRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM "Projeto Logico".Categoria WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func (r *CategoriaRepository) ListSubcategorias(ctx context.Context, paiID *int) ([]model.Categoria, error) {
+	// "IS NOT DISTINCT FROM" trata NULL como valor, permitindo listar as categorias raiz
+	query := `SELECT id, nome, codigo, categoria_pai_id, restrita FROM "Projeto Logico".Categoria
+	          WHERE categoria_pai_id IS NOT DISTINCT FROM $1 ORDER BY codigo, nome`
+	rows, err := r.DB.Query(ctx, query, paiID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categorias []model.Categoria
+	for rows.Next() {
+		var c model.Categoria
+		if err := rows.Scan(&c.ID, &c.Nome, &c.Codigo, &c.PaiID, &c.Restrita); err != nil {
+			return nil, err
+		}
+		categorias = append(categorias, c)
+	}
+	return categorias, rows.Err()
+}
+
+func (r *CategoriaRepository) ContarLivros(ctx context.Context) ([]model.ContagemCategoria, error) {
+	query := `SELECT c.id, c.nome, c.codigo, c.categoria_pai_id, c.restrita, COUNT(cl.livro_isbn)
+	          FROM "Projeto Logico".Categoria c
+	          LEFT JOIN "Projeto Logico".Classifica cl ON cl.categoria_id = c.id
+	          GROUP BY c.id, c.nome, c.codigo, c.categoria_pai_id, c.restrita
+	          ORDER BY c.codigo, c.nome`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contagens []model.ContagemCategoria
+	for rows.Next() {
+		var cc model.ContagemCategoria
+		c := &cc.Categoria
+		if err := rows.Scan(&c.ID, &c.Nome, &c.Codigo, &c.PaiID, &c.Restrita, &cc.Quantidade); err != nil {
+			return nil, err
+		}
+		contagens = append(contagens, cc)
+	}
+	return contagens, rows.Err()
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type EditoraRepository struct {
+	DB DBTX
+}
+
+func NewEditoraRepository(db DBTX) *EditoraRepository {
+	return &EditoraRepository{DB: db}
+}
+
+func (r *EditoraRepository) Create(ctx context.Context, editora model.Editora) error {
+	query := `INSERT INTO "Projeto Logico".Editora (cnpj, nome) VALUES ($1, $2)`
+	_, err := r.DB.Exec(ctx, query, editora.CNPJ, editora.Nome)
+	return err
+}
+
+func (r *EditoraRepository) GetByCNPJ(ctx context.Context, cnpj string) (*model.Editora, error) {
+	query := `SELECT cnpj, nome FROM "Projeto Logico".Editora WHERE cnpj = $1`
+	row := r.DB.QueryRow(ctx, query, cnpj)
+	var e model.Editora
+	err := row.Scan(&e.CNPJ, &e.Nome)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *EditoraRepository) Update(ctx context.Context, editora model.Editora) error {
+	query := `UPDATE "Projeto Logico".Editora SET nome = $1 WHERE cnpj = $2`
+	_, err := r.DB.Exec(ctx, query, editora.Nome, editora.CNPJ)
+	return err
+}
+
+func (r *EditoraRepository) Delete(ctx context.Context, cnpj string) error {
+	query := `DELETE FROM "Projeto Logico".Editora WHERE cnpj = $1`
+	_, err := r.DB.Exec(ctx, query, cnpj)
+	return err
+}
+
+func (r *EditoraRepository) ListByCNPJs(ctx context.Context, cnpjs []string) ([]model.Editora, error) {
+	query := `SELECT cnpj, nome FROM "Projeto Logico".Editora WHERE cnpj = ANY($1)`
+	rows, err := r.DB.Query(ctx, query, cnpjs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Editora, error) {
+		var e model.Editora
+		err := row.Scan(&e.CNPJ, &e.Nome)
+		return e, err
+	})
+}
@@ -3,29 +3,40 @@ package postgres
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type EmprestimoRepository struct {
-	DB *pgx.Conn
+	DB DBTX
 }
 
-func NewEmprestimoRepository(db *pgx.Conn) *EmprestimoRepository {
+func NewEmprestimoRepository(db DBTX) *EmprestimoRepository {
 	return &EmprestimoRepository{DB: db}
 }
 
+const colunasEmprestimo = `id, data_emprestimo, status, quant_livros, cliente_usuario_cpf, renovacoes, data_devolucao`
+
+func scanEmprestimo(row pgx.Row) (model.Emprestimo, error) {
+	var e model.Emprestimo
+	err := row.Scan(&e.ID, &e.DataEmprestimo, &e.Status, &e.QuantLivros, &e.ClienteUsuarioCPF, &e.Renovacoes, &e.DataDevolucao)
+	return e, err
+}
+
 func (r *EmprestimoRepository) Create(ctx context.Context, emprestimo model.Emprestimo) error {
-	query := `INSERT INTO "Projeto Logico".Emprestimo (id, data_emprestimo, status, quant_livros, cliente_usuario_cpf) VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.DB.Exec(ctx, query, emprestimo.ID, emprestimo.DataEmprestimo, emprestimo.Status, emprestimo.QuantLivros, emprestimo.ClienteUsuarioCPF)
+	query := `INSERT INTO "Projeto Logico".Emprestimo (id, data_emprestimo, status, quant_livros, cliente_usuario_cpf, renovacoes, data_devolucao)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.DB.Exec(ctx, query, emprestimo.ID, emprestimo.DataEmprestimo, emprestimo.Status, emprestimo.QuantLivros, emprestimo.ClienteUsuarioCPF,
+		emprestimo.Renovacoes, emprestimo.DataDevolucao)
 	return err
 }
 
 func (r *EmprestimoRepository) GetByID(ctx context.Context, id int) (*model.Emprestimo, error) {
-	query := `SELECT id, data_emprestimo, status, quant_livros, cliente_usuario_cpf FROM "Projeto Logico".Emprestimo WHERE id = $1`
-	row := r.DB.QueryRow(ctx, query, id)
-	var e model.Emprestimo
-	err := row.Scan(&e.ID, &e.DataEmprestimo, &e.Status, &e.QuantLivros, &e.ClienteUsuarioCPF)
+	query := `SELECT ` + colunasEmprestimo + ` FROM "Projeto Logico".Emprestimo WHERE id = $1`
+	e, err := scanEmprestimo(r.DB.QueryRow(ctx, query, id))
 	if err != nil {
 		return nil, err
 	}
@@ -33,8 +44,10 @@ func (r *EmprestimoRepository) GetByID(ctx context.Context, id int) (*model.Empr
 }
 
 func (r *EmprestimoRepository) Update(ctx context.Context, emprestimo model.Emprestimo) error {
-	query := `UPDATE "Projeto Logico".Emprestimo SET data_emprestimo = $1, status = $2, quant_livros = $3, cliente_usuario_cpf = $4 WHERE id = $5`
-	_, err := r.DB.Exec(ctx, query, emprestimo.DataEmprestimo, emprestimo.Status, emprestimo.QuantLivros, emprestimo.ClienteUsuarioCPF, emprestimo.ID)
+	query := `UPDATE "Projeto Logico".Emprestimo SET data_emprestimo = $1, status = $2, quant_livros = $3, cliente_usuario_cpf = $4,
+	              renovacoes = $5, data_devolucao = $6 WHERE id = $7`
+	_, err := r.DB.Exec(ctx, query, emprestimo.DataEmprestimo, emprestimo.Status, emprestimo.QuantLivros, emprestimo.ClienteUsuarioCPF,
+		emprestimo.Renovacoes, emprestimo.DataDevolucao, emprestimo.ID)
 	return err
 }
 
@@ -43,3 +56,47 @@ func (r *EmprestimoRepository) Delete(ctx context.Context, id int) error {
 	_, err := r.DB.Exec(ctx, query, id)
 	return err
 }
+
+func (r *EmprestimoRepository) ProximoID(ctx context.Context) (int, error) {
+	var id int
+	err := r.DB.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) + 1 FROM "Projeto Logico".Emprestimo`).Scan(&id)
+	return id, err
+}
+
+func (r *EmprestimoRepository) List(ctx context.Context, filtro repository.FiltroEmprestimo) ([]model.Emprestimo, error) {
+	var condicoes []string
+	var args []any
+	if filtro.CPF != "" {
+		args = append(args, filtro.CPF)
+		condicoes = append(condicoes, fmt.Sprintf("cliente_usuario_cpf = $%d", len(args)))
+	}
+	if filtro.Status != "" {
+		args = append(args, filtro.Status)
+		condicoes = append(condicoes, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filtro.CPFs != nil {
+		args = append(args, filtro.CPFs)
+		condicoes = append(condicoes, fmt.Sprintf("cliente_usuario_cpf = ANY($%d)", len(args)))
+	}
+	query := `SELECT ` + colunasEmprestimo + ` FROM "Projeto Logico".Emprestimo`
+	if len(condicoes) > 0 {
+		query += " WHERE " + strings.Join(condicoes, " AND ")
+	}
+	query += " ORDER BY data_emprestimo DESC, id"
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emprestimos []model.Emprestimo
+	for rows.Next() {
+		e, err := scanEmprestimo(rows)
+		if err != nil {
+			return nil, err
+		}
+		emprestimos = append(emprestimos, e)
+	}
+	return emprestimos, rows.Err()
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FasciculoRepository struct {
+	DB DBTX
+}
+
+func NewFasciculoRepository(db DBTX) *FasciculoRepository {
+	return &FasciculoRepository{DB: db}
+}
+
+const colunasFasciculo = `id, periodico_issn, volume, numero, data_prevista, data_recebimento, data_reclamacao, status,
+	emprestado_cpf, data_emprestimo`
+
+func (r *FasciculoRepository) Create(ctx context.Context, f model.Fasciculo) error {
+	query := `INSERT INTO "Projeto Logico".Fasciculo (` + colunasFasciculo + `)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.DB.Exec(ctx, query, f.ID, f.PeriodicoISSN, f.Volume, f.Numero, f.DataPrevista, f.DataRecebimento, f.DataReclamacao, f.Status,
+		f.EmprestadoCPF, f.DataEmprestimo)
+	return err
+}
+
+func (r *FasciculoRepository) GetByID(ctx context.Context, id string) (*model.Fasciculo, error) {
+	query := `SELECT ` + colunasFasciculo + ` FROM "Projeto Logico".Fasciculo WHERE id = $1`
+	f, err := scanFasciculo(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *FasciculoRepository) Update(ctx context.Context, f model.Fasciculo) error {
+	query := `UPDATE "Projeto Logico".Fasciculo SET data_prevista = $1, data_recebimento = $2, data_reclamacao = $3, status = $4,
+	              emprestado_cpf = $5, data_emprestimo = $6
+	          WHERE id = $7`
+	_, err := r.DB.Exec(ctx, query, f.DataPrevista, f.DataRecebimento, f.DataReclamacao, f.Status, f.EmprestadoCPF, f.DataEmprestimo, f.ID)
+	return err
+}
+
+func (r *FasciculoRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM "Projeto Logico".Fasciculo WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func (r *FasciculoRepository) ListByPeriodico(ctx context.Context, issn string) ([]model.Fasciculo, error) {
+	query := `SELECT ` + colunasFasciculo + ` FROM "Projeto Logico".Fasciculo WHERE periodico_issn = $1 ORDER BY volume, numero`
+	rows, err := r.DB.Query(ctx, query, issn)
+	if err != nil {
+		return nil, err
+	}
+	return scanFasciculos(rows)
+}
+
+func (r *FasciculoRepository) ListByStatus(ctx context.Context, status string) ([]model.Fasciculo, error) {
+	query := `SELECT ` + colunasFasciculo + ` FROM "Projeto Logico".Fasciculo WHERE status = $1 ORDER BY periodico_issn, volume, numero`
+	rows, err := r.DB.Query(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	return scanFasciculos(rows)
+}
+
+func scanFasciculo(row pgx.Row) (model.Fasciculo, error) {
+	var f model.Fasciculo
+	err := row.Scan(&f.ID, &f.PeriodicoISSN, &f.Volume, &f.Numero, &f.DataPrevista, &f.DataRecebimento, &f.DataReclamacao, &f.Status,
+		&f.EmprestadoCPF, &f.DataEmprestimo)
+	return f, err
+}
+
+func scanFasciculos(rows pgx.Rows) ([]model.Fasciculo, error) {
+	defer rows.Close()
+	var fasciculos []model.Fasciculo
+	for rows.Next() {
+		f, err := scanFasciculo(rows)
+		if err != nil {
+			return nil, err
+		}
+		fasciculos = append(fasciculos, f)
+	}
+	return fasciculos, rows.Err()
+}
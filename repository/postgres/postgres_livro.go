@@ -3,35 +3,93 @@ package postgres
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strings"
+
 	"github.com/jackc/pgx/v5"
 )
 
 type LivroRepository struct {
-	DB *pgx.Conn
+	DB DBTX
 }
 
-func NewLivroRepository(db *pgx.Conn) *LivroRepository {
+func NewLivroRepository(db DBTX) *LivroRepository {
 	return &LivroRepository{DB: db}
 }
 
+// colunas lidas por scanLivro, na mesma ordem
+const colunasLivro = `l.isbn, l.titulo, l.edicao, l.num_paginas, l.editora_cnpj, l.funcionario_matricula,
+	l.sistema_classificacao, l.numero_classificacao, l.obra_id, l.idioma`
+
+func scanLivro(row pgx.Row) (model.Livro, error) {
+	var l model.Livro
+	err := row.Scan(&l.ISBN, &l.Titulo, &l.Edicao, &l.NumPaginas, &l.EditoraCNPJ, &l.FuncionarioMatricula,
+		&l.SistemaClassificacao, &l.NumeroClassificacao, &l.ObraID, &l.Idioma)
+	return l, err
+}
+
+func scanLivros(rows pgx.Rows) ([]model.Livro, error) {
+	defer rows.Close()
+	var livros []model.Livro
+	for rows.Next() {
+		l, err := scanLivro(rows)
+		if err != nil {
+			return nil, err
+		}
+		livros = append(livros, l)
+	}
+	return livros, rows.Err()
+}
+
 func (r *LivroRepository) Create(ctx context.Context, livro model.Livro) error {
-	query := `INSERT INTO "Projeto Logico".Livro (isbn, titulo, edicao, num_paginas, editora_cnpj, funcionario_matricula)
-	          VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.DB.Exec(ctx, query, livro.ISBN, livro.Titulo, livro.Edicao, livro.NumPaginas, livro.EditoraCNPJ, livro.FuncionarioMatricula)
+	query := `INSERT INTO "Projeto Logico".Livro (isbn, titulo, edicao, num_paginas, editora_cnpj, funcionario_matricula, sistema_classificacao, numero_classificacao, obra_id, idioma)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.DB.Exec(ctx, query, livro.ISBN, livro.Titulo, livro.Edicao, livro.NumPaginas, livro.EditoraCNPJ, livro.FuncionarioMatricula,
+		livro.SistemaClassificacao, livro.NumeroClassificacao, livro.ObraID, livro.Idioma)
 	return err
 }
 
+// GetByISBN aceita o ISBN-10 ou ISBN-13, com ou sem hífens
 func (r *LivroRepository) GetByISBN(ctx context.Context, isbn string) (*model.Livro, error) {
-	query := `SELECT isbn, titulo, edicao, num_paginas, editora_cnpj, funcionario_matricula FROM "Projeto Logico".Livro WHERE isbn = $1`
-	row := r.DB.QueryRow(ctx, query, isbn)
-	var l model.Livro
-	err := row.Scan(&l.ISBN, &l.Titulo, &l.Edicao, &l.NumPaginas, &l.EditoraCNPJ, &l.FuncionarioMatricula)
+	query := `SELECT ` + colunasLivro + ` FROM "Projeto Logico".Livro l WHERE l.isbn = ANY($1) LIMIT 1`
+	l, err := scanLivro(r.DB.QueryRow(ctx, query, validacao.FormasISBN(isbn)))
+	if err != nil {
+		return &l, err
+	}
+
+	// carrega as categorias a partir da tabela Classifica
+	rows, err := r.DB.Query(ctx, `SELECT categoria_id FROM "Projeto Logico".Classifica WHERE livro_isbn = $1`, l.ISBN)
+	if err != nil {
+		return &l, err
+	}
+	l.Categorias, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return &l, err
+	}
+
+	// e os autores a partir da tabela Escreve, como os autores embutidos no MongoDB
+	rows, err = r.DB.Query(ctx, `SELECT a.id, a.primeiro_nome, a.sobrenome
+	          FROM "Projeto Logico".Escreve e JOIN "Projeto Logico".Autor a ON a.id = e.autor_id
+	          WHERE e.livro_isbn = $1 ORDER BY a.sobrenome, a.primeiro_nome`, l.ISBN)
+	if err != nil {
+		return &l, err
+	}
+	l.Autores, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Autor, error) {
+		var a model.Autor
+		err := row.Scan(&a.ID, &a.PrimeiroNome, &a.Sobrenome)
+		return a, err
+	})
 	return &l, err
 }
 
 func (r *LivroRepository) Update(ctx context.Context, livro model.Livro) error {
-	query := `UPDATE "Projeto Logico".Livro SET titulo = $1, edicao = $2 WHERE isbn = $3`
-	_, err := r.DB.Exec(ctx, query, livro.Titulo, livro.Edicao, livro.ISBN)
+	query := `UPDATE "Projeto Logico".Livro SET titulo = $1, edicao = $2, num_paginas = $3, editora_cnpj = $4,
+	              sistema_classificacao = $5, numero_classificacao = $6, obra_id = $7, idioma = $8
+	          WHERE isbn = $9`
+	_, err := r.DB.Exec(ctx, query, livro.Titulo, livro.Edicao, livro.NumPaginas, livro.EditoraCNPJ,
+		livro.SistemaClassificacao, livro.NumeroClassificacao, livro.ObraID, livro.Idioma, livro.ISBN)
 	return err
 }
 
@@ -41,6 +99,17 @@ func (r *LivroRepository) Delete(ctx context.Context, isbn string) error {
 	return err
 }
 
+// Search busca livros cujo título contenha o termo, sem diferenciar maiúsculas
+func (r *LivroRepository) Search(ctx context.Context, termo string) ([]model.Livro, error) {
+	query := `SELECT ` + colunasLivro + ` FROM "Projeto Logico".Livro l
+	          WHERE l.titulo ILIKE '%' || $1 || '%' ORDER BY l.titulo, l.edicao`
+	rows, err := r.DB.Query(ctx, query, escaparLike(termo))
+	if err != nil {
+		return nil, err
+	}
+	return scanLivros(rows)
+}
+
 // implementação do relacionamento para postgres (tabela Escreve)
 func (r *LivroRepository) AddAutor(ctx context.Context, isbn string, autor model.Autor) error {
 	query := `INSERT INTO "Projeto Logico".Escreve (livro_isbn, autor_id) VALUES ($1, $2)`
@@ -53,3 +122,236 @@ func (r *LivroRepository) RemoveAutor(ctx context.Context, isbn string, autorID
 	_, err := r.DB.Exec(ctx, query, isbn, autorID)
 	return err
 }
+
+// AutoresPorLivro lê a tabela Escreve de todos os livros em uma única consulta
+func (r *LivroRepository) AutoresPorLivro(ctx context.Context, isbns []string) (map[string][]model.Autor, error) {
+	query := `SELECT e.livro_isbn, a.id, a.primeiro_nome, a.sobrenome
+	          FROM "Projeto Logico".Escreve e JOIN "Projeto Logico".Autor a ON a.id = e.autor_id
+	          WHERE e.livro_isbn = ANY($1) ORDER BY a.sobrenome, a.primeiro_nome`
+	rows, err := r.DB.Query(ctx, query, isbns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	autores := map[string][]model.Autor{}
+	for rows.Next() {
+		var isbn string
+		var a model.Autor
+		if err := rows.Scan(&isbn, &a.ID, &a.PrimeiroNome, &a.Sobrenome); err != nil {
+			return nil, err
+		}
+		autores[isbn] = append(autores[isbn], a)
+	}
+	return autores, rows.Err()
+}
+
+// implementação da classificação por assunto (tabela Classifica)
+func (r *LivroRepository) AddCategoria(ctx context.Context, isbn string, categoriaID int) error {
+	query := `INSERT INTO "Projeto Logico".Classifica (livro_isbn, categoria_id) VALUES ($1, $2)`
+	_, err := r.DB.Exec(ctx, query, isbn, categoriaID)
+	return err
+}
+
+func (r *LivroRepository) RemoveCategoria(ctx context.Context, isbn string, categoriaID int) error {
+	query := `DELETE FROM "Projeto Logico".Classifica WHERE livro_isbn = $1 AND categoria_id = $2`
+	_, err := r.DB.Exec(ctx, query, isbn, categoriaID)
+	return err
+}
+
+func (r *LivroRepository) ListByCategoria(ctx context.Context, categoriaID int) ([]model.Livro, error) {
+	// a CTE recursiva percorre a árvore de subcategorias a partir da categoria informada
+	query := `WITH RECURSIVE arvore AS (
+	              SELECT id FROM "Projeto Logico".Categoria WHERE id = $1
+	              UNION ALL
+	              SELECT c.id FROM "Projeto Logico".Categoria c JOIN arvore a ON c.categoria_pai_id = a.id
+	          )
+	          SELECT ` + colunasLivro + ` FROM "Projeto Logico".Livro l
+	          WHERE l.isbn IN (SELECT cl.livro_isbn FROM "Projeto Logico".Classifica cl WHERE cl.categoria_id IN (SELECT id FROM arvore))
+	          ORDER BY l.numero_classificacao, l.titulo`
+	rows, err := r.DB.Query(ctx, query, categoriaID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLivros(rows)
+}
+
+// ListByObra retorna todas as edições e traduções de uma obra
+func (r *LivroRepository) ListByObra(ctx context.Context, obraID int) ([]model.Livro, error) {
+	query := `SELECT ` + colunasLivro + ` FROM "Projeto Logico".Livro l WHERE l.obra_id = $1 ORDER BY l.idioma, l.edicao`
+	rows, err := r.DB.Query(ctx, query, obraID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLivros(rows)
+}
+
+// Consultar traduz a condição em uma cláusula WHERE; os parâmetros são
+// acumulados em ordem, e os valores comparados com LIKE têm os curingas escapados
+func (r *LivroRepository) Consultar(ctx context.Context, c repository.CondicaoLivro, inicio, limite int) ([]model.Livro, int, error) {
+	var args []any
+	where, err := condicaoSQL(c, &args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT count(*) FROM "Projeto Logico".Livro l WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if limite <= 0 || inicio >= total {
+		return []model.Livro{}, total, nil
+	}
+
+	n := len(args)
+	query := fmt.Sprintf(`SELECT `+colunasLivro+` FROM "Projeto Logico".Livro l WHERE %s
+	          ORDER BY l.titulo, l.edicao, l.isbn OFFSET $%d LIMIT $%d`, where, n+1, n+2)
+	rows, err := r.DB.Query(ctx, query, append(args, inicio, limite)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	livros, err := scanLivros(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// completa autores e categorias da página, como GetByISBN
+	isbns := make([]string, len(livros))
+	for i, l := range livros {
+		isbns[i] = l.ISBN
+	}
+	autores, err := r.AutoresPorLivro(ctx, isbns)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err = r.DB.Query(ctx, `SELECT livro_isbn, categoria_id FROM "Projeto Logico".Classifica
+	          WHERE livro_isbn = ANY($1) ORDER BY categoria_id`, isbns)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	categorias := map[string][]int{}
+	for rows.Next() {
+		var isbn string
+		var id int
+		if err := rows.Scan(&isbn, &id); err != nil {
+			return nil, 0, err
+		}
+		categorias[isbn] = append(categorias[isbn], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	for i := range livros {
+		livros[i].Autores = autores[livros[i].ISBN]
+		livros[i].Categorias = categorias[livros[i].ISBN]
+	}
+	return livros, total, nil
+}
+
+// expressões de texto comparadas em cada campo; o livro atende à condição
+// quando qualquer uma delas atende
+var textoCampo = map[repository.CampoLivro][]string{
+	repository.CampoTitulo:        {`l.titulo`},
+	repository.CampoClassificacao: {`l.numero_classificacao`},
+	repository.CampoIdioma:        {`l.idioma`},
+	repository.CampoAutor:         {`a.primeiro_nome || ' ' || a.sobrenome`, `a.sobrenome || ', ' || a.primeiro_nome`},
+	repository.CampoEditora:       {`ed.nome`},
+	repository.CampoAssunto:       {`c.nome`},
+}
+
+func condicaoSQL(c repository.CondicaoLivro, args *[]any) (string, error) {
+	if c.Operador != "" {
+		if c.Esquerda == nil || c.Direita == nil {
+			return "", fmt.Errorf("condição %q sem os dois operandos", c.Operador)
+		}
+		esquerda, err := condicaoSQL(*c.Esquerda, args)
+		if err != nil {
+			return "", err
+		}
+		direita, err := condicaoSQL(*c.Direita, args)
+		if err != nil {
+			return "", err
+		}
+		switch c.Operador {
+		case repository.OperadorE:
+			return "(" + esquerda + " AND " + direita + ")", nil
+		case repository.OperadorOu:
+			return "(" + esquerda + " OR " + direita + ")", nil
+		case repository.OperadorENao:
+			return "(" + esquerda + " AND NOT " + direita + ")", nil
+		}
+		return "", fmt.Errorf("operador de busca desconhecido: %q", c.Operador)
+	}
+
+	switch c.Campo {
+	case repository.CampoTodos:
+		return "TRUE", nil
+	case repository.CampoQualquer:
+		titulo, err := condicaoSQL(repository.CondicaoLivro{Campo: repository.CampoTitulo, Relacao: c.Relacao, Valor: c.Valor}, args)
+		if err != nil {
+			return "", err
+		}
+		autor, err := condicaoSQL(repository.CondicaoLivro{Campo: repository.CampoAutor, Relacao: c.Relacao, Valor: c.Valor}, args)
+		if err != nil {
+			return "", err
+		}
+		return "(" + titulo + " OR " + autor + ")", nil
+	case repository.CampoISBN:
+		if c.Relacao == repository.RelacaoPrefixo {
+			*args = append(*args, escaparLike(c.Valor))
+			return fmt.Sprintf(`l.isbn LIKE $%d || '%%'`, len(*args)), nil
+		}
+		*args = append(*args, validacao.FormasISBN(c.Valor))
+		return fmt.Sprintf(`l.isbn = ANY($%d)`, len(*args)), nil
+	}
+
+	expressoes, ok := textoCampo[c.Campo]
+	if !ok {
+		return "", fmt.Errorf("campo de busca desconhecido: %q", c.Campo)
+	}
+	var comparacoes []string
+	for _, e := range expressoes {
+		comparacao, err := comparacaoSQL(e, c.Relacao, c.Valor, args)
+		if err != nil {
+			return "", err
+		}
+		comparacoes = append(comparacoes, comparacao)
+	}
+	teste := "(" + strings.Join(comparacoes, " OR ") + ")"
+
+	// campos de outras tabelas são testados em subconsultas correlacionadas
+	switch c.Campo {
+	case repository.CampoAutor:
+		return `EXISTS (SELECT 1 FROM "Projeto Logico".Escreve e JOIN "Projeto Logico".Autor a ON a.id = e.autor_id
+		        WHERE e.livro_isbn = l.isbn AND ` + teste + `)`, nil
+	case repository.CampoEditora:
+		return `EXISTS (SELECT 1 FROM "Projeto Logico".Editora ed WHERE ed.cnpj = l.editora_cnpj AND ` + teste + `)`, nil
+	case repository.CampoAssunto:
+		return `l.isbn IN (WITH RECURSIVE arvore AS (
+		            SELECT c.id FROM "Projeto Logico".Categoria c WHERE ` + teste + `
+		            UNION ALL
+		            SELECT c.id FROM "Projeto Logico".Categoria c JOIN arvore a ON c.categoria_pai_id = a.id
+		        )
+		        SELECT cl.livro_isbn FROM "Projeto Logico".Classifica cl WHERE cl.categoria_id IN (SELECT id FROM arvore))`, nil
+	}
+	return teste, nil
+}
+
+func comparacaoSQL(expressao string, relacao repository.RelacaoBusca, valor string, args *[]any) (string, error) {
+	switch relacao {
+	case repository.RelacaoIgual:
+		*args = append(*args, valor)
+		return fmt.Sprintf(`lower(%s) = lower($%d)`, expressao, len(*args)), nil
+	case repository.RelacaoContem:
+		*args = append(*args, escaparLike(valor))
+		return fmt.Sprintf(`(%s) ILIKE '%%' || $%d || '%%'`, expressao, len(*args)), nil
+	case repository.RelacaoPrefixo:
+		*args = append(*args, escaparLike(valor))
+		return fmt.Sprintf(`(%s) ILIKE $%d || '%%'`, expressao, len(*args)), nil
+	}
+	return "", fmt.Errorf("relação de busca desconhecida: %q", relacao)
+}
+
+// escaparLike faz os curingas de LIKE valerem como texto
+var escaparLike = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"crud-biblioteca/repository"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func simples(campo repository.CampoLivro, relacao repository.RelacaoBusca, valor string) *repository.CondicaoLivro {
+	return &repository.CondicaoLivro{Campo: campo, Relacao: relacao, Valor: valor}
+}
+
+func composta(operador repository.OperadorBusca, esquerda, direita *repository.CondicaoLivro) *repository.CondicaoLivro {
+	return &repository.CondicaoLivro{Operador: operador, Esquerda: esquerda, Direita: direita}
+}
+
+func TestCondicaoSQL(t *testing.T) {
+	titulo := simples(repository.CampoTitulo, repository.RelacaoContem, "dom")
+	idioma := simples(repository.CampoIdioma, repository.RelacaoIgual, "pt")
+	casos := []struct {
+		nome     string
+		condicao *repository.CondicaoLivro
+		esperado string // com "..." no fim, basta o início da expressão
+		args     []any
+	}{
+		{"todos", &repository.CondicaoLivro{Campo: repository.CampoTodos}, "TRUE", nil},
+		{"contém", titulo, `((l.titulo) ILIKE '%' || $1 || '%')`, []any{"dom"}},
+		{"igual", idioma, `(lower(l.idioma) = lower($1))`, []any{"pt"}},
+		{"prefixo", simples(repository.CampoClassificacao, repository.RelacaoPrefixo, "869"), `((l.numero_classificacao) ILIKE $1 || '%')`, []any{"869"}},
+		{"curingas como texto", simples(repository.CampoTitulo, repository.RelacaoContem, `50%_\`), `((l.titulo) ILIKE '%' || $1 || '%')`, []any{`50\%\_\\`}},
+		{"ISBN", simples(repository.CampoISBN, repository.RelacaoIgual, "8535902775"), `l.isbn = ANY($1)`, []any{[]string{"9788535902778", "8535902775"}}},
+		{"prefixo do ISBN", simples(repository.CampoISBN, repository.RelacaoPrefixo, "97885"), `l.isbn LIKE $1 || '%'`, []any{"97885"}},
+		{"e", composta(repository.OperadorE, titulo, idioma),
+			`(((l.titulo) ILIKE '%' || $1 || '%') AND (lower(l.idioma) = lower($2)))`, []any{"dom", "pt"}},
+		{"ou", composta(repository.OperadorOu, titulo, idioma),
+			`(((l.titulo) ILIKE '%' || $1 || '%') OR (lower(l.idioma) = lower($2)))`, []any{"dom", "pt"}},
+		{"e não", composta(repository.OperadorENao, &repository.CondicaoLivro{Campo: repository.CampoTodos}, idioma),
+			`(TRUE AND NOT (lower(l.idioma) = lower($1)))`, []any{"pt"}},
+		{"aninhada à direita", composta(repository.OperadorE, titulo, composta(repository.OperadorOu, idioma, idioma)),
+			`(((l.titulo) ILIKE '%' || $1 || '%') AND ((lower(l.idioma) = lower($2)) OR (lower(l.idioma) = lower($3))))`, []any{"dom", "pt", "pt"}},
+		{"qualquer campo", simples(repository.CampoQualquer, repository.RelacaoContem, "assis"),
+			`(((l.titulo) ILIKE '%' || $1 || '%') OR EXISTS (...`, []any{"assis", "assis", "assis"}},
+		{"autor", simples(repository.CampoAutor, repository.RelacaoIgual, "Machado de Assis"), `EXISTS (...`, []any{"Machado de Assis", "Machado de Assis"}},
+		{"editora", simples(repository.CampoEditora, repository.RelacaoContem, "record"), `EXISTS (...`, []any{"record"}},
+		{"assunto", simples(repository.CampoAssunto, repository.RelacaoContem, "romance"), `l.isbn IN (WITH RECURSIVE arvore AS (...`, []any{"romance"}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var args []any
+			obtido, err := condicaoSQL(*c.condicao, &args)
+			if err != nil {
+				t.Fatalf("condicaoSQL: %v", err)
+			}
+			if inicio, parcial := strings.CutSuffix(c.esperado, "..."); parcial {
+				if !strings.HasPrefix(obtido, inicio) {
+					t.Errorf("condicaoSQL = %s, esperado o início %s", obtido, inicio)
+				}
+			} else if obtido != c.esperado {
+				t.Errorf("condicaoSQL = %s, esperado %s", obtido, c.esperado)
+			}
+			if !reflect.DeepEqual(args, c.args) {
+				t.Errorf("argumentos %#v, esperados %#v", args, c.args)
+			}
+		})
+	}
+}
+
+func TestCondicaoSQLErros(t *testing.T) {
+	casos := []struct {
+		nome     string
+		condicao *repository.CondicaoLivro
+	}{
+		{"sem operando", &repository.CondicaoLivro{Operador: repository.OperadorE, Esquerda: simples(repository.CampoTitulo, repository.RelacaoContem, "a")}},
+		{"operador desconhecido", composta("xor", simples(repository.CampoTitulo, repository.RelacaoContem, "a"), simples(repository.CampoTitulo, repository.RelacaoContem, "b"))},
+		{"campo desconhecido", simples("capa", repository.RelacaoContem, "azul")},
+		{"relação desconhecida", simples(repository.CampoTitulo, "parecido", "dom")},
+		{"erro no operando", composta(repository.OperadorOu, simples(repository.CampoTitulo, repository.RelacaoContem, "a"), simples("capa", repository.RelacaoContem, "b"))},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var args []any
+			if obtido, err := condicaoSQL(*c.condicao, &args); err == nil {
+				t.Errorf("condicaoSQL = %s, esperado erro", obtido)
+			}
+		})
+	}
+}
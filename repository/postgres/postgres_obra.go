@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ObraRepository struct {
+	DB DBTX
+}
+
+func NewObraRepository(db DBTX) *ObraRepository {
+	return &ObraRepository{DB: db}
+}
+
+func (r *ObraRepository) Create(ctx context.Context, obra model.Obra) error {
+	query := `INSERT INTO "Projeto Logico".Obra (id, titulo, idioma_original) VALUES ($1, $2, $3)`
+	_, err := r.DB.Exec(ctx, query, obra.ID, obra.Titulo, obra.IdiomaOriginal)
+	return err
+}
+
+func (r *ObraRepository) GetByID(ctx context.Context, id int) (*model.Obra, error) {
+	query := `SELECT id, titulo, idioma_original FROM "Projeto Logico".Obra WHERE id = $1`
+	row := r.DB.QueryRow(ctx, query, id)
+	var o model.Obra
+	err := row.Scan(&o.ID, &o.Titulo, &o.IdiomaOriginal)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *ObraRepository) Update(ctx context.Context, obra model.Obra) error {
+	query := `UPDATE "Projeto Logico".Obra SET titulo = $1, idioma_original = $2 WHERE id = $3`
+	_, err := r.DB.Exec(ctx, query, obra.Titulo, obra.IdiomaOriginal, obra.ID)
+	return err
+}
+
+func (r *ObraRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM "Projeto Logico".Obra WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func (r *ObraRepository) ListByIDs(ctx context.Context, ids []int) ([]model.Obra, error) {
+	query := `SELECT id, titulo, idioma_original FROM "Projeto Logico".Obra WHERE id = ANY($1)`
+	rows, err := r.DB.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Obra, error) {
+		var o model.Obra
+		err := row.Scan(&o.ID, &o.Titulo, &o.IdiomaOriginal)
+		return o, err
+	})
+}
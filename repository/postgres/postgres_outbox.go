@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	DB DBTX
+}
+
+func NewOutboxRepository(db DBTX) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+const colunasEvento = `id, tipo, agregado, chave, dados::text, ocorrido_em, publicado_em, tentativas, ultimo_erro`
+
+func (r *OutboxRepository) Registrar(ctx context.Context, e model.EventoDominio) error {
+	query := `INSERT INTO "Projeto Logico".EventoDominio (id, tipo, agregado, chave, dados, ocorrido_em)
+	          VALUES ($1, $2, $3, $4, $5::jsonb, $6)`
+	_, err := r.DB.Exec(ctx, query, e.ID, e.Tipo, e.Agregado, e.Chave, e.Dados, e.OcorridoEm)
+	return err
+}
+
+func (r *OutboxRepository) Pendentes(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	query := `SELECT ` + colunasEvento + ` FROM "Projeto Logico".EventoDominio
+	          WHERE publicado_em IS NULL ORDER BY id LIMIT $1`
+	rows, err := r.DB.Query(ctx, query, limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanEventos(rows)
+}
+
+func (r *OutboxRepository) MarcarPublicado(ctx context.Context, id string, em time.Time) error {
+	query := `UPDATE "Projeto Logico".EventoDominio SET publicado_em = $1, ultimo_erro = '' WHERE id = $2`
+	_, err := r.DB.Exec(ctx, query, em, id)
+	return err
+}
+
+func (r *OutboxRepository) RegistrarFalha(ctx context.Context, id string, erro string) error {
+	query := `UPDATE "Projeto Logico".EventoDominio SET tentativas = tentativas + 1, ultimo_erro = $1 WHERE id = $2`
+	_, err := r.DB.Exec(ctx, query, erro, id)
+	return err
+}
+
+func (r *OutboxRepository) List(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	query := `SELECT ` + colunasEvento + ` FROM "Projeto Logico".EventoDominio ORDER BY id DESC LIMIT $1`
+	rows, err := r.DB.Query(ctx, query, limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanEventos(rows)
+}
+
+func scanEventos(rows pgx.Rows) ([]model.EventoDominio, error) {
+	defer rows.Close()
+	var eventos []model.EventoDominio
+	for rows.Next() {
+		var e model.EventoDominio
+		err := rows.Scan(&e.ID, &e.Tipo, &e.Agregado, &e.Chave, &e.Dados, &e.OcorridoEm, &e.PublicadoEm, &e.Tentativas, &e.UltimoErro)
+		if err != nil {
+			return nil, err
+		}
+		eventos = append(eventos, e)
+	}
+	return eventos, rows.Err()
+}
+
+func (r *OutboxRepository) Depois(ctx context.Context, id string, tipos []string, limite int) ([]model.EventoDominio, error) {
+	query := `SELECT ` + colunasEvento + ` FROM "Projeto Logico".EventoDominio
+	          WHERE id > $1 AND tipo = ANY($2) ORDER BY id LIMIT $3`
+	rows, err := r.DB.Query(ctx, query, id, tipos, limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanEventos(rows)
+}
+
+// Avisos escuta o canal eventos_dominio, notificado pelo gatilho da tabela
+// EventoDominio quando a transação que gravou o evento é confirmada
+type Avisos struct {
+	Pool *pgxpool.Pool
+}
+
+func NewAvisos(pool *pgxpool.Pool) *Avisos {
+	return &Avisos{Pool: pool}
+}
+
+func (a *Avisos) Escutar(ctx context.Context, aviso func()) error {
+	// o LISTEN vale para a conexão, que fica reservada enquanto durar a escuta
+	conn, err := a.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "LISTEN eventos_dominio"); err != nil {
+		return err
+	}
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			// a conexão pode ter ficado no meio de uma leitura; não volta ao pool
+			conn.Hijack().Close(context.Background())
+			return err
+		}
+		aviso()
+	}
+}
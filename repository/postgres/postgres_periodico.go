@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PeriodicoRepository struct {
+	DB DBTX
+}
+
+func NewPeriodicoRepository(db DBTX) *PeriodicoRepository {
+	return &PeriodicoRepository{DB: db}
+}
+
+func (r *PeriodicoRepository) Create(ctx context.Context, periodico model.Periodico) error {
+	query := `INSERT INTO "Projeto Logico".Periodico (issn, titulo, editora_cnpj, fasciculos_por_ano, prazo_reclamacao, inicio_assinatura, ativo)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.DB.Exec(ctx, query, periodico.ISSN, periodico.Titulo, periodico.EditoraCNPJ, periodico.FasciculosPorAno,
+		periodico.PrazoReclamacao, periodico.InicioAssinatura, periodico.Ativo)
+	return err
+}
+
+func (r *PeriodicoRepository) GetByISSN(ctx context.Context, issn string) (*model.Periodico, error) {
+	query := `SELECT issn, titulo, editora_cnpj, fasciculos_por_ano, prazo_reclamacao, inicio_assinatura, ativo
+	          FROM "Projeto Logico".Periodico WHERE issn = $1`
+	p, err := scanPeriodico(r.DB.QueryRow(ctx, query, issn))
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PeriodicoRepository) Update(ctx context.Context, periodico model.Periodico) error {
+	query := `UPDATE "Projeto Logico".Periodico SET titulo = $1, editora_cnpj = $2, fasciculos_por_ano = $3, prazo_reclamacao = $4,
+	              inicio_assinatura = $5, ativo = $6
+	          WHERE issn = $7`
+	_, err := r.DB.Exec(ctx, query, periodico.Titulo, periodico.EditoraCNPJ, periodico.FasciculosPorAno, periodico.PrazoReclamacao,
+		periodico.InicioAssinatura, periodico.Ativo, periodico.ISSN)
+	return err
+}
+
+func (r *PeriodicoRepository) Delete(ctx context.Context, issn string) error {
+	query := `DELETE FROM "Projeto Logico".Periodico WHERE issn = $1`
+	_, err := r.DB.Exec(ctx, query, issn)
+	return err
+}
+
+func (r *PeriodicoRepository) ListAtivos(ctx context.Context) ([]model.Periodico, error) {
+	query := `SELECT issn, titulo, editora_cnpj, fasciculos_por_ano, prazo_reclamacao, inicio_assinatura, ativo
+	          FROM "Projeto Logico".Periodico WHERE ativo ORDER BY titulo`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periodicos []model.Periodico
+	for rows.Next() {
+		p, err := scanPeriodico(rows)
+		if err != nil {
+			return nil, err
+		}
+		periodicos = append(periodicos, p)
+	}
+	return periodicos, rows.Err()
+}
+
+func scanPeriodico(row pgx.Row) (model.Periodico, error) {
+	var p model.Periodico
+	err := row.Scan(&p.ISSN, &p.Titulo, &p.EditoraCNPJ, &p.FasciculosPorAno, &p.PrazoReclamacao, &p.InicioAssinatura, &p.Ativo)
+	return p, err
+}
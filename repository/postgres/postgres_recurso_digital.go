@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RecursoDigitalRepository struct {
+	DB DBTX
+}
+
+func NewRecursoDigitalRepository(db DBTX) *RecursoDigitalRepository {
+	return &RecursoDigitalRepository{DB: db}
+}
+
+func (r *RecursoDigitalRepository) Create(ctx context.Context, recurso model.RecursoDigital) error {
+	query := `INSERT INTO "Projeto Logico".Recurso_Digital (id, livro_isbn, tipo, nome_arquivo, caminho, mime, tamanho, sha256, enviado_em)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.DB.Exec(ctx, query, recurso.ID, recurso.LivroISBN, recurso.Tipo, recurso.NomeArquivo, recurso.Caminho,
+		recurso.MIME, recurso.Tamanho, recurso.SHA256, recurso.EnviadoEm)
+	return err
+}
+
+func (r *RecursoDigitalRepository) GetByID(ctx context.Context, id int) (*model.RecursoDigital, error) {
+	query := `SELECT id, livro_isbn, tipo, nome_arquivo, caminho, mime, tamanho, sha256, enviado_em
+	          FROM "Projeto Logico".Recurso_Digital WHERE id = $1`
+	rec, err := scanRecursoDigital(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *RecursoDigitalRepository) ListByLivro(ctx context.Context, isbn string) ([]model.RecursoDigital, error) {
+	query := `SELECT id, livro_isbn, tipo, nome_arquivo, caminho, mime, tamanho, sha256, enviado_em
+	          FROM "Projeto Logico".Recurso_Digital WHERE livro_isbn = $1 ORDER BY id`
+	rows, err := r.DB.Query(ctx, query, isbn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recursos []model.RecursoDigital
+	for rows.Next() {
+		rec, err := scanRecursoDigital(rows)
+		if err != nil {
+			return nil, err
+		}
+		recursos = append(recursos, rec)
+	}
+	return recursos, rows.Err()
+}
+
+func (r *RecursoDigitalRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM "Projeto Logico".Recurso_Digital WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func scanRecursoDigital(row pgx.Row) (model.RecursoDigital, error) {
+	var rec model.RecursoDigital
+	err := row.Scan(&rec.ID, &rec.LivroISBN, &rec.Tipo, &rec.NomeArquivo, &rec.Caminho, &rec.MIME, &rec.Tamanho, &rec.SHA256, &rec.EnviadoEm)
+	return rec, err
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReservaRepository struct {
+	DB DBTX
+}
+
+func NewReservaRepository(db DBTX) *ReservaRepository {
+	return &ReservaRepository{DB: db}
+}
+
+// livro_isbn é NULL nas reservas para qualquer edição da obra
+func (r *ReservaRepository) Create(ctx context.Context, reserva model.Reserva) error {
+	query := `INSERT INTO "Projeto Logico".Reserva (id, usuario_cpf, livro_isbn, obra_id, data_reserva, status)
+	          VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)`
+	_, err := r.DB.Exec(ctx, query, reserva.ID, reserva.UsuarioCPF, reserva.LivroISBN, reserva.ObraID, reserva.DataReserva, reserva.Status)
+	return err
+}
+
+func (r *ReservaRepository) GetByID(ctx context.Context, id int) (*model.Reserva, error) {
+	query := `SELECT id, usuario_cpf, COALESCE(livro_isbn, ''), obra_id, data_reserva, status
+	          FROM "Projeto Logico".Reserva WHERE id = $1`
+	res, err := scanReserva(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *ReservaRepository) Update(ctx context.Context, reserva model.Reserva) error {
+	query := `UPDATE "Projeto Logico".Reserva SET usuario_cpf = $1, livro_isbn = NULLIF($2, ''), obra_id = $3, data_reserva = $4, status = $5
+	          WHERE id = $6`
+	_, err := r.DB.Exec(ctx, query, reserva.UsuarioCPF, reserva.LivroISBN, reserva.ObraID, reserva.DataReserva, reserva.Status, reserva.ID)
+	return err
+}
+
+func (r *ReservaRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM "Projeto Logico".Reserva WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func (r *ReservaRepository) ListByUsuario(ctx context.Context, cpf string) ([]model.Reserva, error) {
+	query := `SELECT id, usuario_cpf, COALESCE(livro_isbn, ''), obra_id, data_reserva, status
+	          FROM "Projeto Logico".Reserva WHERE usuario_cpf = $1 ORDER BY data_reserva`
+	rows, err := r.DB.Query(ctx, query, cpf)
+	if err != nil {
+		return nil, err
+	}
+	return scanReservas(rows)
+}
+
+func (r *ReservaRepository) FilaPorLivro(ctx context.Context, isbn string) ([]model.Reserva, error) {
+	query := `SELECT r.id, r.usuario_cpf, COALESCE(r.livro_isbn, ''), r.obra_id, r.data_reserva, r.status
+	          FROM "Projeto Logico".Reserva r
+	          WHERE r.status = 'A'
+	            AND (r.livro_isbn = $1
+	                 OR (r.livro_isbn IS NULL AND r.obra_id = (SELECT obra_id FROM "Projeto Logico".Livro WHERE isbn = $1)))
+	          ORDER BY r.data_reserva, r.id`
+	rows, err := r.DB.Query(ctx, query, isbn)
+	if err != nil {
+		return nil, err
+	}
+	return scanReservas(rows)
+}
+
+func (r *ReservaRepository) ProximoID(ctx context.Context) (int, error) {
+	var id int
+	err := r.DB.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) + 1 FROM "Projeto Logico".Reserva`).Scan(&id)
+	return id, err
+}
+
+func scanReserva(row pgx.Row) (model.Reserva, error) {
+	var res model.Reserva
+	err := row.Scan(&res.ID, &res.UsuarioCPF, &res.LivroISBN, &res.ObraID, &res.DataReserva, &res.Status)
+	return res, err
+}
+
+func scanReservas(rows pgx.Rows) ([]model.Reserva, error) {
+	defer rows.Close()
+	var reservas []model.Reserva
+	for rows.Next() {
+		res, err := scanReserva(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservas = append(reservas, res)
+	}
+	return reservas, rows.Err()
+}
@@ -3,30 +3,42 @@ package postgres
 import (
 	"context"
 	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"fmt"
+	"strings"
+
 	"github.com/jackc/pgx/v5"
 )
 
 type UsuarioRepository struct {
-	DB *pgx.Conn
+	DB DBTX
 }
 
-func NewUsuarioRepository(db *pgx.Conn) *UsuarioRepository {
+func NewUsuarioRepository(db DBTX) *UsuarioRepository {
 	return &UsuarioRepository{DB: db}
 }
 
+// responsavel_cpf é NULL para usuários sem responsável (chave estrangeira para Usuario)
+const colunasUsuario = `cpf, data_nascimento, sobrenome, primeiro_nome,
+	              email, telefone, endereco_logradouro, endereco_numero, endereco_bairro, endereco_cidade, endereco_uf, endereco_cep,
+	              matricula, categoria, validade_vinculo, COALESCE(responsavel_cpf, '')`
+
 func (r *UsuarioRepository) Create(ctx context.Context, usuario model.Usuario) error {
-	query := `INSERT INTO "Projeto Logico".Usuario (cpf, data_nascimento, sobrenome, primeiro_nome) 
-	          VALUES ($1, $2, $3, $4)`
-	_, err := r.DB.Exec(ctx, query, usuario.CPF, usuario.DataNascimento, usuario.Sobrenome, usuario.PrimeiroNome)
+	query := `INSERT INTO "Projeto Logico".Usuario (cpf, data_nascimento, sobrenome, primeiro_nome,
+	              email, telefone, endereco_logradouro, endereco_numero, endereco_bairro, endereco_cidade, endereco_uf, endereco_cep,
+	              matricula, categoria, validade_vinculo, responsavel_cpf) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''))`
+	e := usuario.Endereco
+	_, err := r.DB.Exec(ctx, query, usuario.CPF, usuario.DataNascimento, usuario.Sobrenome, usuario.PrimeiroNome,
+		usuario.Email, usuario.Telefone, e.Logradouro, e.Numero, e.Bairro, e.Cidade, e.UF, e.CEP,
+		usuario.Matricula, usuario.Categoria, usuario.ValidadeVinculo, usuario.ResponsavelCPF)
 	return err
 }
 
 func (r *UsuarioRepository) GetByCPF(ctx context.Context, cpf string) (*model.Usuario, error) {
-	query := `SELECT cpf, data_nascimento, sobrenome, primeiro_nome 
+	query := `SELECT ` + colunasUsuario + ` 
 	          FROM "Projeto Logico".Usuario WHERE cpf = $1`
-	row := r.DB.QueryRow(ctx, query, cpf)
-	var u model.Usuario
-	err := row.Scan(&u.CPF, &u.DataNascimento, &u.Sobrenome, &u.PrimeiroNome)
+	u, err := scanUsuario(r.DB.QueryRow(ctx, query, cpf))
 	if err != nil {
 		return nil, err
 	}
@@ -35,9 +47,31 @@ func (r *UsuarioRepository) GetByCPF(ctx context.Context, cpf string) (*model.Us
 
 func (r *UsuarioRepository) Update(ctx context.Context, usuario model.Usuario) error {
 	query := `UPDATE "Projeto Logico".Usuario 
-	          SET data_nascimento = $1, sobrenome = $2, primeiro_nome = $3 
-			  WHERE cpf = $4`
-	_, err := r.DB.Exec(ctx, query, usuario.DataNascimento, usuario.Sobrenome, usuario.PrimeiroNome, usuario.CPF)
+	          SET data_nascimento = $1, sobrenome = $2, primeiro_nome = $3,
+	              email = $4, telefone = $5, endereco_logradouro = $6, endereco_numero = $7, endereco_bairro = $8,
+	              endereco_cidade = $9, endereco_uf = $10, endereco_cep = $11,
+	              matricula = $12, categoria = $13, validade_vinculo = $14, responsavel_cpf = NULLIF($15, '') 
+			  WHERE cpf = $16`
+	e := usuario.Endereco
+	_, err := r.DB.Exec(ctx, query, usuario.DataNascimento, usuario.Sobrenome, usuario.PrimeiroNome,
+		usuario.Email, usuario.Telefone, e.Logradouro, e.Numero, e.Bairro, e.Cidade, e.UF, e.CEP,
+		usuario.Matricula, usuario.Categoria, usuario.ValidadeVinculo, usuario.ResponsavelCPF, usuario.CPF)
+	return err
+}
+
+func (r *UsuarioRepository) SenhaHash(ctx context.Context, cpf string) (string, error) {
+	query := `SELECT COALESCE(senha_hash, '') FROM "Projeto Logico".Usuario WHERE cpf = $1`
+	var hash string
+	err := r.DB.QueryRow(ctx, query, cpf).Scan(&hash)
+	return hash, err
+}
+
+func (r *UsuarioRepository) DefinirSenha(ctx context.Context, cpf, hash string) error {
+	query := `UPDATE "Projeto Logico".Usuario SET senha_hash = $1 WHERE cpf = $2`
+	tag, err := r.DB.Exec(ctx, query, hash, cpf)
+	if err == nil && tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
 	return err
 }
 
@@ -46,3 +80,70 @@ func (r *UsuarioRepository) Delete(ctx context.Context, cpf string) error {
 	_, err := r.DB.Exec(ctx, query, cpf)
 	return err
 }
+
+func (r *UsuarioRepository) ListByResponsavel(ctx context.Context, cpf string) ([]model.Usuario, error) {
+	query := `SELECT ` + colunasUsuario + `
+	          FROM "Projeto Logico".Usuario WHERE responsavel_cpf = $1 ORDER BY primeiro_nome, sobrenome`
+	rows, err := r.DB.Query(ctx, query, cpf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usuarios []model.Usuario
+	for rows.Next() {
+		u, err := scanUsuario(rows)
+		if err != nil {
+			return nil, err
+		}
+		usuarios = append(usuarios, u)
+	}
+	return usuarios, rows.Err()
+}
+
+func (r *UsuarioRepository) List(ctx context.Context, filtro repository.FiltroUsuario) ([]model.Usuario, error) {
+	var condicoes []string
+	var args []any
+	if filtro.Nome != "" {
+		args = append(args, escaparLike(filtro.Nome))
+		condicoes = append(condicoes, fmt.Sprintf("primeiro_nome || ' ' || sobrenome ILIKE '%%' || $%d || '%%'", len(args)))
+	}
+	if filtro.Categoria != "" {
+		args = append(args, filtro.Categoria)
+		condicoes = append(condicoes, fmt.Sprintf("categoria = $%d", len(args)))
+	}
+	if filtro.CPFs != nil {
+		args = append(args, filtro.CPFs)
+		condicoes = append(condicoes, fmt.Sprintf("cpf = ANY($%d)", len(args)))
+	}
+	query := `SELECT ` + colunasUsuario + ` FROM "Projeto Logico".Usuario`
+	if len(condicoes) > 0 {
+		query += " WHERE " + strings.Join(condicoes, " AND ")
+	}
+	query += " ORDER BY primeiro_nome, sobrenome"
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usuarios []model.Usuario
+	for rows.Next() {
+		u, err := scanUsuario(rows)
+		if err != nil {
+			return nil, err
+		}
+		usuarios = append(usuarios, u)
+	}
+	return usuarios, rows.Err()
+}
+
+func scanUsuario(row pgx.Row) (model.Usuario, error) {
+	var u model.Usuario
+	e := &u.Endereco
+	err := row.Scan(&u.CPF, &u.DataNascimento, &u.Sobrenome, &u.PrimeiroNome,
+		&u.Email, &u.Telefone, &e.Logradouro, &e.Numero, &e.Bairro, &e.Cidade, &e.UF, &e.CEP,
+		&u.Matricula, &u.Categoria, &u.ValidadeVinculo, &u.ResponsavelCPF)
+	return u, err
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type WebhookRepository struct {
+	DB DBTX
+}
+
+func NewWebhookRepository(db DBTX) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+const colunasWebhook = `id, url, eventos, segredo, ativo, criado_em`
+
+func scanWebhook(row pgx.Row) (model.Webhook, error) {
+	var w model.Webhook
+	err := row.Scan(&w.ID, &w.URL, &w.Eventos, &w.Segredo, &w.Ativo, &w.CriadoEm)
+	return w, err
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook model.Webhook) error {
+	query := `INSERT INTO "Projeto Logico".Webhook (` + colunasWebhook + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.DB.Exec(ctx, query, webhook.ID, webhook.URL, webhook.Eventos, webhook.Segredo, webhook.Ativo, webhook.CriadoEm)
+	return err
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*model.Webhook, error) {
+	query := `SELECT ` + colunasWebhook + ` FROM "Projeto Logico".Webhook WHERE id = $1`
+	w, err := scanWebhook(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook model.Webhook) error {
+	query := `UPDATE "Projeto Logico".Webhook SET url = $1, eventos = $2, segredo = $3, ativo = $4 WHERE id = $5`
+	_, err := r.DB.Exec(ctx, query, webhook.URL, webhook.Eventos, webhook.Segredo, webhook.Ativo, webhook.ID)
+	return err
+}
+
+// as entregas são removidas em cascata pela chave estrangeira
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM "Projeto Logico".Webhook WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]model.Webhook, error) {
+	query := `SELECT ` + colunasWebhook + ` FROM "Projeto Logico".Webhook ORDER BY id`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var webhooks []model.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookRepository) ProximoID(ctx context.Context) (int, error) {
+	var id int
+	err := r.DB.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) + 1 FROM "Projeto Logico".Webhook`).Scan(&id)
+	return id, err
+}
+
+const colunasEntrega = `id, webhook_id, evento_id, evento, corpo, situacao, tentativas, proxima_tentativa,
+	ultimo_status, ultimo_erro, criada_em, entregue_em`
+
+func scanEntrega(row pgx.Row) (model.EntregaWebhook, error) {
+	var e model.EntregaWebhook
+	err := row.Scan(&e.ID, &e.WebhookID, &e.EventoID, &e.Evento, &e.Corpo, &e.Situacao, &e.Tentativas, &e.ProximaTentativa,
+		&e.UltimoStatus, &e.UltimoErro, &e.CriadaEm, &e.EntregueEm)
+	return e, err
+}
+
+func (r *WebhookRepository) CriarEntrega(ctx context.Context, e model.EntregaWebhook) error {
+	query := `INSERT INTO "Projeto Logico".EntregaWebhook (` + colunasEntrega + `)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err := r.DB.Exec(ctx, query, e.ID, e.WebhookID, e.EventoID, e.Evento, e.Corpo, e.Situacao, e.Tentativas, e.ProximaTentativa,
+		e.UltimoStatus, e.UltimoErro, e.CriadaEm, e.EntregueEm)
+	return err
+}
+
+func (r *WebhookRepository) AtualizarEntrega(ctx context.Context, e model.EntregaWebhook) error {
+	query := `UPDATE "Projeto Logico".EntregaWebhook SET situacao = $1, tentativas = $2, proxima_tentativa = $3,
+	              ultimo_status = $4, ultimo_erro = $5, entregue_em = $6 WHERE id = $7`
+	_, err := r.DB.Exec(ctx, query, e.Situacao, e.Tentativas, e.ProximaTentativa, e.UltimoStatus, e.UltimoErro, e.EntregueEm, e.ID)
+	return err
+}
+
+func (r *WebhookRepository) EntregasPendentes(ctx context.Context, ate time.Time, limite int) ([]model.EntregaWebhook, error) {
+	query := `SELECT ` + colunasEntrega + ` FROM "Projeto Logico".EntregaWebhook
+	          WHERE situacao = $1 AND proxima_tentativa <= $2 ORDER BY proxima_tentativa, criada_em LIMIT $3`
+	rows, err := r.DB.Query(ctx, query, model.EntregaPendente, ate, limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntregas(rows)
+}
+
+func (r *WebhookRepository) ListEntregas(ctx context.Context, webhookID int, limite int) ([]model.EntregaWebhook, error) {
+	query := `SELECT ` + colunasEntrega + ` FROM "Projeto Logico".EntregaWebhook
+	          WHERE webhook_id = $1 ORDER BY criada_em DESC, id LIMIT $2`
+	rows, err := r.DB.Query(ctx, query, webhookID, limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntregas(rows)
+}
+
+func scanEntregas(rows pgx.Rows) ([]model.EntregaWebhook, error) {
+	defer rows.Close()
+	var entregas []model.EntregaWebhook
+	for rows.Next() {
+		e, err := scanEntrega(rows)
+		if err != nil {
+			return nil, err
+		}
+		entregas = append(entregas, e)
+	}
+	return entregas, rows.Err()
+}
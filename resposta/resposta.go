@@ -0,0 +1,46 @@
+// Package resposta reúne o que a API REST e as páginas web têm em comum na
+// resposta HTTP: o status de cada classe de erro e o registro do status
+// enviado, usado no log das requisições.
+package resposta
+
+import (
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"errors"
+	"net/http"
+)
+
+// Status escolhe o status HTTP a partir da classe do erro; erros sem classe
+// conhecida são internos (500)
+func Status(err error) int {
+	if _, ok := validacao.ErrosDeCampo(err); ok {
+		return http.StatusUnprocessableEntity
+	}
+	switch {
+	case errors.Is(err, servico.ErrCredenciais):
+		return http.StatusUnauthorized
+	case errors.Is(err, repository.ErrNaoEncontrado):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrDuplicado), errors.Is(err, repository.ErrReferencia):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrInvalido), errors.Is(err, servico.ErrRegra):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// Registrada guarda o status enviado pelo handler
+type Registrada struct {
+	http.ResponseWriter
+	Status int
+}
+
+func Registrar(w http.ResponseWriter) *Registrada {
+	return &Registrada{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *Registrada) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,370 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Operações do autoatendimento: o usuário consulta e altera apenas os
+// próprios empréstimos e reservas. O CPF recebido é sempre o do usuário
+// autenticado; registros de outros usuários são tratados como inexistentes,
+// para que o portal não revele nem o que existe
+
+// TamanhoMinimoSenha é o número mínimo de caracteres da senha do portal
+const TamanhoMinimoSenha = 8
+
+// ErrCredenciais indica CPF ou senha incorretos, sem dizer qual dos dois
+var ErrCredenciais = errors.New("CPF ou senha incorretos")
+
+// hashFalso é comparado quando não há hash, para que a resposta leve o mesmo
+// tempo e não revele quais logins ou CPFs estão cadastrados
+var hashFalso, _ = bcrypt.GenerateFromPassword([]byte("senha"), bcrypt.DefaultCost)
+
+// ConferirSenha compara a senha com o hash bcrypt. Sem hash, quando o login
+// não existe ou não tem senha, a comparação é feita com um hash falso e a
+// senha é recusada no mesmo tempo de uma senha errada
+func ConferirSenha(hash []byte, senha string) bool {
+	if len(hash) == 0 {
+		bcrypt.CompareHashAndPassword(hashFalso, []byte(senha))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(senha)) == nil
+}
+
+// AutenticarUsuario confere a senha do portal. Usuários sem senha só entram
+// depois que a equipe define uma
+func (b *Biblioteca) AutenticarUsuario(ctx context.Context, cpf, senha string) (*model.Usuario, error) {
+	normalizar(&cpf, validacao.NormalizarCPF)
+	hash, err := b.Repos.Usuarios.SenhaHash(ctx, cpf)
+	if err := repository.Classificar(err); err != nil && !isNaoEncontrado(err) {
+		return nil, err
+	}
+	if !ConferirSenha([]byte(hash), senha) {
+		return nil, ErrCredenciais
+	}
+	return b.ObterUsuario(ctx, cpf)
+}
+
+// DefinirSenha grava a senha do portal do usuário, substituindo a anterior
+func (b *Biblioteca) DefinirSenha(ctx context.Context, cpf, senha string) error {
+	switch {
+	case utf8.RuneCountInString(senha) < TamanhoMinimoSenha:
+		return erroCampo("senha", validacao.CodigoInvalido, fmt.Sprintf("a senha deve ter pelo menos %d caracteres", TamanhoMinimoSenha))
+	case len(senha) > 72: // limite do bcrypt
+		return erroCampo("senha", validacao.CodigoInvalido, "a senha deve ter no máximo 72 bytes")
+	}
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(senha), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return naoEncontrado(b.Repos.Usuarios.DefinirSenha(ctx, u.CPF, string(hash)), "usuário com CPF %s", cpf)
+}
+
+// TrocarSenha confere a senha atual antes de gravar a nova
+func (b *Biblioteca) TrocarSenha(ctx context.Context, cpf, atual, nova string) error {
+	if _, err := b.AutenticarUsuario(ctx, cpf, atual); err != nil {
+		if errors.Is(err, ErrCredenciais) {
+			return erroCampo("senha_atual", validacao.CodigoInvalido, "senha atual incorreta")
+		}
+		return err
+	}
+	return b.DefinirSenha(ctx, cpf, nova)
+}
+
+// SituacaoEmprestimo é o empréstimo com o prazo e a multa calculados
+type SituacaoEmprestimo struct {
+	model.Emprestimo
+	Vencimento time.Time
+	DiasAtraso int
+	Multa      int // em centavos
+	Renovavel  bool
+	Motivo     string // por que o empréstimo não pode ser renovado
+}
+
+// PosicaoReserva é a reserva com a sua posição na fila do livro
+type PosicaoReserva struct {
+	model.Reserva
+	Posicao int // 1 é a próxima a ser atendida; 0 para reservas que não estão ativas
+}
+
+// Painel reúne o que o usuário vê no portal
+type Painel struct {
+	Situacao    Situacao
+	Ativos      []SituacaoEmprestimo
+	Historico   []SituacaoEmprestimo // devolvidos e cancelados, os mais recentes primeiro
+	Reservas    []PosicaoReserva
+	Dependentes []Dependente
+	MultaTotal  int // soma das multas do usuário e dos dependentes, em centavos
+}
+
+// Dependente é um menor de idade pelo qual o usuário responde, com os
+// empréstimos ativos e as multas, que ficam a cargo do responsável
+type Dependente struct {
+	Usuario    model.Usuario
+	Ativos     []SituacaoEmprestimo
+	MultaTotal int // em centavos, incluídos os empréstimos já devolvidos
+}
+
+func (b *Biblioteca) PainelUsuario(ctx context.Context, cpf string) (*Painel, error) {
+	situacao, err := b.SituacaoUsuario(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	u := situacao.Usuario
+	emprestimos, err := b.Repos.Emprestimos.List(ctx, repository.FiltroEmprestimo{CPF: u.CPF})
+	if err != nil {
+		return nil, err
+	}
+	p := &Painel{Situacao: *situacao}
+	agora := time.Now()
+	for _, e := range emprestimos {
+		s := situacaoEmprestimo(u, e, agora)
+		if e.Status == StatusAtivo {
+			if s.Renovavel && !situacao.PodeRenovar {
+				s.Renovavel, s.Motivo = false, situacao.Motivo
+			}
+			p.Ativos = append(p.Ativos, s)
+		} else {
+			p.Historico = append(p.Historico, s)
+		}
+		p.MultaTotal += s.Multa
+	}
+
+	dependentes, err := b.Repos.Usuarios.ListByResponsavel(ctx, u.CPF)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dependentes {
+		if d.CPFResponsavel(agora) != u.CPF {
+			continue // já é maior de idade e responde pelos próprios empréstimos
+		}
+		emprestimos, err := b.Repos.Emprestimos.List(ctx, repository.FiltroEmprestimo{CPF: d.CPF})
+		if err != nil {
+			return nil, err
+		}
+		dep := Dependente{Usuario: d}
+		for _, e := range emprestimos {
+			s := situacaoEmprestimo(d, e, agora)
+			if e.Status == StatusAtivo {
+				dep.Ativos = append(dep.Ativos, s)
+			}
+			dep.MultaTotal += s.Multa
+		}
+		p.MultaTotal += dep.MultaTotal
+		p.Dependentes = append(p.Dependentes, dep)
+	}
+
+	reservas, err := b.Repos.Reservas.ListByUsuario(ctx, u.CPF)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reservas {
+		posicao, err := b.posicaoNaFila(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		p.Reservas = append(p.Reservas, PosicaoReserva{r, posicao})
+	}
+	return p, nil
+}
+
+func situacaoEmprestimo(u model.Usuario, e model.Emprestimo, hoje time.Time) SituacaoEmprestimo {
+	prazo := u.PrazoDias()
+	s := SituacaoEmprestimo{Emprestimo: e, Vencimento: e.Vencimento(prazo), DiasAtraso: e.DiasAtraso(prazo, hoje),
+		Multa: e.Multa(prazo, hoje), Renovavel: true}
+	if err := e.PodeRenovar(prazo, hoje); err != nil {
+		s.Renovavel, s.Motivo = false, err.Error()
+	}
+	return s
+}
+
+// posicaoNaFila localiza a reserva ativa na fila do livro. Reservas para
+// qualquer edição entram na fila da primeira edição cadastrada da obra, que
+// é a mesma fila das demais reservas da obra
+func (b *Biblioteca) posicaoNaFila(ctx context.Context, r model.Reserva) (int, error) {
+	if r.Status != model.ReservaAtiva {
+		return 0, nil
+	}
+	isbn := r.LivroISBN
+	if r.QualquerEdicao() {
+		edicoes, err := b.Repos.Livros.ListByObra(ctx, *r.ObraID)
+		if err != nil || len(edicoes) == 0 {
+			return 0, err
+		}
+		isbn = edicoes[0].ISBN
+	}
+	fila, err := b.Repos.Reservas.FilaPorLivro(ctx, isbn)
+	if err := repository.Classificar(err); err != nil {
+		if isNaoEncontrado(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	for i, f := range fila {
+		if f.ID == r.ID {
+			return i + 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// RenovarEmprestimo estende o prazo de um empréstimo por mais um período da
+// categoria de quem o pegou. cpf é o do próprio usuário ou, para empréstimos
+// de menores de idade, o do responsável
+func (b *Biblioteca) RenovarEmprestimo(ctx context.Context, cpf string, id int) (*SituacaoEmprestimo, error) {
+	normalizar(&cpf, validacao.NormalizarCPF)
+	e, err := b.ObterEmprestimo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u, err := b.ObterUsuario(ctx, e.ClienteUsuarioCPF)
+	if err != nil {
+		return nil, err
+	}
+	agora := time.Now()
+	if u.CPF != cpf && u.CPFResponsavel(agora) != cpf {
+		return nil, repository.NaoEncontrado("empréstimo %d", id)
+	}
+	if err := e.PodeRenovar(u.PrazoDias(), agora); err != nil {
+		return nil, regra("%v", err)
+	}
+	if err := u.PodeEmprestar(0, 0, agora); err != nil { // a renovação não leva livros novos
+		return nil, regra("%v", err)
+	}
+	if err := b.conferirResponsavelApto(ctx, u, agora); err != nil {
+		return nil, err
+	}
+	e.Renovacoes++
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Emprestimos.Update(ctx, *e); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoEmprestimoRenovado, "emprestimo", strconv.Itoa(e.ID), e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := situacaoEmprestimo(*u, *e, agora)
+	return &s, nil
+}
+
+// Reservar coloca o usuário na fila de uma edição. As regras são as do
+// empréstimo, já que a reserva termina em um: vínculo ativo, responsável
+// apto para menores e nenhum material restrito
+func (b *Biblioteca) Reservar(ctx context.Context, cpf, isbn string) (*model.Reserva, error) {
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	normalizar(&isbn, validacao.NormalizarISBN)
+	livro, err := b.ObterLivro(ctx, isbn)
+	if err != nil {
+		if isNaoEncontrado(err) {
+			return nil, erroCampo("livro_isbn", validacao.CodigoInvalido, "livro não cadastrado")
+		}
+		return nil, err
+	}
+	agora := time.Now()
+	// o limite de livros só é conferido na retirada, quando o usuário já pode
+	// ter devolvido outros
+	if err := u.PodeEmprestar(0, 0, agora); err != nil {
+		return nil, regra("%v", err)
+	}
+	if err := b.conferirResponsavelApto(ctx, u, agora); err != nil {
+		return nil, err
+	}
+	categorias, err := b.categoriasDoLivro(ctx, *livro)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.PodeRetirar(categorias, agora); err != nil {
+		return nil, regra("%v", err)
+	}
+
+	existentes, err := b.Repos.Reservas.ListByUsuario(ctx, u.CPF)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range existentes {
+		if r.Status == model.ReservaAtiva && r.LivroISBN == livro.ISBN {
+			return nil, fmt.Errorf("%w: já existe a reserva %d para '%s'", repository.ErrDuplicado, r.ID, livro.Titulo)
+		}
+	}
+
+	r := model.Reserva{UsuarioCPF: u.CPF, LivroISBN: livro.ISBN, DataReserva: agora, Status: model.ReservaAtiva}
+	proximo := func(ctx context.Context, b *Biblioteca) (int, error) { return b.Repos.Reservas.ProximoID(ctx) }
+	err = b.gravarComID(ctx, proximo, func(ctx context.Context, b *Biblioteca, id int) error {
+		r.ID = id
+		if err := validacao.ValidarReserva(r); err != nil {
+			return err
+		}
+		if err := b.Repos.Reservas.Create(ctx, r); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoReservaCriada, "reserva", strconv.Itoa(r.ID), r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CancelarReserva cancela uma reserva ativa do usuário
+func (b *Biblioteca) CancelarReserva(ctx context.Context, cpf string, id int) error {
+	normalizar(&cpf, validacao.NormalizarCPF)
+	r, err := b.Repos.Reservas.GetByID(ctx, id)
+	if err == nil && r.UsuarioCPF != cpf {
+		err = repository.ErrNaoEncontrado
+	}
+	if err != nil {
+		return naoEncontrado(err, "reserva %d", id)
+	}
+	if r.Status != model.ReservaAtiva {
+		return regra("a reserva %d não está ativa", id)
+	}
+	r.Status = model.ReservaCancelada
+	return b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Reservas.Update(ctx, *r); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoReservaCancelada, "reserva", strconv.Itoa(r.ID), r)
+	})
+}
+
+// categoriasDoLivro carrega as categorias do livro e todas as superiores,
+// que também valem para as restrições de material
+func (b *Biblioteca) categoriasDoLivro(ctx context.Context, l model.Livro) ([]model.Categoria, error) {
+	var categorias []model.Categoria
+	visitadas := make(map[int]bool)
+	pendentes := append([]int(nil), l.Categorias...)
+	for len(pendentes) > 0 {
+		id := pendentes[0]
+		pendentes = pendentes[1:]
+		if visitadas[id] {
+			continue
+		}
+		visitadas[id] = true
+		c, err := b.Repos.Categorias.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("não foi possível verificar a categoria %d do livro: %w", id, repository.Classificar(err))
+		}
+		categorias = append(categorias, *c)
+		if c.PaiID != nil {
+			pendentes = append(pendentes, *c.PaiID)
+		}
+	}
+	return categorias, nil
+}
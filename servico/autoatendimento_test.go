@@ -0,0 +1,80 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPainelDoResponsavel(t *testing.T) {
+	const (
+		cpfMae   = "52998224725"
+		cpfFilho = "11144477735"
+		cpfOutro = "39053344705"
+		cpfMaior = "16899535009"
+	)
+	hoje := time.Now()
+	mae := model.Usuario{CPF: cpfMae, PrimeiroNome: "Maria", Categoria: model.CategoriaDocente,
+		DataNascimento: hoje.AddDate(-40, 0, 0)}
+	filho := model.Usuario{CPF: cpfFilho, PrimeiroNome: "João", Categoria: model.CategoriaExterno, // prazo de 7 dias
+		DataNascimento: hoje.AddDate(-12, 0, 0), ResponsavelCPF: cpfMae}
+	maior := model.Usuario{CPF: cpfMaior, PrimeiroNome: "Pedro", Categoria: model.CategoriaExterno,
+		DataNascimento: hoje.AddDate(-19, 0, 0), ResponsavelCPF: cpfMae} // fez 18 anos: responde por si
+	outro := model.Usuario{CPF: cpfOutro, PrimeiroNome: "Ana", Categoria: model.CategoriaExterno,
+		DataNascimento: hoje.AddDate(-30, 0, 0)}
+
+	b := bibliotecaMemoria([]model.Usuario{mae, filho, maior, outro}, []model.Emprestimo{
+		// venceu há 3 dias: 2 livros x 3 dias x R$ 1,00
+		{ID: 1, ClienteUsuarioCPF: cpfFilho, QuantLivros: 2, Status: StatusAtivo, DataEmprestimo: hoje.AddDate(0, 0, -10)},
+		{ID: 2, ClienteUsuarioCPF: cpfMaior, QuantLivros: 1, Status: StatusAtivo, DataEmprestimo: hoje.AddDate(0, 0, -10)},
+		{ID: 3, ClienteUsuarioCPF: cpfOutro, QuantLivros: 1, Status: StatusAtivo, DataEmprestimo: hoje.AddDate(0, 0, -10)},
+	})
+
+	p, err := b.PainelUsuario(context.Background(), cpfMae)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Dependentes) != 1 || p.Dependentes[0].Usuario.CPF != cpfFilho {
+		t.Fatalf("dependentes %+v, esperado só o menor de idade", p.Dependentes)
+	}
+	d := p.Dependentes[0]
+	if len(d.Ativos) != 1 || d.Ativos[0].ID != 1 || d.Ativos[0].DiasAtraso != 3 {
+		t.Fatalf("empréstimos do dependente %+v, esperado o 1 com 3 dias de atraso", d.Ativos)
+	}
+	if d.MultaTotal != 600 || p.MultaTotal != 600 {
+		t.Errorf("multa do dependente %d e total %d, esperados 600", d.MultaTotal, p.MultaTotal)
+	}
+	if len(p.Ativos) != 0 {
+		t.Errorf("os empréstimos do dependente não são do responsável: %+v", p.Ativos)
+	}
+}
+
+func TestRenovarEmprestimoDoDependente(t *testing.T) {
+	const (
+		cpfMae   = "52998224725"
+		cpfFilho = "11144477735"
+		cpfOutro = "39053344705"
+	)
+	hoje := time.Now()
+	b := bibliotecaMemoria([]model.Usuario{
+		{CPF: cpfMae, PrimeiroNome: "Maria", Categoria: model.CategoriaDocente, DataNascimento: hoje.AddDate(-40, 0, 0)},
+		{CPF: cpfFilho, PrimeiroNome: "João", Categoria: model.CategoriaExterno, DataNascimento: hoje.AddDate(-12, 0, 0), ResponsavelCPF: cpfMae},
+		{CPF: cpfOutro, PrimeiroNome: "Ana", Categoria: model.CategoriaExterno, DataNascimento: hoje.AddDate(-30, 0, 0)},
+	}, []model.Emprestimo{
+		{ID: 1, ClienteUsuarioCPF: cpfFilho, QuantLivros: 1, Status: StatusAtivo, DataEmprestimo: hoje.AddDate(0, 0, -2)},
+	})
+
+	if _, err := b.RenovarEmprestimo(context.Background(), cpfOutro, 1); !errors.Is(err, repository.ErrNaoEncontrado) {
+		t.Fatalf("outro usuário: esperado ErrNaoEncontrado, obtido %v", err)
+	}
+	s, err := b.RenovarEmprestimo(context.Background(), cpfMae, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Renovacoes != 1 {
+		t.Errorf("%d renovações, esperada 1", s.Renovacoes)
+	}
+}
@@ -0,0 +1,89 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func normalizarAutor(a *model.Autor) {
+	a.PrimeiroNome = strings.TrimSpace(a.PrimeiroNome)
+	a.Sobrenome = strings.TrimSpace(a.Sobrenome)
+}
+
+// ValidarAutor normaliza e valida o autor sem acessar o banco
+func ValidarAutor(a model.Autor) error {
+	normalizarAutor(&a)
+	return validacao.ValidarAutor(a)
+}
+
+func (b *Biblioteca) CriarAutor(ctx context.Context, a model.Autor) (*model.Autor, error) {
+	normalizarAutor(&a)
+	if err := validacao.ValidarAutor(a); err != nil {
+		return nil, err
+	}
+	// o MongoDB só acusa duplicidade de _id; a conferência vale para os dois bancos
+	if _, err := b.ObterAutor(ctx, a.ID); err == nil {
+		return nil, fmt.Errorf("%w: autor %d", repository.ErrDuplicado, a.ID)
+	} else if !isNaoEncontrado(err) {
+		return nil, err
+	}
+	err := b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Autores.Create(ctx, a); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoAutorCriado, "autor", strconv.Itoa(a.ID), a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (b *Biblioteca) ObterAutor(ctx context.Context, id int) (*model.Autor, error) {
+	a, err := b.Repos.Autores.GetByID(ctx, id)
+	if err != nil {
+		return nil, naoEncontrado(err, "autor %d", id)
+	}
+	return a, nil
+}
+
+func (b *Biblioteca) AtualizarAutor(ctx context.Context, a model.Autor) (*model.Autor, error) {
+	if _, err := b.ObterAutor(ctx, a.ID); err != nil {
+		return nil, err
+	}
+	normalizarAutor(&a)
+	if err := validacao.ValidarAutor(a); err != nil {
+		return nil, err
+	}
+	err := b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Autores.Update(ctx, a); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoAutorAtualizado, "autor", strconv.Itoa(a.ID), a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (b *Biblioteca) DeletarAutor(ctx context.Context, id int) error {
+	if _, err := b.ObterAutor(ctx, id); err != nil {
+		return err
+	}
+	return b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Autores.Delete(ctx, id); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoAutorRemovido, "autor", strconv.Itoa(id), map[string]int{"id": id})
+	})
+}
+
+func (b *Biblioteca) ListarAutores(ctx context.Context, nome string) ([]model.Autor, error) {
+	return b.Repos.Autores.List(ctx, strings.TrimSpace(nome))
+}
@@ -0,0 +1,93 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+)
+
+// limiteCatalogo é o maior número de livros retornado por BuscarCatalogo
+const limiteCatalogo = 100
+
+// Ficha reúne o livro e os cadastros que descrevem o registro bibliográfico:
+// a editora, os assuntos e a obra de que o livro é uma edição
+type Ficha struct {
+	Livro    model.Livro
+	Editora  *model.Editora // nil se o CNPJ não estiver cadastrado
+	Assuntos []model.Categoria
+	Obra     *model.Obra
+}
+
+// BuscarCatalogo consulta o acervo e monta as fichas dos livros a partir da
+// posição inicio (0 é o primeiro), no máximo limite deles (até 100). Retorna
+// também o total de livros encontrados
+func (b *Biblioteca) BuscarCatalogo(ctx context.Context, c repository.CondicaoLivro, inicio, limite int) ([]Ficha, int, error) {
+	if inicio < 0 {
+		return nil, 0, regra("a posição inicial não pode ser negativa")
+	}
+	limite = min(max(limite, 0), limiteCatalogo)
+	livros, total, err := b.Repos.Livros.Consultar(ctx, c, inicio, limite)
+	if err != nil {
+		return nil, 0, repository.Classificar(err)
+	}
+
+	// os cadastros relacionados são lidos uma vez para a página inteira
+	var cnpjs []string
+	var obraIDs []int
+	for _, l := range livros {
+		cnpjs = append(cnpjs, l.EditoraCNPJ)
+		if l.ObraID != nil {
+			obraIDs = append(obraIDs, *l.ObraID)
+		}
+	}
+	editoras := map[string]model.Editora{}
+	if len(cnpjs) > 0 {
+		lista, err := b.Repos.Editoras.ListByCNPJs(ctx, cnpjs)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, e := range lista {
+			editoras[e.CNPJ] = e
+		}
+	}
+	obras := map[int]model.Obra{}
+	if len(obraIDs) > 0 {
+		lista, err := b.Repos.Obras.ListByIDs(ctx, obraIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, o := range lista {
+			obras[o.ID] = o
+		}
+	}
+	categorias := map[int]*model.Categoria{}
+
+	fichas := make([]Ficha, 0, len(livros))
+	for _, l := range livros {
+		f := Ficha{Livro: l}
+		if e, ok := editoras[l.EditoraCNPJ]; ok {
+			f.Editora = &e
+		}
+		if l.ObraID != nil {
+			if o, ok := obras[*l.ObraID]; ok {
+				f.Obra = &o
+			}
+		}
+		for _, id := range l.Categorias {
+			c, ok := categorias[id]
+			if !ok {
+				c, err = b.Repos.Categorias.GetByID(ctx, id)
+				if err != nil && !isNaoEncontrado(repository.Classificar(err)) {
+					return nil, 0, err
+				}
+				// categorias removidas ficam de fora dos assuntos
+				categorias[id] = c
+			}
+			if c != nil {
+				f.Assuntos = append(f.Assuntos, *c)
+			}
+		}
+		fichas = append(fichas, f)
+	}
+	return fichas, total, nil
+}
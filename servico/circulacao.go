@@ -0,0 +1,138 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Operações do balcão de circulação, em que o operador informa só o CPF e os
+// livros e o sistema completa o empréstimo: próximo ID, data, quantidade e
+// prazo da categoria do usuário
+
+// Retirada é o empréstimo registrado no balcão, com os livros levados
+type Retirada struct {
+	Emprestimo model.Emprestimo
+	Livros     []model.Livro
+	Vencimento time.Time
+	// Avisos para o operador que não impedem o empréstimo, como livros que
+	// estão reservados para outros usuários
+	Avisos []string
+}
+
+// Emprestar registra um empréstimo dos livros para o usuário e atende as
+// reservas do usuário que estavam à frente da fila desses livros
+func (b *Biblioteca) Emprestar(ctx context.Context, cpf string, isbns []string) (*Retirada, error) {
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	r := &Retirada{}
+	proximo := func(ctx context.Context, b *Biblioteca) (int, error) { return b.Repos.Emprestimos.ProximoID(ctx) }
+	err = b.gravarComID(ctx, proximo, func(ctx context.Context, b *Biblioteca, id int) error {
+		*r = Retirada{}
+		e, livros, err := b.criarEmprestimo(ctx, model.Emprestimo{ID: id, ClienteUsuarioCPF: u.CPF, Livros: isbns,
+			DataEmprestimo: time.Now(), Status: StatusAtivo})
+		if err != nil {
+			return err
+		}
+		r.Emprestimo, r.Livros, r.Vencimento = *e, livros, e.Vencimento(u.PrazoDias())
+		for _, livro := range livros {
+			aviso, propria, err := b.conferirFila(ctx, u.CPF, livro)
+			if err != nil {
+				return err
+			}
+			if aviso != "" {
+				r.Avisos = append(r.Avisos, aviso)
+			}
+			if propria != nil {
+				if err := b.atender(ctx, *propria); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// conferirFila descreve a reserva de outro usuário que está à frente na fila
+// do livro, ou retorna a reserva do próprio usuário quando ela é a primeira;
+// com a fila vazia os dois ficam vazios
+func (b *Biblioteca) conferirFila(ctx context.Context, cpf string, l model.Livro) (string, *model.Reserva, error) {
+	fila, err := b.Repos.Reservas.FilaPorLivro(ctx, l.ISBN)
+	if err := repository.Classificar(err); err != nil {
+		if isNaoEncontrado(err) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	if len(fila) == 0 {
+		return "", nil, nil
+	}
+	if fila[0].UsuarioCPF == cpf {
+		return "", &fila[0], nil
+	}
+	return fmt.Sprintf("'%s' tem %d reserva(s); a primeira da fila é a %d, do CPF %s",
+		l.Titulo, len(fila), fila[0].ID, validacao.FormatarCPF(fila[0].UsuarioCPF)), nil, nil
+}
+
+// AtenderReserva marca a reserva ativa como atendida, o que a tira da fila.
+// Emprestar já atende a reserva do usuário que está à frente da fila do
+// livro retirado; esta operação serve às retiradas registradas de outra forma
+func (b *Biblioteca) AtenderReserva(ctx context.Context, id int) (*model.Reserva, error) {
+	r, err := b.Repos.Reservas.GetByID(ctx, id)
+	if err != nil {
+		return nil, naoEncontrado(err, "reserva %d", id)
+	}
+	if r.Status != model.ReservaAtiva {
+		return nil, regra("a reserva %d não está ativa", id)
+	}
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		return b.atender(ctx, *r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Status = model.ReservaAtendida
+	return r, nil
+}
+
+// atender grava a reserva como atendida; deve ser chamada dentro de gravar
+func (b *Biblioteca) atender(ctx context.Context, r model.Reserva) error {
+	r.Status = model.ReservaAtendida
+	if err := b.Repos.Reservas.Update(ctx, r); err != nil {
+		return repository.Classificar(err)
+	}
+	return b.registrarEvento(ctx, model.TipoReservaAtendida, "reserva", strconv.Itoa(r.ID), r)
+}
+
+// Devolver registra a devolução do empréstimo e retorna o atraso e a multa
+// calculados na data da devolução
+func (b *Biblioteca) Devolver(ctx context.Context, id int) (*SituacaoEmprestimo, error) {
+	e, err := b.ObterEmprestimo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != StatusAtivo {
+		return nil, regra("o empréstimo %d não está ativo", id)
+	}
+	u, err := b.ObterUsuario(ctx, e.ClienteUsuarioCPF)
+	if err != nil {
+		return nil, err
+	}
+	e.Status, e.DataDevolucao = StatusDevolvido, nil
+	e, err = b.AtualizarEmprestimo(ctx, *e)
+	if err != nil {
+		return nil, err
+	}
+	s := situacaoEmprestimo(*u, *e, time.Now())
+	return &s, nil
+}
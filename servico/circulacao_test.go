@@ -0,0 +1,49 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"testing"
+	"time"
+)
+
+// emprestimosConcorrentes grava, antes da primeira inclusão, um empréstimo de
+// outro balcão com o mesmo ID, como se a outra transação terminasse primeiro
+type emprestimosConcorrentes struct {
+	*emprestimosMemoria
+	concorrente model.Emprestimo
+	incluido    bool
+}
+
+func (r *emprestimosConcorrentes) Create(ctx context.Context, e model.Emprestimo) error {
+	if !r.incluido {
+		r.incluido = true
+		r.concorrente.ID = e.ID
+		r.emprestimos = append(r.emprestimos, r.concorrente)
+	}
+	return r.emprestimosMemoria.Create(ctx, e)
+}
+
+func TestEmprestarRepeteComNovoID(t *testing.T) {
+	const cpf = "52998224725"
+	b := bibliotecaMemoria([]model.Usuario{{CPF: cpf, PrimeiroNome: "Ana", Categoria: model.CategoriaGraduacao}}, nil)
+	emprestimos := &emprestimosConcorrentes{emprestimosMemoria: b.Repos.Emprestimos.(*emprestimosMemoria),
+		concorrente: model.Emprestimo{ClienteUsuarioCPF: "11144477735", QuantLivros: 1, Status: StatusAtivo, DataEmprestimo: time.Now()}}
+	b.Repos.Emprestimos = emprestimos
+	transacoes := 0
+	b.Repos.Transacao = func(ctx context.Context, f func(ctx context.Context, repos repository.Repositorios) error) error {
+		transacoes++
+		repos := b.Repos
+		repos.Transacao = nil
+		return f(ctx, repos)
+	}
+
+	r, err := b.Emprestar(context.Background(), cpf, isbnsMemoria[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Emprestimo.ID != 2 || transacoes != 2 {
+		t.Errorf("empréstimo %d em %d transações, esperado o 2 na segunda", r.Emprestimo.ID, transacoes)
+	}
+}
@@ -0,0 +1,230 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// StatusAtivo é o status dos empréstimos ainda não devolvidos
+const StatusAtivo = "A"
+
+// StatusDevolvido é o status dos empréstimos cujos livros já voltaram
+const StatusDevolvido = "D"
+
+func normalizarEmprestimo(e *model.Emprestimo) {
+	normalizar(&e.ClienteUsuarioCPF, validacao.NormalizarCPF)
+	e.Status = strings.ToUpper(strings.TrimSpace(e.Status))
+	for i := range e.Livros {
+		normalizar(&e.Livros[i], validacao.NormalizarISBN)
+	}
+}
+
+// ListaISBNs separa os ISBNs digitados em um só campo, por vírgulas ou espaços
+func ListaISBNs(texto string) []string {
+	return strings.FieldsFunc(texto, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+}
+
+// ValidarEmprestimo normaliza e valida o empréstimo sem acessar o banco
+func ValidarEmprestimo(e model.Emprestimo) error {
+	normalizarEmprestimo(&e)
+	return validacao.ValidarEmprestimo(e)
+}
+
+// CriarEmprestimo registra o empréstimo dos livros de e.Livros; sem data é
+// usado o momento atual, sem status o empréstimo é criado como ativo e sem
+// quantidade vale a dos ISBNs informados
+func (b *Biblioteca) CriarEmprestimo(ctx context.Context, e model.Emprestimo) (*model.Emprestimo, error) {
+	criado, _, err := b.criarEmprestimo(ctx, e)
+	return criado, err
+}
+
+// criarEmprestimo é CriarEmprestimo retornando também os livros conferidos
+func (b *Biblioteca) criarEmprestimo(ctx context.Context, e model.Emprestimo) (*model.Emprestimo, []model.Livro, error) {
+	if e.DataEmprestimo.IsZero() {
+		e.DataEmprestimo = time.Now()
+	}
+	if e.Status == "" {
+		e.Status = StatusAtivo
+	}
+	if e.QuantLivros == 0 {
+		e.QuantLivros = len(e.Livros)
+	}
+	normalizarEmprestimo(&e)
+	if len(e.Livros) == 0 {
+		return nil, nil, erroCampo("livros", validacao.CodigoObrigatorio, "informe os ISBNs dos livros emprestados")
+	}
+	if err := validacao.ValidarEmprestimo(e); err != nil {
+		return nil, nil, err
+	}
+	if len(e.Livros) != e.QuantLivros {
+		return nil, nil, erroCampo("quant_livros", validacao.CodigoInvalido,
+			fmt.Sprintf("a quantidade de livros (%d) não confere com os %d ISBN(s) informados", e.QuantLivros, len(e.Livros)))
+	}
+	u, err := b.conferirEmprestimo(ctx, e)
+	if err != nil {
+		return nil, nil, err
+	}
+	livros, err := b.conferirLivros(ctx, *u, e.Livros, e.DataEmprestimo)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Emprestimos.Create(ctx, e); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoEmprestimoCriado, "emprestimo", strconv.Itoa(e.ID), e)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	b.notificar(ctx, model.EventoEmprestimoCriado, dadosEmprestimo(e))
+	return &e, livros, nil
+}
+
+func (b *Biblioteca) ObterEmprestimo(ctx context.Context, id int) (*model.Emprestimo, error) {
+	e, err := b.Repos.Emprestimos.GetByID(ctx, id)
+	if err != nil {
+		return nil, naoEncontrado(err, "empréstimo %d", id)
+	}
+	return e, nil
+}
+
+// AtualizarEmprestimo substitui os dados do empréstimo identificado por e.ID.
+// As regras do usuário só são conferidas enquanto o empréstimo está ativo,
+// para que a devolução seja registrada mesmo com o vínculo expirado. A data
+// de devolução, usada no cálculo da multa, é registrada na primeira vez em
+// que o empréstimo passa a devolvido
+func (b *Biblioteca) AtualizarEmprestimo(ctx context.Context, e model.Emprestimo) (*model.Emprestimo, error) {
+	atual, err := b.ObterEmprestimo(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	normalizarEmprestimo(&e)
+	switch {
+	case e.Status == StatusAtivo:
+		e.DataDevolucao = nil
+	case e.Status == StatusDevolvido && e.DataDevolucao == nil:
+		e.DataDevolucao = atual.DataDevolucao
+		if e.DataDevolucao == nil {
+			agora := time.Now()
+			e.DataDevolucao = &agora
+		}
+	}
+	if err := validacao.ValidarEmprestimo(e); err != nil {
+		return nil, err
+	}
+	if e.Status == StatusAtivo {
+		if _, err := b.conferirEmprestimo(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+	devolvido := atual.Status != StatusDevolvido && e.Status == StatusDevolvido
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Emprestimos.Update(ctx, e); err != nil {
+			return repository.Classificar(err)
+		}
+		tipo := model.TipoEmprestimoAtualizado
+		if devolvido {
+			tipo = model.TipoEmprestimoDevolvido
+		}
+		return b.registrarEvento(ctx, tipo, "emprestimo", strconv.Itoa(e.ID), e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if devolvido {
+		b.notificar(ctx, model.EventoEmprestimoDevolvido, dadosEmprestimo(e))
+	}
+	return &e, nil
+}
+
+func (b *Biblioteca) DeletarEmprestimo(ctx context.Context, id int) error {
+	if _, err := b.ObterEmprestimo(ctx, id); err != nil {
+		return err
+	}
+	return b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Emprestimos.Delete(ctx, id); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoEmprestimoRemovido, "emprestimo", strconv.Itoa(id), map[string]int{"id": id})
+	})
+}
+
+// ProximoIDEmprestimo retorna o ID sugerido para um novo empréstimo
+func (b *Biblioteca) ProximoIDEmprestimo(ctx context.Context) (int, error) {
+	return b.Repos.Emprestimos.ProximoID(ctx)
+}
+
+func (b *Biblioteca) ListarEmprestimos(ctx context.Context, filtro repository.FiltroEmprestimo) ([]model.Emprestimo, error) {
+	if filtro.CPF != "" {
+		normalizar(&filtro.CPF, validacao.NormalizarCPF)
+	}
+	filtro.Status = strings.ToUpper(filtro.Status)
+	return b.Repos.Emprestimos.List(ctx, filtro)
+}
+
+// conferirEmprestimo aplica as regras do perfil do usuário e, para menores
+// de idade, as do responsável. O limite de livros conta os que o usuário já
+// tem em outros empréstimos ativos. Retorna o usuário do empréstimo
+func (b *Biblioteca) conferirEmprestimo(ctx context.Context, e model.Emprestimo) (*model.Usuario, error) {
+	u, err := b.Repos.Usuarios.GetByCPF(ctx, e.ClienteUsuarioCPF)
+	if err != nil {
+		if err := repository.Classificar(err); !isNaoEncontrado(err) {
+			return nil, err
+		}
+		return nil, erroCampo("cliente_usuario_cpf", validacao.CodigoInvalido, "usuário não cadastrado")
+	}
+	ativos, err := b.Repos.Emprestimos.List(ctx, repository.FiltroEmprestimo{CPF: u.CPF, Status: StatusAtivo})
+	if err != nil {
+		return nil, err
+	}
+	outros := ativos[:0]
+	for _, a := range ativos {
+		if a.ID != e.ID { // na atualização, o próprio empréstimo não conta
+			outros = append(outros, a)
+		}
+	}
+	if err := u.PodeEmprestar(model.LivrosEmPosse(outros), e.QuantLivros, e.DataEmprestimo); err != nil {
+		return nil, regra("%v", err)
+	}
+	if err := b.conferirResponsavelApto(ctx, u, e.DataEmprestimo); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// conferirLivros carrega os livros do empréstimo e confere que existem, não
+// se repetem e não são de categorias restritas para o usuário
+func (b *Biblioteca) conferirLivros(ctx context.Context, u model.Usuario, isbns []string, data time.Time) ([]model.Livro, error) {
+	var livros []model.Livro
+	for _, isbn := range isbns {
+		livro, err := b.ObterLivro(ctx, isbn)
+		if err != nil {
+			if isNaoEncontrado(err) {
+				return nil, erroCampo("livros", validacao.CodigoInvalido, fmt.Sprintf("livro %s não cadastrado", isbn))
+			}
+			return nil, err
+		}
+		for _, l := range livros {
+			if l.ISBN == livro.ISBN {
+				return nil, erroCampo("livros", validacao.CodigoInvalido, fmt.Sprintf("o livro %s foi informado mais de uma vez", isbn))
+			}
+		}
+		categorias, err := b.categoriasDoLivro(ctx, *livro)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.PodeRetirar(categorias, data); err != nil {
+			return nil, regra("%v", err)
+		}
+		livros = append(livros, *livro)
+	}
+	return livros, nil
+}
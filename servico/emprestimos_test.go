@@ -0,0 +1,105 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/validacao"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCriarEmprestimoContaLivrosEmPosse(t *testing.T) {
+	const cpf = "52998224725"
+	aluno := model.Usuario{CPF: cpf, PrimeiroNome: "Ana", Categoria: model.CategoriaGraduacao} // até 3 livros
+	ontem := time.Now().AddDate(0, 0, -1)
+	devolucao := time.Now()
+
+	casos := []struct {
+		nome        string
+		emprestimos []model.Emprestimo
+		quantLivros int
+		recusado    bool
+	}{
+		{"sem empréstimos", nil, 3, false},
+		{"até o limite", []model.Emprestimo{{ID: 1, ClienteUsuarioCPF: cpf, QuantLivros: 2, Status: StatusAtivo, DataEmprestimo: ontem}}, 1, false},
+		{"acima do limite", []model.Emprestimo{{ID: 1, ClienteUsuarioCPF: cpf, QuantLivros: 2, Status: StatusAtivo, DataEmprestimo: ontem}}, 2, true},
+		{"soma vários empréstimos", []model.Emprestimo{
+			{ID: 1, ClienteUsuarioCPF: cpf, QuantLivros: 1, Status: StatusAtivo, DataEmprestimo: ontem},
+			{ID: 2, ClienteUsuarioCPF: cpf, QuantLivros: 2, Status: StatusAtivo, DataEmprestimo: ontem},
+		}, 1, true},
+		{"devolvidos não contam", []model.Emprestimo{
+			{ID: 1, ClienteUsuarioCPF: cpf, QuantLivros: 3, Status: StatusDevolvido, DataEmprestimo: ontem, DataDevolucao: &devolucao},
+		}, 3, false},
+		{"de outro usuário não contam", []model.Emprestimo{
+			{ID: 1, ClienteUsuarioCPF: "11144477735", QuantLivros: 3, Status: StatusAtivo, DataEmprestimo: ontem},
+		}, 3, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			b := bibliotecaMemoria([]model.Usuario{aluno}, c.emprestimos)
+			_, err := b.CriarEmprestimo(context.Background(), model.Emprestimo{ID: 10, ClienteUsuarioCPF: cpf, Livros: isbnsMemoria[:c.quantLivros]})
+			if c.recusado && !errors.Is(err, ErrRegra) {
+				t.Fatalf("esperado ErrRegra, obtido %v", err)
+			}
+			if !c.recusado && err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+		})
+	}
+}
+
+func TestCriarEmprestimoConfereLivros(t *testing.T) {
+	const (
+		cpfMae   = "52998224725"
+		cpfFilho = "11144477735"
+	)
+	hoje := time.Now()
+	mae := model.Usuario{CPF: cpfMae, PrimeiroNome: "Maria", Categoria: model.CategoriaDocente, DataNascimento: hoje.AddDate(-40, 0, 0)}
+	filho := model.Usuario{CPF: cpfFilho, PrimeiroNome: "João", Categoria: model.CategoriaDocente,
+		DataNascimento: hoje.AddDate(-12, 0, 0), ResponsavelCPF: cpfMae}
+	pai := 1
+	restrito := "9791090636071" // na subcategoria 2, cuja categoria superior é restrita
+
+	casos := []struct {
+		nome   string
+		cpf    string
+		livros []string
+		quant  int
+		erro   error
+		campo  string
+	}{
+		{"maior leva material restrito", cpfMae, []string{restrito, isbnsMemoria[0]}, 0, nil, ""},
+		{"menor não leva material restrito", cpfFilho, []string{isbnsMemoria[0], restrito}, 0, ErrRegra, ""},
+		{"menor leva os demais", cpfFilho, isbnsMemoria[:2], 0, nil, ""},
+		{"sem ISBNs", cpfMae, nil, 1, nil, "livros"},
+		{"livro inexistente", cpfMae, []string{"9780000000002"}, 0, nil, "livros"},
+		{"livro repetido", cpfMae, []string{isbnsMemoria[0], "978-85-359-0277-8"}, 0, nil, "livros"},
+		{"quantidade diferente dos ISBNs", cpfMae, isbnsMemoria[:1], 2, nil, "quant_livros"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			b := bibliotecaMemoria([]model.Usuario{mae, filho}, nil)
+			b.Repos.Categorias = categoriasMemoria{categorias: map[int]model.Categoria{
+				1: {ID: 1, Nome: "Adulto", Restrita: true},
+				2: {ID: 2, Nome: "Romance adulto", PaiID: &pai},
+			}}
+			b.Repos.Livros.(livrosMemoria).livros[restrito] = model.Livro{ISBN: restrito, Categorias: []int{2}}
+
+			_, err := b.CriarEmprestimo(context.Background(), model.Emprestimo{ID: 10, ClienteUsuarioCPF: c.cpf, Livros: c.livros, QuantLivros: c.quant})
+			var erros validacao.Erros
+			switch {
+			case c.erro != nil:
+				if !errors.Is(err, c.erro) {
+					t.Fatalf("esperado %v, obtido %v", c.erro, err)
+				}
+			case c.campo != "":
+				if !errors.As(err, &erros) || erros[0].Campo != c.campo {
+					t.Fatalf("esperado erro em %s, obtido %v", c.campo, err)
+				}
+			case err != nil:
+				t.Fatalf("erro inesperado: %v", err)
+			}
+		})
+	}
+}
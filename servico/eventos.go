@@ -0,0 +1,119 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Os eventos de domínio registram as alterações feitas por este pacote. Cada
+// operação grava os seus eventos no outbox dentro de gravar, na mesma
+// transação (ou sessão, no MongoDB) da alteração: ou os dois são confirmados
+// ou nenhum é. O relay do pacote outbox os publica depois, na ordem em que
+// aconteceram. As alterações feitas fora do pacote servico não geram
+// eventos: no menu numerado, as opções de usuários, livros, autores e
+// empréstimos e o atendimento de reservas passam por aqui, e as demais gravam
+// direto nos repositórios
+
+// limiteEventos é o padrão de ListarEventos e EventosPendentes
+const limiteEventos = 50
+
+// gravar executa f em uma transação. Dentro de EmTransacao, ou com
+// repositórios sem transações, f usa os repositórios atuais
+func (b *Biblioteca) gravar(ctx context.Context, f func(ctx context.Context, b *Biblioteca) error) error {
+	if b.Repos.Transacao == nil {
+		return f(ctx, b)
+	}
+	return b.EmTransacao(ctx, f)
+}
+
+// tentativasID é quantas vezes gravarComID tenta gravar com um ID novo
+const tentativasID = 5
+
+// gravarComID executa f em uma transação com o próximo ID, lido dentro dela.
+// Se uma gravação simultânea ficar com o mesmo ID, a chave duplicada desfaz a
+// transação e ela é repetida com um ID lido de novo. Dentro de EmTransacao
+// não há repetição: o erro desfaz a transação de quem chamou
+func (b *Biblioteca) gravarComID(ctx context.Context, proximo func(ctx context.Context, b *Biblioteca) (int, error),
+	f func(ctx context.Context, b *Biblioteca, id int) error) error {
+	gravar := func(ctx context.Context, b *Biblioteca) error {
+		id, err := proximo(ctx, b)
+		if err != nil {
+			return err
+		}
+		return f(ctx, b, id)
+	}
+	if b.Repos.Transacao == nil {
+		return gravar(ctx, b)
+	}
+	var err error
+	for i := 0; i < tentativasID; i++ {
+		if err = b.EmTransacao(ctx, gravar); !errors.Is(err, repository.ErrDuplicado) {
+			return err
+		}
+	}
+	return err
+}
+
+// registrarEvento grava o evento no outbox; deve ser chamada dentro de gravar,
+// depois da alteração que o evento descreve
+func (b *Biblioteca) registrarEvento(ctx context.Context, tipo, agregado, chave string, dados any) error {
+	if b.Repos.Outbox == nil {
+		return nil
+	}
+	j, err := json.Marshal(dados)
+	if err != nil {
+		return err
+	}
+	agora := time.Now()
+	e := model.EventoDominio{
+		ID: fmt.Sprintf("%019d-%s", agora.UnixNano(), aleatorio()[:8]), Tipo: tipo,
+		Agregado: agregado, Chave: chave, Dados: string(j), OcorridoEm: agora,
+	}
+	return repository.Classificar(b.Repos.Outbox.Registrar(ctx, e))
+}
+
+// EventosPendentes retorna os eventos ainda não publicados, dos mais antigos;
+// limite 0 usa o padrão de 50
+func (b *Biblioteca) EventosPendentes(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	if limite <= 0 {
+		limite = limiteEventos
+	}
+	return b.Repos.Outbox.Pendentes(ctx, limite)
+}
+
+func (b *Biblioteca) MarcarPublicado(ctx context.Context, id string) error {
+	return repository.Classificar(b.Repos.Outbox.MarcarPublicado(ctx, id, time.Now()))
+}
+
+func (b *Biblioteca) RegistrarFalhaPublicacao(ctx context.Context, id string, falha error) error {
+	return repository.Classificar(b.Repos.Outbox.RegistrarFalha(ctx, id, falha.Error()))
+}
+
+// ListarEventos retorna os eventos mais recentes, publicados ou não; limite
+// 0 usa o padrão de 50
+func (b *Biblioteca) ListarEventos(ctx context.Context, limite int) ([]model.EventoDominio, error) {
+	if limite <= 0 {
+		limite = limiteEventos
+	}
+	return b.Repos.Outbox.List(ctx, limite)
+}
+
+// EventosDepois retorna, na ordem em que aconteceram, os eventos dos tipos
+// informados posteriores ao evento id; id vazio começa do primeiro
+func (b *Biblioteca) EventosDepois(ctx context.Context, id string, tipos []string, limite int) ([]model.EventoDominio, error) {
+	return b.Repos.Outbox.Depois(ctx, id, tipos, limite)
+}
+
+// UltimoEvento retorna o ID do evento mais recente, ou vazio sem eventos
+func (b *Biblioteca) UltimoEvento(ctx context.Context) (string, error) {
+	eventos, err := b.Repos.Outbox.List(ctx, 1)
+	if err != nil || len(eventos) == 0 {
+		return "", err
+	}
+	return eventos[0].ID, nil
+}
@@ -0,0 +1,217 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strings"
+)
+
+// FiltroLivro restringe a listagem de livros; campos vazios não filtram
+type FiltroLivro struct {
+	Titulo      string // parte do título, sem diferenciar maiúsculas
+	CategoriaID int    // inclui as subcategorias
+	ObraID      int
+}
+
+func normalizarLivro(l *model.Livro) {
+	normalizar(&l.ISBN, validacao.NormalizarISBN)
+	normalizar(&l.EditoraCNPJ, validacao.NormalizarCNPJ)
+	l.Titulo = strings.TrimSpace(l.Titulo)
+	l.SistemaClassificacao = strings.ToUpper(strings.TrimSpace(l.SistemaClassificacao))
+	l.Idioma = strings.ToLower(strings.TrimSpace(l.Idioma))
+	if l.Autores == nil {
+		l.Autores = []model.Autor{}
+	}
+	if l.Categorias == nil {
+		l.Categorias = []int{}
+	}
+}
+
+// ValidarLivro normaliza e valida o livro sem acessar o banco
+func ValidarLivro(l model.Livro) error {
+	normalizarLivro(&l)
+	return validacao.ValidarLivro(l)
+}
+
+// CriarLivro cadastra o livro com o ISBN na forma ISBN-13; os autores
+// informados são vinculados e cadastrados quando ainda não existem
+func (b *Biblioteca) CriarLivro(ctx context.Context, l model.Livro) (*model.Livro, error) {
+	normalizarLivro(&l)
+	if err := validacao.ValidarLivro(l); err != nil {
+		return nil, err
+	}
+	for _, a := range l.Autores {
+		if err := validacao.ValidarAutor(a); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.conferirEditora(ctx, l.EditoraCNPJ); err != nil {
+		return nil, err
+	}
+	autores := l.Autores
+	l.Autores = []model.Autor{}
+	err := b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Livros.Create(ctx, l); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoLivroCriado, "livro", l.ISBN, l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range autores {
+		if _, err := b.VincularAutor(ctx, l.ISBN, a); err != nil {
+			return nil, fmt.Errorf("livro cadastrado, mas o autor %d não foi vinculado: %w", a.ID, err)
+		}
+	}
+	return b.ObterLivro(ctx, l.ISBN)
+}
+
+// ObterLivro aceita o ISBN-10 ou ISBN-13, com ou sem hífens
+func (b *Biblioteca) ObterLivro(ctx context.Context, isbn string) (*model.Livro, error) {
+	l, err := b.Repos.Livros.GetByISBN(ctx, isbn)
+	if err != nil {
+		return nil, naoEncontrado(err, "livro com ISBN %s", isbn)
+	}
+	return l, nil
+}
+
+// AtualizarLivro substitui os dados do livro; autores e categorias são
+// mantidos, pois têm operações próprias de vínculo
+func (b *Biblioteca) AtualizarLivro(ctx context.Context, l model.Livro) (*model.Livro, error) {
+	atual, err := b.ObterLivro(ctx, l.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	l.ISBN = atual.ISBN
+	l.Autores = atual.Autores
+	l.Categorias = atual.Categorias
+	normalizarLivro(&l)
+	if err := validacao.ValidarLivro(l); err != nil {
+		return nil, err
+	}
+	if err := b.conferirEditora(ctx, l.EditoraCNPJ); err != nil {
+		return nil, err
+	}
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Livros.Update(ctx, l); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoLivroAtualizado, "livro", l.ISBN, l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (b *Biblioteca) DeletarLivro(ctx context.Context, isbn string) error {
+	l, err := b.ObterLivro(ctx, isbn)
+	if err != nil {
+		return err
+	}
+	return b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Livros.Delete(ctx, l.ISBN); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoLivroRemovido, "livro", l.ISBN, map[string]string{"isbn": l.ISBN})
+	})
+}
+
+func (b *Biblioteca) ListarLivros(ctx context.Context, filtro FiltroLivro) ([]model.Livro, error) {
+	var livros []model.Livro
+	var err error
+	switch {
+	case filtro.CategoriaID != 0:
+		livros, err = b.Repos.Livros.ListByCategoria(ctx, filtro.CategoriaID)
+	case filtro.ObraID != 0:
+		livros, err = b.Repos.Livros.ListByObra(ctx, filtro.ObraID)
+	default:
+		return b.Repos.Livros.Search(ctx, filtro.Titulo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// filtros combinados são aplicados sobre o resultado da consulta principal
+	termo := strings.ToLower(filtro.Titulo)
+	filtrados := livros[:0]
+	for _, l := range livros {
+		if filtro.ObraID != 0 && (l.ObraID == nil || *l.ObraID != filtro.ObraID) {
+			continue
+		}
+		if termo != "" && !strings.Contains(strings.ToLower(l.Titulo), termo) {
+			continue
+		}
+		filtrados = append(filtrados, l)
+	}
+	return filtrados, nil
+}
+
+// VincularAutor relaciona o autor ao livro, cadastrando-o se ainda não existir.
+// Autores já cadastrados mantêm os dados do cadastro
+func (b *Biblioteca) VincularAutor(ctx context.Context, isbn string, a model.Autor) (*model.Autor, error) {
+	l, err := b.ObterLivro(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	for _, existente := range l.Autores {
+		if existente.ID == a.ID {
+			return nil, fmt.Errorf("%w: o autor %d já está vinculado ao livro", repository.ErrDuplicado, a.ID)
+		}
+	}
+
+	// o autor novo e o vínculo são gravados juntos
+	var autor *model.Autor
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		var err error
+		autor, err = b.ObterAutor(ctx, a.ID)
+		if isNaoEncontrado(err) {
+			autor, err = b.CriarAutor(ctx, a)
+		}
+		if err != nil {
+			return err
+		}
+		if err := b.Repos.Livros.AddAutor(ctx, l.ISBN, *autor); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoLivroAutorVinculado, "livro", l.ISBN,
+			map[string]any{"isbn": l.ISBN, "autor": autor})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return autor, nil
+}
+
+func (b *Biblioteca) DesvincularAutor(ctx context.Context, isbn string, autorID int) error {
+	l, err := b.ObterLivro(ctx, isbn)
+	if err != nil {
+		return err
+	}
+	for _, a := range l.Autores {
+		if a.ID == autorID {
+			return b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+				if err := b.Repos.Livros.RemoveAutor(ctx, l.ISBN, autorID); err != nil {
+					return repository.Classificar(err)
+				}
+				return b.registrarEvento(ctx, model.TipoLivroAutorDesvinculado, "livro", l.ISBN,
+					map[string]any{"isbn": l.ISBN, "autor_id": autorID})
+			})
+		}
+	}
+	return repository.NaoEncontrado("o autor %d não está vinculado ao livro", autorID)
+}
+
+func (b *Biblioteca) conferirEditora(ctx context.Context, cnpj string) error {
+	if _, err := b.Repos.Editoras.GetByCNPJ(ctx, cnpj); err != nil {
+		if err := repository.Classificar(err); !isNaoEncontrado(err) {
+			return err
+		}
+		return erroCampo("editora_cnpj", validacao.CodigoInvalido, "editora não cadastrada")
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"fmt"
+	"slices"
+)
+
+// Repositórios em memória com só o que os testes do pacote usam; os demais
+// métodos vêm da interface embutida, nil, e falham se forem chamados
+
+type usuariosMemoria struct {
+	repository.UsuarioRepository
+	usuarios map[string]model.Usuario
+}
+
+func (r *usuariosMemoria) GetByCPF(_ context.Context, cpf string) (*model.Usuario, error) {
+	u, ok := r.usuarios[cpf]
+	if !ok {
+		return nil, repository.NaoEncontrado("usuário com CPF %s", cpf)
+	}
+	return &u, nil
+}
+
+func (r *usuariosMemoria) ListByResponsavel(_ context.Context, cpf string) ([]model.Usuario, error) {
+	var dependentes []model.Usuario
+	for _, u := range r.usuarios {
+		if u.ResponsavelCPF == cpf {
+			dependentes = append(dependentes, u)
+		}
+	}
+	return dependentes, nil
+}
+
+func (r *usuariosMemoria) List(_ context.Context, filtro repository.FiltroUsuario) ([]model.Usuario, error) {
+	var lista []model.Usuario
+	for _, u := range r.usuarios {
+		if filtro.CPFs == nil || slices.Contains(filtro.CPFs, u.CPF) {
+			lista = append(lista, u)
+		}
+	}
+	return lista, nil
+}
+
+type emprestimosMemoria struct {
+	repository.EmprestimoRepository
+	emprestimos []model.Emprestimo
+}
+
+func (r *emprestimosMemoria) Create(_ context.Context, e model.Emprestimo) error {
+	for _, existente := range r.emprestimos {
+		if existente.ID == e.ID {
+			return fmt.Errorf("%w: empréstimo %d", repository.ErrDuplicado, e.ID)
+		}
+	}
+	r.emprestimos = append(r.emprestimos, e)
+	return nil
+}
+
+func (r *emprestimosMemoria) ProximoID(context.Context) (int, error) {
+	id := 0
+	for _, e := range r.emprestimos {
+		id = max(id, e.ID)
+	}
+	return id + 1, nil
+}
+
+func (r *emprestimosMemoria) GetByID(_ context.Context, id int) (*model.Emprestimo, error) {
+	for _, e := range r.emprestimos {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, repository.NaoEncontrado("empréstimo %d", id)
+}
+
+func (r *emprestimosMemoria) Update(_ context.Context, e model.Emprestimo) error {
+	for i := range r.emprestimos {
+		if r.emprestimos[i].ID == e.ID {
+			r.emprestimos[i] = e
+			return nil
+		}
+	}
+	return repository.NaoEncontrado("empréstimo %d", e.ID)
+}
+
+func (r *emprestimosMemoria) List(_ context.Context, filtro repository.FiltroEmprestimo) ([]model.Emprestimo, error) {
+	var lista []model.Emprestimo
+	for _, e := range r.emprestimos {
+		switch {
+		case filtro.CPF != "" && e.ClienteUsuarioCPF != filtro.CPF,
+			filtro.Status != "" && e.Status != filtro.Status,
+			filtro.CPFs != nil && !slices.Contains(filtro.CPFs, e.ClienteUsuarioCPF):
+			continue
+		}
+		lista = append(lista, e)
+	}
+	return lista, nil
+}
+
+type livrosMemoria struct {
+	repository.LivroRepository
+	livros map[string]model.Livro
+}
+
+func (r livrosMemoria) GetByISBN(_ context.Context, isbn string) (*model.Livro, error) {
+	l, ok := r.livros[isbn]
+	if !ok {
+		return nil, repository.NaoEncontrado("livro com ISBN %s", isbn)
+	}
+	return &l, nil
+}
+
+type categoriasMemoria struct {
+	repository.CategoriaRepository
+	categorias map[int]model.Categoria
+}
+
+func (r categoriasMemoria) GetByID(_ context.Context, id int) (*model.Categoria, error) {
+	c, ok := r.categorias[id]
+	if !ok {
+		return nil, repository.NaoEncontrado("categoria %d", id)
+	}
+	return &c, nil
+}
+
+type reservasMemoria struct {
+	repository.ReservaRepository
+}
+
+func (reservasMemoria) ListByUsuario(context.Context, string) ([]model.Reserva, error) {
+	return nil, nil
+}
+
+func (reservasMemoria) FilaPorLivro(context.Context, string) ([]model.Reserva, error) {
+	return nil, nil
+}
+
+type webhooksMemoria struct {
+	repository.WebhookRepository
+	webhooks []model.Webhook
+	entregas []model.EntregaWebhook
+}
+
+func (r *webhooksMemoria) List(context.Context) ([]model.Webhook, error) {
+	return r.webhooks, nil
+}
+
+func (r *webhooksMemoria) CriarEntrega(_ context.Context, e model.EntregaWebhook) error {
+	for _, existente := range r.entregas {
+		if existente.ID == e.ID {
+			return fmt.Errorf("%w: entrega %s", repository.ErrDuplicado, e.ID)
+		}
+	}
+	r.entregas = append(r.entregas, e)
+	return nil
+}
+
+// bibliotecaMemoria monta a Biblioteca com os usuários e empréstimos
+// informados, além dos livros de isbnsMemoria, sem categorias
+func bibliotecaMemoria(usuarios []model.Usuario, emprestimos []model.Emprestimo) *Biblioteca {
+	u := &usuariosMemoria{usuarios: map[string]model.Usuario{}}
+	for _, usuario := range usuarios {
+		u.usuarios[usuario.CPF] = usuario
+	}
+	l := livrosMemoria{livros: map[string]model.Livro{}}
+	for _, isbn := range isbnsMemoria {
+		l.livros[isbn] = model.Livro{ISBN: isbn, Titulo: "Livro " + isbn}
+	}
+	return New(repository.Repositorios{
+		Usuarios:    u,
+		Emprestimos: &emprestimosMemoria{emprestimos: emprestimos},
+		Livros:      l,
+		Categorias:  categoriasMemoria{categorias: map[int]model.Categoria{}},
+		Reservas:    reservasMemoria{},
+	})
+}
+
+// isbnsMemoria são livros cadastrados em toda bibliotecaMemoria
+var isbnsMemoria = []string{"9788535902778", "9780306406157", "9780804429573"}
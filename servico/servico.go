@@ -0,0 +1,81 @@
+// Package servico reúne as operações da biblioteca com as mesmas validações e
+// regras aplicadas pelo menu interativo, para uso pela API HTTP e pelas demais
+// interfaces que não leem dados do terminal.
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"errors"
+	"fmt"
+)
+
+// ErrRegra indica uma operação recusada pelas regras da biblioteca (limite de
+// livros, vínculo expirado, responsável de menor de idade...)
+var ErrRegra = errors.New("operação não permitida")
+
+type Biblioteca struct {
+	Repos repository.Repositorios
+
+	// notificacoes guarda, dentro de EmTransacao, os eventos dos webhooks até
+	// a transação ser confirmada
+	notificacoes *[]notificacao
+}
+
+func New(repos repository.Repositorios) *Biblioteca {
+	return &Biblioteca{Repos: repos}
+}
+
+// EmTransacao executa f com uma Biblioteca cujas gravações só valem se f
+// terminar sem erro: qualquer erro desfaz tudo o que f gravou. Os eventos
+// dos webhooks gerados por f só são enfileirados depois da confirmação
+func (b *Biblioteca) EmTransacao(ctx context.Context, f func(ctx context.Context, b *Biblioteca) error) error {
+	if b.Repos.Transacao == nil {
+		return errors.New("transações não disponíveis para estes repositórios")
+	}
+	var notificacoes []notificacao
+	err := b.Repos.Transacao(ctx, func(ctx context.Context, repos repository.Repositorios) error {
+		// no MongoDB f é repetida em erros transitórios; valem os eventos da última execução
+		notificacoes = nil
+		return f(ctx, &Biblioteca{Repos: repos, notificacoes: &notificacoes})
+	})
+	if err != nil {
+		return err
+	}
+	for _, n := range notificacoes {
+		b.notificar(ctx, n.evento, n.dados)
+	}
+	return nil
+}
+
+func regra(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrRegra, fmt.Sprintf(format, args...))
+}
+
+// erroCampo monta um erro de validação de um único campo
+func erroCampo(campo, codigo, mensagem string) error {
+	return validacao.Erros{{Campo: campo, Codigo: codigo, Mensagem: mensagem}}
+}
+
+// normalizar aplica a função de normalização de documento; se o valor for
+// inválido ele é mantido, para que a validação do modelo aponte o campo
+func normalizar(valor *string, f func(string) (string, error)) {
+	if n, err := f(*valor); err == nil {
+		*valor = n
+	}
+}
+
+func isNaoEncontrado(err error) bool {
+	return errors.Is(err, repository.ErrNaoEncontrado)
+}
+
+// naoEncontrado classifica o erro do repositório e, quando o registro não
+// existe, troca a mensagem do driver por uma que identifica o registro
+func naoEncontrado(err error, format string, args ...any) error {
+	err = repository.Classificar(err)
+	if isNaoEncontrado(err) {
+		return repository.NaoEncontrado(format, args...)
+	}
+	return err
+}
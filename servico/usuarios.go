@@ -0,0 +1,216 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func normalizarUsuario(u *model.Usuario) {
+	normalizar(&u.CPF, validacao.NormalizarCPF)
+	if u.ResponsavelCPF != "" {
+		normalizar(&u.ResponsavelCPF, validacao.NormalizarCPF)
+	}
+	u.PrimeiroNome = strings.TrimSpace(u.PrimeiroNome)
+	u.Sobrenome = strings.TrimSpace(u.Sobrenome)
+	u.Email = strings.TrimSpace(u.Email)
+	u.Endereco.UF = strings.ToUpper(strings.TrimSpace(u.Endereco.UF))
+	u.Categoria = model.CategoriaUsuario(strings.ToLower(string(u.Categoria)))
+}
+
+// ValidarUsuario normaliza e valida o cadastro sem acessar o banco, para que
+// formulários apontem os erros antes de salvar
+func ValidarUsuario(u model.Usuario) error {
+	normalizarUsuario(&u)
+	return validacao.ValidarUsuario(u)
+}
+
+func (b *Biblioteca) CriarUsuario(ctx context.Context, u model.Usuario) (*model.Usuario, error) {
+	normalizarUsuario(&u)
+	if err := validacao.ValidarUsuario(u); err != nil {
+		return nil, err
+	}
+	if err := b.conferirResponsavel(ctx, u); err != nil {
+		return nil, err
+	}
+	err := b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Usuarios.Create(ctx, u); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoUsuarioCriado, "usuario", u.CPF, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.notificar(ctx, model.EventoUsuarioCriado, func(context.Context, *Biblioteca) (any, error) { return u, nil })
+	return &u, nil
+}
+
+func (b *Biblioteca) ObterUsuario(ctx context.Context, cpf string) (*model.Usuario, error) {
+	normalizado, err := validacao.NormalizarCPF(cpf)
+	if err != nil {
+		return nil, erroCampo("cpf", validacao.CodigoInvalido, err.Error())
+	}
+	u, err := b.Repos.Usuarios.GetByCPF(ctx, normalizado)
+	if err != nil {
+		return nil, naoEncontrado(err, "usuário com CPF %s", validacao.FormatarCPF(normalizado))
+	}
+	return u, nil
+}
+
+// AtualizarUsuario substitui todos os dados do usuário identificado por u.CPF
+func (b *Biblioteca) AtualizarUsuario(ctx context.Context, u model.Usuario) (*model.Usuario, error) {
+	if _, err := b.ObterUsuario(ctx, u.CPF); err != nil {
+		return nil, err
+	}
+	normalizarUsuario(&u)
+	if err := validacao.ValidarUsuario(u); err != nil {
+		return nil, err
+	}
+	if err := b.conferirResponsavel(ctx, u); err != nil {
+		return nil, err
+	}
+	err := b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Usuarios.Update(ctx, u); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoUsuarioAtualizado, "usuario", u.CPF, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// DeletarUsuario remove o usuário; responsáveis por menores não podem ser removidos
+func (b *Biblioteca) DeletarUsuario(ctx context.Context, cpf string) error {
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return err
+	}
+	dependentes, err := b.Repos.Usuarios.ListByResponsavel(ctx, u.CPF)
+	if err != nil {
+		return err
+	}
+	if len(dependentes) > 0 {
+		return regra("o usuário é responsável por %d menor(es) de idade", len(dependentes))
+	}
+	err = b.gravar(ctx, func(ctx context.Context, b *Biblioteca) error {
+		if err := b.Repos.Usuarios.Delete(ctx, u.CPF); err != nil {
+			return repository.Classificar(err)
+		}
+		return b.registrarEvento(ctx, model.TipoUsuarioRemovido, "usuario", u.CPF, map[string]string{"cpf": u.CPF})
+	})
+	if err != nil {
+		return err
+	}
+	b.notificar(ctx, model.EventoUsuarioRemovido, func(context.Context, *Biblioteca) (any, error) { return map[string]string{"cpf": u.CPF}, nil })
+	return nil
+}
+
+func (b *Biblioteca) ListarUsuarios(ctx context.Context, filtro repository.FiltroUsuario) ([]model.Usuario, error) {
+	filtro.Categoria = model.CategoriaUsuario(strings.ToLower(string(filtro.Categoria)))
+	return b.Repos.Usuarios.List(ctx, filtro)
+}
+
+// Dependentes retorna os menores de idade pelos quais o usuário responde
+func (b *Biblioteca) Dependentes(ctx context.Context, cpf string) ([]model.Usuario, error) {
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	return b.Repos.Usuarios.ListByResponsavel(ctx, u.CPF)
+}
+
+// Situacao resume o que o usuário pode fazer em uma data, para sistemas que
+// só precisam liberar ou recusar (catracas, autoatendimento)
+type Situacao struct {
+	Usuario           model.Usuario
+	VinculoAtivo      bool
+	MenorDeIdade      bool
+	EmprestimosAtivos int
+	LivrosEmPosse     int // livros somados dos empréstimos ativos
+	LimiteLivros      int
+	PodeEmprestar     bool
+	Motivo            string // por que o usuário não pode pegar livros emprestados
+	// PodeRenovar ignora o limite de livros, já que a renovação não leva
+	// livros novos; o vínculo e o responsável valem como no empréstimo
+	PodeRenovar bool
+}
+
+func (b *Biblioteca) SituacaoUsuario(ctx context.Context, cpf string) (*Situacao, error) {
+	u, err := b.ObterUsuario(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	ativos, err := b.Repos.Emprestimos.List(ctx, repository.FiltroEmprestimo{CPF: u.CPF, Status: StatusAtivo})
+	if err != nil {
+		return nil, err
+	}
+
+	agora := time.Now()
+	s := &Situacao{
+		Usuario:           *u,
+		VinculoAtivo:      u.VinculoAtivo(agora),
+		MenorDeIdade:      u.MenorDeIdade(agora),
+		EmprestimosAtivos: len(ativos),
+		LivrosEmPosse:     model.LivrosEmPosse(ativos),
+		LimiteLivros:      u.LimiteLivros(),
+		PodeEmprestar:     true,
+		PodeRenovar:       true,
+	}
+	if err := u.PodeEmprestar(0, 0, agora); err != nil {
+		s.PodeEmprestar, s.PodeRenovar, s.Motivo = false, false, err.Error()
+	} else if err := b.conferirResponsavelApto(ctx, u, agora); errors.Is(err, ErrRegra) {
+		s.PodeEmprestar, s.PodeRenovar, s.Motivo = false, false, err.Error()
+	} else if err != nil {
+		return nil, err
+	} else if err := u.PodeEmprestar(s.LivrosEmPosse, 1, agora); err != nil {
+		s.PodeEmprestar, s.Motivo = false, err.Error()
+	}
+	return s, nil
+}
+
+// conferirResponsavel verifica se o responsável informado está cadastrado e é
+// maior de idade; a obrigatoriedade para menores é conferida na validação
+func (b *Biblioteca) conferirResponsavel(ctx context.Context, u model.Usuario) error {
+	if u.ResponsavelCPF == "" {
+		return nil
+	}
+	responsavel, err := b.Repos.Usuarios.GetByCPF(ctx, u.ResponsavelCPF)
+	if err != nil {
+		if err := repository.Classificar(err); !isNaoEncontrado(err) {
+			return err
+		}
+		return erroCampo("responsavel_cpf", validacao.CodigoInvalido, "responsável não cadastrado")
+	}
+	if responsavel.MenorDeIdade(time.Now()) {
+		return erroCampo("responsavel_cpf", validacao.CodigoInvalido, "o responsável deve ser maior de idade")
+	}
+	return nil
+}
+
+// conferirResponsavelApto aplica as regras de responsável antes de
+// empréstimos: o menor precisa de um responsável maior e com vínculo ativo
+func (b *Biblioteca) conferirResponsavelApto(ctx context.Context, u *model.Usuario, data time.Time) error {
+	if !u.MenorDeIdade(data) {
+		return nil
+	}
+	var responsavel *model.Usuario
+	if u.ResponsavelCPF != "" {
+		var err error
+		responsavel, err = b.Repos.Usuarios.GetByCPF(ctx, u.ResponsavelCPF)
+		if err != nil {
+			return fmt.Errorf("não foi possível carregar o responsável: %w", repository.Classificar(err))
+		}
+	}
+	if err := u.ValidarResponsavel(responsavel, data); err != nil {
+		return regra("%v", err)
+	}
+	return nil
+}
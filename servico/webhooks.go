@@ -0,0 +1,366 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Os eventos de circulação são enfileirados como entregas no mesmo banco da
+// operação que os gerou, depois que ela é gravada (dentro de EmTransacao, só
+// depois da confirmação); quem os envia aos destinos é o pacote webhooks, no
+// servidor. Falhas ao enfileirar não desfazem a operação: ficam no log
+
+// Evento é o corpo JSON enviado às assinaturas
+type Evento struct {
+	ID     string    `json:"id"` // o mesmo em todas as tentativas e assinaturas, para descartar repetições
+	Evento string    `json:"evento"`
+	Data   time.Time `json:"data"`
+	Dados  any       `json:"dados"`
+}
+
+// DadosEmprestimo são os dados dos eventos de empréstimo, com o prazo e a
+// multa calculados no momento do evento. Os avisos de vencimento e de atraso
+// trazem também o contato de quem responde pelo empréstimo (o responsável,
+// para menores de idade), para que o destino lhe envie o aviso
+type DadosEmprestimo struct {
+	Emprestimo    model.Emprestimo `json:"emprestimo"`
+	Vencimento    time.Time        `json:"vencimento"`
+	DiasAtraso    int              `json:"dias_atraso"`
+	MultaCentavos int              `json:"multa_centavos"`
+	Contato       *Contato         `json:"contato,omitempty"`
+}
+
+// Contato é a quem se dirige o aviso de um empréstimo
+type Contato struct {
+	CPF      string `json:"cpf"`
+	Nome     string `json:"nome"`
+	Email    string `json:"email"`
+	Telefone string `json:"telefone"`
+}
+
+func contato(u model.Usuario) *Contato {
+	return &Contato{u.CPF, strings.TrimSpace(u.PrimeiroNome + " " + u.Sobrenome), u.Email, u.Telefone}
+}
+
+// EntregaPendente é uma entrega a ser tentada, com a assinatura de destino
+type EntregaPendente struct {
+	Entrega model.EntregaWebhook
+	Webhook model.Webhook
+}
+
+// limiteEntregas é o padrão de ListarEntregas
+const limiteEntregas = 50
+
+func normalizarWebhook(w *model.Webhook) {
+	w.URL = strings.TrimSpace(w.URL)
+	for i, e := range w.Eventos {
+		w.Eventos[i] = strings.ToLower(strings.TrimSpace(e))
+	}
+}
+
+// CriarWebhook cadastra a assinatura com o próximo ID. Sem segredo, um
+// aleatório é gerado; o segredo só aparece nesta resposta
+func (b *Biblioteca) CriarWebhook(ctx context.Context, w model.Webhook) (*model.Webhook, error) {
+	normalizarWebhook(&w)
+	if w.Segredo == "" {
+		w.Segredo = aleatorio()
+	}
+	proximo := func(ctx context.Context, b *Biblioteca) (int, error) { return b.Repos.Webhooks.ProximoID(ctx) }
+	err := b.gravarComID(ctx, proximo, func(ctx context.Context, b *Biblioteca, id int) error {
+		w.ID, w.CriadoEm = id, time.Now()
+		if err := validacao.ValidarWebhook(w); err != nil {
+			return err
+		}
+		return repository.Classificar(b.Repos.Webhooks.Create(ctx, w))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ObterWebhook retorna a assinatura sem o segredo
+func (b *Biblioteca) ObterWebhook(ctx context.Context, id int) (*model.Webhook, error) {
+	w, err := b.Repos.Webhooks.GetByID(ctx, id)
+	if err != nil {
+		return nil, naoEncontrado(err, "webhook %d", id)
+	}
+	w.Segredo = ""
+	return w, nil
+}
+
+// AtualizarWebhook substitui a assinatura; sem segredo, o atual é mantido
+func (b *Biblioteca) AtualizarWebhook(ctx context.Context, w model.Webhook) (*model.Webhook, error) {
+	atual, err := b.Repos.Webhooks.GetByID(ctx, w.ID)
+	if err != nil {
+		return nil, naoEncontrado(err, "webhook %d", w.ID)
+	}
+	normalizarWebhook(&w)
+	if w.Segredo == "" {
+		w.Segredo = atual.Segredo
+	}
+	w.CriadoEm = atual.CriadoEm
+	if err := validacao.ValidarWebhook(w); err != nil {
+		return nil, err
+	}
+	if err := b.Repos.Webhooks.Update(ctx, w); err != nil {
+		return nil, repository.Classificar(err)
+	}
+	w.Segredo = ""
+	return &w, nil
+}
+
+func (b *Biblioteca) DeletarWebhook(ctx context.Context, id int) error {
+	if _, err := b.ObterWebhook(ctx, id); err != nil {
+		return err
+	}
+	return repository.Classificar(b.Repos.Webhooks.Delete(ctx, id))
+}
+
+// ListarWebhooks retorna as assinaturas sem os segredos
+func (b *Biblioteca) ListarWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	webhooks, err := b.Repos.Webhooks.List(ctx)
+	for i := range webhooks {
+		webhooks[i].Segredo = ""
+	}
+	return webhooks, err
+}
+
+// ListarEntregas retorna o registro de entregas da assinatura, das mais
+// recentes; limite 0 usa o padrão de 50
+func (b *Biblioteca) ListarEntregas(ctx context.Context, id, limite int) ([]model.EntregaWebhook, error) {
+	if _, err := b.ObterWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	if limite <= 0 {
+		limite = limiteEntregas
+	}
+	return b.Repos.Webhooks.ListEntregas(ctx, id, limite)
+}
+
+// TestarWebhook enfileira um evento de teste para a assinatura, mesmo que
+// ela esteja desativada ou não assine nenhum evento de circulação
+func (b *Biblioteca) TestarWebhook(ctx context.Context, id int) (*model.EntregaWebhook, error) {
+	w, err := b.Repos.Webhooks.GetByID(ctx, id)
+	if err != nil {
+		return nil, naoEncontrado(err, "webhook %d", id)
+	}
+	evento := Evento{ID: aleatorio(), Evento: model.EventoTeste, Data: time.Now(),
+		Dados: map[string]string{"mensagem": "evento de teste da biblioteca"}}
+	return b.enfileirar(ctx, *w, evento)
+}
+
+// EntregasPendentes retorna as entregas cuja próxima tentativa já chegou.
+// As de assinaturas desativadas são encerradas sem envio
+func (b *Biblioteca) EntregasPendentes(ctx context.Context, limite int) ([]EntregaPendente, error) {
+	entregas, err := b.Repos.Webhooks.EntregasPendentes(ctx, time.Now(), limite)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := map[int]*model.Webhook{}
+	var pendentes []EntregaPendente
+	for _, e := range entregas {
+		w, ok := webhooks[e.WebhookID]
+		if !ok {
+			if w, err = b.Repos.Webhooks.GetByID(ctx, e.WebhookID); err != nil {
+				return nil, naoEncontrado(err, "webhook %d", e.WebhookID)
+			}
+			webhooks[e.WebhookID] = w
+		}
+		if !w.Ativo && e.Evento != model.EventoTeste {
+			e.Situacao, e.UltimoErro = model.EntregaDesistida, "assinatura desativada"
+			if err := b.RegistrarTentativa(ctx, e); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		pendentes = append(pendentes, EntregaPendente{e, *w})
+	}
+	return pendentes, nil
+}
+
+// RegistrarTentativa grava o resultado de uma tentativa de entrega
+func (b *Biblioteca) RegistrarTentativa(ctx context.Context, e model.EntregaWebhook) error {
+	return repository.Classificar(b.Repos.Webhooks.AtualizarEntrega(ctx, e))
+}
+
+// NotificarPrazos enfileira os avisos dos empréstimos ativos: o de vencimento
+// próximo, a partir de model.DiasAvisoVencimento dias antes do vencimento, e
+// o de atraso, depois dele. Cada empréstimo gera cada aviso uma única vez por
+// assinatura e por vencimento; chamadas repetidas não o reenviam
+func (b *Biblioteca) NotificarPrazos(ctx context.Context) (int, error) {
+	vencendo, err := b.assinantes(ctx, model.EventoEmprestimoVencendo)
+	if err != nil {
+		return 0, err
+	}
+	atrasados, err := b.assinantes(ctx, model.EventoEmprestimoAtrasado)
+	if err != nil || len(vencendo)+len(atrasados) == 0 {
+		return 0, err
+	}
+	ativos, err := b.Repos.Emprestimos.List(ctx, repository.FiltroEmprestimo{Status: StatusAtivo})
+	if err != nil {
+		return 0, err
+	}
+	var cpfs []string
+	for _, e := range ativos {
+		cpfs = append(cpfs, e.ClienteUsuarioCPF)
+	}
+	lista, err := b.Repos.Usuarios.List(ctx, repository.FiltroUsuario{CPFs: cpfs})
+	if err != nil {
+		return 0, err
+	}
+	agora := time.Now()
+	usuarios := map[string]model.Usuario{}
+	var responsaveis []string
+	for _, u := range lista {
+		usuarios[u.CPF] = u
+		if r := u.CPFResponsavel(agora); r != u.CPF {
+			responsaveis = append(responsaveis, r)
+		}
+	}
+	if len(responsaveis) > 0 {
+		lista, err := b.Repos.Usuarios.List(ctx, repository.FiltroUsuario{CPFs: responsaveis})
+		if err != nil {
+			return 0, err
+		}
+		for _, u := range lista {
+			usuarios[u.CPF] = u
+		}
+	}
+
+	enfileirados := 0
+	for _, e := range ativos {
+		u, ok := usuarios[e.ClienteUsuarioCPF]
+		if !ok {
+			continue
+		}
+		destinatario, ok := usuarios[u.CPFResponsavel(agora)]
+		if !ok {
+			destinatario = u
+		}
+		prazo := u.PrazoDias()
+		vencimento := e.Vencimento(prazo)
+		evento := Evento{Data: agora, Dados: DadosEmprestimo{e, vencimento, e.DiasAtraso(prazo, agora), e.Multa(prazo, agora), contato(destinatario)}}
+		var webhooks []model.Webhook
+		switch {
+		case e.DiasAtraso(prazo, agora) > 0:
+			evento.ID, evento.Evento = fmt.Sprintf("atraso-%d-%s", e.ID, vencimento.Format("20060102")), model.EventoEmprestimoAtrasado
+			webhooks = atrasados
+		case e.DiasParaVencer(prazo, agora) <= model.DiasAvisoVencimento:
+			evento.ID, evento.Evento = fmt.Sprintf("vencimento-%d-%s", e.ID, vencimento.Format("20060102")), model.EventoEmprestimoVencendo
+			webhooks = vencendo
+		}
+		for _, w := range webhooks {
+			_, err := b.enfileirar(ctx, w, evento)
+			if errors.Is(err, repository.ErrDuplicado) {
+				continue
+			}
+			if err != nil {
+				return enfileirados, err
+			}
+			enfileirados++
+		}
+	}
+	return enfileirados, nil
+}
+
+// notificacao é um evento guardado até a confirmação da transação
+type notificacao struct {
+	evento string
+	dados  func(ctx context.Context, b *Biblioteca) (any, error)
+}
+
+// notificar enfileira o evento para as assinaturas que o recebem. dados só
+// é chamada se houver alguma, para não consultar o banco à toa, e recebe a
+// Biblioteca de quem enfileira: dentro de uma transação, o evento espera a
+// confirmação e os dados são lidos fora dela
+func (b *Biblioteca) notificar(ctx context.Context, evento string, dados func(ctx context.Context, b *Biblioteca) (any, error)) {
+	if b.notificacoes != nil {
+		*b.notificacoes = append(*b.notificacoes, notificacao{evento, dados})
+		return
+	}
+	if b.Repos.Webhooks == nil {
+		return
+	}
+	err := func() error {
+		webhooks, err := b.assinantes(ctx, evento)
+		if err != nil || len(webhooks) == 0 {
+			return err
+		}
+		d, err := dados(ctx, b)
+		if err != nil {
+			return err
+		}
+		e := Evento{ID: aleatorio(), Evento: evento, Data: time.Now(), Dados: d}
+		for _, w := range webhooks {
+			if _, err := b.enfileirar(ctx, w, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		log.Printf("AVISO: não foi possível enfileirar o evento %s para os webhooks: %v\n", evento, err)
+	}
+}
+
+// dadosEmprestimo monta os dados do evento com o prazo da categoria do usuário
+func dadosEmprestimo(e model.Emprestimo) func(ctx context.Context, b *Biblioteca) (any, error) {
+	return func(ctx context.Context, b *Biblioteca) (any, error) {
+		u, err := b.Repos.Usuarios.GetByCPF(ctx, e.ClienteUsuarioCPF)
+		if err != nil {
+			return nil, repository.Classificar(err)
+		}
+		prazo, agora := u.PrazoDias(), time.Now()
+		return DadosEmprestimo{e, e.Vencimento(prazo), e.DiasAtraso(prazo, agora), e.Multa(prazo, agora), nil}, nil
+	}
+}
+
+func (b *Biblioteca) assinantes(ctx context.Context, evento string) ([]model.Webhook, error) {
+	todos, err := b.Repos.Webhooks.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []model.Webhook
+	for _, w := range todos {
+		if w.Recebe(evento) {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (b *Biblioteca) enfileirar(ctx context.Context, w model.Webhook, evento Evento) (*model.EntregaWebhook, error) {
+	corpo, err := json.Marshal(evento)
+	if err != nil {
+		return nil, err
+	}
+	agora := time.Now()
+	e := model.EntregaWebhook{
+		ID: fmt.Sprintf("%d-%s", w.ID, evento.ID), WebhookID: w.ID, EventoID: evento.ID, Evento: evento.Evento,
+		Corpo: string(corpo), Situacao: model.EntregaPendente, ProximaTentativa: agora, CriadaEm: agora,
+	}
+	if err := b.Repos.Webhooks.CriarEntrega(ctx, e); err != nil {
+		return nil, repository.Classificar(err)
+	}
+	return &e, nil
+}
+
+// aleatorio gera os segredos e os IDs dos eventos
+func aleatorio() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
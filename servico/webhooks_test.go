@@ -0,0 +1,107 @@
+package servico
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNotificarPrazos(t *testing.T) {
+	const cpf = "52998224725"
+	aluno := model.Usuario{CPF: cpf, PrimeiroNome: "Ana", Sobrenome: "Souza", Email: "ana@exemplo.com",
+		Categoria: model.CategoriaGraduacao} // prazo de 15 dias
+	emprestado := func(id, diasAtras int) model.Emprestimo {
+		return model.Emprestimo{ID: id, ClienteUsuarioCPF: cpf, QuantLivros: 1, Status: StatusAtivo,
+			DataEmprestimo: time.Now().AddDate(0, 0, -diasAtras)}
+	}
+	b := bibliotecaMemoria([]model.Usuario{aluno}, []model.Emprestimo{
+		emprestado(1, 14), // vence amanhã
+		emprestado(2, 5),  // vence em 10 dias
+		emprestado(3, 20), // venceu há 5 dias
+	})
+	webhooks := &webhooksMemoria{webhooks: []model.Webhook{
+		{ID: 1, Ativo: true, Eventos: []string{model.EventoEmprestimoVencendo, model.EventoEmprestimoAtrasado}},
+	}}
+	b.Repos.Webhooks = webhooks
+
+	n, err := b.NotificarPrazos(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("%d avisos enfileirados, esperados 2", n)
+	}
+	esperados := map[int]string{1: model.EventoEmprestimoVencendo, 3: model.EventoEmprestimoAtrasado}
+	for _, e := range webhooks.entregas {
+		var evento struct {
+			Dados DadosEmprestimo `json:"dados"`
+		}
+		if err := json.Unmarshal([]byte(e.Corpo), &evento); err != nil {
+			t.Fatal(err)
+		}
+		id := evento.Dados.Emprestimo.ID
+		if e.Evento != esperados[id] {
+			t.Errorf("empréstimo %d: evento %q, esperado %q", id, e.Evento, esperados[id])
+		}
+		if c := evento.Dados.Contato; c == nil || c.Email != aluno.Email || c.Nome != "Ana Souza" {
+			t.Errorf("empréstimo %d: contato %+v", id, c)
+		}
+	}
+
+	if n, err := b.NotificarPrazos(context.Background()); err != nil || n != 0 {
+		t.Errorf("a segunda chamada enfileirou %d avisos (erro %v), esperado nenhum", n, err)
+	}
+}
+
+func TestNotificarEsperaATransacao(t *testing.T) {
+	const cpf = "52998224725"
+	aluno := model.Usuario{CPF: cpf, PrimeiroNome: "Ana", Categoria: model.CategoriaGraduacao}
+	errDesfeita := errors.New("desfeita")
+
+	casos := []struct {
+		nome     string
+		desfazer bool
+		entregas int
+	}{
+		{"confirmada", false, 1},
+		{"desfeita", true, 0},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			b := bibliotecaMemoria([]model.Usuario{aluno}, nil)
+			webhooks := &webhooksMemoria{webhooks: []model.Webhook{{ID: 1, Ativo: true, Eventos: []string{model.EventoEmprestimoCriado}}}}
+			b.Repos.Webhooks = webhooks
+			// a "transação" usa os mesmos repositórios, sem desfazer nada, e
+			// só confere o que foi enfileirado antes de terminar
+			b.Repos.Transacao = func(ctx context.Context, f func(ctx context.Context, repos repository.Repositorios) error) error {
+				repos := b.Repos
+				repos.Transacao = nil
+				if err := f(ctx, repos); err != nil {
+					return err
+				}
+				if len(webhooks.entregas) > 0 {
+					t.Error("evento enfileirado antes da confirmação")
+				}
+				if c.desfazer {
+					return errDesfeita
+				}
+				return nil
+			}
+
+			err := b.EmTransacao(context.Background(), func(ctx context.Context, b *Biblioteca) error {
+				_, err := b.CriarEmprestimo(ctx, model.Emprestimo{ID: 1, ClienteUsuarioCPF: cpf, Livros: isbnsMemoria[:1]})
+				return err
+			})
+			if c.desfazer != errors.Is(err, errDesfeita) {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if len(webhooks.entregas) != c.entregas {
+				t.Errorf("%d entregas, esperadas %d", len(webhooks.entregas), c.entregas)
+			}
+		})
+	}
+}
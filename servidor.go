@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"crud-biblioteca/aovivo"
+	"crud-biblioteca/api"
+	"crud-biblioteca/graphqlapi"
+	"crud-biblioteca/grpcapi"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/sru"
+	"crud-biblioteca/web"
+	"crud-biblioteca/webhooks"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// servir atende a API REST e/ou o serviço gRPC nos endereços informados
+// (vazio desativa) até receber Ctrl+C ou um dos servidores falhar
+func servir(ctx context.Context, repos repository.Repositorios, enderecoHTTP, enderecoGRPC string) error {
+	b := servico.New(repos)
+	cfg, err := webhooks.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	contas, err := web.ContasFromEnv()
+	if err != nil {
+		return fmt.Errorf("contas da equipe: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	// as entregas dos webhooks saem do servidor, enquanto ele estiver no ar;
+	// eventos gerados pelas demais interfaces esperam na fila
+	entregador := make(chan struct{})
+	go func() {
+		defer close(entregador)
+		webhooks.New(b, cfg).Executar(ctx)
+	}()
+	defer func() { <-entregador }()
+
+	// o acompanhamento ao vivo encerra as conexões abertas quando o contexto
+	// é cancelado, o que libera o desligamento do servidor HTTP
+	feed := aovivo.New(b, repos.Avisos)
+	go feed.Executar(ctx)
+
+	erros := make(chan error, 2)
+	servidores := 0
+	if enderecoHTTP != "" {
+		servidores++
+		go func() { erros <- servirHTTP(ctx, enderecoHTTP, b, feed, contas) }()
+	}
+	if enderecoGRPC != "" {
+		servidores++
+		go func() { erros <- servirGRPC(ctx, enderecoGRPC, b, contas) }()
+	}
+
+	var primeiro error
+	for i := 0; i < servidores; i++ {
+		if err := <-erros; err != nil && primeiro == nil {
+			// a falha de um servidor encerra os demais
+			primeiro = err
+			stop()
+		}
+	}
+	stop()
+	log.Println("Servidor encerrado.")
+	return primeiro
+}
+
+func servirHTTP(ctx context.Context, endereco string, b *servico.Biblioteca, feed *aovivo.Feed, contas web.Contas) error {
+	mux := http.NewServeMux()
+	mux.Handle(sru.Caminho, sru.New(b))
+	// a API, o GraphQL e a circulação ao vivo alteram e mostram dados de
+	// todos os usuários: só são atendidos com as contas da equipe
+	if contas != nil {
+		mux.Handle(api.Prefixo+"/", contas.ExigirConta(api.New(b)))
+		mux.Handle(graphqlapi.Caminho, contas.ExigirConta(graphqlapi.New(b)))
+		mux.Handle(aovivo.Caminho+"/", contas.ExigirConta(feed))
+		mux.Handle(web.Prefixo+"/", web.New(b, contas))
+		mux.Handle("GET /{$}", http.RedirectHandler(web.Prefixo+"/", http.StatusFound))
+		log.Printf("API disponível em http://%s%s e GraphQL em http://%s%s\n", endereco, api.Prefixo, endereco, graphqlapi.Caminho)
+		log.Printf("Circulação ao vivo em http://%s%s/\n", endereco, aovivo.Caminho)
+		log.Printf("Interface web da equipe em http://%s%s/\n", endereco, web.Prefixo)
+	} else {
+		log.Println("AVISO: API, GraphQL, circulação ao vivo e interface web desativados; informe o arquivo de senhas da equipe em WEB_SENHAS.")
+	}
+	// o portal só aceita usuários cuja senha já foi definida pela equipe
+	mux.Handle(web.PrefixoPortal+"/", web.NovoPortal(b))
+	log.Printf("Portal do usuário em http://%s%s/\n", endereco, web.PrefixoPortal)
+	log.Printf("Catálogo SRU em http://%s%s\n", endereco, sru.Caminho)
+	srv := &http.Server{
+		Addr:              endereco,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		// aguarda as requisições em andamento antes de fechar o banco
+		desligar, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(desligar)
+	}()
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// servirGRPC atende o serviço gRPC, que mostra os dados de todos os usuários:
+// com as contas da equipe, toda chamada exige login; sem elas, só endereços
+// da própria máquina são aceitos
+func servirGRPC(ctx context.Context, endereco string, b *servico.Biblioteca, contas web.Contas) error {
+	var conferir grpcapi.Conferidor
+	if contas != nil {
+		conferir = contas.Conferidor()
+	} else if !loopback(endereco) {
+		return errors.New("gRPC: sem WEB_SENHAS o serviço não pede login; use um endereço local (ex.: 127.0.0.1:9090) ou informe as contas da equipe")
+	}
+	lis, err := net.Listen("tcp", endereco)
+	if err != nil {
+		return err
+	}
+	srv := grpcapi.NovoServidorGRPC(b, conferir)
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	log.Printf("Serviço gRPC disponível em %s\n", lis.Addr())
+	return srv.Serve(lis)
+}
+
+// loopback indica se o endereço só é acessível pela própria máquina; sem
+// host, o servidor escuta em todas as interfaces
+func loopback(endereco string) bool {
+	host, _, err := net.SplitHostPort(endereco)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,379 @@
+package sru
+
+import (
+	"crud-biblioteca/repository"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A consulta CQL (Contextual Query Language, versão 1.2) é lida por um
+// analisador descendente e traduzida em uma repository.CondicaoLivro:
+//
+//	consulta   = clausula { booleano clausula }    (associatividade à esquerda)
+//	clausula   = "(" consulta ")" | [ indice relacao ] termo
+//
+// Relações aceitas: "=" e "adj" (o termo aparece como frase), "==" e "exact"
+// (o campo é igual ao termo), "all" e "any" (todas ou alguma das palavras) e
+// "<>". O asterisco no fim do termo busca pelo prefixo. Modificadores,
+// proximidade, ordenação e atribuição de prefixos não são suportados e
+// resultam em diagnóstico
+
+// limites da consulta: a árvore de condições é percorrida recursivamente pelo
+// analisador e pelos bancos, então o tamanho, o aninhamento de parênteses e o
+// número de cláusulas (cada palavra de any e all conta como uma) são contidos
+const (
+	tamanhoMaximoConsulta = 4096
+	profundidadeMaxima    = 32
+	clausulasMaximas      = 64
+)
+
+// indice é um índice CQL que o servidor sabe buscar
+type indice struct {
+	titulo string // descrição apresentada no explain
+	campo  repository.CampoLivro
+	nomes  []nomeIndice
+}
+
+type nomeIndice struct {
+	conjunto, nome string
+}
+
+// conjuntoLocal agrupa os índices próprios do servidor
+const conjuntoLocal = "biblioteca"
+
+// conjuntos de contexto dos índices, pelo prefixo usado nas consultas
+var conjuntos = []struct{ prefixo, identificador string }{
+	{"cql", "info:srw/cql-context-set/1/cql-v1.2"},
+	{"dc", "info:srw/cql-context-set/1/dc-v1.1"},
+	{"bath", "http://zing.z3950.org/cql/bath/2.0/"},
+	{conjuntoLocal, "info:srw/cql-context-set/16/biblioteca-v1.0"},
+}
+
+var indices = []indice{
+	{"Qualquer campo (título ou autor)", repository.CampoQualquer, []nomeIndice{{"cql", "serverChoice"}, {"cql", "anywhere"}}},
+	{"Todos os registros", repository.CampoTodos, []nomeIndice{{"cql", "allRecords"}}},
+	{"Título", repository.CampoTitulo, []nomeIndice{{"dc", "title"}, {"bath", "title"}}},
+	{"Autor", repository.CampoAutor, []nomeIndice{{"dc", "creator"}, {"bath", "author"}, {"bath", "name"}}},
+	{"Assunto", repository.CampoAssunto, []nomeIndice{{"dc", "subject"}, {"bath", "subject"}}},
+	{"Editora", repository.CampoEditora, []nomeIndice{{"dc", "publisher"}, {"bath", "publisher"}}},
+	{"Idioma", repository.CampoIdioma, []nomeIndice{{"dc", "language"}}},
+	{"ISBN", repository.CampoISBN, []nomeIndice{{"bath", "isbn"}, {"dc", "identifier"}}},
+	{"Número de classificação (CDD ou CDU)", repository.CampoClassificacao, []nomeIndice{{conjuntoLocal, "classificacao"}}},
+}
+
+// campoIndice localiza o índice pelo nome, com ou sem prefixo. Sem prefixo
+// vale o primeiro índice com esse nome
+func campoIndice(nome string) (repository.CampoLivro, bool) {
+	conjunto, nome, prefixado := strings.Cut(nome, ".")
+	if !prefixado {
+		conjunto, nome = "", conjunto
+	}
+	for _, i := range indices {
+		for _, n := range i.nomes {
+			if strings.EqualFold(n.nome, nome) && (!prefixado || strings.EqualFold(n.conjunto, conjunto)) {
+				return i.campo, true
+			}
+		}
+	}
+	return "", false
+}
+
+// tipos de símbolo
+const (
+	simboloFim = iota
+	simboloPalavra
+	simboloTexto // termo entre aspas
+	simboloAbre
+	simboloFecha
+	simboloBarra
+	simboloComparacao // =, ==, <>, <, >, <=, >=
+)
+
+type simbolo struct {
+	tipo  int
+	valor string
+	// termo com o asterisco final sem escape, que pede busca pelo prefixo
+	prefixo bool
+}
+
+// booleanos e relações nomeadas da CQL
+var (
+	booleanos = []string{"and", "or", "not", "prox"}
+	relacoes  = []string{"adj", "all", "any", "exact", "within", "encloses"}
+)
+
+func contem(lista []string, s string) bool {
+	for _, l := range lista {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// lerSimbolos separa a consulta em símbolos, resolvendo aspas e escapes
+func lerSimbolos(consulta string) ([]simbolo, error) {
+	var simbolos []simbolo
+	r := []rune(consulta)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			simbolos = append(simbolos, simbolo{tipo: simboloAbre, valor: "("})
+			i++
+		case c == ')':
+			simbolos = append(simbolos, simbolo{tipo: simboloFecha, valor: ")"})
+			i++
+		case c == '/':
+			simbolos = append(simbolos, simbolo{tipo: simboloBarra, valor: "/"})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			j := i + 1
+			if j < len(r) && (r[j] == '=' || (c == '<' && r[j] == '>')) {
+				j++
+			}
+			simbolos = append(simbolos, simbolo{tipo: simboloComparacao, valor: string(r[i:j])})
+			i = j
+		default:
+			s := simbolo{tipo: simboloPalavra}
+			if c == '"' {
+				s.tipo = simboloTexto
+				i++
+			}
+			var termo strings.Builder
+			fechado := false
+			for ; i < len(r); i++ {
+				c := r[i]
+				if s.tipo == simboloTexto && c == '"' {
+					fechado = true
+					i++
+					break
+				}
+				if s.tipo == simboloPalavra && (unicode.IsSpace(c) || strings.ContainsRune(`()/=<>"`, c)) {
+					break
+				}
+				if c == '\\' && i+1 < len(r) {
+					// o escape só preserva o caractere seguinte como texto
+					i++
+					termo.WriteRune(r[i])
+					continue
+				}
+				if c == '*' || c == '?' || c == '^' {
+					if c == '*' && (i+1 == len(r) || (s.tipo == simboloTexto && r[i+1] == '"') ||
+						(s.tipo == simboloPalavra && (unicode.IsSpace(r[i+1]) || strings.ContainsRune(`()/=<>`, r[i+1])))) {
+						s.prefixo = true
+						continue
+					}
+					return nil, diagnostico(diagMascara, string(c))
+				}
+				termo.WriteRune(c)
+			}
+			if s.tipo == simboloTexto && !fechado {
+				return nil, diagnostico(diagSintaxe, "aspas sem fechamento")
+			}
+			s.valor = termo.String()
+			simbolos = append(simbolos, s)
+		}
+	}
+	return append(simbolos, simbolo{tipo: simboloFim}), nil
+}
+
+type analisador struct {
+	simbolos     []simbolo
+	pos          int
+	profundidade int // parênteses abertos na posição atual
+	clausulas    int // condições simples já traduzidas
+}
+
+func (a *analisador) atual() simbolo    { return a.simbolos[a.pos] }
+func (a *analisador) seguinte() simbolo { return a.simbolos[min(a.pos+1, len(a.simbolos)-1)] }
+
+// palavra informa se o símbolo atual é a palavra (sem aspas) informada
+func (a *analisador) palavra(lista []string) bool {
+	s := a.atual()
+	return s.tipo == simboloPalavra && !s.prefixo && contem(lista, s.valor)
+}
+
+// traduzirCQL analisa a consulta e a traduz na condição equivalente
+func traduzirCQL(consulta string) (repository.CondicaoLivro, error) {
+	if strings.TrimSpace(consulta) == "" {
+		return repository.CondicaoLivro{}, diagnostico(diagObrigatorio, "query")
+	}
+	if len(consulta) > tamanhoMaximoConsulta {
+		return repository.CondicaoLivro{}, diagnostico(diagSintaxe, fmt.Sprintf("consulta com mais de %d bytes", tamanhoMaximoConsulta))
+	}
+	simbolos, err := lerSimbolos(consulta)
+	if err != nil {
+		return repository.CondicaoLivro{}, err
+	}
+	if simbolos[0].tipo == simboloComparacao && simbolos[0].valor == ">" {
+		return repository.CondicaoLivro{}, diagnostico(diagSintaxe, "atribuição de prefixos não suportada")
+	}
+	a := &analisador{simbolos: simbolos}
+	c, err := a.consulta()
+	if err != nil {
+		return repository.CondicaoLivro{}, err
+	}
+	if s := a.atual(); s.tipo != simboloFim {
+		if s.tipo == simboloPalavra && strings.EqualFold(s.valor, "sortBy") {
+			return repository.CondicaoLivro{}, diagnostico(diagOrdenacao, "")
+		}
+		return repository.CondicaoLivro{}, diagnostico(diagSintaxe, "símbolo inesperado: "+s.valor)
+	}
+	return c, nil
+}
+
+func (a *analisador) consulta() (repository.CondicaoLivro, error) {
+	esquerda, err := a.clausula()
+	if err != nil {
+		return esquerda, err
+	}
+	for a.palavra(booleanos) {
+		booleano := strings.ToLower(a.atual().valor)
+		a.pos++
+		if a.atual().tipo == simboloBarra {
+			return esquerda, diagnostico(diagModificadorBooleano, booleano)
+		}
+		var operador repository.OperadorBusca
+		switch booleano {
+		case "and":
+			operador = repository.OperadorE
+		case "or":
+			operador = repository.OperadorOu
+		case "not":
+			operador = repository.OperadorENao
+		default:
+			return esquerda, diagnostico(diagBooleano, booleano)
+		}
+		direita, err := a.clausula()
+		if err != nil {
+			return esquerda, err
+		}
+		e := esquerda
+		esquerda = repository.CondicaoLivro{Operador: operador, Esquerda: &e, Direita: &direita}
+	}
+	return esquerda, nil
+}
+
+func (a *analisador) clausula() (repository.CondicaoLivro, error) {
+	s := a.atual()
+	switch s.tipo {
+	case simboloAbre:
+		if a.profundidade++; a.profundidade > profundidadeMaxima {
+			return repository.CondicaoLivro{}, diagnostico(diagSintaxe, fmt.Sprintf("mais de %d parênteses aninhados", profundidadeMaxima))
+		}
+		a.pos++
+		c, err := a.consulta()
+		if err != nil {
+			return c, err
+		}
+		if a.atual().tipo != simboloFecha {
+			return c, diagnostico(diagSintaxe, "parêntese sem fechamento")
+		}
+		a.pos++
+		a.profundidade--
+		return c, nil
+	case simboloPalavra, simboloTexto:
+	default:
+		return repository.CondicaoLivro{}, diagnostico(diagSintaxe, "termo esperado")
+	}
+
+	// um índice é uma palavra seguida de uma relação e de outro termo; do
+	// contrário, a palavra já é o termo e a busca vale para cql.serverChoice
+	proximo := a.seguinte()
+	relacaoNomeada := proximo.tipo == simboloPalavra && !proximo.prefixo && contem(relacoes, proximo.valor) &&
+		a.simbolos[min(a.pos+2, len(a.simbolos)-1)].tipo != simboloFim
+	if s.tipo == simboloTexto || s.prefixo || (proximo.tipo != simboloComparacao && !relacaoNomeada) {
+		a.pos++
+		return a.termo(repository.CampoQualquer, "=", s)
+	}
+
+	campo, ok := campoIndice(s.valor)
+	if !ok {
+		return repository.CondicaoLivro{}, diagnostico(diagIndice, s.valor)
+	}
+	relacao := strings.ToLower(proximo.valor)
+	a.pos += 2
+	if a.atual().tipo == simboloBarra {
+		return repository.CondicaoLivro{}, diagnostico(diagModificadorRelacao, relacao)
+	}
+	termo := a.atual()
+	if termo.tipo != simboloPalavra && termo.tipo != simboloTexto {
+		return repository.CondicaoLivro{}, diagnostico(diagSintaxe, "termo esperado depois de "+relacao)
+	}
+	a.pos++
+	return a.termo(campo, relacao, termo)
+}
+
+// termo traduz o termo e o conta no limite de cláusulas da consulta
+func (a *analisador) termo(campo repository.CampoLivro, relacao string, s simbolo) (repository.CondicaoLivro, error) {
+	c, err := traduzirTermo(campo, relacao, s)
+	if err != nil {
+		return c, err
+	}
+	if a.clausulas += folhas(c); a.clausulas > clausulasMaximas {
+		return c, diagnostico(diagBooleanosDemais, fmt.Sprintf("mais de %d cláusulas", clausulasMaximas))
+	}
+	return c, nil
+}
+
+// folhas conta as condições simples de c
+func folhas(c repository.CondicaoLivro) int {
+	if c.Operador == "" {
+		return 1
+	}
+	return folhas(*c.Esquerda) + folhas(*c.Direita)
+}
+
+// traduzirTermo monta a condição de um índice, relação e termo
+func traduzirTermo(campo repository.CampoLivro, relacao string, termo simbolo) (repository.CondicaoLivro, error) {
+	if campo == repository.CampoTodos {
+		return repository.CondicaoLivro{Campo: campo}, nil
+	}
+	valor := strings.TrimSpace(termo.valor)
+	if valor == "" {
+		return repository.CondicaoLivro{}, diagnostico(diagTermoVazio, "")
+	}
+	if campo == repository.CampoIdioma {
+		valor = idiomaISO6391(valor)
+	}
+	if campo == repository.CampoISBN && termo.prefixo {
+		// o ISBN é gravado só com os dígitos
+		prefixo := strings.NewReplacer("-", "", " ", "").Replace(valor)
+		return repository.CondicaoLivro{Campo: campo, Relacao: repository.RelacaoPrefixo, Valor: prefixo}, nil
+	}
+
+	simples := func(r repository.RelacaoBusca, v string) repository.CondicaoLivro {
+		return repository.CondicaoLivro{Campo: campo, Relacao: r, Valor: v}
+	}
+	switch relacao {
+	case "=", "adj":
+		// a busca por trecho já encontra as palavras que começam pelo prefixo
+		return simples(repository.RelacaoContem, valor), nil
+	case "==", "exact":
+		if termo.prefixo {
+			return simples(repository.RelacaoPrefixo, valor), nil
+		}
+		return simples(repository.RelacaoIgual, valor), nil
+	case "<>":
+		todos := repository.CondicaoLivro{Campo: repository.CampoTodos}
+		igual := simples(repository.RelacaoIgual, valor)
+		return repository.CondicaoLivro{Operador: repository.OperadorENao, Esquerda: &todos, Direita: &igual}, nil
+	case "all", "any":
+		operador := repository.OperadorE
+		if relacao == "any" {
+			operador = repository.OperadorOu
+		}
+		palavras := strings.Fields(valor)
+		c := simples(repository.RelacaoContem, palavras[0])
+		for _, p := range palavras[1:] {
+			esquerda, direita := c, simples(repository.RelacaoContem, p)
+			c = repository.CondicaoLivro{Operador: operador, Esquerda: &esquerda, Direita: &direita}
+		}
+		return c, nil
+	}
+	return repository.CondicaoLivro{}, diagnostico(diagRelacao, relacao)
+}
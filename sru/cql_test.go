@@ -0,0 +1,125 @@
+package sru
+
+import (
+	"crud-biblioteca/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// descrever mostra a condição de forma compacta, para comparar nas tabelas
+func descrever(c repository.CondicaoLivro) string {
+	if c.Operador != "" {
+		return fmt.Sprintf("(%s %s %s)", descrever(*c.Esquerda), c.Operador, descrever(*c.Direita))
+	}
+	if c.Campo == repository.CampoTodos {
+		return string(c.Campo)
+	}
+	return fmt.Sprintf("%s %s %q", c.Campo, c.Relacao, c.Valor)
+}
+
+// encadear repete o termo n vezes ligado pelo booleano
+func encadear(termo, booleano string, n int) string {
+	return strings.Repeat(termo+" "+booleano+" ", n-1) + termo
+}
+
+func TestTraduzirCQL(t *testing.T) {
+	casos := []struct {
+		nome     string
+		consulta string
+		esperado string
+	}{
+		{"termo sem índice", "dom", `qualquer contem "dom"`},
+		{"frase entre aspas", `"dom casmurro"`, `qualquer contem "dom casmurro"`},
+		{"índice com prefixo", `dc.title="dom casmurro"`, `titulo contem "dom casmurro"`},
+		{"índice sem prefixo", "creator=assis", `autor contem "assis"`},
+		{"índice em maiúsculas", "DC.Title = Dom", `titulo contem "Dom"`},
+		{"adj", `dc.title adj "dom casmurro"`, `titulo contem "dom casmurro"`},
+		{"exact", `dc.title exact "dom casmurro"`, `titulo igual "dom casmurro"`},
+		{"igual duplo", "dc.title==dom", `titulo igual "dom"`},
+		{"diferente", "dc.title<>dom", `(todos e-nao titulo igual "dom")`},
+		{"all", `dc.title all "dom casmurro"`, `(titulo contem "dom" e titulo contem "casmurro")`},
+		{"any", `dc.creator any "assis alencar rosa"`, `((autor contem "assis" ou autor contem "alencar") ou autor contem "rosa")`},
+		{"prefixo com exact", "dc.title==dom*", `titulo prefixo "dom"`},
+		{"prefixo com igual", "dc.title=dom*", `titulo contem "dom"`},
+		{"prefixo sem índice", "casm*", `qualquer contem "casm"`},
+		{"prefixo entre aspas", `dc.title=="dom c*"`, `titulo prefixo "dom c"`},
+		{"prefixo do ISBN", "bath.isbn=978-85*", `isbn prefixo "97885"`},
+		{"asterisco escapado", `dc.title=dom\*`, `titulo contem "dom*"`},
+		{"todos os registros", `cql.allRecords=1`, "todos"},
+		{"idioma MARC", "dc.language=por", `idioma contem "pt"`},
+		{"and antes de or, pela esquerda", "a and b or c", `((qualquer contem "a" e qualquer contem "b") ou qualquer contem "c")`},
+		{"or antes de and, pela esquerda", "a or b and c", `((qualquer contem "a" ou qualquer contem "b") e qualquer contem "c")`},
+		{"parênteses", "a and (b or c)", `(qualquer contem "a" e (qualquer contem "b" ou qualquer contem "c"))`},
+		{"not", "dc.title=dom not dc.creator=assis", `(titulo contem "dom" e-nao autor contem "assis")`},
+		{"booleano em maiúsculas", "a AND b", `(qualquer contem "a" e qualquer contem "b")`},
+		{"relação como termo", "any", `qualquer contem "any"`},
+		{"booleano entre aspas", `dc.title="and"`, `titulo contem "and"`},
+		{"limite de profundidade", strings.Repeat("(", profundidadeMaxima) + "a" + strings.Repeat(")", profundidadeMaxima), `qualquer contem "a"`},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			condicao, err := traduzirCQL(c.consulta)
+			if err != nil {
+				t.Fatalf("traduzirCQL(%q): %v", c.consulta, err)
+			}
+			if obtido := descrever(condicao); obtido != c.esperado {
+				t.Errorf("traduzirCQL(%q) = %s, esperado %s", c.consulta, obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestTraduzirCQLDiagnosticos(t *testing.T) {
+	casos := []struct {
+		nome     string
+		consulta string
+		codigo   int
+	}{
+		{"vazia", "  ", diagObrigatorio},
+		{"índice desconhecido", "dc.format=livro", diagIndice},
+		{"relação não suportada", "dc.title within dom", diagRelacao},
+		{"modificador de relação", "dc.title =/stem dom", diagModificadorRelacao},
+		{"modificador de booleano", "a and/rel.algorithm=cql b", diagModificadorBooleano},
+		{"proximidade", "a prox b", diagBooleano},
+		{"termo vazio", `dc.title=""`, diagTermoVazio},
+		{"máscara no meio", "d?m", diagMascara},
+		{"asterisco no meio", "d*m", diagMascara},
+		{"ordenação", "dom sortBy dc.title", diagOrdenacao},
+		{"parêntese sem fechamento", "(a or b", diagSintaxe},
+		{"parêntese sobrando", "a or b)", diagSintaxe},
+		{"aspas sem fechamento", `"dom casmurro`, diagSintaxe},
+		{"booleano sem termo", "a and", diagSintaxe},
+		{"relação sem termo", "dc.title =", diagSintaxe},
+		{"atribuição de prefixo", `> dc = "info:srw/cql-context-set/1/dc-v1.1" dc.title=dom`, diagSintaxe},
+		{"profundidade demais", strings.Repeat("(", profundidadeMaxima+1) + "a" + strings.Repeat(")", profundidadeMaxima+1), diagSintaxe},
+		{"profundidade sem fechar", strings.Repeat("(", 100000), diagSintaxe},
+		{"longa demais", strings.Repeat("a", tamanhoMaximoConsulta+1), diagSintaxe},
+		{"cláusulas demais", encadear("a", "or", clausulasMaximas+1), diagBooleanosDemais},
+		{"palavras demais em any", `dc.title any "` + encadear("a", "", clausulasMaximas+1) + `"`, diagBooleanosDemais},
+		{"diferente conta duas cláusulas", encadear("dc.title<>a", "or", clausulasMaximas/2+1), diagBooleanosDemais},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			_, err := traduzirCQL(c.consulta)
+			var d *Diagnostico
+			if !errors.As(err, &d) {
+				t.Fatalf("traduzirCQL(%.40q): erro %v, esperado o diagnóstico %d", c.consulta, err, c.codigo)
+			}
+			if uri := fmt.Sprintf("info:srw/diagnostic/1/%d", c.codigo); d.URI != uri {
+				t.Errorf("traduzirCQL(%.40q): diagnóstico %s (%s), esperado %s", c.consulta, d.URI, d.Detalhes, uri)
+			}
+		})
+	}
+}
+
+func TestTraduzirCQLLimiteDeClausulas(t *testing.T) {
+	c, err := traduzirCQL(encadear("a", "or", clausulasMaximas))
+	if err != nil {
+		t.Fatalf("%d cláusulas: %v", clausulasMaximas, err)
+	}
+	if n := folhas(c); n != clausulasMaximas {
+		t.Errorf("folhas = %d, esperado %d", n, clausulasMaximas)
+	}
+}
@@ -0,0 +1,83 @@
+package sru
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// códigos da lista de diagnósticos do SRU (info:srw/diagnostic/1/...)
+const (
+	diagSistema             = 1
+	diagOperacao            = 4
+	diagVersao              = 5
+	diagValor               = 6
+	diagObrigatorio         = 7
+	diagParametro           = 8
+	diagSintaxe             = 10
+	diagIndice              = 16
+	diagRelacao             = 19
+	diagModificadorRelacao  = 20
+	diagTermoVazio          = 27
+	diagMascara             = 28
+	diagBooleano            = 37
+	diagBooleanosDemais     = 38
+	diagModificadorBooleano = 46
+	diagPosicao             = 61
+	diagEsquema             = 66
+	diagEmpacotamento       = 71
+	diagXPath               = 72
+	diagOrdenacao           = 80
+	diagFolhaEstilo         = 110
+)
+
+var mensagensDiagnostico = map[int]string{
+	diagSistema:             "Erro interno do servidor",
+	diagOperacao:            "Operação não suportada",
+	diagVersao:              "Versão não suportada",
+	diagValor:               "Valor de parâmetro não suportado",
+	diagObrigatorio:         "Parâmetro obrigatório não informado",
+	diagParametro:           "Parâmetro não suportado",
+	diagSintaxe:             "Erro de sintaxe na consulta",
+	diagIndice:              "Índice não suportado",
+	diagRelacao:             "Relação não suportada",
+	diagModificadorRelacao:  "Modificador de relação não suportado",
+	diagTermoVazio:          "Termo vazio não suportado",
+	diagMascara:             "Caractere de máscara não suportado nesta posição",
+	diagBooleano:            "Operador booleano não suportado",
+	diagBooleanosDemais:     "Operadores booleanos demais na consulta",
+	diagModificadorBooleano: "Modificador de operador booleano não suportado",
+	diagPosicao:             "Posição do primeiro registro fora do intervalo",
+	diagEsquema:             "Esquema de registro desconhecido",
+	diagEmpacotamento:       "Empacotamento de registro não suportado",
+	diagXPath:               "Recuperação por XPath não suportada",
+	diagOrdenacao:           "Ordenação não suportada",
+	diagFolhaEstilo:         "Folhas de estilo não suportadas",
+}
+
+// Diagnostico é um erro relatado ao cliente no corpo da resposta, como
+// manda o protocolo, e não pelo status HTTP
+type Diagnostico struct {
+	XMLName  xml.Name `xml:"http://www.loc.gov/zing/srw/diagnostic/ diagnostic"`
+	URI      string   `xml:"uri"`
+	Detalhes string   `xml:"details,omitempty"`
+	Mensagem string   `xml:"message"`
+}
+
+func diagnostico(codigo int, detalhes string) *Diagnostico {
+	return &Diagnostico{
+		URI:      fmt.Sprintf("info:srw/diagnostic/1/%d", codigo),
+		Detalhes: detalhes,
+		Mensagem: mensagensDiagnostico[codigo],
+	}
+}
+
+func (d *Diagnostico) Error() string {
+	if d.Detalhes == "" {
+		return d.Mensagem
+	}
+	return d.Mensagem + ": " + d.Detalhes
+}
+
+type diagnosticos struct {
+	Diagnosticos []*Diagnostico `xml:"diagnostic"`
+}
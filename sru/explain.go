@@ -0,0 +1,158 @@
+package sru
+
+import (
+	"encoding/xml"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// O explain descreve o servidor no formato ZeeRex 2.0: endereço, índices
+// buscáveis (agrupados pelos conjuntos de contexto da CQL), esquemas de
+// registro e os limites de cada busca
+
+const esquemaZeeRex = "http://explain.z3950.org/dtd/2.0/"
+
+type respostaExplain struct {
+	XMLName      xml.Name      `xml:"http://www.loc.gov/zing/srw/ explainResponse"`
+	Versao       string        `xml:"version"`
+	Registro     registroSRU   `xml:"record"`
+	Diagnosticos *diagnosticos `xml:"diagnostics,omitempty"`
+}
+
+type zeerex struct {
+	XMLName  xml.Name `xml:"http://explain.z3950.org/dtd/2.0/ explain"`
+	Servidor struct {
+		Protocolo  string `xml:"protocol,attr"`
+		Versao     string `xml:"version,attr"`
+		Transporte string `xml:"transport,attr"`
+		Host       string `xml:"host"`
+		Porta      int    `xml:"port"`
+		Base       string `xml:"database"`
+	} `xml:"serverInfo"`
+	Base struct {
+		Titulo    textoIdioma `xml:"title"`
+		Descricao textoIdioma `xml:"description"`
+	} `xml:"databaseInfo"`
+	Indices struct {
+		Conjuntos []conjuntoZeeRex `xml:"set"`
+		Indices   []indiceZeeRex   `xml:"index"`
+	} `xml:"indexInfo"`
+	Esquemas struct {
+		Esquemas []esquemaZeeRexItem `xml:"schema"`
+	} `xml:"schemaInfo"`
+	Configuracao struct {
+		Itens []configZeeRex `xml:",any"`
+	} `xml:"configInfo"`
+}
+
+type textoIdioma struct {
+	Idioma   string `xml:"lang,attr"`
+	Primario bool   `xml:"primary,attr"`
+	Texto    string `xml:",chardata"`
+}
+
+type conjuntoZeeRex struct {
+	Nome          string `xml:"name,attr"`
+	Identificador string `xml:"identifier,attr"`
+}
+
+type indiceZeeRex struct {
+	Busca  bool         `xml:"search,attr"`
+	Titulo string       `xml:"title"`
+	Mapas  []mapaZeeRex `xml:"map"`
+}
+
+type mapaZeeRex struct {
+	Nome struct {
+		Conjunto string `xml:"set,attr"`
+		Nome     string `xml:",chardata"`
+	} `xml:"name"`
+}
+
+type esquemaZeeRexItem struct {
+	Identificador string `xml:"identifier,attr"`
+	Nome          string `xml:"name,attr"`
+	Ordenacao     bool   `xml:"sort,attr"`
+	Recuperacao   bool   `xml:"retrieve,attr"`
+	Titulo        string `xml:"title"`
+}
+
+// configZeeRex é um item default, setting ou supports de configInfo
+type configZeeRex struct {
+	XMLName xml.Name
+	Tipo    string `xml:"type,attr"`
+	Valor   string `xml:",chardata"`
+}
+
+func (s *Servidor) explain(r *http.Request, req requisicao, diag *Diagnostico) respostaExplain {
+	var z zeerex
+	z.Servidor.Protocolo, z.Servidor.Versao, z.Servidor.Transporte = "SRU", versao, "http"
+	if r.TLS != nil {
+		z.Servidor.Transporte = "https"
+	}
+	z.Servidor.Host, z.Servidor.Porta = hostPorta(r)
+	z.Servidor.Base = strings.TrimPrefix(Caminho, "/")
+	z.Base.Titulo = textoIdioma{"pt", true, "Catálogo da Biblioteca"}
+	z.Base.Descricao = textoIdioma{"pt", true, "Acervo de livros, com autores, editoras, assuntos e classificação CDD/CDU."}
+
+	for _, c := range conjuntos {
+		z.Indices.Conjuntos = append(z.Indices.Conjuntos, conjuntoZeeRex{c.prefixo, c.identificador})
+	}
+	for _, i := range indices {
+		zi := indiceZeeRex{Busca: true, Titulo: i.titulo}
+		for _, n := range i.nomes {
+			var m mapaZeeRex
+			m.Nome.Conjunto, m.Nome.Nome = n.conjunto, n.nome
+			zi.Mapas = append(zi.Mapas, m)
+		}
+		z.Indices.Indices = append(z.Indices.Indices, zi)
+	}
+	for _, e := range esquemas {
+		z.Esquemas.Esquemas = append(z.Esquemas.Esquemas, esquemaZeeRexItem{e.identificador, e.nome, false, true, e.titulo})
+	}
+
+	config := func(elemento, tipo, valor string) {
+		z.Configuracao.Itens = append(z.Configuracao.Itens, configZeeRex{xml.Name{Local: elemento}, tipo, valor})
+	}
+	config("default", "numberOfRecords", strconv.Itoa(registrosPadrao))
+	config("setting", "maximumRecords", strconv.Itoa(registrosMaximo))
+	config("default", "index", "cql.serverChoice")
+	config("default", "relation", "=")
+	config("default", "retrieveSchema", "marcxml")
+	for _, rel := range []string{"=", "==", "<>", "adj", "all", "any", "exact"} {
+		config("supports", "relation", rel)
+	}
+	for _, b := range []string{"and", "or", "not"} {
+		config("supports", "booleanOperator", b)
+	}
+	config("supports", "maskingCharacter", "*")
+
+	conteudo, err := xml.Marshal(z)
+	if err != nil {
+		log.Printf("ERRO: falha ao gerar o explain: %v\n", err)
+		diag = diagnostico(diagSistema, "")
+	}
+	resposta := respostaExplain{Versao: versao, Registro: registroSRU{
+		Esquema: esquemaZeeRex, Empacotamento: req.empacotamento, Dados: empacotar(string(conteudo), req.empacotamento),
+	}}
+	if diag != nil {
+		resposta.Diagnosticos = &diagnosticos{[]*Diagnostico{diag}}
+	}
+	return resposta
+}
+
+// hostPorta separa o endereço pelo qual o cliente chegou ao servidor
+func hostPorta(r *http.Request) (string, int) {
+	host, porta, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, porta = r.Host, "80"
+		if r.TLS != nil {
+			porta = "443"
+		}
+	}
+	n, _ := strconv.Atoi(porta)
+	return host, n
+}
@@ -0,0 +1,216 @@
+package sru
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// esquemas de registro atendidos, pelo identificador e pelo nome curto
+const (
+	esquemaMARCXML = "info:srw/schema/1/marcxml-v1.1"
+	esquemaDC      = "info:srw/schema/1/dc-v1.1"
+)
+
+var esquemas = []struct{ identificador, nome, titulo string }{
+	{esquemaMARCXML, "marcxml", "MARC 21 em XML (MARCXML)"},
+	{esquemaDC, "dc", "Dublin Core"},
+}
+
+// esquemaRegistro resolve o recordSchema informado; vazio usa MARCXML
+func esquemaRegistro(valor string) (string, bool) {
+	if valor == "" {
+		return esquemaMARCXML, true
+	}
+	for _, e := range esquemas {
+		if valor == e.identificador || strings.EqualFold(valor, e.nome) {
+			return e.identificador, true
+		}
+	}
+	return "", false
+}
+
+// códigos de idioma: o cadastro usa a ISO 639-1 e o MARC 21, a lista MARC
+// de idiomas (igual à ISO 639-2/B)
+var idiomasMARC = map[string]string{
+	"pt": "por", "en": "eng", "es": "spa", "fr": "fre", "de": "ger", "it": "ita",
+	"la": "lat", "ru": "rus", "ja": "jpn", "zh": "chi", "ar": "ara", "el": "gre",
+	"nl": "dut", "eo": "epo", "gn": "grn", "he": "heb", "ko": "kor", "pl": "pol",
+}
+
+func idiomaMARC(iso6391 string) string {
+	if c, ok := idiomasMARC[strings.ToLower(iso6391)]; ok {
+		return c
+	}
+	return "und"
+}
+
+// idiomaISO6391 converte para o código do cadastro as buscas feitas com o
+// código MARC; outros valores são mantidos
+func idiomaISO6391(valor string) string {
+	for iso, marc := range idiomasMARC {
+		if strings.EqualFold(valor, marc) {
+			return iso
+		}
+	}
+	return valor
+}
+
+// nomeInvertido é a forma do nome usada nos pontos de acesso: "Sobrenome, Prenome"
+func nomeInvertido(a model.Autor) string {
+	if a.PrimeiroNome == "" {
+		return a.Sobrenome
+	}
+	return a.Sobrenome + ", " + a.PrimeiroNome
+}
+
+type registroMARC struct {
+	XMLName   xml.Name        `xml:"http://www.loc.gov/MARC21/slim record"`
+	Lider     string          `xml:"leader"`
+	Controles []campoControle `xml:"controlfield"`
+	Dados     []campoDados    `xml:"datafield"`
+}
+
+type campoControle struct {
+	Tag   string `xml:"tag,attr"`
+	Valor string `xml:",chardata"`
+}
+
+type campoDados struct {
+	Tag       string     `xml:"tag,attr"`
+	Ind1      string     `xml:"ind1,attr"`
+	Ind2      string     `xml:"ind2,attr"`
+	Subcampos []subcampo `xml:"subfield"`
+}
+
+type subcampo struct {
+	Codigo string `xml:"code,attr"`
+	Valor  string `xml:",chardata"`
+}
+
+func (r *registroMARC) campo(tag, ind1, ind2 string, subcampos ...string) {
+	d := campoDados{Tag: tag, Ind1: ind1, Ind2: ind2}
+	for i := 0; i+1 < len(subcampos); i += 2 {
+		if subcampos[i+1] != "" {
+			d.Subcampos = append(d.Subcampos, subcampo{subcampos[i], subcampos[i+1]})
+		}
+	}
+	if len(d.Subcampos) > 0 {
+		r.Dados = append(r.Dados, d)
+	}
+}
+
+// marcxml monta o registro bibliográfico MARC 21 do livro. O tamanho e o
+// endereço base do líder ficam zerados, como é usual em MARCXML
+func marcxml(f servico.Ficha) registroMARC {
+	l := f.Livro
+	r := registroMARC{Lider: "00000nam a2200000 i 4500"}
+	r.Controles = append(r.Controles, campoControle{"001", l.ISBN})
+
+	// 008: posições 35-37 trazem o idioma; as demais ficam sem informação
+	fixo := []byte(strings.Repeat(" ", 40))
+	copy(fixo[6:], "nuuuuuuuu")
+	copy(fixo[15:], "xx ")
+	copy(fixo[35:], idiomaMARC(l.Idioma))
+	fixo[39] = 'd'
+	r.Controles = append(r.Controles, campoControle{"008", string(fixo)})
+
+	r.campo("020", " ", " ", "a", l.ISBN)
+	if l.Idioma != "" {
+		r.campo("041", "0", " ", "a", idiomaMARC(l.Idioma))
+	}
+	switch l.SistemaClassificacao {
+	case model.ClassificacaoCDD:
+		r.campo("082", "0", "4", "a", l.NumeroClassificacao)
+	case model.ClassificacaoCDU:
+		r.campo("080", " ", " ", "a", l.NumeroClassificacao)
+	}
+
+	// o primeiro autor é a entrada principal e o título não é entrada secundária
+	indTitulo := "0"
+	for i, a := range l.Autores {
+		tag := "700"
+		if i == 0 {
+			tag, indTitulo = "100", "1"
+		}
+		r.campo(tag, "1", " ", "a", nomeInvertido(a))
+	}
+	if f.Obra != nil && f.Obra.Titulo != "" && f.Obra.Titulo != l.Titulo {
+		r.campo("240", "1", "0", "a", f.Obra.Titulo, "l", idiomaMARC(l.Idioma))
+	}
+	r.campo("245", indTitulo, "0", "a", l.Titulo)
+	r.campo("250", " ", " ", "a", l.Edicao)
+	if f.Editora != nil {
+		r.campo("264", " ", "1", "b", f.Editora.Nome)
+	}
+	if l.NumPaginas > 0 {
+		r.campo("300", " ", " ", "a", strconv.Itoa(l.NumPaginas)+" p.")
+	}
+	for _, c := range f.Assuntos {
+		r.campo("650", " ", "4", "a", c.Nome)
+	}
+	return r
+}
+
+// registroDC segue o esquema info:srw/schema/1/dc-v1.1. O encoding/xml não
+// gera prefixos de namespace, e os nomes são escritos já prefixados
+type registroDC struct {
+	XMLName       xml.Name `xml:"srw_dc:dc"`
+	NamespaceSRW  string   `xml:"xmlns:srw_dc,attr"`
+	NamespaceDC   string   `xml:"xmlns:dc,attr"`
+	Titulos       []string `xml:"dc:title"`
+	Autores       []string `xml:"dc:creator"`
+	Assuntos      []string `xml:"dc:subject"`
+	Editora       string   `xml:"dc:publisher,omitempty"`
+	Tipo          string   `xml:"dc:type"`
+	Formato       string   `xml:"dc:format,omitempty"`
+	Identificador string   `xml:"dc:identifier"`
+	Idioma        string   `xml:"dc:language,omitempty"`
+	Relacao       string   `xml:"dc:relation,omitempty"`
+	Descricao     string   `xml:"dc:description,omitempty"`
+}
+
+func dublinCore(f servico.Ficha) registroDC {
+	l := f.Livro
+	r := registroDC{
+		NamespaceSRW: "info:srw/schema/1/dc-schema", NamespaceDC: "http://purl.org/dc/elements/1.1/",
+		Titulos: []string{l.Titulo}, Tipo: "Text", Identificador: "urn:isbn:" + l.ISBN,
+	}
+	if l.Idioma != "" {
+		r.Idioma = idiomaMARC(l.Idioma)
+	}
+	for _, a := range l.Autores {
+		r.Autores = append(r.Autores, nomeInvertido(a))
+	}
+	for _, c := range f.Assuntos {
+		r.Assuntos = append(r.Assuntos, c.Nome)
+	}
+	if l.NumeroClassificacao != "" {
+		r.Assuntos = append(r.Assuntos, strings.TrimSpace(l.SistemaClassificacao+" "+l.NumeroClassificacao))
+	}
+	if f.Editora != nil {
+		r.Editora = f.Editora.Nome
+	}
+	if l.NumPaginas > 0 {
+		r.Formato = strconv.Itoa(l.NumPaginas) + " p."
+	}
+	if f.Obra != nil && f.Obra.Titulo != l.Titulo {
+		r.Relacao = f.Obra.Titulo
+	}
+	if l.Edicao != "" {
+		r.Descricao = "Edição: " + l.Edicao
+	}
+	return r
+}
+
+// registro serializa a ficha no esquema pedido
+func registro(f servico.Ficha, esquema string) (string, error) {
+	var v any = marcxml(f)
+	if esquema == esquemaDC {
+		v = dublinCore(f)
+	}
+	b, err := xml.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,248 @@
+// Package sru expõe o catálogo pelo protocolo SRU (Search/Retrieve via URL,
+// versão 1.2), para que catálogos coletivos e outras bibliotecas possam
+// buscar no acervo. As consultas são escritas em CQL e traduzidas nas
+// consultas do repositório de livros; os registros saem em MARCXML ou Dublin
+// Core. Sem o parâmetro operation, o servidor responde com o explain, que
+// descreve os índices e os esquemas disponíveis.
+package sru
+
+import (
+	"crud-biblioteca/servico"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Caminho em que o servidor é atendido
+const Caminho = "/sru"
+
+const (
+	versao = "1.2"
+
+	registrosPadrao = 10
+	registrosMaximo = 100
+)
+
+// parâmetros aceitos em cada operação, além de operation, version e dos
+// parâmetros de extensão (x-...), que são ignorados
+var parametros = map[string][]string{
+	"explain":        {"recordPacking", "stylesheet"},
+	"searchRetrieve": {"query", "startRecord", "maximumRecords", "recordPacking", "recordSchema", "resultSetTTL", "stylesheet", "sortKeys", "recordXPath"},
+}
+
+type Servidor struct {
+	biblioteca *servico.Biblioteca
+}
+
+func New(b *servico.Biblioteca) *Servidor {
+	return &Servidor{biblioteca: b}
+}
+
+// requisicao são os parâmetros da requisição, já validados
+type requisicao struct {
+	operacao      string
+	consulta      string
+	inicio        int // startRecord
+	maximo        int // maximumRecords
+	empacotamento string
+	esquema       string
+}
+
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	inicio := time.Now()
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "parâmetros inválidos", http.StatusBadRequest)
+		return
+	}
+
+	req, diag := lerRequisicao(r)
+	var resposta any
+	switch {
+	case req.operacao == "searchRetrieve":
+		resposta = s.buscar(r, req, diag)
+	default:
+		resposta = s.explain(r, req, diag)
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	corpo, err := xml.MarshalIndent(resposta, "", "  ")
+	if err != nil {
+		log.Printf("ERRO: falha ao gerar a resposta SRU: %v\n", err)
+		http.Error(w, "erro interno do servidor", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, xml.Header)
+	w.Write(corpo)
+	log.Printf("SRU %s %q %s", req.operacao, req.consulta, time.Since(inicio).Round(time.Millisecond))
+}
+
+// lerRequisicao valida os parâmetros; o primeiro problema encontrado volta
+// como diagnóstico, e a requisição fica com os valores padrão
+func lerRequisicao(r *http.Request) (requisicao, *Diagnostico) {
+	req := requisicao{
+		operacao: r.Form.Get("operation"), consulta: r.Form.Get("query"),
+		inicio: 1, maximo: registrosPadrao, empacotamento: "xml", esquema: esquemaMARCXML,
+	}
+	if req.operacao == "" {
+		req.operacao = "explain"
+	}
+	aceitos, ok := parametros[req.operacao]
+	if !ok {
+		op := req.operacao
+		req.operacao = "explain"
+		return req, diagnostico(diagOperacao, op)
+	}
+	if v := r.Form.Get("version"); v != "" && v != "1.1" && v != versao {
+		return req, diagnostico(diagVersao, v)
+	}
+	for nome := range r.Form {
+		if nome != "operation" && nome != "version" && !strings.HasPrefix(nome, "x-") && !contem(aceitos, nome) {
+			return req, diagnostico(diagParametro, nome)
+		}
+	}
+
+	switch {
+	case r.Form.Get("stylesheet") != "":
+		return req, diagnostico(diagFolhaEstilo, r.Form.Get("stylesheet"))
+	case r.Form.Get("sortKeys") != "":
+		return req, diagnostico(diagOrdenacao, r.Form.Get("sortKeys"))
+	case r.Form.Get("recordXPath") != "":
+		return req, diagnostico(diagXPath, r.Form.Get("recordXPath"))
+	}
+	if v := r.Form.Get("recordPacking"); v != "" {
+		if v != "xml" && v != "string" {
+			return req, diagnostico(diagEmpacotamento, v)
+		}
+		req.empacotamento = v
+	}
+	if req.operacao != "searchRetrieve" {
+		return req, nil
+	}
+
+	if v := r.Form.Get("startRecord"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return req, diagnostico(diagValor, "startRecord="+v)
+		}
+		req.inicio = n
+	}
+	if v := r.Form.Get("maximumRecords"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return req, diagnostico(diagValor, "maximumRecords="+v)
+		}
+		// pedidos maiores recebem o máximo e seguem pelo nextRecordPosition
+		req.maximo = min(n, registrosMaximo)
+	}
+	esquema, ok := esquemaRegistro(r.Form.Get("recordSchema"))
+	if !ok {
+		return req, diagnostico(diagEsquema, r.Form.Get("recordSchema"))
+	}
+	req.esquema = esquema
+	return req, nil
+}
+
+type respostaBusca struct {
+	XMLName      xml.Name      `xml:"http://www.loc.gov/zing/srw/ searchRetrieveResponse"`
+	Versao       string        `xml:"version"`
+	Total        int           `xml:"numberOfRecords"`
+	Registros    *registros    `xml:"records,omitempty"`
+	Proximo      int           `xml:"nextRecordPosition,omitempty"`
+	Eco          eco           `xml:"echoedSearchRetrieveRequest"`
+	Diagnosticos *diagnosticos `xml:"diagnostics,omitempty"`
+}
+
+type registros struct {
+	Registros []registroSRU `xml:"record"`
+}
+
+type registroSRU struct {
+	Esquema       string   `xml:"recordSchema"`
+	Empacotamento string   `xml:"recordPacking"`
+	Dados         dadosSRU `xml:"recordData"`
+	Posicao       int      `xml:"recordPosition,omitempty"`
+}
+
+// dadosSRU leva o registro como XML (recordPacking=xml) ou como texto
+// escapado (recordPacking=string)
+type dadosSRU struct {
+	XML   string `xml:",innerxml"`
+	Texto string `xml:",chardata"`
+}
+
+func empacotar(conteudo, empacotamento string) dadosSRU {
+	if empacotamento == "string" {
+		return dadosSRU{Texto: conteudo}
+	}
+	return dadosSRU{XML: conteudo}
+}
+
+type eco struct {
+	Versao        string `xml:"version"`
+	Consulta      string `xml:"query"`
+	Inicio        int    `xml:"startRecord"`
+	Maximo        int    `xml:"maximumRecords"`
+	Empacotamento string `xml:"recordPacking"`
+	Esquema       string `xml:"recordSchema"`
+}
+
+func (s *Servidor) buscar(r *http.Request, req requisicao, diag *Diagnostico) respostaBusca {
+	resposta := respostaBusca{Versao: versao, Eco: eco{versao, req.consulta, req.inicio, req.maximo, req.empacotamento, req.esquema}}
+	if pedido := r.Form.Get("recordSchema"); pedido != "" {
+		resposta.Eco.Esquema = pedido
+	}
+	falhar := func(d *Diagnostico) respostaBusca {
+		resposta.Diagnosticos = &diagnosticos{[]*Diagnostico{d}}
+		return resposta
+	}
+	if diag != nil {
+		return falhar(diag)
+	}
+
+	condicao, err := traduzirCQL(req.consulta)
+	if err != nil {
+		var d *Diagnostico
+		if errors.As(err, &d) {
+			return falhar(d)
+		}
+		return falhar(diagnostico(diagSintaxe, err.Error()))
+	}
+	fichas, total, err := s.biblioteca.BuscarCatalogo(r.Context(), condicao, req.inicio-1, req.maximo)
+	if err != nil {
+		log.Printf("ERRO: busca SRU %q: %v\n", req.consulta, err)
+		return falhar(diagnostico(diagSistema, ""))
+	}
+	resposta.Total = total
+	if req.inicio > total && total > 0 {
+		return falhar(diagnostico(diagPosicao, strconv.Itoa(req.inicio)))
+	}
+
+	if len(fichas) > 0 {
+		resposta.Registros = &registros{}
+	}
+	for i, f := range fichas {
+		conteudo, err := registro(f, req.esquema)
+		if err != nil {
+			log.Printf("ERRO: registro SRU do livro %s: %v\n", f.Livro.ISBN, err)
+			return falhar(diagnostico(diagSistema, ""))
+		}
+		resposta.Registros.Registros = append(resposta.Registros.Registros, registroSRU{
+			Esquema: req.esquema, Empacotamento: req.empacotamento,
+			Dados: empacotar(conteudo, req.empacotamento), Posicao: req.inicio + i,
+		})
+	}
+	if proximo := req.inicio + len(fichas); len(fichas) > 0 && proximo <= total {
+		resposta.Proximo = proximo
+	}
+	return resposta
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/servico"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// aba lista os registros de uma entidade. As funções definem como buscar,
+// exibir e alterar os registros; registros guarda o valor de cada linha da
+// tabela, na mesma ordem
+type aba struct {
+	titulo    string
+	busca     textinput.Model
+	tabela    table.Model
+	registros []any
+
+	// listar busca pelo termo digitado; termo vazio lista tudo
+	listar    func(ctx context.Context, b *servico.Biblioteca, termo string) ([]any, error)
+	linha     func(r any) table.Row
+	descrever func(r any) string
+	novo      func(m *Modelo) janela
+	editar    func(m *Modelo, r any) janela
+	remover   func(ctx context.Context, b *servico.Biblioteca, r any) error
+	atalhos   []atalho
+}
+
+// atalho é uma ação própria da aba sobre o registro selecionado
+type atalho struct {
+	tecla    key.Binding
+	executar func(m *Modelo, r any) tea.Cmd
+}
+
+func novaAba(titulo, dica string, colunas []table.Column) *aba {
+	busca := textinput.New()
+	busca.Prompt = "Buscar: "
+	busca.Placeholder = dica
+	busca.Width = 40
+	return &aba{
+		titulo: titulo,
+		busca:  busca,
+		tabela: table.New(table.WithColumns(colunas), table.WithFocused(true), table.WithKeyMap(teclasTabela)),
+	}
+}
+
+// teclasTabela deixa as letras livres para os atalhos das abas
+var teclasTabela = table.KeyMap{
+	LineUp:     key.NewBinding(key.WithKeys("up", "k")),
+	LineDown:   key.NewBinding(key.WithKeys("down", "j")),
+	PageUp:     key.NewBinding(key.WithKeys("pgup")),
+	PageDown:   key.NewBinding(key.WithKeys("pgdown")),
+	GotoTop:    key.NewBinding(key.WithKeys("home")),
+	GotoBottom: key.NewBinding(key.WithKeys("end")),
+}
+
+// carregar refaz a busca com o termo atual fora do laço de eventos
+func (a *aba) carregar(m *Modelo) tea.Cmd {
+	termo := strings.TrimSpace(a.busca.Value())
+	return func() tea.Msg {
+		registros, err := a.listar(m.ctx, m.b, termo)
+		return msgLista{a, registros, err}
+	}
+}
+
+func (a *aba) exibirRegistros(registros []any) {
+	a.registros = registros
+	linhas := make([]table.Row, len(registros))
+	for i, r := range registros {
+		linhas[i] = a.linha(r)
+	}
+	a.tabela.SetRows(linhas)
+	if a.tabela.Cursor() >= len(linhas) {
+		a.tabela.SetCursor(max(len(linhas)-1, 0))
+	}
+}
+
+func (a *aba) selecionado() any {
+	if i := a.tabela.Cursor(); i >= 0 && i < len(a.registros) {
+		return a.registros[i]
+	}
+	return nil
+}
+
+func (a *aba) redimensionar(largura, altura int) {
+	a.tabela.SetWidth(largura)
+	a.tabela.SetHeight(max(altura, 3))
+}
+
+func (a *aba) buscando() bool {
+	return a.busca.Focused()
+}
+
+func (a *aba) iniciarBusca() tea.Cmd {
+	a.tabela.Blur()
+	return a.busca.Focus()
+}
+
+// atualizarBusca trata as teclas enquanto o campo de busca está ativo: enter
+// busca, esc cancela e as demais editam o termo
+func (a *aba) atualizarBusca(m *Modelo, msg tea.Msg) tea.Cmd {
+	if tecla, ok := msg.(tea.KeyMsg); ok {
+		switch tecla.String() {
+		case "enter":
+			a.busca.Blur()
+			a.tabela.Focus()
+			a.tabela.SetCursor(0)
+			return a.carregar(m)
+		case "esc":
+			a.busca.Blur()
+			a.tabela.Focus()
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	a.busca, cmd = a.busca.Update(msg)
+	return cmd
+}
+
+func (a *aba) exibir() string {
+	return a.busca.View() + "\n\n" + a.tabela.View()
+}
+
+func (a *aba) ajuda() string {
+	t := []key.Binding{teclas.buscar, teclas.novo, teclas.editar, teclas.remover}
+	for _, at := range a.atalhos {
+		t = append(t, at.tecla)
+	}
+	return ajuda(append(t, teclas.proxima, teclas.recarregar, teclas.menu, teclas.sair)...)
+}
+
+// lista converte os registros para a tabela da aba
+func lista[T any](registros []T, err error) ([]any, error) {
+	r := make([]any, len(registros))
+	for i := range registros {
+		r[i] = &registros[i]
+	}
+	return r, err
+}
+
+// unico trata a busca por chave: o registro encontrado ou nenhum, se não existir
+func unico[T any](registro *T, err error) ([]any, error) {
+	if err != nil {
+		if naoEncontrado(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []any{registro}, nil
+}
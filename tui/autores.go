@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func abaAutores() *aba {
+	a := novaAba("Autores", "nome ou ID", []table.Column{{Title: "ID", Width: 8}, {Title: "Nome", Width: 40}})
+	a.listar = func(ctx context.Context, b *servico.Biblioteca, termo string) ([]any, error) {
+		if id, err := strconv.Atoi(termo); err == nil {
+			return unico(b.ObterAutor(ctx, id))
+		}
+		return lista(b.ListarAutores(ctx, termo))
+	}
+	a.linha = func(r any) table.Row {
+		au := r.(*model.Autor)
+		return table.Row{fmt.Sprint(au.ID), au.PrimeiroNome + " " + au.Sobrenome}
+	}
+	a.descrever = func(r any) string {
+		au := r.(*model.Autor)
+		return fmt.Sprintf("autor %s %s", au.PrimeiroNome, au.Sobrenome)
+	}
+	a.novo = func(m *Modelo) janela {
+		return formularioAutor(m, model.Autor{}, true)
+	}
+	a.editar = func(m *Modelo, r any) janela {
+		return formularioAutor(m, *r.(*model.Autor), false)
+	}
+	a.remover = func(ctx context.Context, b *servico.Biblioteca, r any) error {
+		return b.DeletarAutor(ctx, r.(*model.Autor).ID)
+	}
+	return a
+}
+
+func formularioAutor(m *Modelo, au model.Autor, criar bool) *formulario {
+	var campos []campoInicial
+	titulo := fmt.Sprintf("Autor %d", au.ID)
+	if criar {
+		titulo = "Novo autor"
+		campos = append(campos, campoInicial{"ID", "id", ""})
+	}
+	campos = append(campos,
+		campoInicial{"Primeiro nome", "primeiro_nome", au.PrimeiroNome},
+		campoInicial{"Sobrenome", "sobrenome", au.Sobrenome},
+	)
+	f := novoFormulario(titulo, campos)
+
+	montar := func(v valores) (model.Autor, *conversor) {
+		c := &conversor{}
+		n := au
+		if criar {
+			n.ID = c.inteiro(v, "id")
+		}
+		n.PrimeiroNome, n.Sobrenome = v["primeiro_nome"], v["sobrenome"]
+		return n, c
+	}
+	f.validar = func(v valores) error {
+		n, c := montar(v)
+		return c.juntar(servico.ValidarAutor(n))
+	}
+	f.salvar = func(v valores) (any, error) {
+		n, c := montar(v)
+		if len(c.erros) > 0 {
+			return nil, c.juntar(servico.ValidarAutor(n))
+		}
+		if criar {
+			return m.b.CriarAutor(m.ctx, n)
+		}
+		return m.b.AtualizarAutor(m.ctx, n)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		au := r.(*model.Autor)
+		m.informar(fmt.Sprintf("Autor %s %s salvo.", au.PrimeiroNome, au.Sobrenome), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
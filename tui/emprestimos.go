@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func abaEmprestimos() *aba {
+	a := novaAba("Empréstimos", "ID, CPF ou status (A, D, C)", []table.Column{
+		{Title: "ID", Width: 8}, {Title: "Data", Width: 10}, {Title: "Status", Width: 6},
+		{Title: "Livros", Width: 6}, {Title: "CPF", Width: 14},
+	})
+	a.listar = func(ctx context.Context, b *servico.Biblioteca, termo string) ([]any, error) {
+		if _, err := validacao.NormalizarCPF(termo); err == nil {
+			return lista(b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{CPF: termo}))
+		}
+		if id, err := strconv.Atoi(termo); err == nil {
+			return unico(b.ObterEmprestimo(ctx, id))
+		}
+		switch strings.ToUpper(termo) {
+		case "A", "D", "C":
+			return lista(b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{Status: termo}))
+		}
+		return lista(b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{}))
+	}
+	a.linha = func(r any) table.Row {
+		e := r.(*model.Emprestimo)
+		return table.Row{fmt.Sprint(e.ID), data(e.DataEmprestimo), e.Status, fmt.Sprint(e.QuantLivros),
+			validacao.FormatarCPF(e.ClienteUsuarioCPF)}
+	}
+	a.descrever = func(r any) string {
+		return fmt.Sprintf("empréstimo %d", r.(*model.Emprestimo).ID)
+	}
+	a.novo = func(m *Modelo) janela {
+		return formularioEmprestimo(m, model.Emprestimo{Status: servico.StatusAtivo, DataEmprestimo: time.Now()}, true)
+	}
+	a.editar = func(m *Modelo, r any) janela {
+		return formularioEmprestimo(m, *r.(*model.Emprestimo), false)
+	}
+	a.remover = func(ctx context.Context, b *servico.Biblioteca, r any) error {
+		return b.DeletarEmprestimo(ctx, r.(*model.Emprestimo).ID)
+	}
+	a.atalhos = []atalho{
+		{
+			tecla: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "devolver")),
+			executar: func(m *Modelo, r any) tea.Cmd {
+				e := *r.(*model.Emprestimo)
+				if e.Status != servico.StatusAtivo {
+					m.informar("", fmt.Errorf("o empréstimo %d não está ativo", e.ID))
+					return nil
+				}
+				e.Status = "D"
+				return executar(func() (string, error) {
+					_, err := m.b.AtualizarEmprestimo(m.ctx, e)
+					return fmt.Sprintf("Empréstimo %d devolvido.", e.ID), err
+				})
+			},
+		},
+		{
+			tecla: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "recibo")),
+			executar: func(m *Modelo, r any) tea.Cmd {
+				return abrirRecibo(m, *r.(*model.Emprestimo))
+			},
+		},
+	}
+	return a
+}
+
+func formularioEmprestimo(m *Modelo, e model.Emprestimo, criar bool) *formulario {
+	var campos []campoInicial
+	titulo := fmt.Sprintf("Empréstimo %d", e.ID)
+	if criar {
+		titulo = "Novo empréstimo"
+		campos = append(campos, campoInicial{"ID", "id", numero(e.ID)})
+	}
+	campos = append(campos, campoInicial{"CPF do usuário", "cliente_usuario_cpf", e.ClienteUsuarioCPF})
+	// o empréstimo novo pede os ISBNs, conferidos pelo servico; depois só a
+	// quantidade fica gravada
+	if criar {
+		campos = append(campos, campoInicial{"ISBNs dos livros", "livros", strings.Join(e.Livros, ", ")})
+	} else {
+		campos = append(campos, campoInicial{"Quantidade de livros", "quant_livros", numero(e.QuantLivros)})
+	}
+	campos = append(campos,
+		campoInicial{"Status", "status", e.Status},
+		campoInicial{"Data (AAAA-MM-DD)", "data_emprestimo", data(e.DataEmprestimo)},
+	)
+	f := novoFormulario(titulo, campos)
+	f.dica("status", "A, D ou C")
+	if criar {
+		f.dica("livros", "separados por vírgula")
+	}
+
+	montar := func(v valores) (model.Emprestimo, *conversor) {
+		c := &conversor{}
+		n := e
+		if criar {
+			n.ID = c.inteiro(v, "id")
+		}
+		n.ClienteUsuarioCPF = v["cliente_usuario_cpf"]
+		if criar {
+			n.Livros = servico.ListaISBNs(v["livros"])
+			n.QuantLivros = len(n.Livros)
+		} else {
+			n.QuantLivros = c.inteiro(v, "quant_livros")
+		}
+		n.Status = v["status"]
+		// a data digitada substitui só o dia, mantendo o horário registrado
+		if d := c.data(v, "data_emprestimo"); d.IsZero() || data(d) != data(e.DataEmprestimo) {
+			n.DataEmprestimo = d
+		}
+		return n, c
+	}
+	f.validar = func(v valores) error {
+		n, c := montar(v)
+		return c.juntar(servico.ValidarEmprestimo(n))
+	}
+	f.salvar = func(v valores) (any, error) {
+		n, c := montar(v)
+		if len(c.erros) > 0 {
+			return nil, c.juntar(servico.ValidarEmprestimo(n))
+		}
+		if criar {
+			return m.b.CriarEmprestimo(m.ctx, n)
+		}
+		return m.b.AtualizarEmprestimo(m.ctx, n)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		m.informar(fmt.Sprintf("Empréstimo %d salvo.", r.(*model.Emprestimo).ID), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
+
+// iniciarEmprestimo abre o empréstimo para o usuário selecionado, já com o
+// próximo ID e a situação do usuário; ao salvar, o recibo é exibido
+func iniciarEmprestimo(m *Modelo, cpf string) tea.Cmd {
+	return func() tea.Msg {
+		s, err := m.b.SituacaoUsuario(m.ctx, cpf)
+		if err != nil {
+			return msgStatus{err: err}
+		}
+		id, err := m.b.ProximoIDEmprestimo(m.ctx)
+		if err != nil {
+			return msgStatus{err: err}
+		}
+
+		f := formularioEmprestimo(m, model.Emprestimo{
+			ID: id, ClienteUsuarioCPF: s.Usuario.CPF, QuantLivros: 1,
+			Status: servico.StatusAtivo, DataEmprestimo: time.Now(),
+		}, true)
+		f.titulo = fmt.Sprintf("Empréstimo para %s %s — %d empréstimo(s) ativo(s), %d de %d livros",
+			s.Usuario.PrimeiroNome, s.Usuario.Sobrenome, s.EmprestimosAtivos, s.LivrosEmPosse, s.LimiteLivros)
+		if !s.PodeEmprestar {
+			f.erro = s.Motivo
+		}
+		f.concluido = func(m *Modelo, r any) tea.Cmd {
+			e := r.(*model.Emprestimo)
+			m.informar(fmt.Sprintf("Empréstimo %d registrado.", e.ID), nil)
+			return abrirRecibo(m, *e)
+		}
+		return msgAbrir{f}
+	}
+}
@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"crud-biblioteca/repository"
+	"crud-biblioteca/validacao"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// campoForm é um campo do formulário. chave é o nome do campo nos erros de
+// validação (o mesmo do JSON da API, como "primeiro_nome" ou "endereco.cep")
+type campoForm struct {
+	rotulo  string
+	chave   string
+	entrada textinput.Model
+	tocado  bool // o operador já passou pelo campo; só então os erros aparecem
+}
+
+// valores são os textos digitados, por chave
+type valores map[string]string
+
+// formulario coleta os campos e chama salvar. validar confere os valores sem
+// acessar o banco e roda sempre que o foco sai de um campo, para que os erros
+// apareçam ao lado dos campos antes de salvar
+type formulario struct {
+	titulo string
+	campos []campoForm
+	foco   int
+	erros  map[string]string
+	erro   string // erro que não pertence a um campo
+	// salvando impede um segundo envio enquanto o primeiro não termina
+	salvando bool
+
+	validar func(v valores) error
+	salvar  func(v valores) (any, error)
+	// concluido roda no laço de eventos após salvar com sucesso
+	concluido func(m *Modelo, resultado any) tea.Cmd
+}
+
+type campoInicial struct {
+	rotulo, chave, valor string
+}
+
+func novoFormulario(titulo string, campos []campoInicial) *formulario {
+	f := &formulario{titulo: titulo, erros: map[string]string{}}
+	for _, c := range campos {
+		entrada := textinput.New()
+		entrada.Prompt = ""
+		entrada.SetValue(c.valor)
+		entrada.Width = 40
+		f.campos = append(f.campos, campoForm{rotulo: c.rotulo, chave: c.chave, entrada: entrada})
+	}
+	return f
+}
+
+// dica mostra um texto de exemplo no campo vazio
+func (f *formulario) dica(chave, texto string) {
+	for i := range f.campos {
+		if f.campos[i].chave == chave {
+			f.campos[i].entrada.Placeholder = texto
+		}
+	}
+}
+
+// msgSalvo traz o resultado de salvar o formulário
+type msgSalvo struct {
+	form      *formulario
+	resultado any
+	err       error
+}
+
+func (f *formulario) valores() valores {
+	v := valores{}
+	for _, c := range f.campos {
+		v[c.chave] = strings.TrimSpace(c.entrada.Value())
+	}
+	return v
+}
+
+func (f *formulario) focar(i int) tea.Cmd {
+	f.campos[f.foco].entrada.Blur()
+	f.campos[f.foco].tocado = true
+	f.conferir()
+	f.foco = (i + len(f.campos)) % len(f.campos)
+	return f.campos[f.foco].entrada.Focus()
+}
+
+// conferir roda a validação local e guarda os erros dos campos já visitados
+func (f *formulario) conferir() {
+	f.erros = map[string]string{}
+	if f.validar == nil {
+		return
+	}
+	campos, _ := validacao.ErrosDeCampo(f.validar(f.valores()))
+	for _, c := range campos {
+		for _, campo := range f.campos {
+			if campo.chave == c.Campo && campo.tocado {
+				f.erros[c.Campo] = c.Mensagem
+			}
+		}
+	}
+}
+
+// mostrarErro distribui os erros de validação pelos campos; os demais erros
+// aparecem no rodapé do formulário
+func (f *formulario) mostrarErro(err error) {
+	f.erros, f.erro = map[string]string{}, ""
+	campos, ok := validacao.ErrosDeCampo(err)
+	if !ok {
+		f.erro = descreverErro(err)
+		return
+	}
+	var soltos []string
+	for _, c := range campos {
+		if f.temCampo(c.Campo) {
+			f.erros[c.Campo] = c.Mensagem
+		} else {
+			soltos = append(soltos, c.Mensagem)
+		}
+	}
+	if len(soltos) > 0 {
+		f.erro = strings.Join(soltos, "; ")
+	}
+}
+
+func (f *formulario) temCampo(chave string) bool {
+	for _, c := range f.campos {
+		if c.chave == chave {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formulario) atualizar(m *Modelo, msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case msgSalvo:
+		if msg.form != f {
+			return nil
+		}
+		f.salvando = false
+		if msg.err != nil {
+			f.mostrarErro(msg.err)
+			return nil
+		}
+		m.fechar()
+		return f.concluido(m, msg.resultado)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.fechar()
+			return nil
+		case "tab", "down":
+			return f.focar(f.foco + 1)
+		case "shift+tab", "up":
+			return f.focar(f.foco - 1)
+		case "enter":
+			if f.foco < len(f.campos)-1 {
+				return f.focar(f.foco + 1)
+			}
+			fallthrough
+		case "ctrl+s":
+			return f.enviar()
+		}
+	}
+	var cmd tea.Cmd
+	f.campos[f.foco].entrada, cmd = f.campos[f.foco].entrada.Update(msg)
+	return cmd
+}
+
+func (f *formulario) enviar() tea.Cmd {
+	if f.salvando {
+		return nil
+	}
+	f.salvando, f.erro = true, ""
+	v := f.valores()
+	return func() tea.Msg {
+		resultado, err := f.salvar(v)
+		return msgSalvo{f, resultado, err}
+	}
+}
+
+func (f *formulario) exibir(largura int) string {
+	var s strings.Builder
+	s.WriteString(estiloTitulo.Render(f.titulo) + "\n\n")
+	for i, c := range f.campos {
+		rotulo := estiloRotulo
+		if i == f.foco {
+			rotulo = estiloRotuloFoc
+		}
+		s.WriteString(rotulo.Render(c.rotulo+":") + c.entrada.View())
+		if e, ok := f.erros[c.chave]; ok {
+			s.WriteString("  " + estiloErro.Render("✗ "+e))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	switch {
+	case f.salvando:
+		s.WriteString("Salvando...\n")
+	case f.erro != "":
+		s.WriteString(estiloErro.Render("ERRO: "+f.erro) + "\n")
+	}
+	s.WriteString(estiloAjuda.Render("tab/↑↓ campos • enter próximo campo / salvar • ctrl+s salvar • esc cancelar"))
+	return estiloJanela.Render(s.String())
+}
+
+// confirmacao pede s/n antes de uma operação destrutiva
+type confirmacao struct {
+	pergunta string
+	operacao func() (string, error)
+}
+
+func confirmar(pergunta string, operacao func() (string, error)) *confirmacao {
+	return &confirmacao{pergunta, operacao}
+}
+
+func (c *confirmacao) atualizar(m *Modelo, msg tea.Msg) tea.Cmd {
+	tecla, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch strings.ToLower(tecla.String()) {
+	case "s", "y":
+		m.fechar()
+		return executar(c.operacao)
+	case "n", "esc":
+		m.fechar()
+	}
+	return nil
+}
+
+func (c *confirmacao) exibir(largura int) string {
+	return estiloJanela.Render(c.pergunta + "\n\n" + estiloAjuda.Render("s confirmar • n cancelar"))
+}
+
+// conversão dos textos do formulário; os erros seguem o formato da validação
+// para aparecerem ao lado do campo
+
+// conversor acumula os erros de conversão de um formulário
+type conversor struct {
+	erros validacao.Erros
+}
+
+func (c *conversor) data(v valores, chave string) time.Time {
+	if v[chave] == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02", v[chave])
+	if err != nil {
+		c.erros = append(c.erros, validacao.ErroCampo{Campo: chave, Codigo: validacao.CodigoInvalido, Mensagem: "use o formato AAAA-MM-DD"})
+	}
+	return t
+}
+
+func (c *conversor) inteiro(v valores, chave string) int {
+	if v[chave] == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v[chave])
+	if err != nil {
+		c.erros = append(c.erros, validacao.ErroCampo{Campo: chave, Codigo: validacao.CodigoInvalido, Mensagem: "deve ser um número inteiro"})
+	}
+	return n
+}
+
+// juntar combina os erros de conversão com os da validação do modelo
+func (c *conversor) juntar(err error) error {
+	if len(c.erros) == 0 {
+		return err
+	}
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		// o erro de conversão explica melhor o campo do que o de valor vazio
+		for _, e := range campos {
+			if !c.temCampo(e.Campo) {
+				c.erros = append(c.erros, e)
+			}
+		}
+	}
+	return c.erros
+}
+
+func (c *conversor) temCampo(chave string) bool {
+	for _, e := range c.erros {
+		if e.Campo == chave {
+			return true
+		}
+	}
+	return false
+}
+
+func naoEncontrado(err error) bool {
+	return errors.Is(err, repository.ErrNaoEncontrado)
+}
+
+func data(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func numero(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
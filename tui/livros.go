@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func abaLivros() *aba {
+	a := novaAba("Livros", "título ou ISBN", []table.Column{
+		{Title: "ISBN", Width: 13}, {Title: "Título", Width: 34}, {Title: "Edição", Width: 6},
+		{Title: "Idioma", Width: 6}, {Title: "Autores", Width: 30},
+	})
+	a.listar = func(ctx context.Context, b *servico.Biblioteca, termo string) ([]any, error) {
+		if _, err := validacao.NormalizarISBN(termo); err == nil {
+			return unico(b.ObterLivro(ctx, termo))
+		}
+		return lista(b.ListarLivros(ctx, servico.FiltroLivro{Titulo: termo}))
+	}
+	a.linha = func(r any) table.Row {
+		l := r.(*model.Livro)
+		return table.Row{l.ISBN, l.Titulo, l.Edicao, l.Idioma, nomesAutores(l.Autores)}
+	}
+	a.descrever = func(r any) string {
+		return fmt.Sprintf("livro '%s'", r.(*model.Livro).Titulo)
+	}
+	a.novo = func(m *Modelo) janela {
+		return formularioLivro(m, model.Livro{}, true)
+	}
+	a.editar = func(m *Modelo, r any) janela {
+		return formularioLivro(m, *r.(*model.Livro), false)
+	}
+	a.remover = func(ctx context.Context, b *servico.Biblioteca, r any) error {
+		return b.DeletarLivro(ctx, r.(*model.Livro).ISBN)
+	}
+	a.atalhos = []atalho{
+		{
+			tecla: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "vincular autor")),
+			executar: func(m *Modelo, r any) tea.Cmd {
+				return m.abrir(formularioVincularAutor(m, r.(*model.Livro)))
+			},
+		},
+		{
+			tecla: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "desvincular autor")),
+			executar: func(m *Modelo, r any) tea.Cmd {
+				return m.abrir(formularioDesvincularAutor(m, r.(*model.Livro)))
+			},
+		},
+	}
+	return a
+}
+
+func nomesAutores(autores []model.Autor) string {
+	nomes := make([]string, len(autores))
+	for i, a := range autores {
+		nomes[i] = a.PrimeiroNome + " " + a.Sobrenome
+	}
+	return strings.Join(nomes, "; ")
+}
+
+func formularioLivro(m *Modelo, l model.Livro, criar bool) *formulario {
+	var campos []campoInicial
+	titulo := fmt.Sprintf("Livro %s", l.ISBN)
+	if criar {
+		titulo = "Novo livro"
+		campos = append(campos, campoInicial{"ISBN", "isbn", ""})
+	}
+	obra := ""
+	if l.ObraID != nil {
+		obra = fmt.Sprint(*l.ObraID)
+	}
+	campos = append(campos,
+		campoInicial{"Título", "titulo", l.Titulo},
+		campoInicial{"Edição", "edicao", l.Edicao},
+		campoInicial{"Páginas", "num_paginas", numero(l.NumPaginas)},
+		campoInicial{"CNPJ da editora", "editora_cnpj", l.EditoraCNPJ},
+		campoInicial{"Matrícula do funcionário", "funcionario_matricula", numero(l.FuncionarioMatricula)},
+		campoInicial{"Sistema (CDD/CDU)", "sistema_classificacao", l.SistemaClassificacao},
+		campoInicial{"Nº de classificação", "numero_classificacao", l.NumeroClassificacao},
+		campoInicial{"Obra (ID)", "obra_id", obra},
+		campoInicial{"Idioma", "idioma", l.Idioma},
+	)
+	f := novoFormulario(titulo, campos)
+	f.dica("idioma", "pt, en, es...")
+
+	montar := func(v valores) (model.Livro, *conversor) {
+		c := &conversor{}
+		n := l
+		if criar {
+			n.ISBN = v["isbn"]
+		}
+		n.Titulo, n.Edicao = v["titulo"], v["edicao"]
+		n.NumPaginas = c.inteiro(v, "num_paginas")
+		n.EditoraCNPJ = v["editora_cnpj"]
+		n.FuncionarioMatricula = c.inteiro(v, "funcionario_matricula")
+		n.SistemaClassificacao, n.NumeroClassificacao = v["sistema_classificacao"], v["numero_classificacao"]
+		n.ObraID = nil
+		if id := c.inteiro(v, "obra_id"); id != 0 {
+			n.ObraID = &id
+		}
+		n.Idioma = v["idioma"]
+		return n, c
+	}
+	f.validar = func(v valores) error {
+		n, c := montar(v)
+		return c.juntar(servico.ValidarLivro(n))
+	}
+	f.salvar = func(v valores) (any, error) {
+		n, c := montar(v)
+		if len(c.erros) > 0 {
+			return nil, c.juntar(servico.ValidarLivro(n))
+		}
+		if criar {
+			return m.b.CriarLivro(m.ctx, n)
+		}
+		return m.b.AtualizarLivro(m.ctx, n)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		m.informar(fmt.Sprintf("Livro '%s' salvo.", r.(*model.Livro).Titulo), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
+
+// formularioVincularAutor é o fluxo do menu "Adicionar Autor a um Livro":
+// autores ainda não cadastrados são criados com o nome informado
+func formularioVincularAutor(m *Modelo, l *model.Livro) *formulario {
+	f := novoFormulario(fmt.Sprintf("Vincular autor a '%s'", l.Titulo), []campoInicial{
+		{"ID do autor", "id", ""},
+		{"Primeiro nome", "primeiro_nome", ""},
+		{"Sobrenome", "sobrenome", ""},
+	})
+	f.dica("primeiro_nome", "só para autores novos")
+	f.dica("sobrenome", "só para autores novos")
+	f.salvar = func(v valores) (any, error) {
+		c := &conversor{}
+		a := model.Autor{ID: c.inteiro(v, "id"), PrimeiroNome: v["primeiro_nome"], Sobrenome: v["sobrenome"]}
+		if len(c.erros) > 0 {
+			return nil, c.erros
+		}
+		return m.b.VincularAutor(m.ctx, l.ISBN, a)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		a := r.(*model.Autor)
+		m.informar(fmt.Sprintf("Autor %s %s vinculado a '%s'.", a.PrimeiroNome, a.Sobrenome, l.Titulo), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
+
+func formularioDesvincularAutor(m *Modelo, l *model.Livro) *formulario {
+	f := novoFormulario(fmt.Sprintf("Desvincular autor de '%s'", l.Titulo), []campoInicial{{"ID do autor", "id", ""}})
+	if len(l.Autores) > 0 {
+		ids := make([]string, len(l.Autores))
+		for i, a := range l.Autores {
+			ids[i] = fmt.Sprintf("%d (%s)", a.ID, a.PrimeiroNome)
+		}
+		f.dica("id", strings.Join(ids, ", "))
+	}
+	f.salvar = func(v valores) (any, error) {
+		c := &conversor{}
+		id := c.inteiro(v, "id")
+		if len(c.erros) > 0 {
+			return nil, c.erros
+		}
+		return id, m.b.DesvincularAutor(m.ctx, l.ISBN, id)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		m.informar(fmt.Sprintf("Autor %d desvinculado de '%s'.", r.(int), l.Titulo), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"crud-biblioteca/model"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recibo é o comprovante do empréstimo entregue ao usuário no balcão
+type recibo struct {
+	emprestimo model.Emprestimo
+	texto      string
+	salvo      string
+	erro       error
+}
+
+// abrirRecibo busca o usuário do empréstimo e mostra o recibo
+func abrirRecibo(m *Modelo, e model.Emprestimo) tea.Cmd {
+	return func() tea.Msg {
+		u, err := m.b.ObterUsuario(m.ctx, e.ClienteUsuarioCPF)
+		if err != nil {
+			return msgStatus{err: err}
+		}
+		return msgAbrir{&recibo{emprestimo: e, texto: textoRecibo(e, *u)}}
+	}
+}
+
+func textoRecibo(e model.Emprestimo, u model.Usuario) string {
+	var s strings.Builder
+	fmt.Fprintln(&s, "BIBLIOTECA — RECIBO DE EMPRÉSTIMO")
+	fmt.Fprintln(&s, strings.Repeat("-", 34))
+	fmt.Fprintf(&s, "Empréstimo: %d\n", e.ID)
+	fmt.Fprintf(&s, "Data:       %s\n", e.DataEmprestimo.Format("02/01/2006 15:04"))
+	fmt.Fprintf(&s, "Usuário:    %s %s\n", u.PrimeiroNome, u.Sobrenome)
+	fmt.Fprintf(&s, "CPF:        %s\n", validacao.FormatarCPF(u.CPF))
+	fmt.Fprintf(&s, "Categoria:  %s (até %d livros)\n", u.Categoria, u.LimiteLivros())
+	fmt.Fprintf(&s, "Livros:     %d\n", e.QuantLivros)
+	fmt.Fprintln(&s, strings.Repeat("-", 34))
+	fmt.Fprint(&s, "Assinatura: ____________________")
+	return s.String()
+}
+
+func (r *recibo) arquivo() string {
+	return fmt.Sprintf("recibo_emprestimo_%d.txt", r.emprestimo.ID)
+}
+
+func (r *recibo) atualizar(m *Modelo, msg tea.Msg) tea.Cmd {
+	tecla, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch tecla.String() {
+	case "p":
+		r.erro = os.WriteFile(r.arquivo(), []byte(r.texto+"\n"), 0o644)
+		if r.erro == nil {
+			r.salvo = r.arquivo()
+		}
+	case "esc", "enter", "q":
+		m.fechar()
+	}
+	return nil
+}
+
+func (r *recibo) exibir(largura int) string {
+	s := r.texto + "\n\n"
+	switch {
+	case r.erro != nil:
+		s += estiloErro.Render("ERRO: "+r.erro.Error()) + "\n"
+	case r.salvo != "":
+		s += estiloSucesso.Render("SUCESSO: recibo gravado em "+r.salvo) + "\n"
+	}
+	return estiloJanela.Render(s + estiloAjuda.Render("p imprimir em arquivo • esc fechar"))
+}
@@ -0,0 +1,283 @@
+// Package tui implementa a interface de terminal em tela cheia do balcão de
+// circulação: abas por entidade, tabelas com busca, formulários com validação
+// campo a campo e o fluxo "localizar usuário → emprestar → imprimir recibo".
+// As operações são as do pacote servico, as mesmas da API e da linha de comando.
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Executar abre a interface e só retorna quando o operador sai. menu indica
+// que ele pediu o menu numerado, para as operações que a interface não cobre
+func Executar(ctx context.Context, b *servico.Biblioteca) (menu bool, err error) {
+	m := novoModelo(ctx, b)
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+		return false, err
+	}
+	return m.menu, nil
+}
+
+// Modelo é o estado da interface. Janelas (formulário, confirmação, recibo)
+// ficam sobre a aba atual e recebem as teclas enquanto estão abertas
+type Modelo struct {
+	ctx  context.Context
+	b    *servico.Biblioteca
+	abas []*aba
+	// índice da aba atual
+	atual   int
+	janela  janela
+	status  string
+	erro    bool
+	largura int
+	altura  int
+	menu    bool
+}
+
+// janela é um formulário, uma confirmação ou um recibo aberto sobre a aba
+type janela interface {
+	atualizar(m *Modelo, msg tea.Msg) tea.Cmd
+	exibir(largura int) string
+}
+
+func novoModelo(ctx context.Context, b *servico.Biblioteca) *Modelo {
+	return &Modelo{
+		ctx:  ctx,
+		b:    b,
+		abas: []*aba{abaUsuarios(), abaLivros(), abaAutores(), abaEmprestimos()},
+	}
+}
+
+var teclas = struct {
+	proxima, anterior, buscar, novo, editar, remover, recarregar, menu, sair key.Binding
+}{
+	proxima:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "próxima aba")),
+	anterior:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "aba anterior")),
+	buscar:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "buscar")),
+	novo:       key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "novo")),
+	editar:     key.NewBinding(key.WithKeys("e", "enter"), key.WithHelp("e", "editar")),
+	remover:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "excluir")),
+	recarregar: key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "recarregar")),
+	menu:       key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "menu numerado")),
+	sair:       key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "sair")),
+}
+
+// mensagens trocadas entre os comandos assíncronos e o Update
+
+// msgLista traz o resultado da busca de uma aba
+type msgLista struct {
+	aba       *aba
+	registros []any
+	err       error
+}
+
+// msgStatus mostra uma mensagem na barra de status e recarrega a aba atual
+type msgStatus struct {
+	texto string
+	err   error
+}
+
+// msgAbrir abre uma janela preparada fora do laço de eventos
+type msgAbrir struct {
+	janela janela
+}
+
+func (m *Modelo) Init() tea.Cmd {
+	return m.abas[m.atual].carregar(m)
+}
+
+func (m *Modelo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.largura, m.altura = msg.Width, msg.Height
+		for _, a := range m.abas {
+			a.redimensionar(msg.Width, msg.Height-linhasFixas)
+		}
+		return m, nil
+	case msgLista:
+		msg.aba.exibirRegistros(msg.registros)
+		if msg.err != nil {
+			m.informar("", msg.err)
+		}
+		return m, nil
+	case msgStatus:
+		m.informar(msg.texto, msg.err)
+		return m, m.abas[m.atual].carregar(m)
+	case msgAbrir:
+		return m, m.abrir(msg.janela)
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	if m.janela != nil {
+		return m, m.janela.atualizar(m, msg)
+	}
+	a := m.abas[m.atual]
+	if a.buscando() {
+		return m, a.atualizarBusca(m, msg)
+	}
+
+	tecla, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(tecla, teclas.sair):
+		return m, tea.Quit
+	case key.Matches(tecla, teclas.menu):
+		m.menu = true
+		return m, tea.Quit
+	case key.Matches(tecla, teclas.proxima):
+		return m, m.trocarAba(m.atual + 1)
+	case key.Matches(tecla, teclas.anterior):
+		return m, m.trocarAba(m.atual + len(m.abas) - 1)
+	case key.Matches(tecla, teclas.buscar):
+		return m, a.iniciarBusca()
+	case key.Matches(tecla, teclas.recarregar):
+		return m, a.carregar(m)
+	case key.Matches(tecla, teclas.novo):
+		return m, m.abrir(a.novo(m))
+	case key.Matches(tecla, teclas.editar):
+		if r := a.selecionado(); r != nil {
+			return m, m.abrir(a.editar(m, r))
+		}
+		return m, nil
+	case key.Matches(tecla, teclas.remover):
+		if r := a.selecionado(); r != nil {
+			return m, m.abrir(confirmar(fmt.Sprintf("Excluir %s?", a.descrever(r)), func() (string, error) {
+				return fmt.Sprintf("%s excluído.", a.descrever(r)), a.remover(m.ctx, m.b, r)
+			}))
+		}
+		return m, nil
+	}
+	for _, at := range a.atalhos {
+		if key.Matches(tecla, at.tecla) {
+			if r := a.selecionado(); r != nil {
+				return m, at.executar(m, r)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	a.tabela, cmd = a.tabela.Update(msg)
+	return m, cmd
+}
+
+func (m *Modelo) trocarAba(i int) tea.Cmd {
+	m.atual = i % len(m.abas)
+	m.status = ""
+	return m.abas[m.atual].carregar(m)
+}
+
+// abrir mostra a janela sobre a aba atual; janelas nil são ignoradas
+func (m *Modelo) abrir(j janela) tea.Cmd {
+	if j == nil {
+		return nil
+	}
+	m.janela = j
+	if f, ok := j.(*formulario); ok {
+		return f.focar(0)
+	}
+	return nil
+}
+
+func (m *Modelo) fechar() {
+	m.janela = nil
+}
+
+// informar mostra o resultado de uma operação na barra de status
+func (m *Modelo) informar(texto string, err error) {
+	m.status, m.erro = texto, err != nil
+	if err != nil {
+		m.status = descreverErro(err)
+	}
+}
+
+// descreverErro resume o erro em uma linha; os erros de validação aparecem
+// também ao lado dos campos do formulário
+func descreverErro(err error) string {
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		mensagens := make([]string, len(campos))
+		for i, c := range campos {
+			mensagens[i] = c.Mensagem
+		}
+		return "dados inválidos: " + strings.Join(mensagens, "; ")
+	}
+	return err.Error()
+}
+
+// linhasFixas é a altura ocupada pelas abas, pela busca, pelo status e pela ajuda
+const linhasFixas = 8
+
+var (
+	estiloAba       = lipgloss.NewStyle().Padding(0, 2)
+	estiloAbaAtiva  = estiloAba.Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("6"))
+	estiloTitulo    = lipgloss.NewStyle().Bold(true)
+	estiloErro      = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	estiloSucesso   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	estiloAjuda     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	estiloJanela    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	estiloRotulo    = lipgloss.NewStyle().Width(24)
+	estiloRotuloFoc = estiloRotulo.Bold(true).Foreground(lipgloss.Color("6"))
+)
+
+func (m *Modelo) View() string {
+	var s strings.Builder
+	nomes := make([]string, len(m.abas))
+	for i, a := range m.abas {
+		estilo := estiloAba
+		if i == m.atual {
+			estilo = estiloAbaAtiva
+		}
+		nomes[i] = estilo.Render(a.titulo)
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, nomes...) + "\n\n")
+
+	if m.janela != nil {
+		s.WriteString(m.janela.exibir(m.largura) + "\n")
+	} else {
+		s.WriteString(m.abas[m.atual].exibir() + "\n")
+	}
+
+	switch {
+	case m.status == "":
+		s.WriteString("\n")
+	case m.erro:
+		s.WriteString(estiloErro.Render("ERRO: "+m.status) + "\n")
+	default:
+		s.WriteString(estiloSucesso.Render(m.status) + "\n")
+	}
+	if m.janela == nil {
+		s.WriteString(estiloAjuda.Render(m.abas[m.atual].ajuda()))
+	}
+	return s.String()
+}
+
+// ajuda monta a linha de atalhos a partir das teclas informadas
+func ajuda(teclas ...key.Binding) string {
+	partes := make([]string, 0, len(teclas))
+	for _, t := range teclas {
+		h := t.Help()
+		partes = append(partes, h.Key+" "+h.Desc)
+	}
+	return strings.Join(partes, " • ")
+}
+
+// executar roda a operação fora do laço de eventos e informa o resultado
+func executar(f func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		texto, err := f()
+		return msgStatus{texto, err}
+	}
+}
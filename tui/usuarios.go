@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func abaUsuarios() *aba {
+	a := novaAba("Usuários", "nome ou CPF", []table.Column{
+		{Title: "CPF", Width: 14}, {Title: "Nome", Width: 30}, {Title: "Categoria", Width: 10},
+		{Title: "Matrícula", Width: 10}, {Title: "Validade", Width: 10}, {Title: "Responsável", Width: 14},
+	})
+	a.listar = func(ctx context.Context, b *servico.Biblioteca, termo string) ([]any, error) {
+		if _, err := validacao.NormalizarCPF(termo); err == nil {
+			return unico(b.ObterUsuario(ctx, termo))
+		}
+		return lista(b.ListarUsuarios(ctx, repository.FiltroUsuario{Nome: termo}))
+	}
+	a.linha = func(r any) table.Row {
+		u := r.(*model.Usuario)
+		responsavel := ""
+		if u.ResponsavelCPF != "" {
+			responsavel = validacao.FormatarCPF(u.ResponsavelCPF)
+		}
+		return table.Row{validacao.FormatarCPF(u.CPF), u.PrimeiroNome + " " + u.Sobrenome, string(u.Categoria),
+			u.Matricula, data(u.ValidadeVinculo), responsavel}
+	}
+	a.descrever = func(r any) string {
+		u := r.(*model.Usuario)
+		return fmt.Sprintf("usuário %s %s", u.PrimeiroNome, u.Sobrenome)
+	}
+	a.novo = func(m *Modelo) janela {
+		return formularioUsuario(m, model.Usuario{}, true)
+	}
+	a.editar = func(m *Modelo, r any) janela {
+		return formularioUsuario(m, *r.(*model.Usuario), false)
+	}
+	a.remover = func(ctx context.Context, b *servico.Biblioteca, r any) error {
+		return b.DeletarUsuario(ctx, r.(*model.Usuario).CPF)
+	}
+	a.atalhos = []atalho{{
+		tecla: key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "emprestar")),
+		executar: func(m *Modelo, r any) tea.Cmd {
+			return iniciarEmprestimo(m, r.(*model.Usuario).CPF)
+		},
+	}}
+	return a
+}
+
+func formularioUsuario(m *Modelo, u model.Usuario, criar bool) *formulario {
+	var campos []campoInicial
+	titulo := fmt.Sprintf("Usuário %s", validacao.FormatarCPF(u.CPF))
+	if criar {
+		titulo = "Novo usuário"
+		campos = append(campos, campoInicial{"CPF", "cpf", ""})
+	}
+	e := u.Endereco
+	campos = append(campos,
+		campoInicial{"Primeiro nome", "primeiro_nome", u.PrimeiroNome},
+		campoInicial{"Sobrenome", "sobrenome", u.Sobrenome},
+		campoInicial{"Nascimento (AAAA-MM-DD)", "data_nascimento", data(u.DataNascimento)},
+		campoInicial{"E-mail", "email", u.Email},
+		campoInicial{"Telefone", "telefone", u.Telefone},
+		campoInicial{"Categoria", "categoria", string(u.Categoria)},
+		campoInicial{"Matrícula", "matricula", u.Matricula},
+		campoInicial{"Validade do vínculo", "validade_vinculo", data(u.ValidadeVinculo)},
+		campoInicial{"CPF do responsável", "responsavel_cpf", u.ResponsavelCPF},
+		campoInicial{"Logradouro", "endereco.logradouro", e.Logradouro},
+		campoInicial{"Número", "endereco.numero", e.Numero},
+		campoInicial{"Bairro", "endereco.bairro", e.Bairro},
+		campoInicial{"Cidade", "endereco.cidade", e.Cidade},
+		campoInicial{"UF", "endereco.uf", e.UF},
+		campoInicial{"CEP", "endereco.cep", e.CEP},
+	)
+	f := novoFormulario(titulo, campos)
+	f.dica("categoria", "graduacao, pos, docente, tecnico ou externo")
+
+	montar := func(v valores) (model.Usuario, *conversor) {
+		c := &conversor{}
+		n := u
+		if criar {
+			n.CPF = v["cpf"]
+		}
+		n.PrimeiroNome, n.Sobrenome = v["primeiro_nome"], v["sobrenome"]
+		n.DataNascimento = c.data(v, "data_nascimento")
+		n.Email, n.Telefone = v["email"], v["telefone"]
+		n.Categoria = model.CategoriaUsuario(v["categoria"])
+		n.Matricula = v["matricula"]
+		n.ValidadeVinculo = c.data(v, "validade_vinculo")
+		n.ResponsavelCPF = v["responsavel_cpf"]
+		n.Endereco = model.Endereco{Logradouro: v["endereco.logradouro"], Numero: v["endereco.numero"], Bairro: v["endereco.bairro"],
+			Cidade: v["endereco.cidade"], UF: v["endereco.uf"], CEP: v["endereco.cep"]}
+		return n, c
+	}
+	f.validar = func(v valores) error {
+		n, c := montar(v)
+		return c.juntar(servico.ValidarUsuario(n))
+	}
+	f.salvar = func(v valores) (any, error) {
+		n, c := montar(v)
+		if len(c.erros) > 0 {
+			return nil, c.juntar(servico.ValidarUsuario(n))
+		}
+		if criar {
+			return m.b.CriarUsuario(m.ctx, n)
+		}
+		return m.b.AtualizarUsuario(m.ctx, n)
+	}
+	f.concluido = func(m *Modelo, r any) tea.Cmd {
+		u := r.(*model.Usuario)
+		m.informar(fmt.Sprintf("Usuário %s %s salvo.", u.PrimeiroNome, u.Sobrenome), nil)
+		return m.abas[m.atual].carregar(m)
+	}
+	return f
+}
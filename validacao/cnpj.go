@@ -0,0 +1,79 @@
+package validacao
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCNPJTamanho   = errors.New("CNPJ deve ter 14 caracteres")
+	ErrCNPJCaractere = errors.New("CNPJ deve conter letras maiúsculas ou dígitos nas 12 primeiras posições e dígitos no verificador")
+	ErrCNPJRepetido  = errors.New("CNPJ com todos os caracteres iguais é inválido")
+	ErrCNPJDigito    = errors.New("dígito verificador do CNPJ inválido")
+)
+
+// pesos do cálculo dos dígitos verificadores, aplicados da direita para a esquerda
+var pesosCNPJ = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// NormalizarCNPJ valida o CNPJ e o retorna sem máscara, com 14 caracteres.
+// Aceita o CNPJ numérico tradicional e o CNPJ alfanumérico da Receita Federal
+// (letras nas 12 primeiras posições), com ou sem pontuação e em minúsculas
+func NormalizarCNPJ(cnpj string) (string, error) {
+	var limpo strings.Builder
+	for _, c := range strings.ToUpper(strings.TrimSpace(cnpj)) {
+		switch {
+		case c >= '0' && c <= '9', c >= 'A' && c <= 'Z':
+			limpo.WriteRune(c)
+		case c == '.' || c == '/' || c == '-' || c == ' ':
+			// separadores da máscara são descartados
+		default:
+			return "", ErrCNPJCaractere
+		}
+	}
+	d := limpo.String()
+	if len(d) != 14 {
+		return "", ErrCNPJTamanho
+	}
+	if d[12] < '0' || d[12] > '9' || d[13] < '0' || d[13] > '9' {
+		return "", ErrCNPJCaractere
+	}
+	if strings.Count(d, d[:1]) == 14 {
+		return "", ErrCNPJRepetido
+	}
+	if digitoCNPJ(d[:12]) != d[12] || digitoCNPJ(d[:13]) != d[13] {
+		return "", ErrCNPJDigito
+	}
+	return d, nil
+}
+
+// ValidarCNPJ informa se o CNPJ, numérico ou alfanumérico, é válido
+func ValidarCNPJ(cnpj string) error {
+	_, err := NormalizarCNPJ(cnpj)
+	return err
+}
+
+// FormatarCNPJ aplica a máscara 00.000.000/0000-00 para exibição. CNPJs
+// inválidos são retornados sem alteração
+func FormatarCNPJ(cnpj string) string {
+	d, err := NormalizarCNPJ(cnpj)
+	if err != nil {
+		return cnpj
+	}
+	return d[:2] + "." + d[2:5] + "." + d[5:8] + "/" + d[8:12] + "-" + d[12:]
+}
+
+// digitoCNPJ calcula o dígito verificador para os 12 ou 13 primeiros
+// caracteres. Cada caractere vale seu código ASCII menos 48, de modo que os
+// dígitos mantêm o valor tradicional e as letras vão de 17 ('A') a 42 ('Z')
+func digitoCNPJ(base string) byte {
+	soma := 0
+	pesos := pesosCNPJ[len(pesosCNPJ)-len(base):]
+	for i := 0; i < len(base); i++ {
+		soma += int(base[i]-'0') * pesos[i]
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
@@ -0,0 +1,57 @@
+package validacao
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarCNPJ(t *testing.T) {
+	casos := []struct {
+		nome     string
+		entrada  string
+		esperado string
+		erro     error
+	}{
+		{"numérico sem máscara", "11222333000181", "11222333000181", nil},
+		{"numérico com máscara", "11.222.333/0001-81", "11222333000181", nil},
+		{"alfanumérico sem máscara", "12ABC34501DE35", "12ABC34501DE35", nil},
+		{"alfanumérico com máscara", "12.ABC.345/01DE-35", "12ABC34501DE35", nil},
+		{"alfanumérico em minúsculas", " 12.abc.345/01de-35 ", "12ABC34501DE35", nil},
+		{"numérico dígito errado", "11.222.333/0001-82", "", ErrCNPJDigito},
+		{"alfanumérico dígito errado", "12.ABC.345/01DE-36", "", ErrCNPJDigito},
+		{"letra trocada", "12.ABD.345/01DE-35", "", ErrCNPJDigito},
+		{"dígitos repetidos", "11.111.111/1111-11", "", ErrCNPJRepetido},
+		{"zeros", "00000000000000", "", ErrCNPJRepetido},
+		{"letra no verificador", "12ABC34501DE3A", "", ErrCNPJCaractere},
+		{"letras repetidas", "AAAAAAAAAAAAAA", "", ErrCNPJCaractere},
+		{"caractere inválido", "12.ABC.345_01DE-35", "", ErrCNPJCaractere},
+		{"acento", "12.ÁBC.345/01DE-35", "", ErrCNPJCaractere},
+		{"curto", "1122233300018", "", ErrCNPJTamanho},
+		{"longo", "112223330001810", "", ErrCNPJTamanho},
+		{"vazio", "", "", ErrCNPJTamanho},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			obtido, err := NormalizarCNPJ(c.entrada)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("NormalizarCNPJ(%q): erro %v, esperado %v", c.entrada, err, c.erro)
+			}
+			if obtido != c.esperado {
+				t.Errorf("NormalizarCNPJ(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestFormatarCNPJ(t *testing.T) {
+	casos := []struct{ entrada, esperado string }{
+		{"11222333000181", "11.222.333/0001-81"},
+		{"12abc34501de35", "12.ABC.345/01DE-35"},
+		{"11222333000182", "11222333000182"}, // inválido: sem alteração
+	}
+	for _, c := range casos {
+		if obtido := FormatarCNPJ(c.entrada); obtido != c.esperado {
+			t.Errorf("FormatarCNPJ(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+		}
+	}
+}
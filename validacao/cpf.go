@@ -0,0 +1,74 @@
+// Package validacao reúne a validação e a normalização dos documentos e
+// identificadores usados pela biblioteca.
+package validacao
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCPFTamanho   = errors.New("CPF deve ter 11 dígitos")
+	ErrCPFCaractere = errors.New("CPF deve conter apenas dígitos, pontos e hífen")
+	ErrCPFRepetido  = errors.New("CPF com todos os dígitos iguais é inválido")
+	ErrCPFDigito    = errors.New("dígito verificador do CPF inválido")
+)
+
+// NormalizarCPF valida o CPF e o retorna apenas com os 11 dígitos, formato
+// usado para armazenamento. Aceita a entrada com ou sem máscara
+// ("123.456.789-09" ou "12345678909")
+func NormalizarCPF(cpf string) (string, error) {
+	var digitos strings.Builder
+	for _, c := range strings.TrimSpace(cpf) {
+		switch {
+		case c >= '0' && c <= '9':
+			digitos.WriteRune(c)
+		case c == '.' || c == '-' || c == ' ':
+			// separadores da máscara são descartados
+		default:
+			return "", ErrCPFCaractere
+		}
+	}
+	d := digitos.String()
+	if len(d) != 11 {
+		return "", ErrCPFTamanho
+	}
+	if strings.Count(d, d[:1]) == 11 {
+		return "", ErrCPFRepetido
+	}
+	if digitoCPF(d[:9]) != d[9] || digitoCPF(d[:10]) != d[10] {
+		return "", ErrCPFDigito
+	}
+	return d, nil
+}
+
+// ValidarCPF informa se o CPF, com ou sem máscara, é válido
+func ValidarCPF(cpf string) error {
+	_, err := NormalizarCPF(cpf)
+	return err
+}
+
+// FormatarCPF aplica a máscara 000.000.000-00 para exibição. CPFs inválidos
+// são retornados sem alteração
+func FormatarCPF(cpf string) string {
+	d, err := NormalizarCPF(cpf)
+	if err != nil {
+		return cpf
+	}
+	return d[:3] + "." + d[3:6] + "." + d[6:9] + "-" + d[9:]
+}
+
+// digitoCPF calcula o dígito verificador para os 9 ou 10 primeiros dígitos
+func digitoCPF(base string) byte {
+	soma := 0
+	peso := len(base) + 1
+	for i := 0; i < len(base); i++ {
+		soma += int(base[i]-'0') * peso
+		peso--
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
@@ -0,0 +1,53 @@
+package validacao
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarCPF(t *testing.T) {
+	casos := []struct {
+		nome     string
+		entrada  string
+		esperado string
+		erro     error
+	}{
+		{"válido sem máscara", "52998224725", "52998224725", nil},
+		{"válido com máscara", "529.982.247-25", "52998224725", nil},
+		{"máscara com espaços", " 111.444.777-35 ", "11144477735", nil},
+		{"dígito verificador errado", "529.982.247-26", "", ErrCPFDigito},
+		{"segundo dígito errado", "11144477734", "", ErrCPFDigito},
+		{"dígitos repetidos", "111.111.111-11", "", ErrCPFRepetido},
+		{"zeros", "00000000000", "", ErrCPFRepetido},
+		{"curto", "5299822472", "", ErrCPFTamanho},
+		{"longo", "529982247250", "", ErrCPFTamanho},
+		{"vazio", "", "", ErrCPFTamanho},
+		{"letras", "529.982.247-2A", "", ErrCPFCaractere},
+		{"barra", "529982247/25", "", ErrCPFCaractere},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			obtido, err := NormalizarCPF(c.entrada)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("NormalizarCPF(%q): erro %v, esperado %v", c.entrada, err, c.erro)
+			}
+			if obtido != c.esperado {
+				t.Errorf("NormalizarCPF(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestFormatarCPF(t *testing.T) {
+	casos := []struct{ entrada, esperado string }{
+		{"52998224725", "529.982.247-25"},
+		{"529.982.247-25", "529.982.247-25"},
+		{"52998224726", "52998224726"}, // inválido: sem alteração
+		{"abc", "abc"},
+	}
+	for _, c := range casos {
+		if obtido := FormatarCPF(c.entrada); obtido != c.esperado {
+			t.Errorf("FormatarCPF(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package validacao
+
+import (
+	"errors"
+	"strings"
+)
+
+// códigos de erro usados em ErroCampo.Codigo
+const (
+	CodigoObrigatorio = "obrigatorio"
+	CodigoInvalido    = "invalido"
+	CodigoNegativo    = "negativo"
+	CodigoFuturo      = "data_futura"
+	CodigoForaLimite  = "fora_do_limite"
+)
+
+// ErroCampo descreve um problema de validação em um campo de um modelo.
+// Campo usa o nome do campo em snake_case, como nas colunas do banco (ex.: "num_paginas")
+type ErroCampo struct {
+	Campo    string `json:"campo"`
+	Codigo   string `json:"codigo"`
+	Mensagem string `json:"mensagem"`
+}
+
+func (e ErroCampo) Error() string {
+	return e.Campo + ": " + e.Mensagem
+}
+
+// Erros reúne todos os problemas encontrados na validação de um modelo
+type Erros []ErroCampo
+
+func (e Erros) Error() string {
+	msgs := make([]string, len(e))
+	for i, c := range e {
+		msgs[i] = c.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ErrosDeCampo extrai os erros por campo de um erro retornado pelas funções Validar*
+func ErrosDeCampo(err error) (Erros, bool) {
+	var erros Erros
+	ok := errors.As(err, &erros)
+	return erros, ok
+}
+
+// coletor acumula os erros durante a validação de um modelo
+type coletor struct {
+	erros Erros
+}
+
+func (c *coletor) add(campo, codigo, mensagem string) {
+	c.erros = append(c.erros, ErroCampo{Campo: campo, Codigo: codigo, Mensagem: mensagem})
+}
+
+func (c *coletor) obrigatorio(campo, valor, mensagem string) bool {
+	if strings.TrimSpace(valor) == "" {
+		c.add(campo, CodigoObrigatorio, mensagem)
+		return false
+	}
+	return true
+}
+
+// documento registra o erro de uma validação de documento (CPF, ISBN, CNPJ...)
+func (c *coletor) documento(campo string, err error) {
+	if err != nil {
+		c.add(campo, CodigoInvalido, err.Error())
+	}
+}
+
+// resultado devolve nil quando não há erros, evitando um error não nulo com lista vazia
+func (c *coletor) resultado() error {
+	if len(c.erros) == 0 {
+		return nil
+	}
+	return c.erros
+}
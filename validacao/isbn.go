@@ -0,0 +1,145 @@
+package validacao
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrISBNTamanho   = errors.New("ISBN deve ter 10 ou 13 dígitos")
+	ErrISBNCaractere = errors.New("ISBN deve conter apenas dígitos, hífens e, no ISBN-10, 'X' no dígito verificador")
+	ErrISBNPrefixo   = errors.New("ISBN-13 deve começar com 978 ou 979")
+	ErrISBNDigito    = errors.New("dígito verificador do ISBN inválido")
+)
+
+// limparISBN remove hífens e espaços e converte o 'x' final para maiúsculo
+func limparISBN(isbn string) string {
+	isbn = strings.TrimSpace(isbn)
+	isbn = strings.TrimPrefix(strings.TrimPrefix(isbn, "ISBN"), "isbn")
+	isbn = strings.TrimSpace(strings.TrimPrefix(isbn, ":"))
+	isbn = strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	return strings.ToUpper(isbn)
+}
+
+// NormalizarISBN valida um ISBN-10 ou ISBN-13, com ou sem hífens, e o
+// retorna como ISBN-13 somente com dígitos, formato usado como chave do livro
+func NormalizarISBN(isbn string) (string, error) {
+	d := limparISBN(isbn)
+	switch len(d) {
+	case 10:
+		if err := validarISBN10(d); err != nil {
+			return "", err
+		}
+		return isbn10Para13(d), nil
+	case 13:
+		if err := validarISBN13(d); err != nil {
+			return "", err
+		}
+		return d, nil
+	default:
+		return "", ErrISBNTamanho
+	}
+}
+
+// ValidarISBN informa se o ISBN-10 ou ISBN-13 é válido
+func ValidarISBN(isbn string) error {
+	_, err := NormalizarISBN(isbn)
+	return err
+}
+
+// ISBN13ParaISBN10 converte um ISBN-13 de prefixo 978 para o ISBN-10
+// equivalente. ISBNs de prefixo 979 não têm forma de 10 dígitos
+func ISBN13ParaISBN10(isbn string) (string, bool) {
+	d, err := NormalizarISBN(isbn)
+	if err != nil || !strings.HasPrefix(d, "978") {
+		return "", false
+	}
+	base := d[3:12]
+	return base + string(digitoISBN10(base)), true
+}
+
+// FormasISBN retorna as grafias pelas quais um livro pode estar gravado:
+// o ISBN-13 canônico, o ISBN-10 equivalente e o texto digitado, sem repetições.
+// Permite localizar registros anteriores à normalização
+func FormasISBN(isbn string) []string {
+	var formas []string
+	adicionar := func(f string) {
+		for _, existente := range formas {
+			if existente == f {
+				return
+			}
+		}
+		formas = append(formas, f)
+	}
+
+	if d, err := NormalizarISBN(isbn); err == nil {
+		adicionar(d)
+		if d10, ok := ISBN13ParaISBN10(d); ok {
+			adicionar(d10)
+		}
+	}
+	adicionar(limparISBN(isbn))
+	adicionar(strings.TrimSpace(isbn))
+	return formas
+}
+
+func validarISBN10(d string) error {
+	for i := 0; i < 9; i++ {
+		if d[i] < '0' || d[i] > '9' {
+			return ErrISBNCaractere
+		}
+	}
+	if (d[9] < '0' || d[9] > '9') && d[9] != 'X' {
+		return ErrISBNCaractere
+	}
+	if digitoISBN10(d[:9]) != d[9] {
+		return ErrISBNDigito
+	}
+	return nil
+}
+
+func validarISBN13(d string) error {
+	for i := 0; i < 13; i++ {
+		if d[i] < '0' || d[i] > '9' {
+			return ErrISBNCaractere
+		}
+	}
+	if !strings.HasPrefix(d, "978") && !strings.HasPrefix(d, "979") {
+		return ErrISBNPrefixo
+	}
+	if digitoISBN13(d[:12]) != d[12] {
+		return ErrISBNDigito
+	}
+	return nil
+}
+
+func isbn10Para13(d string) string {
+	base := "978" + d[:9]
+	return base + string(digitoISBN13(base))
+}
+
+// digitoISBN10 calcula o dígito verificador (módulo 11, pesos 10 a 2)
+func digitoISBN10(base string) byte {
+	soma := 0
+	for i := 0; i < 9; i++ {
+		soma += int(base[i]-'0') * (10 - i)
+	}
+	resto := (11 - soma%11) % 11
+	if resto == 10 {
+		return 'X'
+	}
+	return byte('0' + resto)
+}
+
+// digitoISBN13 calcula o dígito verificador (módulo 10, pesos alternados 1 e 3)
+func digitoISBN13(base string) byte {
+	soma := 0
+	for i := 0; i < 12; i++ {
+		peso := 1
+		if i%2 == 1 {
+			peso = 3
+		}
+		soma += int(base[i]-'0') * peso
+	}
+	return byte('0' + (10-soma%10)%10)
+}
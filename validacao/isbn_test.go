@@ -0,0 +1,80 @@
+package validacao
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNormalizarISBN(t *testing.T) {
+	casos := []struct {
+		nome     string
+		entrada  string
+		esperado string
+		erro     error
+	}{
+		{"ISBN-13", "9788535902778", "9788535902778", nil},
+		{"ISBN-13 com hífens", "978-85-359-0277-8", "9788535902778", nil},
+		{"ISBN-13 com prefixo", "ISBN: 978-85-359-0277-8", "9788535902778", nil},
+		{"ISBN-13 de prefixo 979", "979-10-6662346-3", "9791066623463", nil},
+		{"ISBN-10 convertido", "85-359-0277-5", "9788535902778", nil},
+		{"ISBN-10 com X", "0-8044-2957-X", "9780804429573", nil},
+		{"ISBN-10 com x minúsculo", "080442957x", "9780804429573", nil},
+		{"ISBN-13 dígito errado", "9788535902779", "", ErrISBNDigito},
+		{"ISBN-10 dígito errado", "8535902776", "", ErrISBNDigito},
+		{"prefixo inválido", "9778535902770", "", ErrISBNPrefixo},
+		{"X fora do verificador", "08044295X7", "", ErrISBNCaractere},
+		{"X no ISBN-13", "978853590277X", "", ErrISBNCaractere},
+		{"letras", "97885359A2778", "", ErrISBNCaractere},
+		{"curto", "853590277", "", ErrISBNTamanho},
+		{"longo", "97885359027780", "", ErrISBNTamanho},
+		{"vazio", "", "", ErrISBNTamanho},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			obtido, err := NormalizarISBN(c.entrada)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("NormalizarISBN(%q): erro %v, esperado %v", c.entrada, err, c.erro)
+			}
+			if obtido != c.esperado {
+				t.Errorf("NormalizarISBN(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestISBN13ParaISBN10(t *testing.T) {
+	casos := []struct {
+		entrada, esperado string
+		ok                bool
+	}{
+		{"9788535902778", "8535902775", true},
+		{"978-0-8044-2957-3", "080442957X", true},
+		{"8535902775", "8535902775", true},
+		{"9791066623463", "", false}, // 979 não tem forma de 10 dígitos
+		{"9788535902779", "", false},
+	}
+	for _, c := range casos {
+		obtido, ok := ISBN13ParaISBN10(c.entrada)
+		if obtido != c.esperado || ok != c.ok {
+			t.Errorf("ISBN13ParaISBN10(%q) = %q, %v; esperado %q, %v", c.entrada, obtido, ok, c.esperado, c.ok)
+		}
+	}
+}
+
+func TestFormasISBN(t *testing.T) {
+	casos := []struct {
+		entrada  string
+		esperado []string
+	}{
+		{"978-85-359-0277-8", []string{"9788535902778", "8535902775", "978-85-359-0277-8"}},
+		{"8535902775", []string{"9788535902778", "8535902775"}},
+		{"9791066623463", []string{"9791066623463"}},
+		{" abc-1 ", []string{"ABC1", "abc-1"}},
+	}
+	for _, c := range casos {
+		if obtido := FormasISBN(c.entrada); !slices.Equal(obtido, c.esperado) {
+			t.Errorf("FormasISBN(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+		}
+	}
+}
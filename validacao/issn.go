@@ -0,0 +1,43 @@
+package validacao
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrISSNFormato = errors.New("ISSN deve ter 8 caracteres no formato 0000-000X")
+	ErrISSNDigito  = errors.New("dígito verificador do ISSN inválido")
+)
+
+// NormalizarISSN valida o ISSN e o retorna no formato 0000-000X
+func NormalizarISSN(issn string) (string, error) {
+	d := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(issn)))
+	if len(d) != 8 {
+		return "", ErrISSNFormato
+	}
+	soma := 0
+	for i := 0; i < 7; i++ {
+		if d[i] < '0' || d[i] > '9' {
+			return "", ErrISSNFormato
+		}
+		soma += int(d[i]-'0') * (8 - i)
+	}
+	esperado := byte('0' + (11-soma%11)%11)
+	if esperado == '0'+10 {
+		esperado = 'X'
+	}
+	if d[7] != esperado {
+		if (d[7] < '0' || d[7] > '9') && d[7] != 'X' {
+			return "", ErrISSNFormato
+		}
+		return "", ErrISSNDigito
+	}
+	return d[:4] + "-" + d[4:], nil
+}
+
+// ValidarISSN informa se o ISSN (com ou sem hífen) é válido
+func ValidarISSN(issn string) error {
+	_, err := NormalizarISSN(issn)
+	return err
+}
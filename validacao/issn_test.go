@@ -0,0 +1,37 @@
+package validacao
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarISSN(t *testing.T) {
+	casos := []struct {
+		nome     string
+		entrada  string
+		esperado string
+		erro     error
+	}{
+		{"com hífen", "0317-8471", "0317-8471", nil},
+		{"sem hífen", "20493630", "2049-3630", nil},
+		{"verificador X", "2434-561X", "2434-561X", nil},
+		{"verificador x minúsculo", " 2434-561x ", "2434-561X", nil},
+		{"dígito errado", "0317-8472", "", ErrISSNDigito},
+		{"X no lugar de dígito", "0317-847X", "", ErrISSNDigito},
+		{"letra no verificador", "0317-847A", "", ErrISSNFormato},
+		{"letra na base", "03A7-8471", "", ErrISSNFormato},
+		{"curto", "0317-847", "", ErrISSNFormato},
+		{"vazio", "", "", ErrISSNFormato},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			obtido, err := NormalizarISSN(c.entrada)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("NormalizarISSN(%q): erro %v, esperado %v", c.entrada, err, c.erro)
+			}
+			if obtido != c.esperado {
+				t.Errorf("NormalizarISSN(%q) = %q, esperado %q", c.entrada, obtido, c.esperado)
+			}
+		})
+	}
+}
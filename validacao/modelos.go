@@ -0,0 +1,335 @@
+package validacao
+
+import (
+	"crud-biblioteca/model"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// idade máxima aceita na data de nascimento, para pegar erros de digitação no ano
+const idadeMaxima = 130
+
+// situações aceitas em Emprestimo.Status: ativo, devolvido e cancelado
+var statusEmprestimo = []string{"A", "D", "C"}
+
+var ufs = []string{
+	"AC", "AL", "AP", "AM", "BA", "CE", "DF", "ES", "GO", "MA", "MT", "MS", "MG", "PA",
+	"PB", "PR", "PE", "PI", "RJ", "RN", "RS", "RO", "RR", "SC", "SP", "SE", "TO",
+}
+
+// As funções Validar* abaixo verificam todos os campos do modelo e retornam
+// nil ou um Erros com um item por problema encontrado. Os documentos (CPF,
+// ISBN, CNPJ, ISSN) devem estar normalizados, como são gravados no banco
+
+func ValidarUsuario(u model.Usuario) error {
+	var c coletor
+	c.documento("cpf", ValidarCPF(u.CPF))
+	c.obrigatorio("primeiro_nome", u.PrimeiroNome, "o primeiro nome é obrigatório")
+	c.obrigatorio("sobrenome", u.Sobrenome, "o sobrenome é obrigatório")
+
+	hoje := time.Now()
+	switch {
+	case u.DataNascimento.IsZero():
+		c.add("data_nascimento", CodigoObrigatorio, "a data de nascimento é obrigatória")
+	case u.DataNascimento.After(hoje):
+		c.add("data_nascimento", CodigoFuturo, "a data de nascimento não pode estar no futuro")
+	case u.DataNascimento.Before(hoje.AddDate(-idadeMaxima, 0, 0)):
+		c.add("data_nascimento", CodigoForaLimite, fmt.Sprintf("a data de nascimento indica mais de %d anos", idadeMaxima))
+	}
+
+	if u.Email != "" {
+		if addr, err := mail.ParseAddress(u.Email); err != nil || addr.Address != u.Email {
+			c.add("email", CodigoInvalido, "e-mail inválido")
+		}
+	}
+	if u.Telefone != "" && !telefoneValido(u.Telefone) {
+		c.add("telefone", CodigoInvalido, "o telefone deve ter DDD e 8 ou 9 dígitos")
+	}
+	validarEndereco(&c, u.Endereco)
+
+	if !categoriaValida(u.Categoria) {
+		c.add("categoria", CodigoInvalido, fmt.Sprintf("categoria de usuário inválida: '%s'", u.Categoria))
+	}
+	if u.Categoria != model.CategoriaExterno {
+		c.obrigatorio("matricula", u.Matricula, "a matrícula é obrigatória para usuários com vínculo com a UFS")
+	}
+	if u.ResponsavelCPF != "" {
+		c.documento("responsavel_cpf", ValidarCPF(u.ResponsavelCPF))
+		if u.ResponsavelCPF == u.CPF {
+			c.add("responsavel_cpf", CodigoInvalido, "o usuário não pode ser o próprio responsável")
+		}
+	} else if u.MenorDeIdade(hoje) {
+		c.add("responsavel_cpf", CodigoObrigatorio, fmt.Sprintf("usuários menores de %d anos precisam de um responsável", model.MaioridadeAnos))
+	}
+	if !u.ValidadeVinculo.IsZero() && !u.DataNascimento.IsZero() && u.ValidadeVinculo.Before(u.DataNascimento) {
+		c.add("validade_vinculo", CodigoInvalido, "a validade do vínculo é anterior à data de nascimento")
+	}
+	return c.resultado()
+}
+
+func validarEndereco(c *coletor, e model.Endereco) {
+	if e.UF != "" && !contem(ufs, e.UF) {
+		c.add("endereco.uf", CodigoInvalido, fmt.Sprintf("UF inválida: '%s'", e.UF))
+	}
+	if e.CEP != "" {
+		cep := strings.ReplaceAll(e.CEP, "-", "")
+		if len(cep) != 8 || !apenasDigitos(cep) {
+			c.add("endereco.cep", CodigoInvalido, "o CEP deve ter 8 dígitos")
+		}
+	}
+}
+
+func ValidarAutor(a model.Autor) error {
+	var c coletor
+	if a.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID do autor deve ser um número positivo")
+	}
+	c.obrigatorio("primeiro_nome", a.PrimeiroNome, "o primeiro nome do autor é obrigatório")
+	return c.resultado()
+}
+
+func ValidarEditora(e model.Editora) error {
+	var c coletor
+	c.documento("cnpj", ValidarCNPJ(e.CNPJ))
+	c.obrigatorio("nome", e.Nome, "o nome da editora é obrigatório")
+	return c.resultado()
+}
+
+func ValidarLivro(l model.Livro) error {
+	var c coletor
+	c.documento("isbn", ValidarISBN(l.ISBN))
+	c.obrigatorio("titulo", l.Titulo, "o título é obrigatório")
+	if l.NumPaginas < 0 {
+		c.add("num_paginas", CodigoNegativo, "o número de páginas não pode ser negativo")
+	} else if l.NumPaginas == 0 {
+		c.add("num_paginas", CodigoObrigatorio, "o número de páginas é obrigatório")
+	}
+	c.documento("editora_cnpj", ValidarCNPJ(l.EditoraCNPJ))
+
+	switch l.SistemaClassificacao {
+	case "":
+		if l.NumeroClassificacao != "" {
+			c.add("sistema_classificacao", CodigoObrigatorio, "informe o sistema (CDD ou CDU) do número de classificação")
+		}
+	case model.ClassificacaoCDD, model.ClassificacaoCDU:
+		c.obrigatorio("numero_classificacao", l.NumeroClassificacao, "o número de classificação é obrigatório")
+	default:
+		c.add("sistema_classificacao", CodigoInvalido, fmt.Sprintf("sistema de classificação inválido: '%s'", l.SistemaClassificacao))
+	}
+
+	if l.Idioma != "" && !idiomaValido(l.Idioma) {
+		c.add("idioma", CodigoInvalido, "o idioma deve ser um código ISO 639 de 2 ou 3 letras (ex.: pt, en)")
+	}
+	return c.resultado()
+}
+
+func ValidarCategoria(cat model.Categoria) error {
+	var c coletor
+	if cat.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID da categoria deve ser um número positivo")
+	}
+	c.obrigatorio("nome", cat.Nome, "o nome do assunto é obrigatório")
+	c.obrigatorio("codigo", cat.Codigo, "o código de classificação do assunto é obrigatório")
+	if cat.PaiID != nil && *cat.PaiID == cat.ID {
+		c.add("pai_id", CodigoInvalido, "a categoria não pode ser superior a si mesma")
+	}
+	return c.resultado()
+}
+
+func ValidarObra(o model.Obra) error {
+	var c coletor
+	if o.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID da obra deve ser um número positivo")
+	}
+	c.obrigatorio("titulo", o.Titulo, "o título uniforme da obra é obrigatório")
+	if o.IdiomaOriginal != "" && !idiomaValido(o.IdiomaOriginal) {
+		c.add("idioma_original", CodigoInvalido, "o idioma deve ser um código ISO 639 de 2 ou 3 letras (ex.: pt, en)")
+	}
+	return c.resultado()
+}
+
+func ValidarReserva(r model.Reserva) error {
+	var c coletor
+	if r.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID da reserva deve ser um número positivo")
+	}
+	c.documento("usuario_cpf", ValidarCPF(r.UsuarioCPF))
+	if r.LivroISBN == "" && r.ObraID == nil {
+		c.add("livro_isbn", CodigoObrigatorio, "informe o ISBN da edição ou a obra reservada")
+	}
+	if r.DataReserva.IsZero() {
+		c.add("data_reserva", CodigoObrigatorio, "a data da reserva é obrigatória")
+	}
+	if !contem([]string{model.ReservaAtiva, model.ReservaAtendida, model.ReservaCancelada}, r.Status) {
+		c.add("status", CodigoInvalido, fmt.Sprintf("status de reserva inválido: '%s' (use A, T ou C)", r.Status))
+	}
+	return c.resultado()
+}
+
+func ValidarRecursoDigital(r model.RecursoDigital) error {
+	var c coletor
+	if r.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID do recurso deve ser um número positivo")
+	}
+	c.obrigatorio("livro_isbn", r.LivroISBN, "o ISBN do livro é obrigatório")
+	if !contem(model.TiposRecursoDigital, r.Tipo) {
+		c.add("tipo", CodigoInvalido, fmt.Sprintf("tipo de recurso inválido: '%s' (use %s)", r.Tipo, strings.Join(model.TiposRecursoDigital, ", ")))
+	}
+	c.obrigatorio("nome_arquivo", r.NomeArquivo, "o nome do arquivo é obrigatório")
+	c.obrigatorio("caminho", r.Caminho, "o caminho do arquivo é obrigatório")
+	if r.Tamanho < 0 {
+		c.add("tamanho", CodigoNegativo, "o tamanho do arquivo não pode ser negativo")
+	}
+	if len(r.SHA256) != 64 || strings.Trim(strings.ToLower(r.SHA256), "0123456789abcdef") != "" {
+		c.add("sha256", CodigoInvalido, "o SHA-256 deve ter 64 dígitos hexadecimais")
+	}
+	return c.resultado()
+}
+
+func ValidarPeriodico(p model.Periodico) error {
+	var c coletor
+	c.documento("issn", ValidarISSN(p.ISSN))
+	c.obrigatorio("titulo", p.Titulo, "o título do periódico é obrigatório")
+	c.documento("editora_cnpj", ValidarCNPJ(p.EditoraCNPJ))
+	if p.FasciculosPorAno <= 0 {
+		c.add("fasciculos_por_ano", CodigoInvalido, "a quantidade de fascículos por ano deve ser positiva")
+	} else if p.FasciculosPorAno > 365 {
+		c.add("fasciculos_por_ano", CodigoInvalido, "a quantidade de fascículos por ano não pode passar de 365")
+	}
+	if p.PrazoReclamacao < 0 {
+		c.add("prazo_reclamacao", CodigoNegativo, "o prazo de reclamação não pode ser negativo")
+	}
+	if p.InicioAssinatura.IsZero() {
+		c.add("inicio_assinatura", CodigoObrigatorio, "a data de início da assinatura é obrigatória")
+	}
+	return c.resultado()
+}
+
+func ValidarFasciculo(f model.Fasciculo) error {
+	var c coletor
+	c.documento("periodico_issn", ValidarISSN(f.PeriodicoISSN))
+	if f.Volume <= 0 {
+		c.add("volume", CodigoInvalido, "o volume deve ser um número positivo")
+	}
+	if f.Numero <= 0 {
+		c.add("numero", CodigoInvalido, "o número deve ser positivo")
+	}
+	if !contem([]string{model.FasciculoEsperado, model.FasciculoRecebido, model.FasciculoReclamado}, f.Status) {
+		c.add("status", CodigoInvalido, fmt.Sprintf("status de fascículo inválido: '%s'", f.Status))
+	}
+	if f.DataRecebimento != nil && f.DataRecebimento.After(time.Now()) {
+		c.add("data_recebimento", CodigoFuturo, "a data de recebimento não pode estar no futuro")
+	}
+	if f.EmprestadoCPF != "" {
+		c.documento("emprestado_cpf", ValidarCPF(f.EmprestadoCPF))
+	}
+	return c.resultado()
+}
+
+func ValidarEmprestimo(e model.Emprestimo) error {
+	var c coletor
+	if e.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID do empréstimo deve ser um número positivo")
+	}
+	if e.DataEmprestimo.IsZero() {
+		c.add("data_emprestimo", CodigoObrigatorio, "a data do empréstimo é obrigatória")
+	} else if e.DataEmprestimo.After(time.Now()) {
+		c.add("data_emprestimo", CodigoFuturo, "a data do empréstimo não pode estar no futuro")
+	}
+	if !contem(statusEmprestimo, e.Status) {
+		c.add("status", CodigoInvalido, fmt.Sprintf("status de empréstimo inválido: '%s' (use A, D ou C)", e.Status))
+	}
+	if e.QuantLivros < 0 {
+		c.add("quant_livros", CodigoNegativo, "a quantidade de livros não pode ser negativa")
+	} else if e.QuantLivros == 0 {
+		c.add("quant_livros", CodigoObrigatorio, "o empréstimo deve ter ao menos um livro")
+	}
+	c.documento("cliente_usuario_cpf", ValidarCPF(e.ClienteUsuarioCPF))
+	return c.resultado()
+}
+
+// tamanhoMinimoSegredo é o menor segredo aceito para assinar os webhooks
+const tamanhoMinimoSegredo = 16
+
+func ValidarWebhook(w model.Webhook) error {
+	var c coletor
+	if w.ID <= 0 {
+		c.add("id", CodigoInvalido, "o ID da assinatura deve ser um número positivo")
+	}
+	if c.obrigatorio("url", w.URL, "a URL de destino é obrigatória") {
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.add("url", CodigoInvalido, "a URL deve ser absoluta, com http:// ou https://")
+		}
+	}
+	if len(w.Eventos) == 0 {
+		c.add("eventos", CodigoObrigatorio, "assine ao menos um evento")
+	}
+	for i, e := range w.Eventos {
+		if !contem(model.EventosWebhook, e) {
+			c.add("eventos", CodigoInvalido, fmt.Sprintf("evento desconhecido: '%s' (use %s)", e, strings.Join(model.EventosWebhook, ", ")))
+		} else if contem(w.Eventos[:i], e) {
+			c.add("eventos", CodigoInvalido, fmt.Sprintf("o evento '%s' foi informado mais de uma vez", e))
+		}
+	}
+	if len(w.Segredo) < tamanhoMinimoSegredo {
+		c.add("segredo", CodigoInvalido, fmt.Sprintf("o segredo deve ter pelo menos %d caracteres", tamanhoMinimoSegredo))
+	}
+	return c.resultado()
+}
+
+func categoriaValida(cat model.CategoriaUsuario) bool {
+	for _, v := range model.CategoriasUsuario {
+		if v == cat {
+			return true
+		}
+	}
+	return false
+}
+
+// telefoneValido aceita DDD + número, com ou sem máscara e código do país
+func telefoneValido(tel string) bool {
+	n := 0
+	for _, r := range tel {
+		switch {
+		case r >= '0' && r <= '9':
+			n++
+		case strings.ContainsRune(" ()-+", r):
+		default:
+			return false
+		}
+	}
+	return n >= 10 && n <= 13
+}
+
+func idiomaValido(idioma string) bool {
+	if len(idioma) < 2 || len(idioma) > 3 {
+		return false
+	}
+	for _, r := range idioma {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func apenasDigitos(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func contem(lista []string, v string) bool {
+	for _, item := range lista {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package validacao
+
+import (
+	"crud-biblioteca/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+// temErro confere que err é um Erros com um item no campo e com o código
+// esperados; campo vazio espera nenhum erro
+func temErro(t *testing.T, err error, campo, codigo string) {
+	t.Helper()
+	if campo == "" {
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		return
+	}
+	var erros Erros
+	if !errors.As(err, &erros) {
+		t.Fatalf("esperado Erros em %s, obtido %v", campo, err)
+	}
+	for _, e := range erros {
+		if e.Campo == campo && e.Codigo == codigo {
+			return
+		}
+	}
+	t.Fatalf("esperado %s/%s, obtido %+v", campo, codigo, erros)
+}
+
+func TestValidarUsuario(t *testing.T) {
+	hoje := time.Now()
+	valido := func() model.Usuario {
+		return model.Usuario{
+			CPF: "52998224725", PrimeiroNome: "Maria", Sobrenome: "Santos",
+			DataNascimento: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
+			Categoria:      model.CategoriaDocente, Matricula: "202100123",
+			Endereco: model.Endereco{UF: "SE", CEP: "49100-000"},
+		}
+	}
+	casos := []struct {
+		nome    string
+		alterar func(u *model.Usuario)
+		campo   string
+		codigo  string
+	}{
+		{"válido", func(u *model.Usuario) {}, "", ""},
+		{"nascimento no futuro", func(u *model.Usuario) { u.DataNascimento = hoje.AddDate(0, 0, 1) }, "data_nascimento", CodigoFuturo},
+		{"nascimento há mais de 130 anos", func(u *model.Usuario) { u.DataNascimento = hoje.AddDate(-131, 0, 0) }, "data_nascimento", CodigoForaLimite},
+		{"sem nascimento", func(u *model.Usuario) { u.DataNascimento = time.Time{} }, "data_nascimento", CodigoObrigatorio},
+		{"UF inexistente", func(u *model.Usuario) { u.Endereco.UF = "XX" }, "endereco.uf", CodigoInvalido},
+		{"UF em minúsculas", func(u *model.Usuario) { u.Endereco.UF = "se" }, "endereco.uf", CodigoInvalido},
+		{"CEP sem hífen", func(u *model.Usuario) { u.Endereco.CEP = "49100000" }, "", ""},
+		{"CEP curto", func(u *model.Usuario) { u.Endereco.CEP = "4910-000" }, "endereco.cep", CodigoInvalido},
+		{"CEP com letras", func(u *model.Usuario) { u.Endereco.CEP = "49A00-000" }, "endereco.cep", CodigoInvalido},
+		{"menor sem responsável", func(u *model.Usuario) { u.DataNascimento = hoje.AddDate(-10, 0, 0) }, "responsavel_cpf", CodigoObrigatorio},
+		{"menor com responsável", func(u *model.Usuario) {
+			u.DataNascimento, u.ResponsavelCPF = hoje.AddDate(-10, 0, 0), "11144477735"
+		}, "", ""},
+		{"próprio responsável", func(u *model.Usuario) { u.ResponsavelCPF = u.CPF }, "responsavel_cpf", CodigoInvalido},
+		{"externo sem matrícula", func(u *model.Usuario) { u.Categoria, u.Matricula = model.CategoriaExterno, "" }, "", ""},
+		{"docente sem matrícula", func(u *model.Usuario) { u.Matricula = "" }, "matricula", CodigoObrigatorio},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			u := valido()
+			c.alterar(&u)
+			temErro(t, ValidarUsuario(u), c.campo, c.codigo)
+		})
+	}
+}
+
+func TestValidarLivro(t *testing.T) {
+	valido := func() model.Livro {
+		return model.Livro{ISBN: "9788535902778", Titulo: "Dom Casmurro", NumPaginas: 256, EditoraCNPJ: "11222333000181"}
+	}
+	casos := []struct {
+		nome    string
+		alterar func(l *model.Livro)
+		campo   string
+		codigo  string
+	}{
+		{"válido sem classificação", func(l *model.Livro) {}, "", ""},
+		{"CDD com número", func(l *model.Livro) { l.SistemaClassificacao, l.NumeroClassificacao = model.ClassificacaoCDD, "869.3" }, "", ""},
+		{"CDD sem número", func(l *model.Livro) { l.SistemaClassificacao = model.ClassificacaoCDD }, "numero_classificacao", CodigoObrigatorio},
+		{"CDU sem número", func(l *model.Livro) { l.SistemaClassificacao = model.ClassificacaoCDU }, "numero_classificacao", CodigoObrigatorio},
+		{"número sem sistema", func(l *model.Livro) { l.NumeroClassificacao = "869.3" }, "sistema_classificacao", CodigoObrigatorio},
+		{"sistema desconhecido", func(l *model.Livro) { l.SistemaClassificacao, l.NumeroClassificacao = "LCC", "PQ9697" }, "sistema_classificacao", CodigoInvalido},
+		{"páginas negativas", func(l *model.Livro) { l.NumPaginas = -1 }, "num_paginas", CodigoNegativo},
+		{"idioma inválido", func(l *model.Livro) { l.Idioma = "português" }, "idioma", CodigoInvalido},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			l := valido()
+			c.alterar(&l)
+			temErro(t, ValidarLivro(l), c.campo, c.codigo)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// validadeCredencial é o tempo em que uma credencial aceita por Conferidor
+// deixa de ser conferida pelo bcrypt de novo
+const validadeCredencial = 5 * time.Minute
+
+// Conferidor devolve a conferência de login e senha pelas contas da equipe,
+// usada pela API REST, pelo GraphQL, pela circulação ao vivo e pelo gRPC, que
+// não têm página de login. Como o bcrypt é lento de propósito, as credenciais
+// aceitas ficam guardadas (só o hash SHA-256) por validadeCredencial
+func (c Contas) Conferidor() func(login, senha string) bool {
+	var mu sync.Mutex
+	aceitas := make(map[[sha256.Size]byte]time.Time)
+	return func(login, senha string) bool {
+		chave := sha256.Sum256([]byte(login + "\x00" + senha))
+		agora := time.Now()
+		mu.Lock()
+		expira, ok := aceitas[chave]
+		mu.Unlock()
+		if ok && agora.Before(expira) {
+			return true
+		}
+		if !c.autenticar(login, senha) {
+			return false
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for k, e := range aceitas {
+			if !agora.Before(e) {
+				delete(aceitas, k)
+			}
+		}
+		aceitas[chave] = agora.Add(validadeCredencial)
+		return true
+	}
+}
+
+// ExigirConta protege h com autenticação HTTP Basic pelas contas da equipe
+func (c Contas) ExigirConta(h http.Handler) http.Handler {
+	conferir := c.Conferidor()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		login, senha, ok := r.BasicAuth()
+		if !ok || !conferir(login, senha) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="biblioteca", charset="UTF-8"`)
+			http.Error(w, "informe o login e a senha de uma conta da equipe", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
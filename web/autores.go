@@ -0,0 +1,75 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"strconv"
+)
+
+func (s *Servidor) autores() cadastro[model.Autor] {
+	b := s.biblioteca
+	// o ID vem do caminho; um ID que não é número não existe
+	porID := func(chave string) (int, error) {
+		id, err := strconv.Atoi(chave)
+		if err != nil {
+			return 0, repository.NaoEncontrado("autor %s", chave)
+		}
+		return id, nil
+	}
+	return cadastro[model.Autor]{
+		secao:   "autores",
+		titulo:  "Autores",
+		nome:    "autor",
+		dica:    "nome ou ID",
+		colunas: []string{"ID", "Nome"},
+		listar: func(ctx context.Context, termo string) ([]model.Autor, error) {
+			if id, err := strconv.Atoi(termo); err == nil {
+				return unico(b.ObterAutor(ctx, id))
+			}
+			return b.ListarAutores(ctx, termo)
+		},
+		celulas: func(a *model.Autor) []string {
+			return []string{strconv.Itoa(a.ID), a.PrimeiroNome + " " + a.Sobrenome}
+		},
+		chave: func(a *model.Autor) string { return strconv.Itoa(a.ID) },
+		obter: func(ctx context.Context, chave string) (*model.Autor, error) {
+			id, err := porID(chave)
+			if err != nil {
+				return nil, err
+			}
+			return b.ObterAutor(ctx, id)
+		},
+		campos: func(a *model.Autor, criar bool) []campo {
+			var c []campo
+			if criar {
+				c = append(c, campo{Rotulo: "ID", Chave: "id", Valor: numero(a.ID), Tipo: "number"})
+			}
+			return append(c,
+				campo{Rotulo: "Primeiro nome", Chave: "primeiro_nome", Valor: a.PrimeiroNome},
+				campo{Rotulo: "Sobrenome", Chave: "sobrenome", Valor: a.Sobrenome},
+			)
+		},
+		montar: func(l *leitor, a model.Autor, criar bool) model.Autor {
+			if criar {
+				a.ID = l.inteiro("id")
+			}
+			a.PrimeiroNome, a.Sobrenome = l.texto("primeiro_nome"), l.texto("sobrenome")
+			return a
+		},
+		validar: servico.ValidarAutor,
+		criar:   b.CriarAutor,
+		alterar: b.AtualizarAutor,
+		remover: func(ctx context.Context, chave string) error {
+			id, err := porID(chave)
+			if err != nil {
+				return err
+			}
+			return b.DeletarAutor(ctx, id)
+		},
+		detalhes: func(ctx context.Context, f *formulario, a *model.Autor) {
+			f.Titulo = "Autor " + a.PrimeiroNome + " " + a.Sobrenome
+		},
+	}
+}
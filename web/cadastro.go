@@ -0,0 +1,291 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/validacao"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cadastro descreve as páginas de uma entidade: a lista com busca, o
+// formulário de cadastro e o de edição. T é o tipo do pacote model
+type cadastro[T any] struct {
+	secao   string // "usuarios", também o caminho das páginas
+	titulo  string // "Usuários", título da lista
+	nome    string // "usuário", usado nos títulos e avisos
+	dica    string // texto de exemplo do campo de busca
+	colunas []string
+	listar  func(ctx context.Context, termo string) ([]T, error)
+	celulas func(v *T) []string
+	chave   func(v *T) string
+	obter   func(ctx context.Context, chave string) (*T, error)
+	campos  func(v *T, criar bool) []campo
+	montar  func(l *leitor, atual T, criar bool) T
+	validar func(v T) error
+	criar   func(ctx context.Context, v T) (*T, error)
+	alterar func(ctx context.Context, v T) (*T, error)
+	remover func(ctx context.Context, chave string) error
+	// inicial preenche o cadastro a partir da query (ex.: ?cpf= no empréstimo)
+	inicial func(ctx context.Context, q url.Values) T
+	// detalhes completa a página de edição com o que não é campo do registro
+	detalhes func(ctx context.Context, f *formulario, v *T)
+}
+
+// campo é um campo de formulário; Chave é o nome do campo nos erros de
+// validação (o mesmo do JSON da API, como "primeiro_nome" ou "endereco.cep")
+type campo struct {
+	Rotulo, Chave, Valor, Erro, Dica string
+	Tipo                             string   // tipo do <input>; vazio é text
+	Opcoes                           []string // quando há, o campo vira um <select>
+	Lista                            string   // id do <datalist> com sugestões
+}
+
+type formulario struct {
+	Titulo  string
+	Acao    string
+	Campos  []campo
+	Excluir string // endereço para excluir o registro; vazio em cadastros
+	Voltar  string
+	// páginas com ações próprias do registro
+	Livro     *livroDetalhes
+	Emprestar string
+	Devolver  string
+	Senha     string // endereço do formulário da senha do portal do usuário
+}
+
+type lista struct {
+	Titulo, Secao, Busca, Dica, Novo string
+	Colunas                          []string
+	Linhas                           []linhaLista
+}
+
+type linhaLista struct {
+	Link    string
+	Celulas []string
+}
+
+// registrar cria as rotas da entidade:
+//
+//	GET  /<secao>                lista, com ?q= para buscar
+//	GET  /<secao>/novo           formulário de cadastro
+//	POST /<secao>/novo           cadastra
+//	GET  /<secao>/{chave}        formulário de edição
+//	POST /<secao>/{chave}        grava a edição
+//	POST /<secao>/{chave}/excluir
+func registrar[T any](s *Servidor, c cadastro[T]) {
+	raiz := Prefixo + "/" + c.secao
+	s.mux.HandleFunc("GET "+raiz, s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		termo := strings.TrimSpace(r.URL.Query().Get("q"))
+		registros, err := c.listar(r.Context(), termo)
+		if err != nil {
+			s.paginaErro(w, ss, c.secao, err)
+			return
+		}
+		l := lista{Titulo: c.titulo, Secao: c.secao, Busca: termo, Dica: c.dica, Novo: raiz + "/novo", Colunas: c.colunas}
+		for i := range registros {
+			l.Linhas = append(l.Linhas, linhaLista{raiz + "/" + url.PathEscape(c.chave(&registros[i])), c.celulas(&registros[i])})
+		}
+		p := s.base(ss, l.Titulo, c.secao, nil)
+		p.Dados = l
+		s.renderizar(w, http.StatusOK, "lista", p)
+	}))
+
+	s.mux.HandleFunc("GET "+raiz+"/novo", s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		var v T
+		if c.inicial != nil {
+			v = c.inicial(r.Context(), r.URL.Query())
+		}
+		f := formulario{Titulo: "Novo " + c.nome, Acao: raiz + "/novo", Campos: c.campos(&v, true), Voltar: raiz}
+		s.exibirFormulario(w, ss, c.secao, http.StatusOK, f, nil)
+	}))
+
+	s.mux.HandleFunc("POST "+raiz+"/novo", s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		var vazio T
+		l := &leitor{form: r.PostForm}
+		v := c.montar(l, vazio, true)
+		f := formulario{Titulo: "Novo " + c.nome, Acao: raiz + "/novo", Campos: c.campos(&v, true), Voltar: raiz}
+		novo, err := c.gravar(r.Context(), l, v, c.criar)
+		if err != nil {
+			f.Campos = valoresDigitados(f.Campos, r.PostForm)
+			s.exibirFormulario(w, ss, c.secao, statusDoErro(err), f, err)
+			return
+		}
+		s.redirecionar(w, r, ss, "/"+c.secao+"/"+url.PathEscape(c.chave(novo)), primeiraMaiuscula(c.nome)+" cadastrado.")
+	}))
+
+	s.mux.HandleFunc("GET "+raiz+"/{chave}", s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		v, err := c.obter(r.Context(), r.PathValue("chave"))
+		if err != nil {
+			s.paginaErro(w, ss, c.secao, err)
+			return
+		}
+		s.exibirFormulario(w, ss, c.secao, http.StatusOK, c.edicao(r.Context(), raiz, v), nil)
+	}))
+
+	s.mux.HandleFunc("POST "+raiz+"/{chave}", s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		atual, err := c.obter(r.Context(), r.PathValue("chave"))
+		if err != nil {
+			s.paginaErro(w, ss, c.secao, err)
+			return
+		}
+		l := &leitor{form: r.PostForm}
+		v := c.montar(l, *atual, false)
+		if _, err := c.gravar(r.Context(), l, v, c.alterar); err != nil {
+			f := c.edicao(r.Context(), raiz, atual)
+			f.Campos = valoresDigitados(f.Campos, r.PostForm)
+			s.exibirFormulario(w, ss, c.secao, statusDoErro(err), f, err)
+			return
+		}
+		s.redirecionar(w, r, ss, "/"+c.secao+"/"+url.PathEscape(c.chave(atual)), primeiraMaiuscula(c.nome)+" salvo.")
+	}))
+
+	s.mux.HandleFunc("POST "+raiz+"/{chave}/excluir", s.autenticado(func(w http.ResponseWriter, r *http.Request, ss *sessao) {
+		if err := c.remover(r.Context(), r.PathValue("chave")); err != nil {
+			s.paginaErro(w, ss, c.secao, err)
+			return
+		}
+		s.redirecionar(w, r, ss, "/"+c.secao, primeiraMaiuscula(c.nome)+" excluído.")
+	}))
+}
+
+func (c cadastro[T]) edicao(ctx context.Context, raiz string, v *T) formulario {
+	link := raiz + "/" + url.PathEscape(c.chave(v))
+	f := formulario{Titulo: primeiraMaiuscula(c.nome) + " " + c.chave(v), Acao: link, Campos: c.campos(v, false), Excluir: link + "/excluir", Voltar: raiz}
+	if c.detalhes != nil {
+		c.detalhes(ctx, &f, v)
+	}
+	return f
+}
+
+// gravar junta os erros de conversão dos campos aos da validação do modelo
+// antes de chamar o serviço, para que todos apareçam de uma vez
+func (c cadastro[T]) gravar(ctx context.Context, l *leitor, v T, salvar func(context.Context, T) (*T, error)) (*T, error) {
+	if len(l.erros) > 0 {
+		return nil, l.juntar(c.validar(v))
+	}
+	return salvar(ctx, v)
+}
+
+// exibirFormulario mostra o formulário com os erros de validação ao lado dos
+// campos; os demais erros aparecem no topo da página
+func (s *Servidor) exibirFormulario(w http.ResponseWriter, ss *sessao, secao string, status int, f formulario, err error) {
+	p := s.base(ss, f.Titulo, secao, nil)
+	if err != nil {
+		var soltos []string
+		campos, ok := validacao.ErrosDeCampo(err)
+		for _, e := range campos {
+			if !marcarErro(f.Campos, e) {
+				soltos = append(soltos, e.Mensagem)
+			}
+		}
+		switch {
+		case !ok:
+			p.Erro = descreverErro(err)
+		case len(soltos) > 0:
+			p.Erro = strings.Join(soltos, "; ")
+		default:
+			p.Erro = "corrija os campos marcados"
+		}
+	}
+	p.Dados = f
+	s.renderizar(w, status, "formulario", p)
+}
+
+func marcarErro(campos []campo, e validacao.ErroCampo) bool {
+	for i := range campos {
+		if campos[i].Chave == e.Campo {
+			campos[i].Erro = e.Mensagem
+			return true
+		}
+	}
+	return false
+}
+
+// valoresDigitados devolve o formulário com o que o operador digitou, e não
+// com os valores convertidos, para que um erro não apague o texto
+func valoresDigitados(campos []campo, form url.Values) []campo {
+	for i := range campos {
+		if v, ok := form[campos[i].Chave]; ok {
+			campos[i].Valor = strings.TrimSpace(v[0])
+		}
+	}
+	return campos
+}
+
+// leitor converte os campos do formulário; os erros seguem o formato da
+// validação para aparecerem ao lado do campo
+type leitor struct {
+	form  url.Values
+	erros validacao.Erros
+}
+
+func (l *leitor) texto(chave string) string {
+	return strings.TrimSpace(l.form.Get(chave))
+}
+
+func (l *leitor) data(chave string) time.Time {
+	v := l.texto(chave)
+	if v == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		l.erros = append(l.erros, validacao.ErroCampo{Campo: chave, Codigo: validacao.CodigoInvalido, Mensagem: "use o formato AAAA-MM-DD"})
+	}
+	return t
+}
+
+func (l *leitor) inteiro(chave string) int {
+	v := l.texto(chave)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		l.erros = append(l.erros, validacao.ErroCampo{Campo: chave, Codigo: validacao.CodigoInvalido, Mensagem: "deve ser um número inteiro"})
+	}
+	return n
+}
+
+// juntar combina os erros de conversão com os da validação do modelo; o erro
+// de conversão explica melhor o campo do que o de valor vazio
+func (l *leitor) juntar(err error) error {
+	erros := l.erros
+	campos, _ := validacao.ErrosDeCampo(err)
+	for _, e := range campos {
+		repetido := false
+		for _, c := range l.erros {
+			repetido = repetido || c.Campo == e.Campo
+		}
+		if !repetido {
+			erros = append(erros, e)
+		}
+	}
+	return erros
+}
+
+func data(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func numero(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func primeiraMaiuscula(s string) string {
+	for i := range s {
+		if i > 0 {
+			return strings.ToUpper(s[:i]) + s[i:]
+		}
+	}
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Servidor) emprestimos() cadastro[model.Emprestimo] {
+	b := s.biblioteca
+	porID := func(chave string) (int, error) {
+		id, err := strconv.Atoi(chave)
+		if err != nil {
+			return 0, repository.NaoEncontrado("empréstimo %s", chave)
+		}
+		return id, nil
+	}
+	return cadastro[model.Emprestimo]{
+		secao:   "emprestimos",
+		titulo:  "Empréstimos",
+		nome:    "empréstimo",
+		dica:    "ID, CPF ou status (A, D, C)",
+		colunas: []string{"ID", "Data", "Status", "Livros", "CPF"},
+		listar: func(ctx context.Context, termo string) ([]model.Emprestimo, error) {
+			if _, err := validacao.NormalizarCPF(termo); err == nil {
+				return b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{CPF: termo})
+			}
+			if id, err := strconv.Atoi(termo); err == nil {
+				return unico(b.ObterEmprestimo(ctx, id))
+			}
+			switch strings.ToUpper(termo) {
+			case "A", "D", "C":
+				return b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{Status: termo})
+			}
+			return b.ListarEmprestimos(ctx, repository.FiltroEmprestimo{})
+		},
+		celulas: func(e *model.Emprestimo) []string {
+			return []string{strconv.Itoa(e.ID), data(e.DataEmprestimo), e.Status, strconv.Itoa(e.QuantLivros), validacao.FormatarCPF(e.ClienteUsuarioCPF)}
+		},
+		chave: func(e *model.Emprestimo) string { return strconv.Itoa(e.ID) },
+		obter: func(ctx context.Context, chave string) (*model.Emprestimo, error) {
+			id, err := porID(chave)
+			if err != nil {
+				return nil, err
+			}
+			return b.ObterEmprestimo(ctx, id)
+		},
+		campos: func(e *model.Emprestimo, criar bool) []campo {
+			var c []campo
+			if criar {
+				c = append(c, campo{Rotulo: "ID", Chave: "id", Valor: numero(e.ID), Tipo: "number"})
+			}
+			c = append(c, campo{Rotulo: "CPF do usuário", Chave: "cliente_usuario_cpf", Valor: e.ClienteUsuarioCPF})
+			// o empréstimo novo pede os ISBNs, conferidos pelo servico; depois só
+			// a quantidade fica gravada
+			if criar {
+				c = append(c, campo{Rotulo: "ISBNs dos livros", Chave: "livros", Valor: strings.Join(e.Livros, ", "), Dica: "separados por vírgula"})
+			} else {
+				c = append(c, campo{Rotulo: "Quantidade de livros", Chave: "quant_livros", Valor: numero(e.QuantLivros), Tipo: "number"})
+			}
+			return append(c,
+				campo{Rotulo: "Status", Chave: "status", Valor: e.Status, Opcoes: []string{"A", "D", "C"}, Dica: "A ativo, D devolvido, C cancelado"},
+				campo{Rotulo: "Data", Chave: "data_emprestimo", Valor: data(e.DataEmprestimo), Tipo: "date"},
+			)
+		},
+		montar: func(l *leitor, e model.Emprestimo, criar bool) model.Emprestimo {
+			if criar {
+				e.ID = l.inteiro("id")
+			}
+			e.ClienteUsuarioCPF = l.texto("cliente_usuario_cpf")
+			if criar {
+				e.Livros = servico.ListaISBNs(l.texto("livros"))
+				e.QuantLivros = len(e.Livros)
+			} else {
+				e.QuantLivros = l.inteiro("quant_livros")
+			}
+			e.Status = l.texto("status")
+			// a data informada substitui só o dia, mantendo o horário registrado
+			if d := l.data("data_emprestimo"); data(d) != data(e.DataEmprestimo) {
+				e.DataEmprestimo = d
+			}
+			return e
+		},
+		validar: servico.ValidarEmprestimo,
+		criar:   b.CriarEmprestimo,
+		alterar: b.AtualizarEmprestimo,
+		remover: func(ctx context.Context, chave string) error {
+			id, err := porID(chave)
+			if err != nil {
+				return err
+			}
+			return b.DeletarEmprestimo(ctx, id)
+		},
+		inicial: func(ctx context.Context, q url.Values) model.Emprestimo {
+			return model.Emprestimo{ID: s.proximoEmprestimo(ctx), ClienteUsuarioCPF: q.Get("cpf"), QuantLivros: 1,
+				Status: servico.StatusAtivo, DataEmprestimo: time.Now()}
+		},
+		detalhes: func(ctx context.Context, f *formulario, e *model.Emprestimo) {
+			if e.Status == servico.StatusAtivo {
+				f.Devolver = fmt.Sprintf("%s/emprestimos/%d/devolver", Prefixo, e.ID)
+			}
+		},
+	}
+}
+
+// proximoEmprestimo sugere o ID do próximo empréstimo; sem empréstimos, ou se
+// a consulta falhar, a sugestão é 1 e o operador pode trocá-la
+func (s *Servidor) proximoEmprestimo(ctx context.Context) int {
+	id, err := s.biblioteca.ProximoIDEmprestimo(ctx)
+	if err != nil {
+		return 1
+	}
+	return id
+}
+
+func (s *Servidor) rotasDevolucao() {
+	s.mux.HandleFunc("POST "+Prefixo+"/emprestimos/{id}/devolver", s.autenticado(s.devolver))
+}
+
+// devolver registra a devolução de um empréstimo ativo
+func (s *Servidor) devolver(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	e, err := s.biblioteca.ObterEmprestimo(r.Context(), id)
+	if err == nil && e.Status != servico.StatusAtivo {
+		err = fmt.Errorf("%w: o empréstimo %d não está ativo", servico.ErrRegra, id)
+	}
+	if err == nil {
+		e.Status = "D"
+		_, err = s.biblioteca.AtualizarEmprestimo(r.Context(), *e)
+	}
+	if err != nil {
+		s.paginaErro(w, ss, "emprestimos", err)
+		return
+	}
+	s.redirecionar(w, r, ss, fmt.Sprintf("/emprestimos/%d", id), fmt.Sprintf("Empréstimo %d devolvido.", id))
+}
@@ -0,0 +1,172 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func (s *Servidor) livros() cadastro[model.Livro] {
+	b := s.biblioteca
+	return cadastro[model.Livro]{
+		secao:   "livros",
+		titulo:  "Livros",
+		nome:    "livro",
+		dica:    "título ou ISBN",
+		colunas: []string{"ISBN", "Título", "Edição", "Idioma", "Autores"},
+		listar: func(ctx context.Context, termo string) ([]model.Livro, error) {
+			if _, err := validacao.NormalizarISBN(termo); err == nil {
+				return unico(b.ObterLivro(ctx, termo))
+			}
+			return b.ListarLivros(ctx, servico.FiltroLivro{Titulo: termo})
+		},
+		celulas: func(l *model.Livro) []string {
+			return []string{l.ISBN, l.Titulo, l.Edicao, l.Idioma, nomesAutores(l.Autores)}
+		},
+		chave: func(l *model.Livro) string { return l.ISBN },
+		obter: b.ObterLivro,
+		campos: func(l *model.Livro, criar bool) []campo {
+			var c []campo
+			if criar {
+				c = append(c, campo{Rotulo: "ISBN", Chave: "isbn", Valor: l.ISBN})
+			}
+			obra := ""
+			if l.ObraID != nil {
+				obra = strconv.Itoa(*l.ObraID)
+			}
+			return append(c,
+				campo{Rotulo: "Título", Chave: "titulo", Valor: l.Titulo},
+				campo{Rotulo: "Edição", Chave: "edicao", Valor: l.Edicao},
+				campo{Rotulo: "Páginas", Chave: "num_paginas", Valor: numero(l.NumPaginas), Tipo: "number"},
+				campo{Rotulo: "CNPJ da editora", Chave: "editora_cnpj", Valor: l.EditoraCNPJ},
+				campo{Rotulo: "Matrícula do funcionário", Chave: "funcionario_matricula", Valor: numero(l.FuncionarioMatricula), Tipo: "number"},
+				campo{Rotulo: "Sistema de classificação", Chave: "sistema_classificacao", Valor: l.SistemaClassificacao, Opcoes: []string{"", "CDD", "CDU"}},
+				campo{Rotulo: "Nº de classificação", Chave: "numero_classificacao", Valor: l.NumeroClassificacao},
+				campo{Rotulo: "Obra (ID)", Chave: "obra_id", Valor: obra, Tipo: "number"},
+				campo{Rotulo: "Idioma", Chave: "idioma", Valor: l.Idioma, Dica: "pt, en, es..."},
+			)
+		},
+		montar: func(lr *leitor, l model.Livro, criar bool) model.Livro {
+			if criar {
+				l.ISBN = lr.texto("isbn")
+			}
+			l.Titulo, l.Edicao = lr.texto("titulo"), lr.texto("edicao")
+			l.NumPaginas = lr.inteiro("num_paginas")
+			l.EditoraCNPJ = lr.texto("editora_cnpj")
+			l.FuncionarioMatricula = lr.inteiro("funcionario_matricula")
+			l.SistemaClassificacao, l.NumeroClassificacao = lr.texto("sistema_classificacao"), lr.texto("numero_classificacao")
+			l.ObraID = nil
+			if id := lr.inteiro("obra_id"); id != 0 {
+				l.ObraID = &id
+			}
+			l.Idioma = lr.texto("idioma")
+			return l
+		},
+		validar: servico.ValidarLivro,
+		criar:   b.CriarLivro,
+		alterar: b.AtualizarLivro,
+		remover: b.DeletarLivro,
+		detalhes: func(ctx context.Context, f *formulario, l *model.Livro) {
+			f.Titulo = "Livro " + l.Titulo
+			f.Livro = s.livroDetalhes(ctx, l)
+		},
+	}
+}
+
+func nomesAutores(autores []model.Autor) string {
+	nomes := make([]string, len(autores))
+	for i, a := range autores {
+		nomes[i] = a.PrimeiroNome + " " + a.Sobrenome
+	}
+	return strings.Join(nomes, "; ")
+}
+
+// livroDetalhes é a seção de autores da página do livro, com o vínculo de
+// autores que no menu fica em "Adicionar Autor a um Livro"
+type livroDetalhes struct {
+	Autores     []autorVinculado
+	Vincular    string  // endereço do formulário de vínculo
+	Campos      []campo // ID, primeiro nome e sobrenome do autor a vincular
+	Cadastrados []model.Autor
+}
+
+type autorVinculado struct {
+	model.Autor
+	Remover string
+}
+
+func (s *Servidor) livroDetalhes(ctx context.Context, l *model.Livro) *livroDetalhes {
+	raiz := Prefixo + "/livros/" + url.PathEscape(l.ISBN) + "/autores"
+	d := &livroDetalhes{Vincular: raiz, Campos: []campo{
+		{Rotulo: "ID do autor", Chave: "id", Tipo: "number", Dica: "escolha um autor ou informe um ID novo", Lista: "autores"},
+		{Rotulo: "Primeiro nome", Chave: "primeiro_nome", Dica: "só para autores novos"},
+		{Rotulo: "Sobrenome", Chave: "sobrenome", Dica: "só para autores novos"},
+	}}
+	for _, a := range l.Autores {
+		d.Autores = append(d.Autores, autorVinculado{a, fmt.Sprintf("%s/%d/remover", raiz, a.ID)})
+	}
+	// a lista só serve de sugestão no campo de ID; sem ela o vínculo funciona igual
+	d.Cadastrados, _ = s.biblioteca.ListarAutores(ctx, "")
+	return d
+}
+
+func (s *Servidor) rotasAutoresDoLivro() {
+	raiz := Prefixo + "/livros/{isbn}/autores"
+	s.mux.HandleFunc("POST "+raiz, s.autenticado(s.vincularAutor))
+	s.mux.HandleFunc("POST "+raiz+"/{id}/remover", s.autenticado(s.desvincularAutor))
+}
+
+// vincularAutor relaciona um autor ao livro; autores ainda não cadastrados
+// são criados com o nome informado
+func (s *Servidor) vincularAutor(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	isbn := r.PathValue("isbn")
+	l := &leitor{form: r.PostForm}
+	a := model.Autor{ID: l.inteiro("id"), PrimeiroNome: l.texto("primeiro_nome"), Sobrenome: l.texto("sobrenome")}
+	var err error
+	if len(l.erros) > 0 {
+		err = l.erros
+	} else {
+		_, err = s.biblioteca.VincularAutor(r.Context(), isbn, a)
+	}
+	if err == nil {
+		s.redirecionar(w, r, ss, "/livros/"+url.PathEscape(isbn), fmt.Sprintf("Autor %d vinculado ao livro.", a.ID))
+		return
+	}
+
+	livro, errLivro := s.biblioteca.ObterLivro(r.Context(), isbn)
+	if errLivro != nil {
+		s.paginaErro(w, ss, "livros", errLivro)
+		return
+	}
+	f := s.livros().edicao(r.Context(), Prefixo+"/livros", livro)
+	p := s.base(ss, f.Titulo, "livros", err)
+	f.Livro.Campos = valoresDigitados(f.Livro.Campos, r.PostForm)
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		for _, e := range campos {
+			marcarErro(f.Livro.Campos, e)
+		}
+		p.Erro = "não foi possível vincular o autor; corrija os campos marcados"
+	}
+	p.Dados = f
+	s.renderizar(w, statusDoErro(err), "formulario", p)
+}
+
+func (s *Servidor) desvincularAutor(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	isbn := r.PathValue("isbn")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.biblioteca.DesvincularAutor(r.Context(), isbn, id); err != nil {
+		s.paginaErro(w, ss, "livros", err)
+		return
+	}
+	s.redirecionar(w, r, ss, "/livros/"+url.PathEscape(isbn), fmt.Sprintf("Autor %d desvinculado do livro.", id))
+}
@@ -0,0 +1,172 @@
+package web
+
+import "html/template"
+
+// paginas são os modelos de cada página, todos sobre o mesmo layout
+var paginas = map[string]*template.Template{
+	"login":      modelo("login", modeloLogin),
+	"lista":      modelo("lista", modeloLista),
+	"formulario": modelo("formulario", modeloFormulario),
+	"painel":     modelo("painel", modeloPainel),
+	"senha":      modelo("senha", modeloSenha),
+	"erro":       modelo("erro", `{{define "conteudo"}}<p><a href="{{.Prefixo}}/{{.Secao}}">Voltar</a></p>{{end}}`),
+}
+
+func modelo(nome, conteudo string) *template.Template {
+	return template.Must(template.Must(template.New(nome).Parse(modeloLayout)).Parse(conteudo))
+}
+
+const modeloLayout = `{{define "layout"}}<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Titulo}} — Biblioteca</title>
+<style>
+body { font-family: sans-serif; max-width: 70em; margin: 0 auto; padding: 0 1em 2em; color: #222; }
+nav { display: flex; gap: 1em; align-items: center; border-bottom: 1px solid #ccc; padding: .8em 0; margin-bottom: 1em; }
+nav a { text-decoration: none; color: #1565c0; }
+nav a.atual { font-weight: bold; color: #222; }
+nav form { margin-left: auto; }
+table { border-collapse: collapse; width: 100%; margin: .5em 0; }
+td, th { border-bottom: 1px solid #ddd; padding: .3em .6em; text-align: left; }
+tr:hover td { background: #f6f8fa; }
+label { display: block; margin: .5em 0 .2em; font-weight: bold; }
+input, select { padding: .3em; width: 24em; max-width: 100%; }
+button { padding: .3em 1em; cursor: pointer; }
+.campo { margin-bottom: .4em; }
+.dica { color: #666; font-size: .85em; }
+.erro-campo { color: #c62828; font-size: .9em; }
+.invalido { border: 1px solid #c62828; }
+.aviso { background: #e8f5e9; border: 1px solid #2e7d32; padding: .5em 1em; }
+.erro { background: #ffebee; border: 1px solid #c62828; padding: .5em 1em; }
+.acoes { display: flex; gap: .6em; margin-top: 1em; }
+.perigo { color: #c62828; }
+section { border-top: 1px solid #ccc; margin-top: 2em; }
+</style>
+</head>
+<body>
+{{if .Login}}<nav>
+<strong>Biblioteca</strong>
+{{range .Menu}}<a href="{{$.Prefixo}}/{{.Secao}}"{{if eq .Secao $.Secao}} class="atual"{{end}}>{{.Rotulo}}</a>
+{{end}}<form method="post" action="{{.Prefixo}}/sair">{{.Login}} <input type="hidden" name="csrf" value="{{.CSRF}}"><button>Sair</button></form>
+</nav>{{end}}
+<h1>{{.Titulo}}</h1>
+{{if .Aviso}}<p class="aviso">{{.Aviso}}</p>{{end}}
+{{if .Erro}}<p class="erro">ERRO: {{.Erro}}</p>{{end}}
+{{template "conteudo" .}}
+</body>
+</html>{{end}}
+
+{{define "campo"}}<div class="campo">
+<label for="{{.Chave}}">{{.Rotulo}}</label>
+{{if .Opcoes}}<select id="{{.Chave}}" name="{{.Chave}}"{{if .Erro}} class="invalido"{{end}}>
+{{$valor := .Valor}}{{range .Opcoes}}<option value="{{.}}"{{if eq . $valor}} selected{{end}}>{{.}}</option>{{end}}
+</select>{{else}}<input id="{{.Chave}}" name="{{.Chave}}" type="{{or .Tipo "text"}}" value="{{.Valor}}"{{if .Dica}} placeholder="{{.Dica}}"{{end}}{{if .Lista}} list="{{.Lista}}"{{end}}{{if .Erro}} class="invalido" aria-invalid="true"{{end}}>{{end}}
+{{if .Erro}}<div class="erro-campo">✗ {{.Erro}}</div>{{else if and .Dica .Opcoes}}<div class="dica">{{.Dica}}</div>{{end}}
+</div>{{end}}`
+
+const modeloLogin = `{{define "conteudo"}}<form method="post" action="{{.Prefixo}}/login">
+<input type="hidden" name="voltar" value="{{.Dados.Voltar}}">
+<label for="login">{{.Dados.Rotulo}}</label><input id="login" name="login" value="{{.Dados.Login}}" autofocus required>
+<label for="senha">Senha</label><input id="senha" name="senha" type="password" required>
+<div class="acoes"><button>Entrar</button></div>
+</form>{{end}}`
+
+const modeloLista = `{{define "conteudo"}}{{with .Dados}}<form method="get" class="acoes">
+<input name="q" value="{{.Busca}}" placeholder="{{.Dica}}" autofocus>
+<button>Buscar</button>
+<a href="{{.Novo}}">Novo cadastro</a>
+</form>
+{{if .Linhas}}<table>
+<tr>{{range .Colunas}}<th>{{.}}</th>{{end}}</tr>
+{{range .Linhas}}<tr>{{$link := .Link}}{{range $i, $c := .Celulas}}<td>{{if eq $i 0}}<a href="{{$link}}">{{$c}}</a>{{else}}{{$c}}{{end}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>Nenhum registro encontrado.</p>{{end}}{{end}}{{end}}`
+
+const modeloFormulario = `{{define "conteudo"}}{{$csrf := .CSRF}}{{$portal := "` + PrefixoPortal + `"}}{{with .Dados}}<form method="post" action="{{.Acao}}" novalidate>
+<input type="hidden" name="csrf" value="{{$csrf}}">
+{{range .Campos}}{{template "campo" .}}{{end}}
+<div class="acoes"><button>Salvar</button> <a href="{{.Voltar}}">Voltar à lista</a></div>
+</form>
+{{if or .Emprestar .Devolver .Excluir}}<div class="acoes">
+{{if .Emprestar}}<a href="{{.Emprestar}}">Novo empréstimo para este usuário</a>{{end}}
+{{if .Devolver}}<form method="post" action="{{.Devolver}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button>Registrar devolução</button></form>{{end}}
+{{if .Excluir}}<form method="post" action="{{.Excluir}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button class="perigo">Excluir</button></form>{{end}}
+</div>{{end}}
+{{if .Senha}}<section>
+<h2>Senha do portal</h2>
+<p class="dica">Com o CPF e esta senha o usuário acompanha os próprios empréstimos em <a href="{{$portal}}/">{{$portal}}</a>.</p>
+<form method="post" action="{{.Senha}}" novalidate>
+<input type="hidden" name="csrf" value="{{$csrf}}">
+<label for="senha">Nova senha</label><input id="senha" name="senha" type="password" autocomplete="new-password">
+<div class="acoes"><button>Definir senha</button></div>
+</form>
+</section>{{end}}
+{{with .Livro}}<section>
+<h2>Autores</h2>
+{{if .Autores}}<table>
+<tr><th>ID</th><th>Nome</th><th></th></tr>
+{{range .Autores}}<tr><td>{{.ID}}</td><td>{{.PrimeiroNome}} {{.Sobrenome}}</td>
+<td><form method="post" action="{{.Remover}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button>Desvincular</button></form></td></tr>
+{{end}}</table>{{else}}<p>Nenhum autor vinculado.</p>{{end}}
+<h3>Vincular autor</h3>
+<p class="dica">Autores ainda não cadastrados são criados com o nome informado.</p>
+<form method="post" action="{{.Vincular}}" novalidate>
+<input type="hidden" name="csrf" value="{{$csrf}}">
+{{range .Campos}}{{template "campo" .}}{{end}}
+<datalist id="autores">{{range .Cadastrados}}<option value="{{.ID}}">{{.PrimeiroNome}} {{.Sobrenome}}</option>{{end}}</datalist>
+<div class="acoes"><button>Vincular</button></div>
+</form>
+</section>{{end}}{{end}}{{end}}`
+
+const modeloPainel = `{{define "conteudo"}}{{$csrf := .CSRF}}{{$prefixo := .Prefixo}}{{with .Dados}}<p>Categoria {{.Categoria}}: até {{.Limite}} livro(s) emprestado(s) ao mesmo tempo, por {{.PrazoDias}} dias; você está com {{.EmPosse}}.
+{{if .Vinculo}}Vínculo válido até {{.Vinculo}}.{{end}}</p>
+{{if .Bloqueio}}<p class="erro">Novos empréstimos, renovações e reservas estão bloqueados: {{.Bloqueio}}.</p>
+{{else if .NoLimite}}<p class="erro">Novos empréstimos estão bloqueados: {{.NoLimite}}. Renovações e reservas continuam liberadas.</p>{{end}}
+<h2>Empréstimos ativos</h2>
+{{if .Ativos}}<table>
+<tr><th>ID</th><th>Data</th><th>Livros</th><th>Devolver até</th><th>Renovações</th><th>Atraso</th><th>Multa</th><th></th></tr>
+{{range .Ativos}}<tr><td>{{.ID}}</td><td>{{.Data}}</td><td>{{.Livros}}</td><td>{{.Vencimento}}</td><td>{{.Renovacoes}}</td>
+<td>{{if .DiasAtraso}}{{.DiasAtraso}} dia(s){{end}}</td><td>{{.Multa}}</td>
+<td>{{if .Renovar}}<form method="post" action="{{.Renovar}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button>Renovar</button></form>{{else}}<span class="dica">{{.Motivo}}</span>{{end}}</td></tr>
+{{end}}</table>{{else}}<p>Nenhum empréstimo ativo.</p>{{end}}
+{{range .Dependentes}}<section>
+<h2>{{.Nome}} ({{.CPF}}), sob sua responsabilidade</h2>
+{{if .Ativos}}<table>
+<tr><th>ID</th><th>Data</th><th>Livros</th><th>Devolver até</th><th>Renovações</th><th>Atraso</th><th>Multa</th><th></th></tr>
+{{range .Ativos}}<tr><td>{{.ID}}</td><td>{{.Data}}</td><td>{{.Livros}}</td><td>{{.Vencimento}}</td><td>{{.Renovacoes}}</td>
+<td>{{if .DiasAtraso}}{{.DiasAtraso}} dia(s){{end}}</td><td>{{.Multa}}</td>
+<td>{{if .Renovar}}<form method="post" action="{{.Renovar}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button>Renovar</button></form>{{else}}<span class="dica">{{.Motivo}}</span>{{end}}</td></tr>
+{{end}}</table>{{else}}<p>Nenhum empréstimo ativo.</p>{{end}}
+{{if .Multa}}<p>Multas: {{.Multa}}.</p>{{end}}
+</section>
+{{end}}{{if .MultaTotal}}<p>Total de multas{{if .Dependentes}}, incluídas as dos dependentes{{end}}: <strong>{{.MultaTotal}}</strong>. O pagamento é feito no balcão da biblioteca.</p>{{end}}
+<section>
+<h2>Reservas</h2>
+{{if .Reservas}}<table>
+<tr><th>ID</th><th>Livro</th><th>Data</th><th>Situação</th><th></th></tr>
+{{range .Reservas}}<tr><td>{{.ID}}</td><td>{{.Livro}}</td><td>{{.Data}}</td><td>{{.Situacao}}</td>
+<td>{{if .Cancelar}}<form method="post" action="{{.Cancelar}}"><input type="hidden" name="csrf" value="{{$csrf}}"><button class="perigo">Cancelar</button></form>{{end}}</td></tr>
+{{end}}</table>{{else}}<p>Nenhuma reserva.</p>{{end}}
+<h3>Reservar um livro</h3>
+<form method="post" action="{{$prefixo}}/reservas" novalidate>
+<input type="hidden" name="csrf" value="{{$csrf}}">
+{{range .Reservar}}{{template "campo" .}}{{end}}
+<div class="acoes"><button>Reservar</button></div>
+</form>
+</section>
+<section>
+<h2>Histórico</h2>
+{{if .Historico}}<table>
+<tr><th>ID</th><th>Data</th><th>Livros</th><th>Situação</th><th>Devolução</th><th>Atraso</th><th>Multa</th></tr>
+{{range .Historico}}<tr><td>{{.ID}}</td><td>{{.Data}}</td><td>{{.Livros}}</td><td>{{.Situacao}}</td><td>{{.Devolucao}}</td>
+<td>{{if .DiasAtraso}}{{.DiasAtraso}} dia(s){{end}}</td><td>{{.Multa}}</td></tr>
+{{end}}</table>{{else}}<p>Nenhum empréstimo anterior.</p>{{end}}
+</section>{{end}}{{end}}`
+
+const modeloSenha = `{{define "conteudo"}}<form method="post" action="{{.Prefixo}}/senha" novalidate>
+<input type="hidden" name="csrf" value="{{.CSRF}}">
+{{range .Dados}}{{template "campo" .}}{{end}}
+<div class="acoes"><button>Salvar</button> <a href="{{.Prefixo}}/">Voltar</a></div>
+</form>{{end}}`
@@ -0,0 +1,260 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// PrefixoPortal das páginas do portal do usuário
+const PrefixoPortal = "/portal"
+
+// NovoPortal cria o portal em que cada usuário da biblioteca consulta os
+// próprios empréstimos, prazos, multas e reservas, renova empréstimos e faz
+// reservas. O login é o CPF com a senha definida pela equipe; o CPF das
+// operações é sempre o da sessão e nenhuma página aceita outro, de modo que
+// registros de outros usuários simplesmente não existem para o portal
+func NovoPortal(b *servico.Biblioteca) *Servidor {
+	menu := []itemMenu{{"", "Meus empréstimos"}, {"senha", "Trocar senha"}}
+	s := novoServidor(b, PrefixoPortal, "biblioteca_portal", "CPF", menu, func(ctx context.Context, login, senha string) (conta, error) {
+		u, err := b.AutenticarUsuario(ctx, login, senha)
+		if err != nil {
+			return conta{}, err
+		}
+		return conta{u.CPF, u.PrimeiroNome + " " + u.Sobrenome}, nil
+	})
+	s.mux.HandleFunc("GET "+PrefixoPortal+"/{$}", s.autenticado(s.painel))
+	s.mux.HandleFunc("POST "+PrefixoPortal+"/emprestimos/{id}/renovar", s.autenticado(s.renovar))
+	s.mux.HandleFunc("POST "+PrefixoPortal+"/reservas", s.autenticado(s.reservar))
+	s.mux.HandleFunc("POST "+PrefixoPortal+"/reservas/{id}/cancelar", s.autenticado(s.cancelarReserva))
+	s.mux.HandleFunc("GET "+PrefixoPortal+"/senha", s.autenticado(s.paginaSenha))
+	s.mux.HandleFunc("POST "+PrefixoPortal+"/senha", s.autenticado(s.trocarSenha))
+	return s
+}
+
+type dadosPainel struct {
+	Categoria   string
+	PrazoDias   int
+	Limite      int
+	EmPosse     int    // livros dos empréstimos ativos
+	Vinculo     string // validade do vínculo; vazio se não expira
+	Bloqueio    string // por que o usuário não pode pegar livros nem renovar
+	NoLimite    string // por que o usuário não pode pegar mais livros, embora possa renovar
+	Ativos      []linhaEmprestimo
+	Historico   []linhaEmprestimo
+	Reservas    []linhaReserva
+	Dependentes []linhaDependente
+	MultaTotal  string
+	Reservar    []campo
+}
+
+// linhaDependente é um menor de idade pelo qual o usuário responde
+type linhaDependente struct {
+	Nome, CPF, Multa string
+	Ativos           []linhaEmprestimo
+}
+
+type linhaEmprestimo struct {
+	ID, Livros, Renovacoes, DiasAtraso int
+	Data, Vencimento, Devolucao        string
+	Situacao, Multa                    string
+	Renovar                            string // endereço da renovação; vazio se não pode ser renovado
+	Motivo                             string
+}
+
+type linhaReserva struct {
+	ID                    int
+	Livro, Data, Situacao string
+	Cancelar              string // endereço do cancelamento; vazio se a reserva não está ativa
+}
+
+func (s *Servidor) painel(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	s.exibirPainel(w, r, ss, http.StatusOK, nil)
+}
+
+// exibirPainel mostra o painel do usuário da sessão; err é o erro da operação
+// que acabou de ser tentada, com os erros do formulário de reserva ao lado
+// do campo
+func (s *Servidor) exibirPainel(w http.ResponseWriter, r *http.Request, ss *sessao, status int, err error) {
+	p, errPainel := s.biblioteca.PainelUsuario(r.Context(), ss.login)
+	if errPainel != nil {
+		s.paginaErro(w, ss, "", errPainel)
+		return
+	}
+	d := s.montarPainel(r.Context(), p)
+	pg := s.base(ss, "Meus empréstimos", "", err)
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		d.Reservar = valoresDigitados(d.Reservar, r.PostForm)
+		for _, e := range campos {
+			marcarErro(d.Reservar, e)
+		}
+		pg.Erro = "não foi possível reservar; corrija o campo marcado"
+	}
+	pg.Dados = d
+	s.renderizar(w, status, "painel", pg)
+}
+
+func (s *Servidor) montarPainel(ctx context.Context, p *servico.Painel) dadosPainel {
+	u := p.Situacao.Usuario
+	d := dadosPainel{Categoria: string(u.Categoria), PrazoDias: u.PrazoDias(), Limite: p.Situacao.LimiteLivros,
+		EmPosse: p.Situacao.LivrosEmPosse, Vinculo: data(u.ValidadeVinculo), MultaTotal: reais(p.MultaTotal),
+		Reservar: []campo{{Rotulo: "ISBN", Chave: "livro_isbn", Dica: "ISBN-10 ou ISBN-13 da edição desejada"}}}
+	switch {
+	case !p.Situacao.PodeRenovar:
+		d.Bloqueio = p.Situacao.Motivo
+	case !p.Situacao.PodeEmprestar:
+		d.NoLimite = p.Situacao.Motivo
+	}
+	for _, e := range p.Ativos {
+		l := linhaDoEmprestimo(e)
+		if e.Renovavel {
+			l.Renovar = fmt.Sprintf("%s/emprestimos/%d/renovar", PrefixoPortal, e.ID)
+		}
+		d.Ativos = append(d.Ativos, l)
+	}
+	for _, e := range p.Historico {
+		d.Historico = append(d.Historico, linhaDoEmprestimo(e))
+	}
+	for _, r := range p.Reservas {
+		d.Reservas = append(d.Reservas, s.linhaDaReserva(ctx, r))
+	}
+	for _, dep := range p.Dependentes {
+		l := linhaDependente{Nome: dep.Usuario.PrimeiroNome + " " + dep.Usuario.Sobrenome,
+			CPF: validacao.FormatarCPF(dep.Usuario.CPF), Multa: reais(dep.MultaTotal)}
+		for _, e := range dep.Ativos {
+			le := linhaDoEmprestimo(e)
+			if e.Renovavel {
+				le.Renovar = fmt.Sprintf("%s/emprestimos/%d/renovar", PrefixoPortal, e.ID)
+			}
+			l.Ativos = append(l.Ativos, le)
+		}
+		d.Dependentes = append(d.Dependentes, l)
+	}
+	return d
+}
+
+func linhaDoEmprestimo(e servico.SituacaoEmprestimo) linhaEmprestimo {
+	l := linhaEmprestimo{ID: e.ID, Livros: e.QuantLivros, Renovacoes: e.Renovacoes, DiasAtraso: e.DiasAtraso,
+		Data: data(e.DataEmprestimo), Vencimento: data(e.Vencimento), Multa: reais(e.Multa), Motivo: e.Motivo}
+	if e.DataDevolucao != nil {
+		l.Devolucao = data(*e.DataDevolucao)
+	}
+	switch e.Status {
+	case servico.StatusAtivo:
+		l.Situacao = "ativo"
+	case servico.StatusDevolvido:
+		l.Situacao = "devolvido"
+	default:
+		l.Situacao = "cancelado"
+	}
+	return l
+}
+
+func (s *Servidor) linhaDaReserva(ctx context.Context, r servico.PosicaoReserva) linhaReserva {
+	l := linhaReserva{ID: r.ID, Livro: r.LivroISBN, Data: data(r.DataReserva)}
+	if r.QualquerEdicao() {
+		l.Livro = fmt.Sprintf("qualquer edição da obra %d", *r.ObraID)
+	} else if livro, err := s.biblioteca.ObterLivro(ctx, r.LivroISBN); err == nil {
+		l.Livro = livro.Titulo + " (ISBN " + livro.ISBN + ")"
+	}
+	switch r.Status {
+	case model.ReservaAtiva:
+		l.Situacao = "aguardando"
+		if r.Posicao > 0 {
+			l.Situacao = fmt.Sprintf("%dº da fila", r.Posicao)
+		}
+		l.Cancelar = fmt.Sprintf("%s/reservas/%d/cancelar", PrefixoPortal, r.ID)
+	case model.ReservaAtendida:
+		l.Situacao = "atendida"
+	default:
+		l.Situacao = "cancelada"
+	}
+	return l
+}
+
+func (s *Servidor) renovar(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	e, err := s.biblioteca.RenovarEmprestimo(r.Context(), ss.login, id)
+	if err != nil {
+		s.exibirPainel(w, r, ss, statusDoErro(err), err)
+		return
+	}
+	s.redirecionar(w, r, ss, "/", fmt.Sprintf("Empréstimo %d renovado; o novo prazo é %s.", id, data(e.Vencimento)))
+}
+
+func (s *Servidor) reservar(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	reserva, err := s.biblioteca.Reservar(r.Context(), ss.login, r.PostFormValue("livro_isbn"))
+	if err != nil {
+		s.exibirPainel(w, r, ss, statusDoErro(err), err)
+		return
+	}
+	s.redirecionar(w, r, ss, "/", fmt.Sprintf("Reserva %d registrada.", reserva.ID))
+}
+
+func (s *Servidor) cancelarReserva(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.biblioteca.CancelarReserva(r.Context(), ss.login, id); err != nil {
+		s.exibirPainel(w, r, ss, statusDoErro(err), err)
+		return
+	}
+	s.redirecionar(w, r, ss, "/", fmt.Sprintf("Reserva %d cancelada.", id))
+}
+
+func camposSenha() []campo {
+	return []campo{
+		{Rotulo: "Senha atual", Chave: "senha_atual", Tipo: "password"},
+		{Rotulo: "Nova senha", Chave: "senha", Tipo: "password", Dica: fmt.Sprintf("pelo menos %d caracteres", servico.TamanhoMinimoSenha)},
+		{Rotulo: "Repita a nova senha", Chave: "confirmacao", Tipo: "password"},
+	}
+}
+
+func (s *Servidor) paginaSenha(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	p := s.base(ss, "Trocar senha", "senha", nil)
+	p.Dados = camposSenha()
+	s.renderizar(w, http.StatusOK, "senha", p)
+}
+
+// trocarSenha grava a nova senha; as senhas digitadas nunca voltam na página
+func (s *Servidor) trocarSenha(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	nova := r.PostFormValue("senha")
+	var err error
+	if nova != r.PostFormValue("confirmacao") {
+		err = validacao.Erros{{Campo: "confirmacao", Codigo: validacao.CodigoInvalido, Mensagem: "as senhas não conferem"}}
+	} else {
+		err = s.biblioteca.TrocarSenha(r.Context(), ss.login, r.PostFormValue("senha_atual"), nova)
+	}
+	if err == nil {
+		s.redirecionar(w, r, ss, "/", "Senha alterada.")
+		return
+	}
+	p := s.base(ss, "Trocar senha", "senha", err)
+	campos := camposSenha()
+	if erros, ok := validacao.ErrosDeCampo(err); ok {
+		for _, e := range erros {
+			marcarErro(campos, e)
+		}
+		p.Erro = "corrija os campos marcados"
+	}
+	p.Dados = campos
+	s.renderizar(w, statusDoErro(err), "senha", p)
+}
+
+// reais formata um valor em centavos; zero não é mostrado
+func reais(centavos int) string {
+	if centavos == 0 {
+		return ""
+	}
+	return fmt.Sprintf("R$ %d,%02d", centavos/100, centavos%100)
+}
@@ -0,0 +1,233 @@
+package web
+
+import (
+	"bufio"
+	"crud-biblioteca/servico"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Contas são os logins da equipe e o hash bcrypt da senha de cada um
+type Contas map[string][]byte
+
+// CarregarContas lê um arquivo no formato do htpasswd, uma conta por linha
+// ("login:hash"), como o gerado por `htpasswd -nbB login senha`. Só hashes
+// bcrypt são aceitos; linhas vazias e iniciadas por # são ignoradas
+func CarregarContas(arquivo string) (Contas, error) {
+	f, err := os.Open(arquivo)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	contas := Contas{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		linha := strings.TrimSpace(sc.Text())
+		if linha == "" || strings.HasPrefix(linha, "#") {
+			continue
+		}
+		login, hash, ok := strings.Cut(linha, ":")
+		if !ok || login == "" {
+			return nil, fmt.Errorf("%s:%d: use o formato login:hash", arquivo, n)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("%s:%d: a senha de '%s' não é um hash bcrypt", arquivo, n, login)
+		}
+		contas[login] = []byte(hash)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(contas) == 0 {
+		return nil, fmt.Errorf("%s: nenhuma conta cadastrada", arquivo)
+	}
+	return contas, nil
+}
+
+// ContasFromEnv carrega as contas do arquivo indicado por WEB_SENHAS. Sem a
+// variável, a interface web fica desativada e o retorno é nil
+func ContasFromEnv() (Contas, error) {
+	arquivo := os.Getenv("WEB_SENHAS")
+	if arquivo == "" {
+		return nil, nil
+	}
+	return CarregarContas(arquivo)
+}
+
+// autenticar confere a senha; um login inexistente leva o mesmo tempo que
+// uma senha errada
+func (c Contas) autenticar(login, senha string) bool {
+	return servico.ConferirSenha(c[login], senha)
+}
+
+// duracaoSessao é o tempo sem uso após o qual o login é pedido de novo
+const duracaoSessao = 8 * time.Hour
+
+type sessao struct {
+	login  string // o login da equipe ou, no portal, o CPF do usuário
+	nome   string // mostrado no menu
+	csrf   string // conferido em todo POST, contra envios forjados por outros sites
+	expira time.Time
+	aviso  string
+}
+
+// sessoes ficam em memória: reiniciar o servidor exige um novo login. Cada
+// interface tem as suas, em um cookie próprio restrito ao seu caminho
+type sessoes struct {
+	mu      sync.Mutex
+	m       map[string]*sessao
+	cookie  string
+	caminho string
+}
+
+func novasSessoes(cookie, caminho string) *sessoes {
+	return &sessoes{m: map[string]*sessao{}, cookie: cookie, caminho: caminho}
+}
+
+func (ss *sessoes) criar(c conta) string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	agora := time.Now()
+	for id, s := range ss.m {
+		if agora.After(s.expira) {
+			delete(ss.m, id)
+		}
+	}
+	id := aleatorio()
+	s := &sessao{login: c.login, nome: c.nome, csrf: aleatorio(), expira: agora.Add(duracaoSessao)}
+	ss.m[id] = s
+	return id
+}
+
+// obter devolve a sessão válida do cookie e renova a sua validade
+func (ss *sessoes) obter(r *http.Request) *sessao {
+	c, err := r.Cookie(ss.cookie)
+	if err != nil {
+		return nil
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	s, ok := ss.m[c.Value]
+	if !ok || time.Now().After(s.expira) {
+		delete(ss.m, c.Value)
+		return nil
+	}
+	s.expira = time.Now().Add(duracaoSessao)
+	return s
+}
+
+func (ss *sessoes) encerrar(r *http.Request) {
+	if c, err := r.Cookie(ss.cookie); err == nil {
+		ss.mu.Lock()
+		delete(ss.m, c.Value)
+		ss.mu.Unlock()
+	}
+}
+
+func (ss *sessoes) avisar(s *sessao, aviso string) {
+	ss.mu.Lock()
+	s.aviso = aviso
+	ss.mu.Unlock()
+}
+
+// lerAviso devolve o aviso pendente e o descarta
+func (ss *sessoes) lerAviso(s *sessao) string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	aviso := s.aviso
+	s.aviso = ""
+	return aviso
+}
+
+func aleatorio() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (ss *sessoes) definirCookie(w http.ResponseWriter, r *http.Request, valor string, idade int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ss.cookie,
+		Value:    valor,
+		Path:     ss.caminho,
+		MaxAge:   idade,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// autenticado exige login para a página; nos POSTs confere também o token
+// CSRF do formulário
+func (s *Servidor) autenticado(h func(w http.ResponseWriter, r *http.Request, ss *sessao)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ss := s.sessoes.obter(r)
+		if ss == nil {
+			if r.Method != http.MethodGet {
+				http.Error(w, "sessão expirada; entre novamente", http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, s.prefixo+"/login?voltar="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+			return
+		}
+		if r.Method == http.MethodPost && subtle.ConstantTimeCompare([]byte(r.PostFormValue("csrf")), []byte(ss.csrf)) != 1 {
+			http.Error(w, "formulário inválido ou expirado; recarregue a página", http.StatusForbidden)
+			return
+		}
+		h(w, r, ss)
+	}
+}
+
+// errLogin é a falha de login da equipe
+var errLogin = errors.New("login ou senha incorretos")
+
+type dadosLogin struct {
+	Rotulo, Login, Voltar string
+}
+
+func (s *Servidor) paginaLogin(w http.ResponseWriter, r *http.Request) {
+	dados := dadosLogin{Rotulo: s.rotuloLogin, Voltar: s.voltar(r.URL.Query().Get("voltar"))}
+	s.renderizar(w, http.StatusOK, "login", pagina{Titulo: "Entrar", Dados: dados})
+}
+
+func (s *Servidor) entrar(w http.ResponseWriter, r *http.Request) {
+	login, senha := strings.TrimSpace(r.PostFormValue("login")), r.PostFormValue("senha")
+	dados := dadosLogin{Rotulo: s.rotuloLogin, Login: login, Voltar: s.voltar(r.PostFormValue("voltar"))}
+	c, err := s.autenticar(r.Context(), login, senha)
+	if err != nil {
+		s.renderizar(w, statusDoErro(err), "login", pagina{Titulo: "Entrar", Erro: descreverErro(err), Dados: dados})
+		return
+	}
+	id := s.sessoes.criar(c)
+	s.sessoes.definirCookie(w, r, id, int(duracaoSessao.Seconds()))
+	http.Redirect(w, r, dados.Voltar, http.StatusSeeOther)
+}
+
+func (s *Servidor) sair(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	s.sessoes.encerrar(r)
+	s.sessoes.definirCookie(w, r, "", -1)
+	http.Redirect(w, r, s.prefixo+"/login", http.StatusSeeOther)
+}
+
+// voltar só aceita destinos dentro da interface, para que o link de login
+// não sirva para redirecionar a outros sites
+func (s *Servidor) voltar(destino string) string {
+	if !strings.HasPrefix(destino, s.prefixo+"/") || strings.HasPrefix(destino, "//") || strings.Contains(destino, "\\") {
+		return s.prefixo + "/"
+	}
+	return destino
+}
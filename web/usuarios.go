@@ -0,0 +1,117 @@
+package web
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/repository"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+var categorias = []string{"", string(model.CategoriaGraduacao), string(model.CategoriaPos), string(model.CategoriaDocente),
+	string(model.CategoriaTecnico), string(model.CategoriaExterno)}
+
+func (s *Servidor) usuarios() cadastro[model.Usuario] {
+	b := s.biblioteca
+	return cadastro[model.Usuario]{
+		secao:   "usuarios",
+		titulo:  "Usuários",
+		nome:    "usuário",
+		dica:    "nome ou CPF",
+		colunas: []string{"CPF", "Nome", "Categoria", "Matrícula", "Validade do vínculo"},
+		listar: func(ctx context.Context, termo string) ([]model.Usuario, error) {
+			if _, err := validacao.NormalizarCPF(termo); err == nil {
+				return unico(b.ObterUsuario(ctx, termo))
+			}
+			return b.ListarUsuarios(ctx, repository.FiltroUsuario{Nome: termo})
+		},
+		celulas: func(u *model.Usuario) []string {
+			return []string{validacao.FormatarCPF(u.CPF), u.PrimeiroNome + " " + u.Sobrenome, string(u.Categoria), u.Matricula, data(u.ValidadeVinculo)}
+		},
+		chave: func(u *model.Usuario) string { return u.CPF },
+		obter: b.ObterUsuario,
+		campos: func(u *model.Usuario, criar bool) []campo {
+			var c []campo
+			if criar {
+				c = append(c, campo{Rotulo: "CPF", Chave: "cpf", Valor: u.CPF})
+			}
+			e := u.Endereco
+			return append(c,
+				campo{Rotulo: "Primeiro nome", Chave: "primeiro_nome", Valor: u.PrimeiroNome},
+				campo{Rotulo: "Sobrenome", Chave: "sobrenome", Valor: u.Sobrenome},
+				campo{Rotulo: "Nascimento", Chave: "data_nascimento", Valor: data(u.DataNascimento), Tipo: "date"},
+				campo{Rotulo: "E-mail", Chave: "email", Valor: u.Email, Tipo: "email"},
+				campo{Rotulo: "Telefone", Chave: "telefone", Valor: u.Telefone, Tipo: "tel"},
+				campo{Rotulo: "Categoria", Chave: "categoria", Valor: string(u.Categoria), Opcoes: categorias},
+				campo{Rotulo: "Matrícula", Chave: "matricula", Valor: u.Matricula},
+				campo{Rotulo: "Validade do vínculo", Chave: "validade_vinculo", Valor: data(u.ValidadeVinculo), Tipo: "date"},
+				campo{Rotulo: "CPF do responsável", Chave: "responsavel_cpf", Valor: u.ResponsavelCPF, Dica: "obrigatório para menores de idade"},
+				campo{Rotulo: "Logradouro", Chave: "endereco.logradouro", Valor: e.Logradouro},
+				campo{Rotulo: "Número", Chave: "endereco.numero", Valor: e.Numero},
+				campo{Rotulo: "Bairro", Chave: "endereco.bairro", Valor: e.Bairro},
+				campo{Rotulo: "Cidade", Chave: "endereco.cidade", Valor: e.Cidade},
+				campo{Rotulo: "UF", Chave: "endereco.uf", Valor: e.UF},
+				campo{Rotulo: "CEP", Chave: "endereco.cep", Valor: e.CEP},
+			)
+		},
+		montar: func(l *leitor, u model.Usuario, criar bool) model.Usuario {
+			if criar {
+				u.CPF = l.texto("cpf")
+			}
+			u.PrimeiroNome, u.Sobrenome = l.texto("primeiro_nome"), l.texto("sobrenome")
+			u.DataNascimento = l.data("data_nascimento")
+			u.Email, u.Telefone = l.texto("email"), l.texto("telefone")
+			u.Categoria = model.CategoriaUsuario(l.texto("categoria"))
+			u.Matricula = l.texto("matricula")
+			u.ValidadeVinculo = l.data("validade_vinculo")
+			u.ResponsavelCPF = l.texto("responsavel_cpf")
+			u.Endereco = model.Endereco{Logradouro: l.texto("endereco.logradouro"), Numero: l.texto("endereco.numero"),
+				Bairro: l.texto("endereco.bairro"), Cidade: l.texto("endereco.cidade"), UF: l.texto("endereco.uf"), CEP: l.texto("endereco.cep")}
+			return u
+		},
+		validar: servico.ValidarUsuario,
+		criar:   b.CriarUsuario,
+		alterar: b.AtualizarUsuario,
+		remover: b.DeletarUsuario,
+		detalhes: func(ctx context.Context, f *formulario, u *model.Usuario) {
+			f.Titulo = "Usuário " + u.PrimeiroNome + " " + u.Sobrenome
+			f.Emprestar = Prefixo + "/emprestimos/novo?cpf=" + url.QueryEscape(u.CPF)
+			f.Senha = Prefixo + "/usuarios/" + url.PathEscape(u.CPF) + "/senha"
+		},
+	}
+}
+
+func (s *Servidor) rotasSenhaDoPortal() {
+	s.mux.HandleFunc("POST "+Prefixo+"/usuarios/{cpf}/senha", s.autenticado(s.definirSenha))
+}
+
+// definirSenha grava a senha do portal do usuário, para o primeiro acesso ou
+// quando ele a esquece
+func (s *Servidor) definirSenha(w http.ResponseWriter, r *http.Request, ss *sessao) {
+	cpf := r.PathValue("cpf")
+	err := s.biblioteca.DefinirSenha(r.Context(), cpf, r.PostFormValue("senha"))
+	if err == nil {
+		s.redirecionar(w, r, ss, "/usuarios/"+url.PathEscape(cpf), "Senha do portal definida.")
+		return
+	}
+	u, errUsuario := s.biblioteca.ObterUsuario(r.Context(), cpf)
+	if errUsuario != nil {
+		s.paginaErro(w, ss, "usuarios", errUsuario)
+		return
+	}
+	s.exibirFormulario(w, ss, "usuarios", statusDoErro(err), s.usuarios().edicao(r.Context(), Prefixo+"/usuarios", u), err)
+}
+
+// unico trata a busca por chave: o registro encontrado ou nenhum, se não existir
+func unico[T any](registro *T, err error) ([]T, error) {
+	if err != nil {
+		if errors.Is(err, repository.ErrNaoEncontrado) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []T{*registro}, nil
+}
@@ -0,0 +1,157 @@
+// Package web implementa as interfaces web da biblioteca: páginas geradas no
+// servidor com html/template, sem scripts. A da equipe (New) busca e cadastra
+// usuários, livros, autores e empréstimos, com as contas vindas de um arquivo
+// no formato do htpasswd; o portal do usuário (NovoPortal) mostra a cada
+// usuário os próprios empréstimos e reservas. As regras de negócio são as do
+// pacote servico, as mesmas da API REST e do menu interativo.
+package web
+
+import (
+	"context"
+	"crud-biblioteca/resposta"
+	"crud-biblioteca/servico"
+	"crud-biblioteca/validacao"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Prefixo das páginas da interface web
+const Prefixo = "/web"
+
+// Servidor atende as páginas de uma das interfaces, todas abaixo de prefixo
+type Servidor struct {
+	biblioteca *servico.Biblioteca
+	prefixo    string
+	menu       []itemMenu
+	// autenticar confere o login e a senha; a falha de credenciais é errLogin
+	// ou servico.ErrCredenciais
+	autenticar  func(ctx context.Context, login, senha string) (conta, error)
+	rotuloLogin string // rótulo do campo de login: "Login" ou "CPF"
+	sessoes     *sessoes
+	mux         *http.ServeMux
+}
+
+type itemMenu struct {
+	Secao, Rotulo string
+}
+
+// conta é quem entrou: o login guardado na sessão e o nome mostrado no menu
+type conta struct {
+	login, nome string
+}
+
+func novoServidor(b *servico.Biblioteca, prefixo, cookie, rotuloLogin string, menu []itemMenu,
+	autenticar func(ctx context.Context, login, senha string) (conta, error)) *Servidor {
+	s := &Servidor{biblioteca: b, prefixo: prefixo, menu: menu, autenticar: autenticar, rotuloLogin: rotuloLogin,
+		sessoes: novasSessoes(cookie, prefixo), mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET "+prefixo+"/login", s.paginaLogin)
+	s.mux.HandleFunc("POST "+prefixo+"/login", s.entrar)
+	s.mux.HandleFunc("POST "+prefixo+"/sair", s.autenticado(s.sair))
+	return s
+}
+
+// New cria a interface da equipe
+func New(b *servico.Biblioteca, contas Contas) *Servidor {
+	menu := []itemMenu{{"usuarios", "Usuários"}, {"livros", "Livros"}, {"autores", "Autores"}, {"emprestimos", "Empréstimos"}}
+	s := novoServidor(b, Prefixo, "biblioteca_sessao", "Login", menu, func(_ context.Context, login, senha string) (conta, error) {
+		if !contas.autenticar(login, senha) {
+			return conta{}, errLogin
+		}
+		return conta{login, login}, nil
+	})
+	s.mux.Handle("GET "+Prefixo+"/{$}", http.RedirectHandler(Prefixo+"/usuarios", http.StatusFound))
+	registrar(s, s.usuarios())
+	registrar(s, s.livros())
+	registrar(s, s.autores())
+	registrar(s, s.emprestimos())
+	s.rotasAutoresDoLivro()
+	s.rotasDevolucao()
+	s.rotasSenhaDoPortal()
+	return s
+}
+
+// ServeHTTP atende a requisição e registra no log o método, o caminho, o
+// status e a duração
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	inicio := time.Now()
+	rw := resposta.Registrar(w)
+	// as páginas mostram dados pessoais e não devem ficar em cache
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("X-Frame-Options", "DENY")
+	s.mux.ServeHTTP(rw, r)
+	log.Printf("%s %s %d %s", r.Method, r.URL.Path, rw.Status, time.Since(inicio).Round(time.Millisecond))
+}
+
+// pagina são os dados comuns a todas as páginas; Dados é o conteúdo próprio
+// de cada uma
+type pagina struct {
+	Titulo  string
+	Secao   string // item do menu destacado
+	Login   string // nome de quem entrou; vazio nas páginas sem login
+	CSRF    string
+	Aviso   string
+	Erro    string
+	Prefixo string
+	Menu    []itemMenu
+	Dados   any
+}
+
+func (s *Servidor) renderizar(w http.ResponseWriter, status int, nome string, p pagina) {
+	p.Prefixo, p.Menu = s.prefixo, s.menu
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := paginas[nome].ExecuteTemplate(w, "layout", p); err != nil {
+		log.Printf("ERRO: falha ao gerar a página %s: %v\n", nome, err)
+	}
+}
+
+// redirecionar volta para uma página após um POST, com uma mensagem de
+// sucesso que aparece uma única vez
+func (s *Servidor) redirecionar(w http.ResponseWriter, r *http.Request, ss *sessao, caminho, aviso string) {
+	if aviso != "" {
+		s.sessoes.avisar(ss, aviso)
+	}
+	http.Redirect(w, r, s.prefixo+caminho, http.StatusSeeOther)
+}
+
+// statusDoErro escolhe o status HTTP a partir da classe do erro, como na API
+func statusDoErro(err error) int {
+	if errors.Is(err, errLogin) {
+		return http.StatusUnauthorized
+	}
+	return resposta.Status(err)
+}
+
+// descreverErro é a mensagem mostrada ao operador; o detalhe de erros
+// internos fica apenas no log do servidor
+func descreverErro(err error) string {
+	if statusDoErro(err) == http.StatusInternalServerError {
+		log.Printf("ERRO: %v\n", err)
+		return "erro interno do servidor"
+	}
+	if campos, ok := validacao.ErrosDeCampo(err); ok {
+		mensagens := make([]string, len(campos))
+		for i, c := range campos {
+			mensagens[i] = c.Mensagem
+		}
+		return "dados inválidos: " + strings.Join(mensagens, "; ")
+	}
+	return err.Error()
+}
+
+// paginaErro mostra um erro que impede a página de ser montada, como um
+// registro inexistente
+func (s *Servidor) paginaErro(w http.ResponseWriter, ss *sessao, secao string, err error) {
+	s.renderizar(w, statusDoErro(err), "erro", s.base(ss, "Erro", secao, err))
+}
+
+func (s *Servidor) base(ss *sessao, titulo, secao string, err error) pagina {
+	p := pagina{Titulo: titulo, Secao: secao, Login: ss.nome, CSRF: ss.csrf, Aviso: s.sessoes.lerAviso(ss)}
+	if err != nil {
+		p.Erro = descreverErro(err)
+	}
+	return p
+}
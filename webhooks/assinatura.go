@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A assinatura vai no cabeçalho X-Biblioteca-Assinatura no formato
+// "t=<segundos desde 1970>,v1=<HMAC-SHA256 em hexadecimal>", calculada sobre
+// "<t>.<corpo>". O momento entra na conta para que uma entrega capturada não
+// possa ser reenviada depois, e o destino deve recusar assinaturas antigas
+
+// ToleranciaAssinatura é a diferença máxima aceita por VerificarAssinatura
+// entre o momento da assinatura e o relógio de quem recebe
+const ToleranciaAssinatura = 5 * time.Minute
+
+// ErrAssinatura indica uma entrega que não foi assinada com o segredo esperado
+var ErrAssinatura = errors.New("assinatura do webhook inválida")
+
+func Assinar(segredo string, momento time.Time, corpo []byte) string {
+	t := momento.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(hmacCorpo(segredo, t, corpo)))
+}
+
+func hmacCorpo(segredo string, t int64, corpo []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	fmt.Fprintf(mac, "%d.", t)
+	mac.Write(corpo)
+	return mac.Sum(nil)
+}
+
+// VerificarAssinatura confere o cabeçalho recebido com o corpo da requisição,
+// para uso por quem recebe os eventos
+func VerificarAssinatura(segredo, cabecalho string, corpo []byte, agora time.Time) error {
+	var t int64
+	var recebida []byte
+	for _, parte := range strings.Split(cabecalho, ",") {
+		chave, valor, _ := strings.Cut(strings.TrimSpace(parte), "=")
+		switch chave {
+		case "t":
+			t, _ = strconv.ParseInt(valor, 10, 64)
+		case "v1":
+			recebida, _ = hex.DecodeString(valor)
+		}
+	}
+	if t == 0 || recebida == nil {
+		return fmt.Errorf("%w: cabeçalho malformado", ErrAssinatura)
+	}
+	if d := agora.Sub(time.Unix(t, 0)); d > ToleranciaAssinatura || d < -ToleranciaAssinatura {
+		return fmt.Errorf("%w: assinada há %s", ErrAssinatura, d.Round(time.Second))
+	}
+	if !hmac.Equal(recebida, hmacCorpo(segredo, t, corpo)) {
+		return fmt.Errorf("%w: não confere com o segredo", ErrAssinatura)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerificarAssinatura(t *testing.T) {
+	momento := time.Unix(1747750000, 0)
+	corpo := []byte(`{"id":"3f9c","evento":"emprestimo.criado"}`)
+	cabecalho := Assinar("segredo", momento, corpo)
+
+	casos := []struct {
+		nome      string
+		segredo   string
+		cabecalho string
+		corpo     []byte
+		agora     time.Time
+		erro      error
+	}{
+		{"válida", "segredo", cabecalho, corpo, momento, nil},
+		{"dentro da tolerância", "segredo", cabecalho, corpo, momento.Add(ToleranciaAssinatura), nil},
+		{"relógio atrasado", "segredo", cabecalho, corpo, momento.Add(-ToleranciaAssinatura), nil},
+		{"fora da tolerância", "segredo", cabecalho, corpo, momento.Add(ToleranciaAssinatura + time.Second), ErrAssinatura},
+		{"assinada no futuro", "segredo", cabecalho, corpo, momento.Add(-ToleranciaAssinatura - time.Second), ErrAssinatura},
+		{"corpo alterado", "segredo", cabecalho, []byte(`{"id":"3f9c","evento":"emprestimo.devolvido"}`), momento, ErrAssinatura},
+		{"segredo errado", "outro", cabecalho, corpo, momento, ErrAssinatura},
+		{"momento alterado", "segredo", "t=1747750001" + cabecalho[len("t=1747750000"):], corpo, momento, ErrAssinatura},
+		{"sem v1", "segredo", "t=1747750000", corpo, momento, ErrAssinatura},
+		{"v1 fora do hexadecimal", "segredo", "t=1747750000,v1=xyz", corpo, momento, ErrAssinatura},
+		{"vazio", "segredo", "", corpo, momento, ErrAssinatura},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			err := VerificarAssinatura(c.segredo, c.cabecalho, c.corpo, c.agora)
+			if !errors.Is(err, c.erro) {
+				t.Errorf("VerificarAssinatura(%q): erro %v, esperado %v", c.cabecalho, err, c.erro)
+			}
+		})
+	}
+}
+
+func TestAssinarFormato(t *testing.T) {
+	obtido := Assinar("segredo", time.Unix(1747750000, 0), []byte("{}"))
+	if len(obtido) != len("t=1747750000,v1=")+64 || obtido[:len("t=1747750000,v1=")] != "t=1747750000,v1=" {
+		t.Errorf("Assinar = %q, esperado t=1747750000,v1=<64 dígitos hexadecimais>", obtido)
+	}
+}
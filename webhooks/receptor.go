@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Receptor é um destino local para testar as assinaturas sem o sistema
+// integrado: mostra cada entrega recebida e, com o segredo, confere a
+// assinatura. Para exercitar as novas tentativas, as primeiras falhas
+// tentativas de cada entrega são respondidas com 503
+type Receptor struct {
+	segredo string
+	falhas  int
+	saida   io.Writer
+
+	mu        sync.Mutex
+	tentativa map[string]int // tentativas recebidas por entrega
+}
+
+func NovoReceptor(segredo string, falhas int, saida io.Writer) *Receptor {
+	return &Receptor{segredo: segredo, falhas: falhas, saida: saida, tentativa: map[string]int{}}
+}
+
+func (rc *Receptor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	corpo, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entrega := r.Header.Get(CabecalhoEntrega)
+	rc.mu.Lock()
+	rc.tentativa[entrega]++
+	n := rc.tentativa[entrega]
+	rc.mu.Unlock()
+
+	situacao := "assinatura não conferida (informe o segredo)"
+	status := http.StatusNoContent
+	if rc.segredo != "" {
+		situacao = "assinatura válida"
+		if err := VerificarAssinatura(rc.segredo, r.Header.Get(CabecalhoAssinatura), corpo, time.Now()); err != nil {
+			situacao, status = err.Error(), http.StatusUnauthorized
+		}
+	}
+	if status == http.StatusNoContent && n <= rc.falhas {
+		situacao, status = fmt.Sprintf("%s; falha simulada %d de %d", situacao, n, rc.falhas), http.StatusServiceUnavailable
+	}
+
+	var formatado bytes.Buffer
+	if json.Indent(&formatado, corpo, "  ", "  ") != nil {
+		formatado.Reset()
+		formatado.Write(corpo)
+	}
+	fmt.Fprintf(rc.saida, "%s %s entrega %s, tentativa %d: %s → %d\n  %s\n",
+		time.Now().Format("15:04:05"), r.Header.Get(CabecalhoEvento), entrega, n, situacao, status, formatado.String())
+	w.WriteHeader(status)
+}
@@ -0,0 +1,190 @@
+// Package webhooks entrega aos sistemas externos os eventos de circulação
+// enfileirados pelo pacote servico: cada entrega é um POST com o JSON do
+// evento, assinado com HMAC-SHA256, repetido com espera crescente até o
+// destino responder 2xx ou as tentativas acabarem. O resultado de cada
+// tentativa fica gravado na própria entrega, que serve de registro.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// cabeçalhos enviados com cada entrega
+const (
+	CabecalhoEvento     = "X-Biblioteca-Evento"
+	CabecalhoEntrega    = "X-Biblioteca-Entrega"
+	CabecalhoAssinatura = "X-Biblioteca-Assinatura"
+)
+
+// Config controla as entregas
+type Config struct {
+	Tentativas int           // tentativas de cada entrega antes de desistir
+	Intervalo  time.Duration // espera após a primeira falha, dobrada a cada nova falha
+	Timeout    time.Duration // tempo máximo de cada requisição
+	Varredura  time.Duration // de quanto em quanto tempo a fila é consultada
+	Prazos     time.Duration // de quanto em quanto tempo os vencimentos e atrasos são procurados
+}
+
+// esperaMaxima limita o intervalo entre tentativas
+const esperaMaxima = 6 * time.Hour
+
+// tamanhoLote é quantas entregas são tentadas a cada consulta da fila
+const tamanhoLote = 50
+
+// ConfigFromEnv lê WEBHOOK_TENTATIVAS (padrão 8) e WEBHOOK_INTERVALO (padrão
+// 30s, no formato de time.ParseDuration). Com os padrões, a última tentativa
+// acontece pouco mais de uma hora depois da primeira
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Tentativas: 8, Intervalo: 30 * time.Second, Timeout: 10 * time.Second,
+		Varredura: 5 * time.Second, Prazos: time.Hour}
+	if v := os.Getenv("WEBHOOK_TENTATIVAS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("WEBHOOK_TENTATIVAS deve ser um número positivo: '%s'", v)
+		}
+		cfg.Tentativas = n
+	}
+	if v := os.Getenv("WEBHOOK_INTERVALO"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("WEBHOOK_INTERVALO deve ser uma duração positiva, como 30s ou 5m: '%s'", v)
+		}
+		cfg.Intervalo = d
+	}
+	return cfg, nil
+}
+
+type Entregador struct {
+	biblioteca *servico.Biblioteca
+	cfg        Config
+	cliente    *http.Client
+}
+
+func New(b *servico.Biblioteca, cfg Config) *Entregador {
+	return &Entregador{biblioteca: b, cfg: cfg, cliente: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Executar consulta a fila e procura empréstimos atrasados periodicamente
+// até o contexto ser cancelado
+func (e *Entregador) Executar(ctx context.Context) {
+	fila := time.NewTicker(e.cfg.Varredura)
+	defer fila.Stop()
+	prazos := time.NewTicker(e.cfg.Prazos)
+	defer prazos.Stop()
+
+	e.verificarPrazos(ctx)
+	for {
+		if _, err := e.Processar(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("ERRO: webhooks: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-prazos.C:
+			e.verificarPrazos(ctx)
+		case <-fila.C:
+		}
+	}
+}
+
+func (e *Entregador) verificarPrazos(ctx context.Context) {
+	n, err := e.biblioteca.NotificarPrazos(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("ERRO: webhooks: não foi possível procurar vencimentos e atrasos: %v\n", err)
+	} else if n > 0 {
+		log.Printf("Webhooks: %d aviso(s) de vencimento ou atraso enfileirado(s).\n", n)
+	}
+}
+
+// Processar tenta as entregas pendentes cuja vez já chegou, até esvaziar a
+// fila, e retorna quantas foram tentadas
+func (e *Entregador) Processar(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		pendentes, err := e.biblioteca.EntregasPendentes(ctx, tamanhoLote)
+		if err != nil {
+			return total, err
+		}
+		for _, p := range pendentes {
+			if ctx.Err() != nil {
+				return total, ctx.Err()
+			}
+			entrega := e.tentar(ctx, p)
+			if err := e.biblioteca.RegistrarTentativa(ctx, entrega); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(pendentes) < tamanhoLote {
+			return total, nil
+		}
+	}
+}
+
+// tentar envia a entrega e devolve a entrega com o resultado: entregue,
+// pendente com a próxima tentativa agendada ou desistida
+func (e *Entregador) tentar(ctx context.Context, p servico.EntregaPendente) model.EntregaWebhook {
+	entrega := p.Entrega
+	entrega.Tentativas++
+	status, err := e.enviar(ctx, p.Webhook, entrega)
+	agora := time.Now()
+	entrega.UltimoStatus, entrega.UltimoErro = status, ""
+	if err == nil {
+		entrega.Situacao, entrega.EntregueEm = model.EntregaEntregue, &agora
+		return entrega
+	}
+	entrega.UltimoErro = err.Error()
+	if entrega.Tentativas >= e.cfg.Tentativas || status == http.StatusGone {
+		// 410 indica que o destino não quer mais receber os eventos
+		entrega.Situacao = model.EntregaDesistida
+		log.Printf("AVISO: webhook %d: desistindo da entrega %s após %d tentativa(s): %v\n", p.Webhook.ID, entrega.ID, entrega.Tentativas, err)
+		return entrega
+	}
+	entrega.ProximaTentativa = agora.Add(e.espera(entrega.Tentativas))
+	return entrega
+}
+
+// espera é o intervalo antes da próxima tentativa, após n falhas
+func (e *Entregador) espera(n int) time.Duration {
+	d := e.cfg.Intervalo
+	for i := 1; i < n && d < esperaMaxima; i++ {
+		d *= 2
+	}
+	return min(d, esperaMaxima)
+}
+
+// enviar faz o POST e retorna o status da resposta; erro para falhas de rede
+// e respostas fora da faixa 2xx
+func (e *Entregador) enviar(ctx context.Context, w model.Webhook, entrega model.EntregaWebhook) (int, error) {
+	corpo := []byte(entrega.Corpo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(corpo))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "crud-biblioteca-webhooks")
+	req.Header.Set(CabecalhoEvento, entrega.Evento)
+	req.Header.Set(CabecalhoEntrega, entrega.ID)
+	req.Header.Set(CabecalhoAssinatura, Assinar(w.Segredo, time.Now(), corpo))
+	resp, err := e.cliente.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// só o início da resposta é guardado, para o registro
+	trecho, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("o destino respondeu %s: %s", resp.Status, bytes.TrimSpace(trecho))
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+	"crud-biblioteca/model"
+	"crud-biblioteca/servico"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEspera(t *testing.T) {
+	e := &Entregador{cfg: Config{Intervalo: 30 * time.Second}}
+	casos := []struct {
+		falhas   int
+		esperado time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, esperaMaxima},
+		{1000, esperaMaxima},
+	}
+	for _, c := range casos {
+		if obtido := e.espera(c.falhas); obtido != c.esperado {
+			t.Errorf("espera(%d) = %s, esperado %s", c.falhas, obtido, c.esperado)
+		}
+	}
+}
+
+func TestEntregaAoReceptor(t *testing.T) {
+	casos := []struct {
+		nome       string
+		segredo    string // segredo conferido pelo receptor
+		falhas     int
+		tentativas int
+		situacoes  []string // situação após cada tentativa
+		status     int      // status da última resposta
+	}{
+		{"entregue na primeira", "segredo", 0, 3, []string{model.EntregaEntregue}, http.StatusNoContent},
+		{"entregue após falha", "segredo", 1, 3, []string{model.EntregaPendente, model.EntregaEntregue}, http.StatusNoContent},
+		{"receptor sem segredo", "", 0, 3, []string{model.EntregaEntregue}, http.StatusNoContent},
+		{"segredo errado", "outro", 0, 2, []string{model.EntregaPendente, model.EntregaDesistida}, http.StatusUnauthorized},
+		{"tentativas esgotadas", "segredo", 5, 2, []string{model.EntregaPendente, model.EntregaDesistida}, http.StatusServiceUnavailable},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			srv := httptest.NewServer(NovoReceptor(c.segredo, c.falhas, io.Discard))
+			defer srv.Close()
+			e := &Entregador{cfg: Config{Tentativas: c.tentativas, Intervalo: time.Second}, cliente: srv.Client()}
+
+			p := servico.EntregaPendente{
+				Webhook: model.Webhook{ID: 1, URL: srv.URL, Segredo: "segredo"},
+				Entrega: model.EntregaWebhook{ID: "1-3f9c", Evento: model.EventoEmprestimoCriado,
+					Corpo: `{"id":"3f9c","evento":"emprestimo.criado"}`, Situacao: model.EntregaPendente},
+			}
+			for i, esperada := range c.situacoes {
+				antes := time.Now()
+				p.Entrega = e.tentar(context.Background(), p)
+				if p.Entrega.Situacao != esperada {
+					t.Fatalf("tentativa %d: situação %q, esperada %q (erro %q)", i+1, p.Entrega.Situacao, esperada, p.Entrega.UltimoErro)
+				}
+				if p.Entrega.Tentativas != i+1 {
+					t.Errorf("tentativa %d: %d tentativa(s) registrada(s)", i+1, p.Entrega.Tentativas)
+				}
+				if esperada == model.EntregaPendente && p.Entrega.ProximaTentativa.Before(antes.Add(e.espera(i+1))) {
+					t.Errorf("tentativa %d: próxima tentativa em %s, antes da espera de %s", i+1, p.Entrega.ProximaTentativa, e.espera(i+1))
+				}
+			}
+			if p.Entrega.UltimoStatus != c.status {
+				t.Errorf("último status %d, esperado %d", p.Entrega.UltimoStatus, c.status)
+			}
+			if (p.Entrega.Situacao == model.EntregaEntregue) != (p.Entrega.EntregueEm != nil) {
+				t.Errorf("situação %q com EntregueEm %v", p.Entrega.Situacao, p.Entrega.EntregueEm)
+			}
+		})
+	}
+}